@@ -0,0 +1,56 @@
+package envied
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+)
+
+// coverageIgnoreDirective marks a file to be skipped by coverage tools
+const coverageIgnoreDirective = "//coverage:ignore"
+
+// linterNamePattern matches linter names accepted in nolint
+var linterNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// AnnotationsConfig adds lint suppression and coverage directives to the generated file
+type AnnotationsConfig struct {
+	Nolint         []string `json:"nolint,omitempty"`          // Linters suppressed with a file-level //nolint directive, "all" for all linters
+	CoverageIgnore bool     `json:"coverage_ignore,omitempty"` // Adds //coverage:ignore so coverage tools skip the file
+	Comments       []string `json:"comments,omitempty"`        // Additional comment lines written above the package clause
+}
+
+// validate checks that the annotations are single-line comments
+func (a *AnnotationsConfig) validate() error {
+	if a == nil {
+		return nil
+	}
+	for _, linter := range a.Nolint {
+		if !linterNamePattern.MatchString(linter) {
+			return newError(MessageInvalidNolintLinter, linter)
+		}
+	}
+	for _, comment := range a.Comments {
+		if !strings.HasPrefix(comment, "//") || strings.ContainsAny(comment, "\r\n") {
+			return newError(MessageInvalidAnnotation, comment)
+		}
+	}
+	return nil
+}
+
+// writeAnnotations writes the directives directly above the package clause,
+// where file-level directives of golangci-lint and coverage tools are expected
+func writeAnnotations(w io.Writer, annotations *AnnotationsConfig) {
+	if annotations == nil {
+		return
+	}
+	if annotations.CoverageIgnore {
+		fmt.Fprintf(w, "%s\n", coverageIgnoreDirective)
+	}
+	for _, comment := range annotations.Comments {
+		fmt.Fprintf(w, "%s\n", comment)
+	}
+	if len(annotations.Nolint) > 0 {
+		fmt.Fprintf(w, "//nolint:%s\n", strings.Join(annotations.Nolint, ","))
+	}
+}
@@ -0,0 +1,27 @@
+package envied
+
+// layerBase reads the base env file of an environment under its variables: variables missing
+// from the environment are taken from the base, the others override it. Returns the hash of
+// the base env file.
+func layerBase(envConfig EnvironmentConfig, envVars map[string]EnvValue, provenance map[string]Provenance) (string, error) {
+	baseVars, err := readEnvFile(EnvironmentConfig{EnvFile: envConfig.BaseEnvFile, KeySeparator: envConfig.KeySeparator})
+	if err != nil {
+		return "", newError(MessageFailedReadBaseEnvFile, envConfig.BaseEnvFile, err)
+	}
+	hash, err := hashFile(envConfig.BaseEnvFile)
+	if err != nil {
+		return "", newError(MessageFailedHashBaseEnvFile, envConfig.BaseEnvFile, err)
+	}
+
+	for name, value := range baseVars {
+		base := valueSource(envConfig.BaseEnvFile, value)
+		if overriding, exists := provenance[name]; exists {
+			overriding.Overrides = append(overriding.Overrides, base)
+			provenance[name] = overriding
+			continue
+		}
+		provenance[name] = Provenance{Source: base}
+		envVars[name] = value
+	}
+	return hash, nil
+}
@@ -0,0 +1,77 @@
+package envied
+
+import (
+	"errors"
+	"time"
+)
+
+// Target describes a single generation job of a batch
+type Target struct {
+	Name            string // Target name used in results and errors (ConfigPath if empty)
+	ConfigPath      string // Path to go-envied-config.json of the target
+	ContinueOnError bool   // Continue with the remaining targets if this one fails
+
+	// Receives the progress messages of the target, they are discarded if nil
+	Messages func(Message)
+}
+
+// Result contains the outcome of a single target
+type Result struct {
+	Target     Target
+	OutputFile string        // Generated file, empty if generation failed
+	Duration   time.Duration // Time spent on the target
+	Warnings   []Warning     // Warnings of generation
+	Err        error         // Generation error, nil on success
+}
+
+// Succeeded reports whether the target was generated successfully
+func (r Result) Succeeded() bool {
+	return r.Err == nil
+}
+
+// name returns the display name of the target
+func (t Target) name() string {
+	if t.Name != "" {
+		return t.Name
+	}
+	return t.ConfigPath
+}
+
+// GenerateAll generates every target in order and returns one result per processed target.
+// Processing stops at the first failing target unless its ContinueOnError is set;
+// targets after the stop are not included in the results.
+// The returned error joins the errors of all failed targets. Nothing is printed, progress
+// messages go to the Messages handler of each target and warnings are returned in its result.
+func GenerateAll(targets []Target) ([]Result, error) {
+	results := make([]Result, 0, len(targets))
+	var errs []error
+
+	for _, target := range targets {
+		start := time.Now()
+		result := Result{Target: target}
+
+		configFile, err := LoadConfigFile(target.ConfigPath)
+		if err == nil {
+			var log *messageLog
+			if target.Messages != nil {
+				log = &messageLog{handler: target.Messages}
+			}
+			result.Warnings, err = generateFromConfig(configFile, target.ConfigPath, configFile.outputFile(), log)
+		}
+		if err == nil {
+			result.OutputFile = configFile.outputFile()
+		} else {
+			result.Err = newError(MessageBatchTarget, target.name(), err)
+			errs = append(errs, result.Err)
+		}
+
+		result.Duration = time.Since(start)
+		results = append(results, result)
+
+		if err != nil && !target.ContinueOnError {
+			break
+		}
+	}
+
+	return results, errors.Join(errs...)
+}
@@ -0,0 +1,204 @@
+package envied
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// DefaultBuildTagPrefix prefixes environment names in the build tags selecting an environment
+const DefaultBuildTagPrefix = "envied_"
+
+// buildTagNamePattern matches environment names usable in build tags
+var buildTagNamePattern = regexp.MustCompile(`^[A-Za-z0-9_.]+$`)
+
+// reservedBuildTags are set by the go command, an environment tag with one of these names would
+// be selected by the platform or toolchain instead of by -tags
+var reservedBuildTags = map[string]bool{
+	"aix": true, "android": true, "darwin": true, "dragonfly": true, "freebsd": true, "hurd": true,
+	"illumos": true, "ios": true, "js": true, "linux": true, "nacl": true, "netbsd": true,
+	"openbsd": true, "plan9": true, "solaris": true, "wasip1": true, "windows": true, "zos": true,
+	"386": true, "amd64": true, "arm": true, "arm64": true, "loong64": true, "mips": true,
+	"mipsle": true, "mips64": true, "mips64le": true, "ppc64": true, "ppc64le": true,
+	"riscv64": true, "s390x": true, "wasm": true,
+	"unix": true, "cgo": true, "gc": true, "gccgo": true, "ignore": true, "race": true,
+	"msan": true, "asan": true, "purego": true,
+}
+
+// buildTagPrefix returns the prefix of the build tags of environments
+func (c *ConfigFile) buildTagPrefix() string {
+	if c.BuildTagPrefix == nil {
+		return DefaultBuildTagPrefix
+	}
+	return *c.BuildTagPrefix
+}
+
+// buildTag returns the build tag selecting an environment
+func buildTag(prefix, envName string) string {
+	return prefix + envName
+}
+
+// checkBuildTagNames fails for environment names that can't be used in build tags
+func checkBuildTagNames(configFile *ConfigFile) error {
+	if !configFile.BuildTags {
+		return nil
+	}
+	prefix := configFile.buildTagPrefix()
+	if prefix != "" && !buildTagNamePattern.MatchString(prefix) {
+		return newError(MessageInvalidBuildTagPrefix, prefix)
+	}
+	for _, envName := range configFile.environmentNames() {
+		if !buildTagNamePattern.MatchString(envName) {
+			return newError(MessageInvalidBuildTagEnvironment, envName)
+		}
+		if tag := buildTag(prefix, envName); reservedBuildTags[tag] || strings.HasPrefix(tag, "go1.") {
+			return newError(MessageReservedBuildTag, tag, envName)
+		}
+	}
+	return nil
+}
+
+// checkSplitEnvironments fails for split_environments without build_tags, which select the
+// compiled environment, and with internal_package, whose shim re-exports every environment
+func checkSplitEnvironments(configFile *ConfigFile) error {
+	if !configFile.SplitEnvironments {
+		return nil
+	}
+	if !configFile.BuildTags {
+		return newError(MessageSplitEnvironmentsRequiresBuildTags, configFile.buildTagPrefix())
+	}
+	if configFile.InternalPackage {
+		return newError(MessageSplitWithInternalPackage)
+	}
+	return nil
+}
+
+// environmentFile returns the path of the file of an environment next to codeFile
+func environmentFile(codeFile, fileName string) string {
+	return strings.TrimSuffix(strings.TrimSuffix(codeFile, ".go"), ".gen") + "_" + fileName + ".gen.go"
+}
+
+// buildTagFiles returns the contents of the files selecting NewConfig by build tag, keyed by path:
+// one file per environment next to codeFile and a guard failing compilation unless exactly one
+// environment tag is set. The file of an environment is a NewConfig stub, or holds the whole
+// environment with split_environments.
+func buildTagFiles(codeFile string, data *mergedConfig) (map[string][]byte, error) {
+	envNames := sortedEnvironmentNames(data.Environments)
+
+	files := make(map[string][]byte, len(envNames)+1)
+	if data.Split {
+		merged := newMergedData(data)
+		for _, env := range merged.Environments {
+			code, err := merged.RenderEnvironment(env)
+			if err != nil {
+				return nil, err
+			}
+			files[environmentFile(codeFile, data.namer.File(env.Name))] = code
+		}
+	} else {
+		for _, envName := range envNames {
+			var stub bytes.Buffer
+			writeBuildTagStub(&stub, data, envName)
+			files[environmentFile(codeFile, data.namer.File(envName))] = stub.Bytes()
+		}
+	}
+	var guard bytes.Buffer
+	writeBuildTagGuard(&guard, data, envNames)
+	files[environmentFile(codeFile, "buildtags")] = guard.Bytes()
+	return files, nil
+}
+
+// writeBuildTagHeader writes the header and build constraint of a build tag file
+func writeBuildTagHeader(w io.Writer, data *mergedConfig, constraint string) {
+	fmt.Fprintf(w, "%s\n\n", generatedHeader)
+	fmt.Fprintf(w, "//go:build %s\n\n", constraint)
+	writeAnnotations(w, data.Annotations)
+	fmt.Fprintf(w, "package %s\n\n", data.PackageName)
+}
+
+// writeBuildTagStub writes the NewConfig of an environment, compiled with its build tag only
+func writeBuildTagStub(w io.Writer, data *mergedConfig, envName string) {
+	writeBuildTagHeader(w, data, buildTag(data.TagPrefix, envName))
+	fmt.Fprintf(w, "// NewConfig creates the configuration of the %s environment selected by the %s build tag\n", envName, buildTag(data.TagPrefix, envName))
+	fmt.Fprintf(w, "func NewConfig(opts ...Override) ConfigInterface {\n")
+	fmt.Fprintf(w, "\treturn New%sConfig(opts...)\n", data.Environments[envName].StructName)
+	fmt.Fprintf(w, "}\n")
+}
+
+// writeBuildTagGuard writes the file referencing an undefined identifier unless exactly one
+// environment tag is set, so such builds fail with a compile error instead of at run time
+func writeBuildTagGuard(w io.Writer, data *mergedConfig, envNames []string) {
+	tags := make([]string, len(envNames))
+	for i, envName := range envNames {
+		tags[i] = buildTag(data.TagPrefix, envName)
+	}
+
+	constraint := "!" + tags[0]
+	if len(tags) > 1 {
+		terms := make([]string, len(tags))
+		for i := range tags {
+			literals := make([]string, len(tags))
+			for j, tag := range tags {
+				if i == j {
+					literals[j] = tag
+				} else {
+					literals[j] = "!" + tag
+				}
+			}
+			terms[i] = "(" + strings.Join(literals, " && ") + ")"
+		}
+		constraint = "!(" + strings.Join(terms, " || ") + ")"
+	}
+
+	writeBuildTagHeader(w, data, constraint)
+	fmt.Fprintf(w, "// Builds of this package require exactly one environment build tag: -tags %s\n", strings.Join(tags, " or -tags "))
+	fmt.Fprintf(w, "var _ = enviedBuildRequiresExactlyOneEnvironmentTag\n")
+}
+
+// generateBuildTagFiles writes the build tag files of a generated code file
+func generateBuildTagFiles(codeFile string, data *mergedConfig) error {
+	files, err := buildTagFiles(codeFile, data)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		if err := os.WriteFile(path, files[path], 0644); err != nil {
+			return newError(MessageFailedWrite, path, err)
+		}
+	}
+	return nil
+}
+
+// checkBuildTagFiles compares the build tag files of a generated code file with the expected contents
+func checkBuildTagFiles(codeFile string, data *mergedConfig) error {
+	files, err := buildTagFiles(codeFile, data)
+	if err != nil {
+		return err
+	}
+	paths := make([]string, 0, len(files))
+	for path := range files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		existing, err := readGenerated(path)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(files[path], existing) {
+			return outdatedError(path, files[path], existing, "")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package envied
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+)
+
+// Encodings of []byte variables
+const (
+	EncodingBase64    = "base64"    // Standard base64 with padding (default)
+	EncodingBase64URL = "base64url" // URL-safe base64 with padding
+	EncodingHex       = "hex"       // Hexadecimal
+)
+
+// resolveEncoding returns the encoding of a []byte variable, EncodingBase64 if not configured
+func resolveEncoding(encoding string) (string, error) {
+	switch encoding {
+	case "":
+		return EncodingBase64, nil
+	case EncodingBase64, EncodingBase64URL, EncodingHex:
+		return encoding, nil
+	default:
+		return "", newError(MessageUnknownEncoding, encoding, EncodingBase64, EncodingBase64URL, EncodingHex)
+	}
+}
+
+// decodeBytes decodes a value in the given encoding
+func decodeBytes(encoding, value string) ([]byte, error) {
+	switch encoding {
+	case EncodingHex:
+		return hex.DecodeString(value)
+	case EncodingBase64URL:
+		return base64.URLEncoding.DecodeString(value)
+	default:
+		return base64.StdEncoding.DecodeString(value)
+	}
+}
+
+// ParseBytes decodes a base64 or hex string to []byte
+func ParseBytes(encoding, value string) []byte {
+	result, _ := decodeBytes(encoding, value)
+	return result
+}
+
+// CoerceBytes decodes a layered base64 or hex value to []byte
+func CoerceBytes(name, encoding, value string) ([]byte, error) {
+	result, err := decodeBytes(encoding, value)
+	if err != nil {
+		return nil, newError(MessageInvalidEncodedValue, name, encoding)
+	}
+	return result, nil
+}
+
+// FormatBytes encodes []byte as in a .env file
+func FormatBytes(encoding string, value []byte) string {
+	switch encoding {
+	case EncodingHex:
+		return hex.EncodeToString(value)
+	case EncodingBase64URL:
+		return base64.URLEncoding.EncodeToString(value)
+	default:
+		return base64.StdEncoding.EncodeToString(value)
+	}
+}
@@ -0,0 +1,298 @@
+package envied
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrOutdated is returned by Check when the generated file does not match the configuration
+var ErrOutdated error = &Error{ID: MessageOutdated}
+
+// generatedAtPattern finds the generation time embedded by GeneratedAt methods
+var generatedAtPattern = regexp.MustCompile(`return time\.Unix\((-?\d+), 0\)\.UTC\(\)`)
+
+// embeddedGenerationTime returns the generation time of a generated file
+func embeddedGenerationTime(source []byte) (time.Time, bool) {
+	match := generatedAtPattern.FindSubmatch(source)
+	if match == nil {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(string(match[1]), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0).UTC(), true
+}
+
+// Check regenerates the configuration in memory and compares it with the generated file,
+// returning an error wrapping ErrOutdated if they differ. The generation time of the
+// existing file is reused, as are the keys and data of obfuscated values decoding to the
+// expected values, so random obfuscation keys compare equal.
+func Check(opts GenerateOptions) error {
+	return opts.run(func() error {
+		return check(opts)
+	})
+}
+
+// check implements Check
+func check(opts GenerateOptions) error {
+	configFile, configPath, outputFile, err := opts.load()
+	if err != nil {
+		return err
+	}
+
+	codeFile, internalImport, err := configFile.generatedCodeFile(outputFile)
+	if err != nil {
+		return err
+	}
+
+	existing, err := readGenerated(codeFile)
+	if err != nil {
+		return err
+	}
+
+	mergedData, err := buildMergedConfig(configFile, configPath, nil)
+	if err != nil {
+		return err
+	}
+	// Types renamed for conflicts with other package files are expected renamed
+	if configFile.OnConflict == ConflictRename {
+		if err := checkPackageConflicts(outputFile, mergedData, ConflictRename, nil); err != nil {
+			return err
+		}
+		if internalImport != "" {
+			if err := checkPackageConflicts(codeFile, mergedData, ConflictRename, nil); err != nil {
+				return err
+			}
+		}
+	}
+	if generatedAt, ok := embeddedGenerationTime(existing); ok {
+		mergedData.GeneratedAt = generatedAt
+	}
+	// With split_environments the obfuscated values are in the files of the environments
+	sources := [][]byte{existing}
+	if mergedData.Split {
+		for _, envName := range sortedEnvironmentNames(mergedData.Environments) {
+			if envCode, err := os.ReadFile(environmentFile(codeFile, mergedData.namer.File(envName))); err == nil {
+				sources = append(sources, envCode)
+			}
+		}
+	}
+	reuseObfuscatedValues(mergedData, sources...)
+
+	var expected bytes.Buffer
+	if err := generateCodeDirectly(&expected, mergedData); err != nil {
+		return err
+	}
+	if !bytes.Equal(expected.Bytes(), existing) {
+		return outdatedError(codeFile, expected.Bytes(), existing, "")
+	}
+	if mergedData.BuildTags {
+		if err := checkBuildTagFiles(codeFile, mergedData); err != nil {
+			return err
+		}
+	}
+
+	if internalImport == "" {
+		return nil
+	}
+	existingShim, err := readGenerated(outputFile)
+	if err != nil {
+		return err
+	}
+	var expectedShim bytes.Buffer
+	writeShim(&expectedShim, mergedData, internalImport)
+	if !bytes.Equal(expectedShim.Bytes(), existingShim) {
+		return outdatedError(outputFile, expectedShim.Bytes(), existingShim, "")
+	}
+	return nil
+}
+
+// reuseObfuscatedValues replaces the obfuscated values of data with the keys and data declared in
+// the existing generated sources if both decode to the same value
+func reuseObfuscatedValues(data *mergedConfig, sources ...[]byte) {
+	declared := make(map[string]any)
+	for _, source := range sources {
+		file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+		if err != nil {
+			continue
+		}
+		for _, decl := range file.Decls {
+			gen, ok := decl.(*ast.GenDecl)
+			if !ok || gen.Tok != token.VAR {
+				continue
+			}
+			for _, spec := range gen.Specs {
+				valueSpec := spec.(*ast.ValueSpec)
+				if len(valueSpec.Names) != 1 || len(valueSpec.Values) != 1 {
+					continue
+				}
+				if value, ok := sliceLiteral(valueSpec.Values[0]); ok {
+					declared[valueSpec.Names[0].Name] = value
+				}
+			}
+		}
+	}
+
+	for envName, envData := range data.Environments {
+		envPrefix := strings.ToLower(envName)
+		for name, result := range envData.Obfuscated {
+			key, keyExists := declared[envPrefix+result.KeyName]
+			value, valueExists := declared[envPrefix+result.ValueName]
+			if !keyExists || !valueExists {
+				continue
+			}
+			existingValue, ok := decodeObfuscated(name, key, value)
+			if !ok {
+				continue
+			}
+			if expectedValue, ok := decodeObfuscated(name, result.Key, result.Value); ok && reflect.DeepEqual(existingValue, expectedValue) {
+				result.Key, result.Value = key, value
+			}
+		}
+	}
+}
+
+// decodeObfuscated decodes the key and data of an obfuscated value of the named variable,
+// ok is false if they don't decode
+func decodeObfuscated(name string, key, data any) (any, bool) {
+	switch key := key.(type) {
+	case []int:
+		data, ok := data.([]int)
+		if !ok {
+			return nil, false
+		}
+		value, err := DecodeString(key, data)
+		return value, err == nil
+	case []byte:
+		data, ok := data.([]byte)
+		if !ok {
+			return nil, false
+		}
+		value, err := OpenString(name, key, data)
+		return value, err == nil
+	case [][]int:
+		data, ok := data.([][]int)
+		if !ok || len(data) != len(key) {
+			return nil, false
+		}
+		elements := make([]string, len(key))
+		for i := range key {
+			element, err := DecodeString(key[i], data[i])
+			if err != nil {
+				return nil, false
+			}
+			elements[i] = element
+		}
+		return elements, true
+	case [][]byte:
+		data, ok := data.([][]byte)
+		if !ok || len(data) != len(key) {
+			return nil, false
+		}
+		elements := make([]string, len(key))
+		for i := range key {
+			element, err := OpenString(elementName(name, i), key[i], data[i])
+			if err != nil {
+				return nil, false
+			}
+			elements[i] = element
+		}
+		return elements, true
+	default:
+		return nil, false
+	}
+}
+
+// sliceLiteral evaluates the []int, [][]int, []byte and [][]byte literals written by goLiteral
+func sliceLiteral(expr ast.Expr) (any, bool) {
+	lit, ok := expr.(*ast.CompositeLit)
+	if !ok {
+		return nil, false
+	}
+	sliceType, ok := lit.Type.(*ast.ArrayType)
+	if !ok || sliceType.Len != nil {
+		return nil, false
+	}
+
+	switch elt := sliceType.Elt.(type) {
+	case *ast.Ident:
+		if elt.Name != "int" && elt.Name != "byte" {
+			return nil, false
+		}
+		values := make([]int, len(lit.Elts))
+		for i, element := range lit.Elts {
+			basic, ok := element.(*ast.BasicLit)
+			if !ok || basic.Kind != token.INT {
+				return nil, false
+			}
+			value, err := strconv.ParseInt(basic.Value, 0, 64)
+			if err != nil || (elt.Name == "byte" && (value < 0 || value > 255)) {
+				return nil, false
+			}
+			values[i] = int(value)
+		}
+		if elt.Name == "int" {
+			return values, true
+		}
+		data := make([]byte, len(values))
+		for i, value := range values {
+			data[i] = byte(value)
+		}
+		return data, true
+	case *ast.ArrayType:
+		elementType, ok := elt.Elt.(*ast.Ident)
+		if !ok || elt.Len != nil {
+			return nil, false
+		}
+		ints := make([][]int, len(lit.Elts))
+		data := make([][]byte, len(lit.Elts))
+		for i, element := range lit.Elts {
+			inner, ok := element.(*ast.CompositeLit)
+			if !ok {
+				return nil, false
+			}
+			// Elements of nested literals omit their type
+			inner = &ast.CompositeLit{Type: elt, Elts: inner.Elts}
+			value, ok := sliceLiteral(inner)
+			if !ok {
+				return nil, false
+			}
+			switch value := value.(type) {
+			case []int:
+				ints[i] = value
+			case []byte:
+				data[i] = value
+			}
+		}
+		if elementType.Name == "int" {
+			return ints, true
+		}
+		return data, true
+	default:
+		return nil, false
+	}
+}
+
+// readGenerated reads a generated file, a missing file is out of date
+func readGenerated(filename string) ([]byte, error) {
+	data, err := os.ReadFile(filename)
+	if os.IsNotExist(err) {
+		return nil, newError(MessageOutdatedMissingFile, ErrOutdated, filename)
+	}
+	return data, err
+}
+
+// outdatedError reports the first difference between the expected and existing contents of a generated file
+func outdatedError(filename string, expected, existing []byte, hint string) error {
+	line, expectedLine, existingLine := firstDifference(expected, existing)
+	return newError(MessageOutdatedDiffers, ErrOutdated, filename, line, expectedLine, existingLine, hint)
+}
@@ -0,0 +1,120 @@
+package envied
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// CloneOptions configures CloneEnvironment
+type CloneOptions struct {
+	EnvFile    string // Env file of the new environment, the source env file with the name replaced if empty
+	CopyValues bool   // Copies the values of the source env file instead of leaving them empty
+}
+
+// CloneEnvironment adds the environment to, declared like from with a copy of its env file, to
+// the configuration and returns the path of the new env file. Values are left empty unless
+// opts.CopyValues is set, so secrets of one deployment stage don't leak into another. Variables
+// restricted to from with only are generated for to as well. Overlays and profiles belong to
+// the source environment and are not cloned.
+func CloneEnvironment(configFilePath, from, to string, opts CloneOptions) (string, error) {
+	configFile, err := LoadConfigFile(configFilePath)
+	if err != nil {
+		return "", err
+	}
+
+	source, exists := configFile.Environments[from]
+	if !exists {
+		return "", &ErrUnknownEnvironment{Name: from, Valid: configFile.environmentNames()}
+	}
+	if _, exists := configFile.Environments[to]; exists {
+		return "", newError(MessageEnvironmentExists, to)
+	}
+	if source.EnvFile == "" {
+		return "", newError(MessageCloneRemoteSource, from, to)
+	}
+	structName := capitalize(to)
+	for _, envName := range configFile.environmentNames() {
+		if configFile.Environments[envName].StructName == structName {
+			return "", newError(MessageCloneStructNameUsed, structName, to, envName)
+		}
+	}
+
+	envFile := opts.EnvFile
+	if envFile == "" {
+		envFile = clonedEnvFileName(source.EnvFile, from, to)
+	}
+	if _, err := os.Stat(envFile); err == nil {
+		return "", newError(MessageAlreadyExists, envFile)
+	}
+
+	content, encrypted, err := readEnvFileDecrypted(source.EnvFile)
+	if err != nil {
+		return "", err
+	}
+	if !opts.CopyValues {
+		if isYAMLFile(source.EnvFile) || isTOMLFile(source.EnvFile) || isJSONFile(source.EnvFile) {
+			return "", newError(MessageCloneValuesOnlyCopied, from, source.EnvFile)
+		}
+		content = blankEnvFileValues(content)
+	}
+
+	clone := source
+	clone.EnvFile = envFile
+	clone.StructName = structName
+	clone.Overlays = nil
+	clone.Profiles = nil
+	configFile.Environments[to] = clone
+
+	for name, variable := range configFile.Variables {
+		if slices.Contains(variable.Only, from) {
+			variable.Only = append(variable.Only, to)
+			configFile.Variables[name] = variable
+		}
+	}
+
+	// Checked first, so a configuration that can't be written leaves no env file behind
+	if err := checkWritableConfigFile(configFilePath); err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(envFile), 0755); err != nil {
+		return "", err
+	}
+	if err := writeEnvFileContent(envFile, content, encrypted); err != nil {
+		return "", err
+	}
+	if err := configFile.Save(configFilePath); err != nil {
+		return "", err
+	}
+	return envFile, nil
+}
+
+// clonedEnvFileName returns the env file of a cloned environment: the source env file with the
+// source environment name in its file name replaced, or <to>.env next to it
+func clonedEnvFileName(envFile, from, to string) string {
+	dir, base := filepath.Split(envFile)
+	if strings.Contains(base, from) {
+		return filepath.Join(dir, strings.Replace(base, from, to, 1))
+	}
+	return filepath.Join(dir, to+".env")
+}
+
+// blankEnvFileValues empties the values of an env file, keeping keys, comments, hints and line endings
+func blankEnvFileValues(content []byte) []byte {
+	lines := strings.Split(string(content), "\n")
+	var blanked []string
+	for _, envLine := range envFileLines(string(normalizeEnvFileContent(content))) {
+		key, _, ok := strings.Cut(envLine.text, "=")
+		if !ok || strings.HasPrefix(strings.TrimSpace(envLine.text), "#") {
+			blanked = append(blanked, lines[envLine.number-1:envLine.number-1+envLine.count]...)
+			continue
+		}
+		line := strings.TrimRight(key, " \t") + "="
+		if strings.HasSuffix(lines[envLine.number-1+envLine.count-1], "\r") {
+			line += "\r"
+		}
+		blanked = append(blanked, line)
+	}
+	return []byte(strings.Join(blanked, "\n"))
+}
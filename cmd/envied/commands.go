@@ -0,0 +1,506 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// envFlags collects repeated --env name=path flags
+type envFlags map[string]string
+
+func (e envFlags) String() string {
+	var parts []string
+	for name, path := range e {
+		parts = append(parts, name+"="+path)
+	}
+	return strings.Join(parts, ",")
+}
+
+func (e envFlags) Set(value string) error {
+	name, path, ok := strings.Cut(value, "=")
+	if !ok || name == "" || path == "" {
+		return newError(messageExpectedNamePath, value)
+	}
+	e[name] = path
+	return nil
+}
+
+// setFlags collects repeated --set name=value substitutions, values may be empty
+type setFlags map[string]string
+
+func (s setFlags) String() string {
+	return envFlags(s).String()
+}
+
+func (s setFlags) Set(value string) error {
+	name, substitution, ok := strings.Cut(value, "=")
+	if !ok || name == "" {
+		return newError(messageExpectedNameValue, value)
+	}
+	s[name] = substitution
+	return nil
+}
+
+// configFlags are the flags shared by commands working on a configuration
+type configFlags struct {
+	configPath string
+	outputFile string
+	envFiles   envFlags
+	set        setFlags
+	valuesFrom string
+	onConflict string
+	noNetwork  bool
+	verbose    bool
+	timings    bool
+	profileFlags
+}
+
+// newConfigFlagSet creates the flag set of a command with the shared configuration flags
+func newConfigFlagSet(name string) (*flag.FlagSet, *configFlags) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	config := &configFlags{envFiles: envFlags{}, set: setFlags{}}
+	flags.StringVar(&config.configPath, "config", "", text(messageFlagConfig))
+	flags.StringVar(&config.outputFile, "output", "", text(messageFlagOutput))
+	flags.StringVar(&config.outputFile, "out", "", text(messageFlagOut))
+	flags.Var(config.envFiles, "env", text(messageFlagEnv))
+	flags.Var(config.set, "set", text(messageFlagSet))
+	flags.StringVar(&config.valuesFrom, "values-from", "", text(messageFlagValuesFrom))
+	flags.StringVar(&config.onConflict, "on-conflict", "", text(messageFlagOnConflict))
+	flags.BoolFunc("force", text(messageFlagForce), func(string) error {
+		config.onConflict = envied.ConflictForce
+		return nil
+	})
+	flags.BoolVar(&config.noNetwork, "no-network", false, text(messageFlagNoNetwork))
+	flags.BoolVar(&config.verbose, "verbose", false, text(messageFlagVerbose))
+	flags.StringVar(&config.cpuProfile, "cpuprofile", "", text(messageFlagCPUProfile))
+	flags.StringVar(&config.memProfile, "memprofile", "", text(messageFlagMemProfile))
+	flags.StringVar(&config.traceFile, "trace", "", text(messageFlagTrace))
+	return flags, config
+}
+
+// options returns the library options of the flags
+func (c *configFlags) options() envied.GenerateOptions {
+	return envied.GenerateOptions{
+		ConfigPath: c.configPath,
+		OutputFile: c.outputFile,
+		EnvFiles:   c.envFiles,
+		Set:        c.set,
+		ValuesFrom: c.valuesFrom,
+		OnConflict: c.onConflict,
+		NoNetwork:  c.noNetwork,
+		Verbose:    c.verbose,
+		Timings:    c.timings,
+		Catalog:    catalog,
+	}
+}
+
+// guard runs fn under envied.WithoutNetwork if -no-network is set, profiled as requested
+func (c *configFlags) guard(fn func() error) error {
+	return c.profile(func() error {
+		if c.noNetwork {
+			return envied.WithoutNetwork(fn)
+		}
+		return fn()
+	})
+}
+
+func runGenerate(args []string) error {
+	flags, config := newConfigFlagSet("generate")
+	hermetic := flags.Bool("hermetic", false, text(messageFlagHermetic))
+	assertDeterministic := flags.Bool("assert-deterministic", false, text(messageFlagDeterministic))
+	flags.BoolVar(&config.timings, "timings", false, text(messageFlagTimings))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return &usageError{messageUnexpectedArguments, []any{strings.Join(flags.Args(), " ")}}
+	}
+
+	if *hermetic && config.valuesFrom != "" {
+		return &usageError{id: messageValuesFromHermetic}
+	}
+
+	return config.guard(func() error {
+		if *hermetic {
+			return envied.GenerateHermetic(envied.HermeticOptions{
+				ConfigPath: config.configPath,
+				OutputFile: config.outputFile,
+				EnvFiles:   config.envFiles,
+				Set:        config.set,
+
+				AssertDeterministic: *assertDeterministic,
+			})
+		}
+
+		if *assertDeterministic {
+			if err := envied.AssertDeterministic(config.options()); err != nil {
+				return err
+			}
+		}
+		return envied.Generate(config.options())
+	})
+}
+
+func runValidate(args []string) error {
+	flags, config := newConfigFlagSet("validate")
+	flags.BoolVar(&config.timings, "timings", false, text(messageFlagValidateTimings))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if err := config.profile(func() error { return envied.Validate(config.options()) }); err != nil {
+		return err
+	}
+	say(messageValid)
+	return nil
+}
+
+func runCheck(args []string) error {
+	flags, config := newConfigFlagSet("check")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if err := config.profile(func() error { return envied.Check(config.options()) }); err != nil {
+		return err
+	}
+	say(messageUpToDate)
+	return nil
+}
+
+func runVerify(args []string) error {
+	flags, config := newConfigFlagSet("verify")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if err := config.profile(func() error { return envied.Verify(config.options()) }); err != nil {
+		return err
+	}
+	say(messageEnvFilesMatch)
+	return nil
+}
+
+func runDoctor(args []string) error {
+	flags, config := newConfigFlagSet("doctor")
+	timeout := flags.Duration("timeout", envied.DefaultDoctorTimeout, text(messageFlagTimeout))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() > 0 {
+		return &usageError{messageUnexpectedArguments, []any{strings.Join(flags.Args(), " ")}}
+	}
+
+	var checks []envied.DoctorCheck
+	err := config.profile(func() (err error) {
+		checks, err = envied.Doctor(config.options(), *timeout)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	failed := 0
+	source := ""
+	for _, check := range checks {
+		// Checks of remote sources are grouped under their environment and source
+		indent := ""
+		if remote := check.Check == envied.DoctorCredentials || check.Check == envied.DoctorAccess; remote {
+			indent = "   "
+			if check.Environment+check.Source != source {
+				source = check.Environment + check.Source
+				say(messageDoctorSource, check.Environment, check.Source)
+			}
+		}
+		if check.OK {
+			say(messageDoctorPassed, indent, check.Check, check.Message)
+			continue
+		}
+		failed++
+		say(messageDoctorFailed, indent, check.Check, check.Message)
+		say(messageDoctorHint, indent, check.Hint)
+	}
+	if failed > 0 {
+		return newError(messageChecksFailed, failed)
+	}
+	say(messageAllChecksPassed)
+	return nil
+}
+
+func runExplain(args []string) error {
+	flags, config := newConfigFlagSet("explain")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return &usageError{id: messageUsageExplain}
+	}
+
+	var explanation *envied.VariableExplanation
+	err := config.profile(func() (err error) {
+		explanation, err = envied.Explain(config.options(), flags.Arg(0))
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	say(messageExplainVariable, explanation.Name)
+	if explanation.Description != "" {
+		say(messageExplainDescription, explanation.Description)
+	}
+	if len(explanation.Transforms) > 0 {
+		say(messageExplainTransforms, strings.Join(explanation.Transforms, ", "))
+	}
+	if len(explanation.Only) > 0 {
+		say(messageExplainOnly, strings.Join(explanation.Only, ", "))
+	}
+
+	fmt.Println()
+	for _, env := range explanation.Environments {
+		origin := text(messageExplainDetected)
+		if env.Declared {
+			origin = text(messageExplainDeclared)
+		}
+		sensitivity := text(messageExplainPlainText)
+		if env.Obfuscation != envied.ObfuscationNone {
+			sensitivity = text(messageExplainObfuscated, env.Obfuscation)
+		}
+		say(messageExplainEnvironment, env.Environment, env.Source)
+		if len(env.Overrides) > 0 {
+			say(messageExplainOverrides, strings.Join(env.Overrides, ", "))
+		}
+		say(messageExplainType, env.Type, origin, sensitivity, env.ValueGroup)
+		say(messageExplainIdentifiers, strings.Join(env.Identifiers, ", "))
+	}
+	for _, envName := range explanation.Missing {
+		say(messageExplainMissing, envName)
+	}
+
+	fmt.Println()
+	if len(explanation.Identifiers) > 0 {
+		say(messageExplainShared, strings.Join(explanation.Identifiers, ", "))
+	}
+	if explanation.ValuesDiffer {
+		say(messageExplainValuesDiffer)
+	} else {
+		say(messageExplainSameValue)
+	}
+	return nil
+}
+
+func runDiff(args []string) error {
+	flags, config := newConfigFlagSet("diff")
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if flags.NArg() != 2 {
+		return &usageError{id: messageUsageDiff}
+	}
+	from, to := flags.Arg(0), flags.Arg(1)
+
+	var differences []envied.VariableDifference
+	err := config.profile(func() (err error) {
+		differences, err = envied.DiffEnvironments(config.options(), from, to)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if len(differences) == 0 {
+		say(messageDiffSame, from, to)
+		return nil
+	}
+
+	for _, difference := range differences {
+		switch difference.Kind {
+		case envied.DiffAdded:
+			say(messageDiffAdded, difference.Name, difference.ToType, to)
+		case envied.DiffRemoved:
+			say(messageDiffRemoved, difference.Name, difference.FromType, from)
+		case envied.DiffTypeChanged:
+			say(messageDiffTypeChanged, difference.Name, difference.FromType, difference.ToType)
+		case envied.DiffValueChanged:
+			say(messageDiffValueChanged, difference.Name)
+		}
+	}
+	return errDifferences
+}
+
+func runReleaseDiff(args []string) error {
+	flags, config := newConfigFlagSet("release-diff")
+	previousPath := flags.String("previous", "", text(messageFlagPrevious))
+	ref := flags.String("ref", "", text(messageFlagRef))
+	keyEnv := flags.String("key-env", "", text(messageFlagManifestKeyEnv))
+	format := flags.String("format", envied.ReportMarkdown, text(messageFlagFormat))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if (*previousPath == "" && *ref == "") || flags.NArg() > 0 {
+		return &usageError{id: messageUsageReleaseDiff}
+	}
+
+	var key []byte
+	if *keyEnv != "" {
+		var err error
+		if key, err = envied.ArtifactKey(*keyEnv); err != nil {
+			return err
+		}
+	}
+
+	var previous *envied.Manifest
+	var err error
+	switch {
+	case *ref != "":
+		path := *previousPath
+		if path == "" {
+			if path, err = emittedManifestPath(config.configPath); err != nil {
+				return err
+			}
+		}
+		previous, err = envied.ReadManifestAtRef(*ref, path, key)
+	default:
+		var data []byte
+		if data, err = os.ReadFile(*previousPath); err != nil {
+			return err
+		}
+		if previous, err = envied.ParseManifest(data, key); err != nil {
+			err = newError(messageInvalidManifest, *previousPath, err)
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	var differences []envied.ReleaseDifference
+	err = config.guard(func() (err error) {
+		differences, err = envied.DiffRelease(config.options(), previous)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	return envied.WriteReleaseReport(os.Stdout, differences, *format)
+}
+
+// emittedManifestPath returns emit.manifest of the configuration, searched for if configPath is empty
+func emittedManifestPath(configPath string) (string, error) {
+	if configPath == "" {
+		configPath = envied.FindConfigFile()
+	}
+	if configPath == "" {
+		return "", newError(envied.MessageConfigNotFound, envied.DefaultConfigFileName)
+	}
+	configFile, err := envied.LoadConfigFile(configPath)
+	if err != nil {
+		return "", err
+	}
+	if configFile.Emit == nil || configFile.Emit.Manifest == "" {
+		return "", newError(messageNoManifestToCompare, configPath)
+	}
+	return configFile.Emit.Manifest, nil
+}
+
+func runInit(args []string) error {
+	flags := flag.NewFlagSet("init", flag.ContinueOnError)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	dir := "."
+	switch flags.NArg() {
+	case 0:
+	case 1:
+		dir = flags.Arg(0)
+	default:
+		return &usageError{id: messageUsageInit}
+	}
+
+	written, err := envied.InitProject(dir)
+	for _, file := range written {
+		say(envied.MessageWritten, file)
+	}
+	if err != nil {
+		return err
+	}
+	say(messageRunGenerate)
+	return nil
+}
+
+func runDecrypt(args []string) error {
+	flags := flag.NewFlagSet("decrypt", flag.ContinueOnError)
+	keyEnv := flags.String("key-env", "", text(messageFlagKeyEnv))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *keyEnv == "" || flags.NArg() != 1 {
+		return &usageError{id: messageUsageDecrypt}
+	}
+
+	key, err := envied.ArtifactKey(*keyEnv)
+	if err != nil {
+		return err
+	}
+	data, err := os.ReadFile(flags.Arg(0))
+	if err != nil {
+		return err
+	}
+	plaintext, err := envied.DecryptArtifact(data, key)
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(plaintext)
+	return err
+}
+
+func runEncryptEnv(args []string) error {
+	return convertEnvFiles("encrypt-env", args, envied.EncryptEnvFile, messageEncrypted, messageAlreadyEncrypted)
+}
+
+func runDecryptEnv(args []string) error {
+	return convertEnvFiles("decrypt-env", args, envied.DecryptEnvFile, messageDecrypted, messageNotEncrypted)
+}
+
+// convertEnvFiles encrypts or decrypts the given env files in place with the passphrase in
+// envied.PassphraseEnv, by default the env files of all environments of the configuration
+func convertEnvFiles(name string, args []string, convert func(filename, passphrase string) (bool, error), converted, unchanged envied.MessageID) error {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	configPath := flags.String("config", "", text(messageFlagEnvFilesConfig))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	passphrase := os.Getenv(envied.PassphraseEnv)
+	if passphrase == "" {
+		return newError(messagePassphraseNotSet, envied.PassphraseEnv)
+	}
+	files := flags.Args()
+	if len(files) == 0 {
+		if *configPath == "" {
+			*configPath = envied.FindConfigFile()
+		}
+		if *configPath == "" {
+			return newError(envied.MessageConfigNotFound, envied.DefaultConfigFileName)
+		}
+		var err error
+		if files, err = envied.ConfigEnvFiles(*configPath); err != nil {
+			return err
+		}
+	}
+
+	for _, file := range files {
+		changed, err := convert(file, passphrase)
+		if err != nil {
+			return err
+		}
+		if changed {
+			say(converted, file)
+		} else {
+			say(unchanged, file)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,436 @@
+// Command envied generates type-safe Go configuration from .env files.
+//
+// Usage:
+//
+//	envied <command> [flags]
+//
+// Commands:
+//
+//	generate        generate config_env.gen.go (default when no command is given)
+//	validate        run all generation checks without writing anything
+//	check           fail if the generated file is out of date
+//	verify          fail if an env file changed since generation, without regenerating
+//	diff            compare the variables of two environments
+//	release-diff    report variables added, removed or changed since the previous release
+//	doctor          check the project setup and remote sources before generating
+//	explain         show where a variable is defined, its type and generated identifiers
+//	init            write a starter configuration with dev and prod environments
+//	clone-env       add an environment declared like an existing one, with a copy of its env file
+//	fix             rewrite files generated by older releases to the current runtime API
+//	prune           report or remove variables never referenced by the code
+//	vet             report os.Getenv and os.LookupEnv calls reading managed variables
+//	decrypt         print an emitted file encrypted with emit.encrypt_key_env
+//	encrypt-env     encrypt env files in place so they can be committed
+//	decrypt-env     decrypt env files encrypted by encrypt-env in place
+//	serve-manifest  serve emitted manifests read-only over HTTP for fleet audits
+//
+// generate, validate, check, verify, doctor, diff, release-diff and explain accept -config
+// (searched in the current and parent directories if empty), -output (overrides the generated file
+// path) and repeated -env name=path flags replacing the env file of an environment. Repeated -set name=value
+// flags substitute {{.name}} placeholders in env values, over the substitutions of the configuration.
+// Environments declaring a vault secret are only generated with -values-from vault, which reads
+// their values over the structure of their env files.
+// With -no-network any network access during the run panics and remote sources are rejected.
+// -cpuprofile, -memprofile and -trace write a CPU profile, heap profile and execution trace of
+// the command, and generate and validate accept -timings to print how long every phase took.
+//
+// Hermetic mode (generate -hermetic) is intended for build systems such as Bazel: all inputs
+// are explicit flags, relative paths in the configuration are resolved against its directory,
+// the generated file is written to -output and nothing except errors is printed.
+// With -assert-deterministic generation is first run twice in memory and the command fails
+// if the outputs differ, which lets CI gate reproducibility.
+//
+// The release-diff command reports the variables added, removed or changed per environment since
+// the previous release for release notes and security reviews, as Markdown or with -format json.
+// The previous release is the manifest given by -previous, or with -ref <revision> the manifest
+// written by emit.manifest as committed at that revision. Values are compared by their hashes in
+// the manifest and never printed.
+//
+// The doctor command prints a checklist of the project setup: that the configuration is found,
+// env files are readable and not writable by all users, the output directory holds only the
+// configured package inside a Go module and the go command is installed. It then checks the
+// remote sources generation reads: that the environment variables and files they need are present
+// and, within -timeout per source, that they are reachable and readable with these credentials.
+// Failed checks print setup guidance. It accepts the flags of generate, so -values-from vault
+// also checks the Vault secrets.
+//
+// The fix command rewrites committed files generated by older go-envied releases to the
+// current runtime API. Paths may be files or directories and default to the current directory.
+//
+// The prune command reports variables whose generated getters and fields are never referenced
+// in the given packages (./... by default). With -write they are removed from the env files
+// and the configuration, so they are no longer embedded in binaries.
+//
+// The clone-env command adds an environment declared like an existing one, with a copy of its
+// env file next to it, so a new deployment stage starts from the variables of an existing one:
+// envied clone-env prod -as staging. Values are left empty unless -copy-values is set.
+//
+// The vet command reports os.Getenv and os.LookupEnv calls with constant names of variables
+// managed by go-envied in the given packages (./... by default) and fails if there are any,
+// so code reads the typed generated configuration instead.
+//
+// The encrypt-env command encrypts env files in place with a key derived from the passphrase
+// in ENVIED_PASSPHRASE, so they can be committed, and decrypt-env restores them for editing.
+// Files default to the env files of all environments of -config. Generation and every other
+// command read encrypted env files transparently when ENVIED_PASSPHRASE is set.
+//
+// The serve-manifest command serves the manifests written by emit.manifest to audit jobs
+// querying which variables the latest build of every service embeds, never their values.
+// Requests must present the token held by the -token-env variable as a bearer token. Manifests
+// are given as repeated -manifest service=path flags, or default to emit.manifest of -config.
+//
+// Messages, warnings and errors are printed in English. ENVIED_CATALOG names a JSON file mapping
+// message IDs of the command and of the library to other formats, e.g. to translate the output.
+//
+// Exit codes:
+//
+//	0  success
+//	1  generation, validation or I/O error, or vet found direct lookups
+//	2  invalid command line
+//	3  check or verify found an out of date file or diff found differences
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// Exit codes
+const (
+	exitOK          = 0
+	exitError       = 1
+	exitUsage       = 2
+	exitDifferences = 3
+)
+
+// errDifferences reports a successful comparison that found differences
+var errDifferences = errors.New("differences found")
+
+// usageError marks invalid command lines
+type usageError struct {
+	id   envied.MessageID
+	args []any
+}
+
+func (e *usageError) Error() string {
+	return text(e.id, e.args...)
+}
+
+// command is a subcommand of envied
+type command struct {
+	name    string
+	summary envied.MessageID
+	run     func(args []string) error
+}
+
+var commands []command
+
+func init() {
+	commands = []command{
+		{"generate", messageSummaryGenerate, runGenerate},
+		{"validate", messageSummaryValidate, runValidate},
+		{"check", messageSummaryCheck, runCheck},
+		{"verify", messageSummaryVerify, runVerify},
+		{"diff", messageSummaryDiff, runDiff},
+		{"release-diff", messageSummaryReleaseDiff, runReleaseDiff},
+		{"doctor", messageSummaryDoctor, runDoctor},
+		{"explain", messageSummaryExplain, runExplain},
+		{"init", messageSummaryInit, runInit},
+		{"clone-env", messageSummaryCloneEnv, runCloneEnv},
+		{"fix", messageSummaryFix, runFix},
+		{"prune", messageSummaryPrune, runPrune},
+		{"vet", messageSummaryVet, runVet},
+		{"decrypt", messageSummaryDecrypt, runDecrypt},
+		{"encrypt-env", messageSummaryEncryptEnv, runEncryptEnv},
+		{"decrypt-env", messageSummaryDecryptEnv, runDecryptEnv},
+		{"serve-manifest", messageSummaryServeManifest, runServeManifest},
+		{"help", messageSummaryHelp, runHelp},
+	}
+}
+
+func main() {
+	if err := loadCatalog(); err != nil {
+		os.Exit(exitCode(err))
+	}
+	os.Exit(exitCode(dispatch(os.Args[1:])))
+}
+
+// dispatch runs the command named by the first argument, generate if there is none
+func dispatch(args []string) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return runGenerate(args)
+	}
+
+	for _, cmd := range commands {
+		if cmd.name == args[0] {
+			return cmd.run(args[1:])
+		}
+	}
+	return &usageError{messageUnknownCommand, []any{args[0]}}
+}
+
+// exitCode prints the error with the catalog and maps it to the exit code of the process
+func exitCode(err error) int {
+	var usage *usageError
+	switch {
+	case err == nil, errors.Is(err, flag.ErrHelp):
+		return exitOK
+	case errors.As(err, &usage):
+		fmt.Fprintln(os.Stderr, catalog.Error(err))
+		return exitUsage
+	case errors.Is(err, errDifferences):
+		return exitDifferences
+	case errors.Is(err, envied.ErrOutdated):
+		fmt.Fprintln(os.Stderr, catalog.Error(err))
+		return exitDifferences
+	default:
+		fmt.Fprintln(os.Stderr, catalog.Error(err))
+		return exitError
+	}
+}
+
+func runHelp(args []string) error {
+	say(messageHelpUsage)
+	fmt.Println()
+	say(messageHelpCommands)
+	width := 0
+	for _, cmd := range commands {
+		width = max(width, len(cmd.name))
+	}
+	for _, cmd := range commands {
+		fmt.Printf("  %-*s %s\n", width, cmd.name, text(cmd.summary))
+	}
+	fmt.Println()
+	say(messageHelpFlags)
+	return nil
+}
+
+// parseFlags parses command flags, reporting invalid flags as usage errors
+func parseFlags(flags *flag.FlagSet, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return err
+		}
+		return &usageError{messageInvalidFlags, []any{err}}
+	}
+	return nil
+}
+
+func runFix(args []string) error {
+	flags := flag.NewFlagSet("fix", flag.ContinueOnError)
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	paths := flags.Args()
+	if len(paths) == 0 {
+		paths = []string{"."}
+	}
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			return err
+		}
+
+		if !info.IsDir() {
+			fixed, err := envied.FixFile(path)
+			if err != nil {
+				return err
+			}
+			if fixed {
+				say(messageFixed, path)
+			}
+			continue
+		}
+
+		changed, err := envied.FixDir(path)
+		if err != nil {
+			return err
+		}
+		for _, file := range changed {
+			say(messageFixed, file)
+		}
+	}
+
+	return nil
+}
+
+func runPrune(args []string) error {
+	flags := flag.NewFlagSet("prune", flag.ContinueOnError)
+	configPath := flags.String("config", "", text(messageFlagConfig))
+	analyze := flags.Bool("analyze", false, text(messageFlagAnalyze))
+	write := flags.Bool("write", false, text(messageFlagWrite))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if !*analyze {
+		return &usageError{id: messagePruneRequiresAnalyze}
+	}
+	if *configPath == "" {
+		*configPath = envied.FindConfigFile()
+	}
+	if *configPath == "" {
+		return newError(envied.MessageConfigNotFound, envied.DefaultConfigFileName)
+	}
+
+	unused, err := envied.FindUnusedVariables(*configPath, flags.Args()...)
+	if err != nil {
+		return err
+	}
+	if len(unused) == 0 {
+		say(messageAllVariablesUsed)
+		return nil
+	}
+
+	names := make([]string, len(unused))
+	for i, variable := range unused {
+		names[i] = variable.Name
+		say(messageUnusedVariable, variable.Name, variable.Getter, strings.Join(variable.Environments, ", "))
+	}
+
+	if !*write {
+		return nil
+	}
+	if err := envied.PruneVariables(*configPath, names); err != nil {
+		return err
+	}
+	say(messageRemovedVariables, len(names))
+	return nil
+}
+
+func runCloneEnv(args []string) error {
+	flags := flag.NewFlagSet("clone-env", flag.ContinueOnError)
+	configPath := flags.String("config", "", text(messageFlagCloneConfig))
+	as := flags.String("as", "", text(messageFlagAs))
+	envFile := flags.String("env-file", "", text(messageFlagEnvFile))
+	copyValues := flags.Bool("copy-values", false, text(messageFlagCopyValues))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	// Flags may follow the source environment: clone-env prod -as staging
+	var envNames []string
+	if flags.NArg() > 0 {
+		envNames = append(envNames, flags.Arg(0))
+		if err := parseFlags(flags, flags.Args()[1:]); err != nil {
+			return err
+		}
+		envNames = append(envNames, flags.Args()...)
+	}
+	if len(envNames) != 1 || *as == "" {
+		return &usageError{id: messageUsageCloneEnv}
+	}
+
+	if *configPath == "" {
+		*configPath = envied.FindConfigFile()
+	}
+	if *configPath == "" {
+		return newError(envied.MessageConfigNotFound, envied.DefaultConfigFileName)
+	}
+
+	written, err := envied.CloneEnvironment(*configPath, envNames[0], *as, envied.CloneOptions{EnvFile: *envFile, CopyValues: *copyValues})
+	if err != nil {
+		return err
+	}
+	say(envied.MessageWritten, written)
+	say(messageAddedEnvironment, *as, *configPath)
+	if !*copyValues {
+		say(messageFillInValues)
+	}
+	return nil
+}
+
+func runServeManifest(args []string) error {
+	flags := flag.NewFlagSet("serve-manifest", flag.ContinueOnError)
+	configPath := flags.String("config", "", text(messageFlagServeConfig))
+	addr := flags.String("addr", "localhost:8080", text(messageFlagAddr))
+	tokenEnv := flags.String("token-env", "", text(messageFlagTokenEnv))
+	keyEnv := flags.String("key-env", "", text(messageFlagManifestsKeyEnv))
+	manifests := envFlags{}
+	flags.Var(manifests, "manifest", text(messageFlagManifest))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+	if *tokenEnv == "" || flags.NArg() > 0 {
+		return &usageError{id: messageUsageServeManifest}
+	}
+
+	token := os.Getenv(*tokenEnv)
+	if token == "" {
+		return newError(messageTokenNotSet, *tokenEnv)
+	}
+	var key []byte
+	if *keyEnv != "" {
+		var err error
+		if key, err = envied.ArtifactKey(*keyEnv); err != nil {
+			return err
+		}
+	}
+	if len(manifests) == 0 {
+		if *configPath == "" {
+			*configPath = envied.FindConfigFile()
+		}
+		if *configPath == "" {
+			return newError(envied.MessageConfigNotFound, envied.DefaultConfigFileName)
+		}
+		configFile, err := envied.LoadConfigFile(*configPath)
+		if err != nil {
+			return err
+		}
+		if configFile.Emit == nil || configFile.Emit.Manifest == "" {
+			return newError(messageNoManifestToServe, *configPath)
+		}
+		manifests[configFile.PackageName] = configFile.Emit.Manifest
+	}
+
+	handler, err := envied.NewManifestHandler(envied.ManifestServerOptions{Manifests: manifests, Token: token, Key: key})
+	if err != nil {
+		return err
+	}
+	server := &http.Server{Addr: *addr, Handler: handler, ReadHeaderTimeout: 10 * time.Second}
+	say(messageServing, len(manifests), *addr)
+	return server.ListenAndServe()
+}
+
+func runVet(args []string) error {
+	flags := flag.NewFlagSet("vet", flag.ContinueOnError)
+	configPath := flags.String("config", "", text(messageFlagConfig))
+	if err := parseFlags(flags, args); err != nil {
+		return err
+	}
+
+	if *configPath == "" {
+		*configPath = envied.FindConfigFile()
+	}
+	if *configPath == "" {
+		return newError(envied.MessageConfigNotFound, envied.DefaultConfigFileName)
+	}
+
+	calls, err := envied.FindGetenvCalls(*configPath, flags.Args()...)
+	if err != nil {
+		return err
+	}
+	if len(calls) == 0 {
+		say(messageNoDirectLookups)
+		return nil
+	}
+
+	wd, _ := os.Getwd()
+	for _, call := range calls {
+		position := call.Position
+		if rel, err := filepath.Rel(wd, position.Filename); err == nil && !strings.HasPrefix(rel, "..") {
+			position.Filename = rel
+		}
+		say(messageDirectLookup, position, call.Function, call.Name, call.Getter)
+	}
+	return newError(messageDirectLookupsFound, len(calls))
+}
@@ -0,0 +1,336 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// catalogEnv names the environment variable holding the path of a JSON object mapping message
+// IDs to formats, which replace the messages of the command and of the library, e.g. to
+// translate them
+const catalogEnv = "ENVIED_CATALOG"
+
+// Messages of the envied command, with the arguments of their format
+const (
+	messageUnknownCommand       envied.MessageID = "unknown_command"           // command
+	messageInvalidFlags         envied.MessageID = "invalid_flags"             // error
+	messageUnexpectedArguments  envied.MessageID = "unexpected_arguments"      // arguments
+	messageExpectedNamePath     envied.MessageID = "expected_name_path"        // flag value
+	messageExpectedNameValue    envied.MessageID = "expected_name_value"       // flag value
+	messageInvalidCatalog       envied.MessageID = "invalid_catalog"           // catalog file, error
+	messageHelpUsage            envied.MessageID = "help_usage"                // no arguments
+	messageHelpCommands         envied.MessageID = "help_commands"             // no arguments
+	messageHelpFlags            envied.MessageID = "help_flags"                // no arguments
+	messageSummaryGenerate      envied.MessageID = "summary_generate"          // no arguments
+	messageSummaryValidate      envied.MessageID = "summary_validate"          // no arguments
+	messageSummaryCheck         envied.MessageID = "summary_check"             // no arguments
+	messageSummaryVerify        envied.MessageID = "summary_verify"            // no arguments
+	messageSummaryDiff          envied.MessageID = "summary_diff"              // no arguments
+	messageSummaryReleaseDiff   envied.MessageID = "summary_release_diff"      // no arguments
+	messageSummaryDoctor        envied.MessageID = "summary_doctor"            // no arguments
+	messageSummaryExplain       envied.MessageID = "summary_explain"           // no arguments
+	messageSummaryInit          envied.MessageID = "summary_init"              // no arguments
+	messageSummaryCloneEnv      envied.MessageID = "summary_clone_env"         // no arguments
+	messageSummaryFix           envied.MessageID = "summary_fix"               // no arguments
+	messageSummaryPrune         envied.MessageID = "summary_prune"             // no arguments
+	messageSummaryVet           envied.MessageID = "summary_vet"               // no arguments
+	messageSummaryDecrypt       envied.MessageID = "summary_decrypt"           // no arguments
+	messageSummaryEncryptEnv    envied.MessageID = "summary_encrypt_env"       // no arguments
+	messageSummaryDecryptEnv    envied.MessageID = "summary_decrypt_env"       // no arguments
+	messageSummaryServeManifest envied.MessageID = "summary_serve_manifest"    // no arguments
+	messageSummaryHelp          envied.MessageID = "summary_help"              // no arguments
+	messageFlagConfig           envied.MessageID = "flag_config"               // no arguments
+	messageFlagCloneConfig      envied.MessageID = "flag_clone_config"         // no arguments
+	messageFlagServeConfig      envied.MessageID = "flag_serve_config"         // no arguments
+	messageFlagEnvFilesConfig   envied.MessageID = "flag_env_files_config"     // no arguments
+	messageFlagOutput           envied.MessageID = "flag_output"               // no arguments
+	messageFlagOut              envied.MessageID = "flag_out"                  // no arguments
+	messageFlagEnv              envied.MessageID = "flag_env"                  // no arguments
+	messageFlagSet              envied.MessageID = "flag_set"                  // no arguments
+	messageFlagValuesFrom       envied.MessageID = "flag_values_from"          // no arguments
+	messageFlagOnConflict       envied.MessageID = "flag_on_conflict"          // no arguments
+	messageFlagForce            envied.MessageID = "flag_force"                // no arguments
+	messageFlagNoNetwork        envied.MessageID = "flag_no_network"           // no arguments
+	messageFlagVerbose          envied.MessageID = "flag_verbose"              // no arguments
+	messageFlagCPUProfile       envied.MessageID = "flag_cpuprofile"           // no arguments
+	messageFlagMemProfile       envied.MessageID = "flag_memprofile"           // no arguments
+	messageFlagTrace            envied.MessageID = "flag_trace"                // no arguments
+	messageFlagHermetic         envied.MessageID = "flag_hermetic"             // no arguments
+	messageFlagDeterministic    envied.MessageID = "flag_assert_deterministic" // no arguments
+	messageFlagTimings          envied.MessageID = "flag_timings"              // no arguments
+	messageFlagValidateTimings  envied.MessageID = "flag_validate_timings"     // no arguments
+	messageFlagTimeout          envied.MessageID = "flag_timeout"              // no arguments
+	messageFlagPrevious         envied.MessageID = "flag_previous"             // no arguments
+	messageFlagRef              envied.MessageID = "flag_ref"                  // no arguments
+	messageFlagManifestKeyEnv   envied.MessageID = "flag_manifest_key_env"     // no arguments
+	messageFlagManifestsKeyEnv  envied.MessageID = "flag_manifests_key_env"    // no arguments
+	messageFlagKeyEnv           envied.MessageID = "flag_key_env"              // no arguments
+	messageFlagFormat           envied.MessageID = "flag_format"               // no arguments
+	messageFlagAnalyze          envied.MessageID = "flag_analyze"              // no arguments
+	messageFlagWrite            envied.MessageID = "flag_write"                // no arguments
+	messageFlagAs               envied.MessageID = "flag_as"                   // no arguments
+	messageFlagEnvFile          envied.MessageID = "flag_env_file"             // no arguments
+	messageFlagCopyValues       envied.MessageID = "flag_copy_values"          // no arguments
+	messageFlagAddr             envied.MessageID = "flag_addr"                 // no arguments
+	messageFlagTokenEnv         envied.MessageID = "flag_token_env"            // no arguments
+	messageFlagManifest         envied.MessageID = "flag_manifest"             // no arguments
+	messageUsageCloneEnv        envied.MessageID = "usage_clone_env"           // no arguments
+	messageUsageServeManifest   envied.MessageID = "usage_serve_manifest"      // no arguments
+	messageUsageExplain         envied.MessageID = "usage_explain"             // no arguments
+	messageUsageDiff            envied.MessageID = "usage_diff"                // no arguments
+	messageUsageReleaseDiff     envied.MessageID = "usage_release_diff"        // no arguments
+	messageUsageInit            envied.MessageID = "usage_init"                // no arguments
+	messageUsageDecrypt         envied.MessageID = "usage_decrypt"             // no arguments
+	messageValuesFromHermetic   envied.MessageID = "values_from_hermetic"      // no arguments
+	messageFixed                envied.MessageID = "fixed"                     // file
+	messagePruneRequiresAnalyze envied.MessageID = "prune_requires_analyze"    // no arguments
+	messageAllVariablesUsed     envied.MessageID = "all_variables_used"        // no arguments
+	messageUnusedVariable       envied.MessageID = "unused_variable"           // variable, getter, environments
+	messageRemovedVariables     envied.MessageID = "removed_variables"         // variable count
+	messageAddedEnvironment     envied.MessageID = "added_environment"         // environment, configuration file
+	messageFillInValues         envied.MessageID = "fill_in_values"            // no arguments
+	messageTokenNotSet          envied.MessageID = "token_not_set"             // environment variable
+	messageNoManifestToServe    envied.MessageID = "no_manifest_to_serve"      // configuration file
+	messageServing              envied.MessageID = "serving"                   // manifest count, address
+	messageNoDirectLookups      envied.MessageID = "no_direct_lookups"         // no arguments
+	messageDirectLookup         envied.MessageID = "direct_lookup"             // position, function, variable, getter
+	messageDirectLookupsFound   envied.MessageID = "direct_lookups_found"      // lookup count
+	messageValid                envied.MessageID = "valid"                     // no arguments
+	messageUpToDate             envied.MessageID = "up_to_date"                // no arguments
+	messageEnvFilesMatch        envied.MessageID = "env_files_match"           // no arguments
+	messageDoctorSource         envied.MessageID = "doctor_source"             // environment, source
+	messageDoctorPassed         envied.MessageID = "doctor_passed"             // indentation, check, message
+	messageDoctorFailed         envied.MessageID = "doctor_failed"             // indentation, check, message
+	messageDoctorHint           envied.MessageID = "doctor_hint"               // indentation, hint
+	messageChecksFailed         envied.MessageID = "checks_failed"             // failed check count
+	messageAllChecksPassed      envied.MessageID = "all_checks_passed"         // no arguments
+	messageExplainVariable      envied.MessageID = "explain_variable"          // variable
+	messageExplainDescription   envied.MessageID = "explain_description"       // description
+	messageExplainTransforms    envied.MessageID = "explain_transforms"        // transforms
+	messageExplainOnly          envied.MessageID = "explain_only"              // environments
+	messageExplainDeclared      envied.MessageID = "explain_declared"          // no arguments
+	messageExplainDetected      envied.MessageID = "explain_detected"          // no arguments
+	messageExplainPlainText     envied.MessageID = "explain_plain_text"        // no arguments
+	messageExplainObfuscated    envied.MessageID = "explain_obfuscated"        // obfuscation
+	messageExplainEnvironment   envied.MessageID = "explain_environment"       // environment, source
+	messageExplainOverrides     envied.MessageID = "explain_overrides"         // overriding sources
+	messageExplainType          envied.MessageID = "explain_type"              // type, origin, sensitivity, value number
+	messageExplainIdentifiers   envied.MessageID = "explain_identifiers"       // identifiers
+	messageExplainMissing       envied.MessageID = "explain_missing"           // environment
+	messageExplainShared        envied.MessageID = "explain_shared"            // identifiers
+	messageExplainValuesDiffer  envied.MessageID = "explain_values_differ"     // no arguments
+	messageExplainSameValue     envied.MessageID = "explain_same_value"        // no arguments
+	messageDiffSame             envied.MessageID = "diff_same"                 // environment, other environment
+	messageDiffAdded            envied.MessageID = "diff_added"                // variable, type, environment
+	messageDiffRemoved          envied.MessageID = "diff_removed"              // variable, type, environment
+	messageDiffTypeChanged      envied.MessageID = "diff_type_changed"         // variable, type, other type
+	messageDiffValueChanged     envied.MessageID = "diff_value_changed"        // variable
+	messageInvalidManifest      envied.MessageID = "invalid_manifest"          // manifest file, error
+	messageNoManifestToCompare  envied.MessageID = "no_manifest_to_compare"    // configuration file
+	messageRunGenerate          envied.MessageID = "run_generate"              // no arguments
+	messageEncrypted            envied.MessageID = "encrypted"                 // env file
+	messageAlreadyEncrypted     envied.MessageID = "already_encrypted"         // env file
+	messageDecrypted            envied.MessageID = "decrypted"                 // env file
+	messageNotEncrypted         envied.MessageID = "not_encrypted"             // env file
+	messagePassphraseNotSet     envied.MessageID = "passphrase_not_set"        // passphrase environment variable
+	messageCreateCPUProfile     envied.MessageID = "create_cpu_profile"        // error
+	messageStartCPUProfile      envied.MessageID = "start_cpu_profile"         // error
+	messageCreateTrace          envied.MessageID = "create_trace"              // error
+	messageStartTrace           envied.MessageID = "start_trace"               // error
+	messageCreateMemoryProfile  envied.MessageID = "create_memory_profile"     // error
+	messageWriteMemoryProfile   envied.MessageID = "write_memory_profile"      // error
+)
+
+// commandMessages holds the English messages of the command
+var commandMessages = envied.Catalog{
+	messageUnknownCommand:       "unknown command %q, run 'envied help' for usage",
+	messageInvalidFlags:         "%v",
+	messageUnexpectedArguments:  "unexpected arguments: %s",
+	messageExpectedNamePath:     "expected name=path, got %q",
+	messageExpectedNameValue:    "expected name=value, got %q",
+	messageInvalidCatalog:       "❌ ERROR: invalid message catalog %s: %v",
+	messageHelpUsage:            "Usage: envied <command> [flags]",
+	messageHelpCommands:         "Commands:",
+	messageHelpFlags:            "Run 'envied <command> -h' for the flags of a command.",
+	messageSummaryGenerate:      "generate config_env.gen.go (default)",
+	messageSummaryValidate:      "run all generation checks without writing anything",
+	messageSummaryCheck:         "fail if the generated file is out of date",
+	messageSummaryVerify:        "fail if an env file changed since generation, without regenerating",
+	messageSummaryDiff:          "compare the variables of two environments: diff <from> <to>",
+	messageSummaryReleaseDiff:   "report variables changed since a release: release-diff -ref <revision>",
+	messageSummaryDoctor:        "check the project setup and remote sources before generating",
+	messageSummaryExplain:       "show where a variable is defined, its type and generated identifiers: explain <VAR>",
+	messageSummaryInit:          "write a starter configuration with dev and prod environments",
+	messageSummaryCloneEnv:      "add an environment like an existing one: clone-env <from> -as <to>",
+	messageSummaryFix:           "rewrite files generated by older releases: fix [path ...]",
+	messageSummaryPrune:         "report or remove unused variables: prune -analyze [-write] [packages]",
+	messageSummaryVet:           "report os.Getenv calls reading managed variables: vet [packages]",
+	messageSummaryDecrypt:       "print an encrypted emitted file: decrypt -key-env NAME <file>",
+	messageSummaryEncryptEnv:    "encrypt env files in place with ENVIED_PASSPHRASE: encrypt-env [file ...]",
+	messageSummaryDecryptEnv:    "decrypt env files encrypted by encrypt-env in place: decrypt-env [file ...]",
+	messageSummaryServeManifest: "serve manifests for audits: serve-manifest -token-env NAME",
+	messageSummaryHelp:          "show this help",
+	messageFlagConfig:           "path to go-envied-config.json, .yaml, .yml or .toml (searched in current and parent directories if empty)",
+	messageFlagCloneConfig:      "path to go-envied-config.json (searched in current and parent directories if empty)",
+	messageFlagServeConfig:      "path to go-envied-config.json, .yaml, .yml or .toml, serving its emit.manifest if no -manifest is given",
+	messageFlagEnvFilesConfig:   "path to go-envied-config.json, .yaml, .yml or .toml, whose env files are used if no files are given",
+	messageFlagOutput:           "path of the generated file (config_env.gen.go in output_dir if empty)",
+	messageFlagOut:              "alias of -output",
+	messageFlagEnv:              "env file override as name=path, can be repeated",
+	messageFlagSet:              "substitution of {{.name}} placeholders in env values as name=value, can be repeated",
+	messageFlagValuesFrom:       "read the values of environments declaring a vault secret from vault, which are skipped otherwise",
+	messageFlagOnConflict:       "strategy for declarations duplicated by other files of the output package: error, rename or force (on_conflict if empty)",
+	messageFlagForce:            "alias of -on-conflict force",
+	messageFlagNoNetwork:        "panic on any network access and reject remote sources",
+	messageFlagVerbose:          "print the source every value comes from",
+	messageFlagCPUProfile:       "write a CPU profile of the command to this file",
+	messageFlagMemProfile:       "write a heap profile of the command to this file",
+	messageFlagTrace:            "write an execution trace of the command to this file",
+	messageFlagHermetic:         "hermetic mode for build systems: requires -config and -output, prints only errors",
+	messageFlagDeterministic:    "fail if two in-memory generations produce different output",
+	messageFlagTimings:          "print how long every generation phase took",
+	messageFlagValidateTimings:  "print how long every validation phase took",
+	messageFlagTimeout:          "time limit of reading each remote source",
+	messageFlagPrevious:         "manifest of the previous release (emit.manifest of the configuration with -ref if empty)",
+	messageFlagRef:              "git revision of the previous release, reading the manifest as committed there",
+	messageFlagManifestKeyEnv:   "environment variable holding the base64 AES-256 key of an encrypted manifest",
+	messageFlagManifestsKeyEnv:  "environment variable holding the base64 AES-256 key of encrypted manifests",
+	messageFlagKeyEnv:           "environment variable holding the base64 AES-256 key",
+	messageFlagFormat:           "report format: markdown or json",
+	messageFlagAnalyze:          "find variables never referenced in the given packages",
+	messageFlagWrite:            "remove unused variables from the env files and configuration",
+	messageFlagAs:               "name of the new environment",
+	messageFlagEnvFile:          "env file of the new environment (the source env file with the name replaced if empty)",
+	messageFlagCopyValues:       "copy the values of the env file instead of leaving them empty",
+	messageFlagAddr:             "address to listen on",
+	messageFlagTokenEnv:         "environment variable holding the bearer token of requests",
+	messageFlagManifest:         "manifest to serve as service=path, can be repeated",
+	messageUsageCloneEnv:        "usage: envied clone-env [flags] <from> -as <to>",
+	messageUsageServeManifest:   "usage: envied serve-manifest -token-env NAME [-manifest service=path ...]",
+	messageUsageExplain:         "usage: envied explain [flags] <VAR>",
+	messageUsageDiff:            "usage: envied diff [flags] <from> <to>",
+	messageUsageReleaseDiff:     "usage: envied release-diff [flags] -previous <manifest> | -ref <revision>",
+	messageUsageInit:            "usage: envied init [dir]",
+	messageUsageDecrypt:         "usage: envied decrypt -key-env NAME <file>",
+	messageValuesFromHermetic:   "❌ ERROR: -values-from reads the network and can't be used in hermetic mode",
+	messageFixed:                "🔧 Fixed %s",
+	messagePruneRequiresAnalyze: "❌ ERROR: prune requires -analyze",
+	messageAllVariablesUsed:     "✅ All variables are used",
+	messageUnusedVariable:       "⚠️ %s is unused (%s never called), embedded in: %s",
+	messageRemovedVariables:     "✂️ Removed %d unused variables, regenerate the configuration",
+	messageAddedEnvironment:     "🌱 Added environment '%s' to %s",
+	messageFillInValues:         "✏️ Fill in the values, then run 'envied generate'",
+	messageTokenNotSet:          "❌ ERROR: environment variable %s holding the token is not set",
+	messageNoManifestToServe:    "❌ ERROR: %s has no emit.manifest, pass -manifest service=path",
+	messageServing:              "🛰️ Serving %d manifests on http://%s/manifests",
+	messageNoDirectLookups:      "✅ No direct lookups of managed variables",
+	messageDirectLookup:         "%s: %s(%q) bypasses the generated configuration, use %s()",
+	messageDirectLookupsFound:   "❌ ERROR: %d direct lookups of managed variables",
+	messageValid:                "✅ Configuration is valid",
+	messageUpToDate:             "✅ Generated configuration is up to date",
+	messageEnvFilesMatch:        "✅ Env files match the generated configuration",
+	messageDoctorSource:         "🩺 %s: %s",
+	messageDoctorPassed:         "%s✅ %s: %s",
+	messageDoctorFailed:         "%s❌ %s: %s",
+	messageDoctorHint:           "%s   💡 %s",
+	messageChecksFailed:         "❌ ERROR: %d checks failed, fix them before generating",
+	messageAllChecksPassed:      "🎉 All checks passed",
+	messageExplainVariable:      "🔎 %s",
+	messageExplainDescription:   "   %s",
+	messageExplainTransforms:    "   transforms: %s",
+	messageExplainOnly:          "   only in: %s",
+	messageExplainDeclared:      "declared",
+	messageExplainDetected:      "detected",
+	messageExplainPlainText:     "plain text",
+	messageExplainObfuscated:    "obfuscated (%s)",
+	messageExplainEnvironment:   "📄 %s: %s",
+	messageExplainOverrides:     "   overrides: %s",
+	messageExplainType:          "   type: %s (%s), %s, value #%d",
+	messageExplainIdentifiers:   "   identifiers: %s",
+	messageExplainMissing:       "➖ %s: not defined",
+	messageExplainShared:        "🔗 shared identifiers: %s",
+	messageExplainValuesDiffer:  "⚠️  values differ between environments, see the value numbers above",
+	messageExplainSameValue:     "✅ same value in all defining environments",
+	messageDiffSame:             "✅ Environments '%s' and '%s' have the same variables and values",
+	messageDiffAdded:            "+ %s (%s) only in %s",
+	messageDiffRemoved:          "- %s (%s) only in %s",
+	messageDiffTypeChanged:      "~ %s type %s -> %s",
+	messageDiffValueChanged:     "~ %s value differs",
+	messageInvalidManifest:      "❌ ERROR: %s: %v",
+	messageNoManifestToCompare:  "❌ ERROR: %s has no emit.manifest, pass -previous with the manifest path",
+	messageRunGenerate:          "🚀 Run 'envied generate' to generate the configuration",
+	messageEncrypted:            "🔒 Encrypted %s",
+	messageAlreadyEncrypted:     "✅ %s is already encrypted",
+	messageDecrypted:            "🔓 Decrypted %s",
+	messageNotEncrypted:         "✅ %s is not encrypted",
+	messagePassphraseNotSet:     "❌ ERROR: set %s to the passphrase",
+	messageCreateCPUProfile:     "❌ ERROR: failed to create CPU profile: %v",
+	messageStartCPUProfile:      "❌ ERROR: failed to start CPU profile: %v",
+	messageCreateTrace:          "❌ ERROR: failed to create trace: %v",
+	messageStartTrace:           "❌ ERROR: failed to start trace: %v",
+	messageCreateMemoryProfile:  "❌ ERROR: failed to create memory profile: %v",
+	messageWriteMemoryProfile:   "❌ ERROR: failed to write memory profile: %v",
+}
+
+// catalog formats the messages of the command and is passed to the library, with the formats
+// of ENVIED_CATALOG over commandMessages
+var catalog = commandMessages
+
+// loadCatalog reads the message formats of ENVIED_CATALOG
+func loadCatalog() error {
+	path := os.Getenv(catalogEnv)
+	if path == "" {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return newError(messageInvalidCatalog, path, err)
+	}
+	var formats envied.Catalog
+	if err := json.Unmarshal(data, &formats); err != nil {
+		return newError(messageInvalidCatalog, path, err)
+	}
+	catalog = maps.Clone(commandMessages)
+	maps.Copy(catalog, formats)
+	return nil
+}
+
+// text formats a message with the catalog
+func text(id envied.MessageID, args ...any) string {
+	return catalog.Format(id, args...)
+}
+
+// say prints a message of the catalog
+func say(id envied.MessageID, args ...any) {
+	fmt.Println(text(id, args...))
+}
+
+// commandError is an error of the command formatted with the catalog
+type commandError struct {
+	id   envied.MessageID
+	args []any
+}
+
+// newError returns an error of the command with a message ID
+func newError(id envied.MessageID, args ...any) error {
+	return &commandError{id: id, args: args}
+}
+
+func (e *commandError) Error() string {
+	return text(e.id, e.args...)
+}
+
+// Unwrap returns the errors among the arguments
+func (e *commandError) Unwrap() []error {
+	var wrapped []error
+	for _, arg := range e.args {
+		if err, ok := arg.(error); ok {
+			wrapped = append(wrapped, err)
+		}
+	}
+	return wrapped
+}
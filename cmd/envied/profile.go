@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"runtime"
+	"runtime/pprof"
+	"runtime/trace"
+)
+
+// profileFlags are the flags writing profiles of a command, so performance problems with
+// large configurations can be reported with actionable data
+type profileFlags struct {
+	cpuProfile string
+	memProfile string
+	traceFile  string
+}
+
+// profile runs fn writing the requested CPU profile, execution trace and heap profile
+func (p *profileFlags) profile(fn func() error) error {
+	if p.cpuProfile != "" {
+		file, err := os.Create(p.cpuProfile)
+		if err != nil {
+			return newError(messageCreateCPUProfile, err)
+		}
+		defer file.Close()
+		if err := pprof.StartCPUProfile(file); err != nil {
+			return newError(messageStartCPUProfile, err)
+		}
+		defer pprof.StopCPUProfile()
+	}
+
+	if p.traceFile != "" {
+		file, err := os.Create(p.traceFile)
+		if err != nil {
+			return newError(messageCreateTrace, err)
+		}
+		defer file.Close()
+		if err := trace.Start(file); err != nil {
+			return newError(messageStartTrace, err)
+		}
+		defer trace.Stop()
+	}
+
+	if err := fn(); err != nil {
+		return err
+	}
+
+	if p.memProfile != "" {
+		file, err := os.Create(p.memProfile)
+		if err != nil {
+			return newError(messageCreateMemoryProfile, err)
+		}
+		defer file.Close()
+		// Up to date allocation statistics
+		runtime.GC()
+		if err := pprof.WriteHeapProfile(file); err != nil {
+			return newError(messageWriteMemoryProfile, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package envied
+
+import "sort"
+
+// isConditional reports whether the variable is restricted to specific environments
+func (v VariableConfig) isConditional() bool {
+	return len(v.Only) > 0
+}
+
+// includedIn reports whether the variable is generated for the environment
+func (v VariableConfig) includedIn(envName string) bool {
+	if !v.isConditional() {
+		return true
+	}
+	for _, name := range v.Only {
+		if name == envName {
+			return true
+		}
+	}
+	return false
+}
+
+// applyConditionalVariables removes conditional variables from environments they are not
+// enabled for and checks that they are defined in every environment they are enabled for
+func applyConditionalVariables(configFile *ConfigFile, allEnvVars map[string]map[string]EnvValue) error {
+	varNames := make([]string, 0, len(configFile.Variables))
+	for varName := range configFile.Variables {
+		varNames = append(varNames, varName)
+	}
+	sort.Strings(varNames)
+
+	for _, varName := range varNames {
+		variable := configFile.Variables[varName]
+		if !variable.isConditional() {
+			continue
+		}
+
+		for _, envName := range variable.Only {
+			envVars, exists := allEnvVars[envName]
+			if !exists {
+				return newError(MessageEnabledForUnknownEnvironment, varName, envName)
+			}
+			if _, exists := envVars[varName]; !exists {
+				return newError(MessageVariableMissing, varName, envName)
+			}
+		}
+
+		for envName, envVars := range allEnvVars {
+			if !variable.includedIn(envName) {
+				delete(envVars, varName)
+			}
+		}
+	}
+
+	return nil
+}
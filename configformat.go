@@ -0,0 +1,502 @@
+package envied
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// configFileNames are the configuration file names searched by FindConfigFile, in order
+var configFileNames = []string{
+	DefaultConfigFileName,
+	"go-envied-config.jsonc",
+	"go-envied-config.yaml",
+	"go-envied-config.yml",
+	"go-envied-config.toml",
+}
+
+// yamlNumberPattern matches plain YAML scalars decoded as numbers in configuration files
+var yamlNumberPattern = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][+-]?\d+)?$`)
+
+// isJSONConfigFile reports whether a configuration file is JSON, the format Save writes
+func isJSONConfigFile(configFilePath string) bool {
+	return !isYAMLFile(configFilePath) && !isTOMLFile(configFilePath)
+}
+
+// configJSON converts the contents of a YAML or TOML configuration file to JSON, selected by
+// extension, so all formats are decoded with the JSON field names. Other files are read as JSON
+// with comments and trailing commas.
+func configJSON(configFilePath string, data []byte) ([]byte, error) {
+	var document map[string]any
+	var err error
+	switch {
+	case isYAMLFile(configFilePath):
+		document, err = parseYAMLDocument(string(data))
+	case isTOMLFile(configFilePath):
+		document, err = parseTOMLDocument(string(data))
+	default:
+		data, err = stripJSONC(data)
+		if err != nil {
+			return nil, newError(MessageAtPath, filepath.Base(configFilePath), err)
+		}
+		return data, nil
+	}
+	if err != nil {
+		return nil, newError(MessageAtPath, filepath.Base(configFilePath), err)
+	}
+	return json.Marshal(document)
+}
+
+// yamlLine is a non-empty line of a YAML document without its comment
+type yamlLine struct {
+	number  int
+	indent  int
+	content string
+}
+
+// stripComment removes a trailing comment starting with '#' outside of quotes
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return strings.TrimRight(line[:i], " \t")
+		}
+	}
+	return line
+}
+
+// parseYAMLDocument parses a YAML mapping of mappings, block sequences of scalars and flow
+// collections. Plain scalars are typed: booleans, numbers and null; everything else is a string.
+func parseYAMLDocument(content string) (map[string]any, error) {
+	var lines []yamlLine
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(stripComment(line), " \r")
+		trimmed := strings.TrimLeft(line, " ")
+		if trimmed == "" || (trimmed == "---" && len(lines) == 0) {
+			continue
+		}
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, newError(MessageTabIndentationAtLine, i+1)
+		}
+		lines = append(lines, yamlLine{number: i + 1, indent: len(line) - len(trimmed), content: trimmed})
+	}
+	if len(lines) == 0 {
+		return map[string]any{}, nil
+	}
+
+	value, next, err := parseYAMLBlock(lines, 0, lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if next < len(lines) {
+		return nil, newError(MessageUnexpectedIndentationAtLine, lines[next].number)
+	}
+	document, ok := value.(map[string]any)
+	if !ok {
+		return nil, newError(MessageExpectedMapping, lines[0].number)
+	}
+	return document, nil
+}
+
+// isYAMLSequenceItem reports whether a line is an item of a block sequence
+func isYAMLSequenceItem(content string) bool {
+	return content == "-" || strings.HasPrefix(content, "- ")
+}
+
+// parseYAMLBlock parses the mapping or sequence starting at lines[i] with the given
+// indentation and returns it with the index of the first line after it
+func parseYAMLBlock(lines []yamlLine, i, indent int) (any, int, error) {
+	if isYAMLSequenceItem(lines[i].content) {
+		var items []any
+		for ; i < len(lines) && lines[i].indent == indent && isYAMLSequenceItem(lines[i].content); i++ {
+			item := strings.TrimSpace(strings.TrimPrefix(lines[i].content, "-"))
+			if item == "" {
+				return nil, 0, newError(MessageNestedSequencesNotSupported, lines[i].number)
+			}
+			if _, _, err := splitYAMLKey(item); err == nil && item[0] != '"' && item[0] != '\'' && item[0] != '[' && item[0] != '{' {
+				return nil, 0, newError(MessageSequencesOfMappingsNotSupported, lines[i].number)
+			}
+			value, err := parseYAMLValue(item)
+			if err != nil {
+				return nil, 0, newError(MessageAtLine, lines[i].number, err)
+			}
+			items = append(items, value)
+		}
+		return items, i, nil
+	}
+
+	mapping := make(map[string]any)
+	for i < len(lines) && lines[i].indent == indent {
+		line := lines[i]
+		if isYAMLSequenceItem(line.content) {
+			return nil, 0, newError(MessageUnexpectedSequenceItem, line.number)
+		}
+		key, rawValue, err := splitYAMLKey(line.content)
+		if err != nil {
+			return nil, 0, newError(MessageAtLine, line.number, err)
+		}
+		if _, exists := mapping[key]; exists {
+			return nil, 0, newError(MessageKeyAlreadyDefinedAtLine, line.number, key)
+		}
+		i++
+
+		if rawValue != "" {
+			if mapping[key], err = parseYAMLValue(rawValue); err != nil {
+				return nil, 0, newError(MessageKeyErrorAtLine, line.number, key, err)
+			}
+			continue
+		}
+
+		// A key without a value opens a nested block, sequences may keep the key's indentation
+		if i < len(lines) && (lines[i].indent > indent || (lines[i].indent == indent && isYAMLSequenceItem(lines[i].content))) {
+			if mapping[key], i, err = parseYAMLBlock(lines, i, lines[i].indent); err != nil {
+				return nil, 0, err
+			}
+		} else {
+			mapping[key] = nil
+		}
+	}
+	if i < len(lines) && lines[i].indent > indent {
+		return nil, 0, newError(MessageUnexpectedIndentationAtLine, lines[i].number)
+	}
+	return mapping, i, nil
+}
+
+// parseYAMLValue parses a scalar or a flow collection filling the rest of a line
+func parseYAMLValue(raw string) (any, error) {
+	if raw[0] == '[' || raw[0] == '{' {
+		value, rest, err := parseYAMLFlow(raw)
+		if err != nil {
+			return nil, err
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, newError(MessageUnexpectedAfterFlowCollection, rest)
+		}
+		return value, nil
+	}
+	value, quoted, err := parseYAMLScalar(raw)
+	if err != nil {
+		return nil, err
+	}
+	return typedYAMLScalar(value, quoted), nil
+}
+
+// typedYAMLScalar types a plain scalar as boolean, number or null, quoted scalars are strings
+func typedYAMLScalar(value string, quoted bool) any {
+	if quoted {
+		return value
+	}
+	switch {
+	case value == "":
+		return nil
+	case value == "true" || value == "True" || value == "TRUE":
+		return true
+	case value == "false" || value == "False" || value == "FALSE":
+		return false
+	case yamlNumberPattern.MatchString(value):
+		return json.Number(value)
+	}
+	return value
+}
+
+// parseYAMLFlow parses the flow sequence or mapping at the start of s and returns it with the rest of s
+func parseYAMLFlow(s string) (any, string, error) {
+	open, closing := s[0], byte(']')
+	if open == '{' {
+		closing = '}'
+	}
+	s = strings.TrimLeft(s[1:], " ")
+
+	var items []any
+	mapping := make(map[string]any)
+	for {
+		if s == "" {
+			return nil, "", newError(MessageUnterminatedFlowCollection)
+		}
+		if s[0] == closing {
+			break
+		}
+
+		var key string
+		if open == '{' {
+			raw, rest, err := cutYAMLFlowScalar(s, ":")
+			if err != nil {
+				return nil, "", err
+			}
+			if key, _, err = parseYAMLScalar(raw); err != nil {
+				return nil, "", err
+			}
+			rest, found := strings.CutPrefix(rest, ":")
+			if !found {
+				return nil, "", newError(MessageExpectedColonAfterKey, key)
+			}
+			s = strings.TrimLeft(rest, " ")
+		}
+
+		var value any
+		if s != "" && (s[0] == '[' || s[0] == '{') {
+			var err error
+			if value, s, err = parseYAMLFlow(s); err != nil {
+				return nil, "", err
+			}
+		} else {
+			raw, rest, err := cutYAMLFlowScalar(s, ",]}")
+			if err != nil {
+				return nil, "", err
+			}
+			scalar, quoted, err := parseYAMLScalar(raw)
+			if err != nil {
+				return nil, "", err
+			}
+			value, s = typedYAMLScalar(scalar, quoted), rest
+		}
+		if open == '{' {
+			mapping[key] = value
+		} else {
+			items = append(items, value)
+		}
+
+		s = strings.TrimLeft(s, " ")
+		if rest, found := strings.CutPrefix(s, ","); found {
+			s = strings.TrimLeft(rest, " ")
+		}
+	}
+
+	rest := s[1:]
+	if open == '{' {
+		return mapping, rest, nil
+	}
+	if items == nil {
+		items = []any{}
+	}
+	return items, rest, nil
+}
+
+// cutYAMLFlowScalar returns the raw scalar at the start of s ending before any of the delimiters
+func cutYAMLFlowScalar(s, delimiters string) (string, string, error) {
+	if s != "" && (s[0] == '"' || s[0] == '\'') {
+		_, rest, err := cutYAMLQuoted(s)
+		if err != nil {
+			return "", "", err
+		}
+		return s[:len(s)-len(rest)], strings.TrimLeft(rest, " "), nil
+	}
+	end := strings.IndexAny(s, delimiters)
+	if end == -1 {
+		return "", "", newError(MessageUnterminatedFlowCollection)
+	}
+	raw := strings.TrimSpace(s[:end])
+	if raw == "" {
+		return "", "", newError(MessageEmptyFlowCollectionEntry)
+	}
+	return raw, s[end:], nil
+}
+
+// parseTOMLDocument parses a TOML document of tables, dotted keys, arrays and inline tables
+func parseTOMLDocument(content string) (map[string]any, error) {
+	document := make(map[string]any)
+	table := document
+	lines := strings.Split(content, "\n")
+	for i := 0; i < len(lines); i++ {
+		lineNumber := i + 1
+		line := strings.TrimSpace(stripComment(lines[i]))
+		switch {
+		case line == "":
+			continue
+		case strings.HasPrefix(line, "[["):
+			return nil, newError(MessageArraysOfTablesAtLine, lineNumber)
+		case strings.HasPrefix(line, "["):
+			keys, rest, err := parseTOMLKey(line[1:])
+			if err != nil {
+				return nil, newError(MessageAtLine, lineNumber, err)
+			}
+			if strings.TrimSpace(rest) != "]" {
+				return nil, newError(MessageUnterminatedTableNameAtLine, lineNumber)
+			}
+			if table, err = tomlTable(document, keys); err != nil {
+				return nil, newError(MessageAtLine, lineNumber, err)
+			}
+			continue
+		}
+
+		keys, rest, err := parseTOMLKey(line)
+		if err != nil {
+			return nil, newError(MessageAtLine, lineNumber, err)
+		}
+		rawValue, found := strings.CutPrefix(rest, "=")
+		if !found {
+			return nil, newError(MessageExpectedTOMLKeyValueAtLine, lineNumber)
+		}
+		rawValue = strings.TrimSpace(rawValue)
+		// Arrays, also inside inline tables, may span lines until their brackets are balanced
+		for (strings.HasPrefix(rawValue, "[") || strings.HasPrefix(rawValue, "{")) && !tomlBalanced(rawValue) && i+1 < len(lines) {
+			i++
+			rawValue += " " + strings.TrimSpace(stripComment(lines[i]))
+		}
+
+		value, rest, err := parseTOMLAny(rawValue)
+		if err != nil {
+			return nil, newError(MessageKeyErrorAtLine, lineNumber, strings.Join(keys, "."), err)
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, newError(MessageUnexpectedAfterValue, lineNumber, strings.Join(keys, "."), rest)
+		}
+		parent, err := tomlTable(table, keys[:len(keys)-1])
+		if err != nil {
+			return nil, newError(MessageAtLine, lineNumber, err)
+		}
+		key := keys[len(keys)-1]
+		if _, exists := parent[key]; exists {
+			return nil, newError(MessageKeyAlreadyDefinedAtLine, lineNumber, strings.Join(keys, "."))
+		}
+		parent[key] = value
+	}
+	return document, nil
+}
+
+// tomlTable returns the table at the keys below table, creating missing tables
+func tomlTable(table map[string]any, keys []string) (map[string]any, error) {
+	for _, key := range keys {
+		child, exists := table[key]
+		if !exists {
+			child = make(map[string]any)
+			table[key] = child
+		}
+		nested, ok := child.(map[string]any)
+		if !ok {
+			return nil, newError(MessageKeyNotTable, key)
+		}
+		table = nested
+	}
+	return table, nil
+}
+
+// tomlBalanced reports whether the brackets of a value outside of strings are balanced
+func tomlBalanced(value string) bool {
+	depth := 0
+	var quote byte
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '[' || c == '{':
+			depth++
+		case c == ']' || c == '}':
+			depth--
+		}
+	}
+	return depth <= 0
+}
+
+// parseTOMLAny parses the value at the start of s and returns it with the rest of s
+func parseTOMLAny(s string) (any, string, error) {
+	s = strings.TrimLeft(s, " \t")
+	if s == "" {
+		return nil, "", newError(MessageMissingValue)
+	}
+	switch s[0] {
+	case '"', '\'':
+		if strings.HasPrefix(s, `"""`) || strings.HasPrefix(s, "'''") {
+			return nil, "", newError(MessageMultiLineStringsNotSupported)
+		}
+		if s[0] == '\'' {
+			end := strings.IndexByte(s[1:], '\'')
+			if end == -1 {
+				return nil, "", newError(MessageUnterminatedLiteralString)
+			}
+			return s[1 : end+1], s[end+2:], nil
+		}
+		value, rest, err := cutYAMLQuoted(s)
+		return value, rest, err
+	case '[':
+		items := []any{}
+		s = s[1:]
+		for {
+			s = strings.TrimLeft(s, " \t")
+			if rest, found := strings.CutPrefix(s, "]"); found {
+				return items, rest, nil
+			}
+			item, rest, err := parseTOMLAny(s)
+			if err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+			s = strings.TrimLeft(rest, " \t")
+			if rest, found := strings.CutPrefix(s, ","); found {
+				s = rest
+			} else if !strings.HasPrefix(s, "]") {
+				return nil, "", newError(MessageExpectedArraySeparator)
+			}
+		}
+	case '{':
+		table := make(map[string]any)
+		s = strings.TrimLeft(s[1:], " \t")
+		if rest, found := strings.CutPrefix(s, "}"); found {
+			return table, rest, nil
+		}
+		for {
+			keys, rest, err := parseTOMLKey(s)
+			if err != nil {
+				return nil, "", err
+			}
+			rest, found := strings.CutPrefix(rest, "=")
+			if !found {
+				return nil, "", newError(MessageExpectedInlineTableEquals)
+			}
+			value, rest, err := parseTOMLAny(rest)
+			if err != nil {
+				return nil, "", err
+			}
+			parent, err := tomlTable(table, keys[:len(keys)-1])
+			if err != nil {
+				return nil, "", err
+			}
+			parent[keys[len(keys)-1]] = value
+
+			s = strings.TrimLeft(rest, " \t")
+			if rest, found := strings.CutPrefix(s, "}"); found {
+				return table, rest, nil
+			}
+			rest, found = strings.CutPrefix(s, ",")
+			if !found {
+				return nil, "", newError(MessageExpectedInlineTableSeparator)
+			}
+			s = rest
+		}
+	}
+
+	end := strings.IndexAny(s, ",]} \t")
+	if end == -1 {
+		end = len(s)
+	}
+	raw, rest := s[:end], s[end:]
+	switch {
+	case raw == "true":
+		return true, rest, nil
+	case raw == "false":
+		return false, rest, nil
+	case tomlNumberPattern.MatchString(raw):
+		number := strings.ReplaceAll(strings.TrimPrefix(raw, "+"), "_", "")
+		if !json.Valid([]byte(number)) {
+			return nil, "", newError(MessageInvalidNumber, raw)
+		}
+		return json.Number(number), rest, nil
+	}
+	return nil, "", newError(MessageInvalidUnquotedValue, raw)
+}
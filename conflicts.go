@@ -0,0 +1,310 @@
+package envied
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"maps"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// Conflict strategies for generating into a package with other files
+const (
+	ConflictError  = "error"  // Refuse to generate when declarations would be duplicated (default)
+	ConflictRename = "rename" // Prefix the conflicting environment and profile types with ConflictRenamePrefix
+	ConflictForce  = "force"  // Generate anyway, the package won't compile until the conflict is fixed
+)
+
+// ConflictRenamePrefix is prepended to the struct names of environments and profiles whose
+// declarations conflict with other package files under ConflictRename
+const ConflictRenamePrefix = "Envied"
+
+// generatedSymbol is a top-level declaration of the merged configuration file and what it is
+// generated for
+type generatedSymbol struct {
+	name   string
+	origin string
+}
+
+// generatedDeclarations returns the top-level declarations of the merged configuration file in
+// the order they are generated. Methods are returned as "Type.Method".
+func generatedDeclarations(data *mergedConfig) []generatedSymbol {
+	var symbols []generatedSymbol
+	for _, name := range []string{"ConfigInterface", "ErrUnknownEnvironment", "Environments", "NewEnvironmentConfig", "Override", "enviedOverrides", "NewLayeredConfig"} {
+		symbols = append(symbols, generatedSymbol{name, "go-envied"})
+	}
+	for _, field := range overridableFields(data) {
+		symbols = append(symbols, generatedSymbol{"With" + field.FieldName, "variable " + field.EnvName})
+	}
+	if renamedFields(data) != nil {
+		symbols = append(symbols, generatedSymbol{"FieldEnvNames", "go-envied"})
+	}
+	// Declared by the file of every environment, one of which is compiled
+	if data.BuildTags {
+		symbols = append(symbols, generatedSymbol{"NewConfig", "build_tags"})
+	}
+	if data.Split {
+		symbols = append(symbols, generatedSymbol{"enviedEnvironment", "split_environments"}, generatedSymbol{"enviedGeneratedAt", "split_environments"})
+	}
+	if data.Ldflags {
+		symbols = append(symbols, generatedSymbol{"CheckLinkedValues", "ldflags"})
+		if data.Split {
+			symbols = append(symbols, generatedSymbol{"enviedLinked", "ldflags"})
+		}
+	}
+	for _, envData := range data.Environments {
+		if len(envData.Obfuscated) > 0 {
+			symbols = append(symbols, generatedSymbol{"enviedObfuscationVersion", "go-envied"})
+			break
+		}
+	}
+
+	for _, envName := range sortedEnvironmentNames(data.Environments) {
+		envData := data.Environments[envName]
+		origin := fmt.Sprintf("environment '%s'", envName)
+		envPrefix := strings.ToLower(envName)
+
+		symbols = append(symbols, typeDeclarations(envData.StructName+"Config", envData.Fields, origin)...)
+		if len(envData.Extras) > 0 {
+			symbols = append(symbols, generatedSymbol{envData.StructName + "Interface", origin})
+		}
+		for _, name := range slices.Sorted(maps.Keys(envData.Obfuscated)) {
+			if obfuscated := envData.Obfuscated[name]; obfuscated != nil {
+				variable := fmt.Sprintf("variable %s of %s", name, origin)
+				symbols = append(symbols, generatedSymbol{envPrefix + obfuscated.KeyName, variable}, generatedSymbol{envPrefix + obfuscated.ValueName, variable})
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(envData.Linked)) {
+			symbols = append(symbols, generatedSymbol{envData.Linked[name], fmt.Sprintf("variable %s of %s", name, origin)})
+		}
+
+		for _, profile := range envData.Profiles {
+			profileOrigin := fmt.Sprintf("profile %s of %s", profile.StructName, origin)
+			symbols = append(symbols, typeDeclarations(profile.StructName+"Config", profile.Fields, profileOrigin)...)
+		}
+	}
+	return symbols
+}
+
+// typeDeclarations returns the declarations of a generated configuration type
+func typeDeclarations(structName string, fields []Field, origin string) []generatedSymbol {
+	symbols := []generatedSymbol{{structName, origin}, {"New" + structName, origin}}
+	for _, field := range fields {
+		symbols = append(symbols, generatedSymbol{structName + "." + field.Getter, origin})
+	}
+	for _, method := range []string{"Lookup", "Environment", "GeneratedAt", "SourceHash"} {
+		symbols = append(symbols, generatedSymbol{structName + "." + method, origin})
+	}
+	return symbols
+}
+
+// generatedSymbols returns the sorted top-level declarations of the merged configuration file.
+// Methods are returned as "Type.Method".
+func generatedSymbols(data *mergedConfig) []string {
+	declarations := generatedDeclarations(data)
+	symbols := make([]string, len(declarations))
+	for i, symbol := range declarations {
+		symbols[i] = symbol.name
+	}
+	sort.Strings(symbols)
+	return symbols
+}
+
+// checkGeneratedCollisions fails when the merged configuration file would declare a symbol twice,
+// such as struct names clashing with each other or with ConfigInterface, or variables of
+// different environments sharing a field name, before any file is written
+func checkGeneratedCollisions(data *mergedConfig) error {
+	fieldOwners := make(map[string]string)
+	for _, envName := range sortedEnvironmentNames(data.Environments) {
+		for _, field := range data.Environments[envName].Fields {
+			if owner, exists := fieldOwners[field.FieldName]; exists && owner != field.EnvName {
+				return newError(MessageDuplicateFieldName, owner, field.EnvName, field.FieldName)
+			}
+			fieldOwners[field.FieldName] = field.EnvName
+		}
+	}
+
+	owners := make(map[string]string)
+	for _, symbol := range generatedDeclarations(data) {
+		if owner, exists := owners[symbol.name]; exists {
+			return newError(MessageDuplicateDeclaration, owner, symbol.origin, symbol.name)
+		}
+		owners[symbol.name] = symbol.origin
+	}
+	return nil
+}
+
+// receiverName returns the type name of a method receiver
+func receiverName(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return receiverName(t.X)
+	case *ast.Ident:
+		return t.Name
+	case *ast.IndexExpr:
+		return receiverName(t.X)
+	case *ast.IndexListExpr:
+		return receiverName(t.X)
+	}
+	return ""
+}
+
+// declaredSymbols parses a Go file and returns its top-level declarations,
+// methods are returned as "Type.Method". External test packages declare nothing
+// in the package and are skipped.
+func declaredSymbols(filename string) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), filename, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+	if strings.HasSuffix(file.Name.Name, "_test") {
+		return nil, nil
+	}
+
+	var symbols []string
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil && len(d.Recv.List) > 0 {
+				symbols = append(symbols, receiverName(d.Recv.List[0].Type)+"."+d.Name.Name)
+			} else {
+				symbols = append(symbols, d.Name.Name)
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					symbols = append(symbols, s.Name.Name)
+				case *ast.ValueSpec:
+					for _, name := range s.Names {
+						symbols = append(symbols, name.Name)
+					}
+				}
+			}
+		}
+	}
+	return symbols, nil
+}
+
+// checkPackageConflicts parses the other Go files of the output package and reports
+// declarations that the generated file would duplicate. With ConflictRename the conflicting
+// environment and profile types are renamed in data instead, which is reported as a warning.
+func checkPackageConflicts(outputFile string, data *mergedConfig, strategy string, log *messageLog) error {
+	switch strategy {
+	case "", ConflictError, ConflictRename:
+	case ConflictForce:
+		return nil
+	default:
+		return newError(MessageUnknownConflictStrategy, strategy, ConflictError, ConflictRename, ConflictForce)
+	}
+
+	declared, err := packageDeclarations(outputFile, data)
+	if err != nil {
+		return err
+	}
+
+	conflicts := conflictingDeclarations(declared, data)
+	if len(conflicts) > 0 && strategy == ConflictRename {
+		warnings := &warningLog{log: log}
+		renameConflictingTypes(declared, data, warnings)
+		data.Warnings = append(data.Warnings, warnings.warnings...)
+		if err := checkGeneratedCollisions(data); err != nil {
+			return err
+		}
+		conflicts = conflictingDeclarations(declared, data)
+	}
+
+	if len(conflicts) > 0 {
+		return newError(MessagePackageConflicts, strings.Join(conflicts, ", "), ConflictRename)
+	}
+
+	return nil
+}
+
+// packageDeclarations returns the top-level declarations of the other Go files of the output
+// package with the file declaring them. Files of a previous generation are replaced and skipped.
+func packageDeclarations(outputFile string, data *mergedConfig) (map[string]string, error) {
+	files, err := filepath.Glob(filepath.Join(filepath.Dir(outputFile), "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	own := map[string]bool{filepath.Base(outputFile): true}
+	if data.BuildTags {
+		for envName := range data.Environments {
+			own[filepath.Base(environmentFile(outputFile, data.namer.File(envName)))] = true
+		}
+		own[filepath.Base(environmentFile(outputFile, "buildtags"))] = true
+	}
+
+	declared := make(map[string]string)
+	for _, file := range files {
+		if own[filepath.Base(file)] {
+			continue
+		}
+
+		symbols, err := declaredSymbols(file)
+		if err != nil {
+			return nil, newError(MessageFailedParse, file, err)
+		}
+		for _, symbol := range symbols {
+			declared[symbol] = filepath.Base(file)
+		}
+	}
+	return declared, nil
+}
+
+// conflictingDeclarations lists the generated declarations declared by other package files
+func conflictingDeclarations(declared map[string]string, data *mergedConfig) []string {
+	var conflicts []string
+	for _, symbol := range generatedSymbols(data) {
+		if file, exists := declared[symbol]; exists {
+			conflicts = append(conflicts, fmt.Sprintf("%s (%s)", symbol, file))
+		}
+	}
+	return conflicts
+}
+
+// renameConflictingTypes prefixes the struct names of environments and profiles declaring a
+// symbol of another package file with ConflictRenamePrefix
+func renameConflictingTypes(declared map[string]string, data *mergedConfig, warnings *warningLog) {
+	conflicting := func(structName string, fields []Field, interfaceName bool) bool {
+		symbols := typeDeclarations(structName+"Config", fields, "")
+		if interfaceName {
+			symbols = append(symbols, generatedSymbol{name: structName + "Interface"})
+		}
+		for _, symbol := range symbols {
+			if _, exists := declared[symbol.name]; exists {
+				return true
+			}
+		}
+		return false
+	}
+	rename := func(envName, structName string) string {
+		renamed := ConflictRenamePrefix + structName
+		warnings.warn(Warning{
+			Code:        WarningConflictRenamed,
+			Environment: envName,
+		}, MessageWarningConflictRenamed, structName, renamed)
+		return renamed
+	}
+
+	for _, envName := range sortedEnvironmentNames(data.Environments) {
+		envData := data.Environments[envName]
+		if conflicting(envData.StructName, envData.Fields, len(envData.Extras) > 0) {
+			envData.StructName = rename(envName, envData.StructName)
+		}
+		profiles := slices.Clone(envData.Profiles)
+		for i, profile := range profiles {
+			if conflicting(profile.StructName, profile.Fields, false) {
+				profiles[i].StructName = rename(envName, profile.StructName)
+			}
+		}
+		envData.Profiles = profiles
+		data.Environments[envName] = envData
+	}
+}
@@ -0,0 +1,100 @@
+package envied
+
+import (
+	"slices"
+	"sort"
+)
+
+// ConsistencyReport is the result of CheckConsistency
+type ConsistencyReport struct {
+	Environments []string          // Checked environments sorted by name
+	Variables    []string          // Variables of all environments sorted by name
+	Missing      []MissingVariable // Variables missing in environments, sorted by variable and environment
+}
+
+// MissingVariable is a variable an environment doesn't define although it should
+type MissingVariable struct {
+	Variable    string
+	Environment string
+}
+
+// OK reports whether no variable is missing
+func (r ConsistencyReport) OK() bool {
+	return len(r.Missing) == 0
+}
+
+// ConsistencyOption configures CheckConsistency
+type ConsistencyOption func(*consistencyOptions)
+
+// consistencyOptions holds the settings of ConsistencyOption
+type consistencyOptions struct {
+	only       map[string][]string
+	allowExtra bool
+}
+
+// WithConditionalVariables restricts variables to the listed environments like the only setting
+// of variables: they must be defined in those environments and are ignored in the others
+func WithConditionalVariables(only map[string][]string) ConsistencyOption {
+	return func(o *consistencyOptions) {
+		o.only = only
+	}
+}
+
+// WithExtraVariablesAllowed reports missing variables without failing, like allow_extra_variables
+func WithExtraVariablesAllowed() ConsistencyOption {
+	return func(o *consistencyOptions) {
+		o.allowExtra = true
+	}
+}
+
+// CheckConsistency checks that environments, given as variable values by environment name, define
+// the same variables, the check generation runs on the env files. It needs no configuration file
+// or generation, so tools such as pre-commit hooks and chat bots can validate sets of env files
+// read with ReadEnvFile. The report lists all missing variables; the error describes the first.
+func CheckConsistency(environments map[string]map[string]string, opts ...ConsistencyOption) (ConsistencyReport, error) {
+	var options consistencyOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var report ConsistencyReport
+	variables := make(map[string]bool)
+	for envName, envVars := range environments {
+		report.Environments = append(report.Environments, envName)
+		for name := range envVars {
+			variables[name] = true
+		}
+	}
+	for name := range options.only {
+		variables[name] = true
+	}
+	for name := range variables {
+		report.Variables = append(report.Variables, name)
+	}
+	sort.Strings(report.Environments)
+	sort.Strings(report.Variables)
+
+	for _, name := range report.Variables {
+		only := options.only[name]
+		conditional := len(only) > 0
+		for _, envName := range only {
+			if _, exists := environments[envName]; !exists {
+				return report, newError(MessageEnabledForUnknownEnvironment, name, envName)
+			}
+		}
+		for _, envName := range report.Environments {
+			if conditional && !slices.Contains(only, envName) {
+				continue
+			}
+			if _, exists := environments[envName][name]; !exists {
+				report.Missing = append(report.Missing, MissingVariable{Variable: name, Environment: envName})
+			}
+		}
+	}
+
+	if !report.OK() && !options.allowExtra {
+		missing := report.Missing[0]
+		return report, newError(MessageVariableMissing, missing.Variable, missing.Environment)
+	}
+	return report, nil
+}
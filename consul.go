@@ -0,0 +1,180 @@
+package envied
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Consul defaults, following the Consul CLI environment variables
+const (
+	DefaultConsulAddress  = "http://127.0.0.1:8500"
+	DefaultConsulTokenEnv = "CONSUL_HTTP_TOKEN"
+)
+
+// consulWaitTime is the maximum duration of a blocking query
+const consulWaitTime = 5 * time.Minute
+
+// consulRetryDelay is the delay before retrying a failed watch request
+var consulRetryDelay = time.Second
+
+// ConsulConfig configures reading variables from Consul KV under a prefix.
+// Keys below the prefix become variable names, nested keys are joined with '_'.
+type ConsulConfig struct {
+	Address    string `json:"address,omitempty"`    // Consul HTTP address (CONSUL_HTTP_ADDR or DefaultConsulAddress if empty)
+	Prefix     string `json:"prefix"`               // KV prefix holding the variables
+	Datacenter string `json:"datacenter,omitempty"` // Datacenter to query (agent's datacenter if empty)
+	TokenEnv   string `json:"token_env,omitempty"`  // Environment variable holding the ACL token (DefaultConsulTokenEnv if empty)
+}
+
+// consulPair is an entry of the Consul KV HTTP API response
+type consulPair struct {
+	Key   string
+	Value []byte // Base64 in JSON, decoded by encoding/json
+}
+
+// address returns the Consul HTTP address with scheme
+func (c ConsulConfig) address() string {
+	address := c.Address
+	if address == "" {
+		address = os.Getenv("CONSUL_HTTP_ADDR")
+	}
+	if address == "" {
+		address = DefaultConsulAddress
+	}
+	if !strings.Contains(address, "://") {
+		address = "http://" + address
+	}
+	return strings.TrimSuffix(address, "/")
+}
+
+// describe returns a human-readable location of the variables
+func (c ConsulConfig) describe() string {
+	return fmt.Sprintf("consul %s/%s", c.address(), strings.Trim(c.Prefix, "/"))
+}
+
+// fetch reads all variables under the prefix. A non-zero index turns the request into
+// a blocking query returning when the data changes after index. It returns the new index.
+func (c ConsulConfig) fetch(ctx context.Context, client *http.Client, index uint64) (map[string]string, uint64, error) {
+	query := url.Values{"recurse": {"true"}}
+	if c.Datacenter != "" {
+		query.Set("dc", c.Datacenter)
+	}
+	if index > 0 {
+		query.Set("index", strconv.FormatUint(index, 10))
+		query.Set("wait", consulWaitTime.String())
+	}
+	requestURL := fmt.Sprintf("%s/v1/kv/%s?%s", c.address(), strings.Trim(c.Prefix, "/"), query.Encode())
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	tokenEnv := c.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = DefaultConsulTokenEnv
+	}
+	if token := os.Getenv(tokenEnv); token != "" {
+		request.Header.Set("X-Consul-Token", token)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, 0, newError(MessageFailedQuery, c.describe(), err)
+	}
+	defer response.Body.Close()
+
+	newIndex, _ := strconv.ParseUint(response.Header.Get("X-Consul-Index"), 10, 64)
+	values := make(map[string]string)
+	switch response.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		// No keys under the prefix
+		return values, newIndex, nil
+	default:
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return nil, 0, newError(MessageQueryStatus, c.describe(), response.Status, strings.TrimSpace(string(body)))
+	}
+
+	var pairs []consulPair
+	if err := json.NewDecoder(response.Body).Decode(&pairs); err != nil {
+		return nil, 0, newError(MessageInvalidResponse, c.describe(), err)
+	}
+	for _, pair := range pairs {
+		if name, ok := prefixVariableName(strings.Trim(c.Prefix, "/")+"/", pair.Key); ok {
+			values[name] = string(pair.Value)
+		}
+	}
+	return values, newIndex, nil
+}
+
+// ConsulSource is a runtime Source reading Consul KV, see NewConsulSource
+type ConsulSource struct {
+	watchedValues
+
+	config ConsulConfig
+	client *http.Client
+	index  uint64 // Consul index of values, used for blocking queries
+}
+
+// NewConsulSource reads the variables under the configured prefix.
+// Call Watch to keep them up to date.
+func NewConsulSource(ctx context.Context, config ConsulConfig) (*ConsulSource, error) {
+	source := &ConsulSource{
+		config: config,
+		client: &http.Client{},
+	}
+
+	values, index, err := config.fetch(ctx, source.client, 0)
+	if err != nil {
+		return nil, err
+	}
+	source.update(values)
+	source.index = index
+	return source, nil
+}
+
+// Watch follows changes under the prefix with blocking queries and calls onChange with the
+// new variables after every change. Failed requests are retried. Watch returns when ctx is done.
+func (s *ConsulSource) Watch(ctx context.Context, onChange ChangeFunc) error {
+	for {
+		values, newIndex, err := s.config.fetch(ctx, s.client, s.index)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(consulRetryDelay):
+			}
+			continue
+		}
+
+		// An index going backwards means the Consul state was reset, start over
+		if newIndex < s.index {
+			newIndex = 0
+		}
+		s.index = newIndex
+
+		if s.update(values) && onChange != nil {
+			onChange(s.Values())
+		}
+
+		// Without an index the next query would not block
+		if newIndex == 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(consulRetryDelay):
+			}
+		}
+	}
+}
@@ -0,0 +1,81 @@
+package envied
+
+import (
+	"bytes"
+	"time"
+)
+
+// renderFromConfig builds the merged configuration in memory and returns the generated source
+// stamped with generatedAt
+func renderFromConfig(configFile *ConfigFile, configFilePath string, generatedAt time.Time) ([]byte, error) {
+	mergedData, err := buildMergedConfig(configFile, configFilePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	mergedData.GeneratedAt = generatedAt
+
+	var buf bytes.Buffer
+	if err := generateCodeDirectly(&buf, mergedData); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// AssertDeterministic runs generation twice in memory with the options applied and returns an
+// error describing the first difference if the outputs are not identical (map order or RNG
+// dependence). The output embeds the generation time, so SOURCE_DATE_EPOCH must be set.
+// Nothing is written to disk.
+func AssertDeterministic(opts GenerateOptions) error {
+	return opts.run(func() error {
+		configFile, configPath, _, err := opts.load()
+		if err != nil {
+			return err
+		}
+		return assertDeterministic(configFile, configPath)
+	})
+}
+
+// assertDeterministic compares two in-memory generations of a loaded config stamped with
+// SOURCE_DATE_EPOCH. Without it every build embeds another time, which is refused up front.
+func assertDeterministic(configFile *ConfigFile, configFilePath string) error {
+	generatedAt, fixedTime := sourceDateEpoch()
+	if !fixedTime {
+		return newError(MessageSourceDateEpochRequired)
+	}
+
+	first, err := renderFromConfig(configFile, configFilePath, generatedAt)
+	if err != nil {
+		return err
+	}
+	second, err := renderFromConfig(configFile, configFilePath, generatedAt)
+	if err != nil {
+		return err
+	}
+
+	if bytes.Equal(first, second) {
+		return nil
+	}
+
+	line, firstLine, secondLine := firstDifference(first, second)
+	return newError(MessageNotDeterministic, line, firstLine, secondLine)
+}
+
+// firstDifference returns the 1-based number of the first differing line of two outputs
+// and the line of each output
+func firstDifference(first, second []byte) (int, string, string) {
+	firstLines := bytes.Split(first, []byte("\n"))
+	secondLines := bytes.Split(second, []byte("\n"))
+	line := 0
+	for line < len(firstLines) && line < len(secondLines) && bytes.Equal(firstLines[line], secondLines[line]) {
+		line++
+	}
+	return line + 1, lineAt(firstLines, line), lineAt(secondLines, line)
+}
+
+// lineAt returns the line at index or a marker past the end of the output
+func lineAt(lines [][]byte, index int) string {
+	if index < len(lines) {
+		return string(lines[index])
+	}
+	return "<end of output>"
+}
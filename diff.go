@@ -0,0 +1,84 @@
+package envied
+
+import (
+	"sort"
+)
+
+// Kinds of differences between environments
+const (
+	DiffAdded        = "added"   // Variable defined only in the second environment
+	DiffRemoved      = "removed" // Variable defined only in the first environment
+	DiffTypeChanged  = "type"    // Variable has different types
+	DiffValueChanged = "value"   // Variable has different values
+)
+
+// VariableDifference describes a variable differing between two environments.
+// Values are not included, they may be secrets.
+type VariableDifference struct {
+	Name     string    `json:"name"`
+	Kind     string    `json:"kind"`                // One of DiffAdded, DiffRemoved, DiffTypeChanged, DiffValueChanged
+	FromType FieldType `json:"from_type,omitempty"` // Type in the first environment, empty if added
+	ToType   FieldType `json:"to_type,omitempty"`   // Type in the second environment, empty if removed
+}
+
+// DiffEnvironments compares the variables of two environments after transforms,
+// sorted by variable name
+func DiffEnvironments(opts GenerateOptions, from, to string) ([]VariableDifference, error) {
+	var differences []VariableDifference
+	err := opts.run(func() error {
+		var err error
+		differences, err = diffEnvironments(opts, from, to)
+		return err
+	})
+	return differences, err
+}
+
+// diffEnvironments implements DiffEnvironments
+func diffEnvironments(opts GenerateOptions, from, to string) ([]VariableDifference, error) {
+	configFile, configPath, _, err := opts.load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Differences are what is being looked for, so they must not fail the build
+	configFile.AllowExtraVariables = true
+	mergedData, err := buildMergedConfig(configFile, configPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make(map[string]map[string]Field, 2)
+	for _, envName := range []string{from, to} {
+		envData, exists := mergedData.Environments[envName]
+		if !exists {
+			return nil, &ErrUnknownEnvironment{Name: envName, Valid: sortedEnvironmentNames(mergedData.Environments)}
+		}
+		fields[envName] = make(map[string]Field, len(envData.Fields))
+		for _, field := range envData.Fields {
+			fields[envName][field.EnvName] = field
+		}
+	}
+
+	var differences []VariableDifference
+	for name, fromField := range fields[from] {
+		toField, exists := fields[to][name]
+		switch {
+		case !exists:
+			differences = append(differences, VariableDifference{Name: name, Kind: DiffRemoved, FromType: fromField.Type})
+		case fromField.Type != toField.Type:
+			differences = append(differences, VariableDifference{Name: name, Kind: DiffTypeChanged, FromType: fromField.Type, ToType: toField.Type})
+		case fromField.Value != toField.Value:
+			differences = append(differences, VariableDifference{Name: name, Kind: DiffValueChanged, FromType: fromField.Type, ToType: toField.Type})
+		}
+	}
+	for name, toField := range fields[to] {
+		if _, exists := fields[from][name]; !exists {
+			differences = append(differences, VariableDifference{Name: name, Kind: DiffAdded, ToType: toField.Type})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		return differences[i].Name < differences[j].Name
+	})
+	return differences, nil
+}
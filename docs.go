@@ -0,0 +1,305 @@
+package envied
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// EmitConfig configures documentation files written next to the generated code.
+// Paths are relative to the working directory, like output_dir; empty paths are not written.
+type EmitConfig struct {
+	Markdown   string `json:"markdown,omitempty"`    // Markdown reference of all variables
+	EnvExample string `json:"env_example,omitempty"` // .env.example template without values
+	Manifest   string `json:"manifest,omitempty"`    // JSON manifest of environments and variables without values
+	Fixtures   string `json:"fixtures,omitempty"`    // Directory of <environment>.env fixtures with fake values for integration tests
+	Properties string `json:"properties,omitempty"`  // Directory of <environment>.properties files with the values, for Java services
+	INI        string `json:"ini,omitempty"`         // INI file with the values of every environment in its own section
+	Helm       string `json:"helm,omitempty"`        // Directory of values-<environment>.yaml Helm values with Deployment env sections
+	HelmSecret string `json:"helm_secret,omitempty"` // Secret referenced for sensitive values, {environment} is replaced, <package_name>-{environment} if empty
+
+	SealedSecrets     string `json:"sealed_secrets,omitempty"`      // Directory of sealedsecret-<environment>.yaml Bitnami SealedSecrets of the sensitive values, named like helm_secret
+	SealedSecretsCert string `json:"sealed_secrets_cert,omitempty"` // PEM certificate of the Sealed Secrets controller, as printed by kubeseal --fetch-cert
+	Namespace         string `json:"namespace,omitempty"`           // Kubernetes namespace of the sealed secrets, default if empty
+
+	// Environment variable holding a base64 AES-256 key; when set, all emitted files are encrypted
+	// with AES-256-GCM so the variable inventory can be archived, see DecryptArtifact
+	EncryptKeyEnv string `json:"encrypt_key_env,omitempty"`
+
+	// Environment variable holding a base64 32-byte key the value hashes of the manifest are keyed
+	// with; without it sensitive values have no hash, see ValueHash
+	HashKeyEnv string `json:"hash_key_env,omitempty"`
+
+	// Names variables are written with, keyed by emitter (env_example, fixtures, properties, ini,
+	// helm or sealed_secrets) and then by variable name, e.g. DB_URL exported as DATABASE_URL
+	Rename map[string]map[string]string `json:"rename,omitempty"`
+}
+
+// Manifest describes a generated configuration without its values
+type Manifest struct {
+	PackageName  string                `json:"package_name"`
+	GeneratedAt  time.Time             `json:"generated_at"`
+	Environments []ManifestEnvironment `json:"environments"`
+	Variables    []ManifestVariable    `json:"variables"`
+	Crypto       *ManifestCrypto       `json:"crypto"`
+}
+
+// ManifestEnvironment describes a generated environment
+type ManifestEnvironment struct {
+	Name       string `json:"name"`
+	StructName string `json:"struct_name"`
+	SourceHash string `json:"source_hash"` // SHA-256 of the env file contents
+}
+
+// ManifestVariable describes a generated variable
+type ManifestVariable struct {
+	Name         string    `json:"name"`
+	Type         FieldType `json:"type"`
+	Description  string    `json:"description,omitempty"`
+	Environments []string  `json:"environments"` // Environments the variable is generated for
+
+	Sources map[string]Provenance `json:"sources,omitempty"` // Where the value of each environment comes from
+	Hashes  map[string]string     `json:"hashes,omitempty"`  // Value hash of each environment, see ValueHash; sensitive values only with emit.hash_key_env
+}
+
+// applyDescriptions copies variable descriptions from the configuration to the fields
+func applyDescriptions(fields []Field, variables map[string]VariableConfig) {
+	for i := range fields {
+		fields[i].Description = variables[fields[i].EnvName].Description
+	}
+}
+
+// writeDescription writes a description as comment lines starting with prefix
+func writeDescription(w io.Writer, prefix string, description string) {
+	if description == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSpace(description), "\n") {
+		fmt.Fprintf(w, "%s%s\n", prefix, strings.TrimSpace(line))
+	}
+}
+
+// writeGetterDoc writes the godoc of a getter for a field with a description
+func writeGetterDoc(w io.Writer, indent string, field Field) {
+	if field.Description == "" {
+		return
+	}
+	writeDescription(w, indent+"// ", fmt.Sprintf("%s returns %s - %s", field.Getter, field.EnvName, field.Description))
+}
+
+// hashKey reads the key of value hashes, nil if emit.hash_key_env is not set
+func (emit *EmitConfig) hashKey() ([]byte, error) {
+	if emit == nil || emit.HashKeyEnv == "" {
+		return nil, nil
+	}
+	return ArtifactKey(emit.HashKeyEnv)
+}
+
+// buildManifest describes the merged configuration with variables sorted by name. Values are
+// hashed with hashKey; without a key sensitive values are left out, since the names salting
+// their hashes are public.
+func buildManifest(data *mergedConfig, hashKey []byte) *Manifest {
+	manifest := &Manifest{
+		PackageName: data.PackageName,
+		GeneratedAt: data.GeneratedAt,
+		Crypto:      manifestCrypto(data),
+	}
+
+	variables := make(map[string]*ManifestVariable)
+	for _, envName := range sortedEnvironmentNames(data.Environments) {
+		envData := data.Environments[envName]
+		manifest.Environments = append(manifest.Environments, ManifestEnvironment{
+			Name:       envName,
+			StructName: envData.StructName,
+			SourceHash: envData.SourceHash,
+		})
+
+		for _, field := range envData.Fields {
+			variable, exists := variables[field.EnvName]
+			if !exists {
+				variable = &ManifestVariable{
+					Name:        field.EnvName,
+					Type:        field.Type,
+					Description: field.Description,
+				}
+				variables[field.EnvName] = variable
+			}
+			variable.Environments = append(variable.Environments, envName)
+			if hashKey != nil || !envData.isSensitive(field.EnvName) {
+				if variable.Hashes == nil {
+					variable.Hashes = make(map[string]string)
+				}
+				variable.Hashes[envName] = ValueHash(hashKey, envName, field.EnvName, field.Value)
+			}
+			if provenance, exists := envData.Provenance[field.EnvName]; exists {
+				if variable.Sources == nil {
+					variable.Sources = make(map[string]Provenance)
+				}
+				variable.Sources[envName] = provenance
+			}
+		}
+	}
+
+	for _, variable := range variables {
+		manifest.Variables = append(manifest.Variables, *variable)
+	}
+	sort.Slice(manifest.Variables, func(i, j int) bool {
+		return manifest.Variables[i].Name < manifest.Variables[j].Name
+	})
+
+	return manifest
+}
+
+// renderManifest renders the manifest as indented JSON
+func renderManifest(manifest *Manifest) ([]byte, error) {
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append(data, '\n'), nil
+}
+
+// renderMarkdown renders a Markdown reference table of all variables
+func renderMarkdown(manifest *Manifest) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "<!-- %s -->\n\n", strings.TrimPrefix(generatedHeader, "// "))
+	fmt.Fprintf(&buf, "# Configuration variables\n\n")
+	fmt.Fprintf(&buf, "Package `%s`\n\n", manifest.PackageName)
+	fmt.Fprintf(&buf, "| Variable | Type | Environments | Description |\n")
+	fmt.Fprintf(&buf, "|----------|------|--------------|-------------|\n")
+
+	replacer := strings.NewReplacer("|", "\\|", "\r\n", "<br>", "\n", "<br>")
+	for _, variable := range manifest.Variables {
+		fmt.Fprintf(&buf, "| `%s` | %s | %s | %s |\n",
+			variable.Name,
+			variable.Type,
+			strings.Join(variable.Environments, ", "),
+			replacer.Replace(strings.TrimSpace(variable.Description)))
+	}
+	return buf.Bytes()
+}
+
+// renderEnvExample renders a .env.example template listing all variables without values
+func renderEnvExample(manifest *Manifest, names map[string]string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", strings.TrimPrefix(generatedHeader, "// "))
+
+	for _, variable := range manifest.Variables {
+		fmt.Fprintln(&buf)
+		writeDescription(&buf, "# ", variable.Description)
+		fmt.Fprintf(&buf, "# Type: %s\n", variable.Type)
+		if len(variable.Environments) < len(manifest.Environments) {
+			fmt.Fprintf(&buf, "# Environments: %s\n", strings.Join(variable.Environments, ", "))
+		}
+		fmt.Fprintf(&buf, "%s=\n", exportedName(names, variable.Name))
+	}
+
+	return buf.Bytes()
+}
+
+// emittedFile is a file written by emitDocs
+type emittedFile struct {
+	path    string
+	content []byte
+}
+
+// emitDocs writes the documentation files configured by emit
+func emitDocs(emit *EmitConfig, data *mergedConfig, log *messageLog) error {
+	if emit == nil {
+		return nil
+	}
+
+	var key []byte
+	if emit.EncryptKeyEnv != "" {
+		var err error
+		if key, err = ArtifactKey(emit.EncryptKeyEnv); err != nil {
+			return err
+		}
+	}
+
+	hashKey, err := emit.hashKey()
+	if err != nil {
+		return err
+	}
+	manifest := buildManifest(data, hashKey)
+	if err := checkRenames(emit.Rename, manifest); err != nil {
+		return err
+	}
+	if key != nil {
+		manifest.Crypto.ArtifactEncryption = algorithmAESGCM
+	}
+	manifestData, err := renderManifest(manifest)
+	if err != nil {
+		return newError(MessageFailedRenderManifest, err)
+	}
+
+	var sealingKey *rsa.PublicKey
+	if emit.SealedSecrets != "" {
+		if sealingKey, err = loadSealingKey(emit.SealedSecretsCert); err != nil {
+			return err
+		}
+	}
+	namespace := emit.Namespace
+	if namespace == "" {
+		namespace = defaultNamespace
+	}
+
+	outputs := []emittedFile{
+		{emit.Markdown, renderMarkdown(manifest)},
+		{emit.EnvExample, renderEnvExample(manifest, emit.Rename["env_example"])},
+		{emit.Manifest, manifestData},
+	}
+	for _, envName := range sortedEnvironmentNames(data.Environments) {
+		if emit.Fixtures != "" {
+			outputs = append(outputs, emittedFile{fixturePath(emit.Fixtures, envName), renderFixture(data.Environments[envName], emit.Rename["fixtures"])})
+		}
+		if emit.Properties != "" {
+			outputs = append(outputs, emittedFile{propertiesPath(emit.Properties, envName), renderProperties(envName, data.Environments[envName], emit.Rename["properties"])})
+		}
+		secretName := helmSecretName(emit, data.PackageName, envName)
+		if emit.Helm != "" {
+			outputs = append(outputs, emittedFile{helmValuesPath(emit.Helm, envName), renderHelmValues(envName, data.Environments[envName], secretName, emit.Rename["helm"], emit.Rename["sealed_secrets"])})
+		}
+		if sealingKey != nil {
+			sealedSecret, err := renderSealedSecret(envName, data.Environments[envName], secretName, namespace, sealingKey, emit.Rename["sealed_secrets"])
+			if err != nil {
+				return err
+			}
+			outputs = append(outputs, emittedFile{sealedSecretPath(emit.SealedSecrets, envName), sealedSecret})
+		}
+	}
+	if emit.INI != "" {
+		outputs = append(outputs, emittedFile{emit.INI, renderINI(data, emit.Rename["ini"])})
+	}
+	for _, output := range outputs {
+		if output.path == "" {
+			continue
+		}
+		content := output.content
+		if key != nil {
+			if content, err = EncryptArtifact(content, key); err != nil {
+				return newError(MessageFailedEncrypt, output.path, err)
+			}
+		}
+		if err := os.MkdirAll(filepath.Dir(output.path), 0755); err != nil {
+			return newError(MessageFailedCreateDirectory, output.path, err)
+		}
+		if err := os.WriteFile(output.path, content, 0644); err != nil {
+			return newError(MessageFailedWrite, output.path, err)
+		}
+		if key != nil {
+			log.say(MessageWrittenEncrypted, output.path)
+		} else {
+			log.say(MessageWritten, output.path)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,449 @@
+package envied
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"go/parser"
+	"go/token"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// DefaultDoctorTimeout bounds how long Doctor waits for each remote source
+const DefaultDoctorTimeout = 10 * time.Second
+
+// Doctor checks
+const (
+	DoctorConfig      = "config"      // The configuration is found and loads
+	DoctorEnvFile     = "env file"    // An env file is readable and not writable by all users
+	DoctorOutput      = "output"      // The output directory is inside a Go module and holds only the configured package
+	DoctorToolchain   = "toolchain"   // The go command is installed
+	DoctorCredentials = "credentials" // Required environment variables and files of a remote source are present
+	DoctorAccess      = "access"      // A remote source is reachable and the credentials may read it
+)
+
+// DoctorCheck is the result of a diagnostic check of the project or of an environment
+type DoctorCheck struct {
+	Environment string `json:"environment,omitempty"` // Empty for checks of the project
+	Source      string `json:"source"`                // What was checked, e.g. a file or consul http://127.0.0.1:8500/app/prod
+	Check       string `json:"check"`                 // One of the Doctor checks, e.g. DoctorEnvFile
+	OK          bool   `json:"ok"`
+	Message     string `json:"message"`        // What was found, formatted with GenerateOptions.Catalog
+	Hint        string `json:"hint,omitempty"` // How to fix a failed check, formatted with GenerateOptions.Catalog
+}
+
+// Doctor messages, with the arguments of their format
+const (
+	MessageDoctorConfigFound         MessageID = "doctor_config_found"           // configuration file, environment count
+	MessageDoctorConfigHint          MessageID = "doctor_config_hint"            // no arguments
+	MessageDoctorEnvFileMissing      MessageID = "doctor_env_file_missing"       // env file
+	MessageDoctorEnvFileMissingHint  MessageID = "doctor_env_file_missing_hint"  // env file
+	MessageDoctorEnvFileHint         MessageID = "doctor_env_file_hint"          // env file
+	MessageDoctorNotRegularFile      MessageID = "doctor_not_regular_file"       // env file
+	MessageDoctorNotRegularFileHint  MessageID = "doctor_not_regular_file_hint"  // no arguments
+	MessageDoctorWorldWritable       MessageID = "doctor_world_writable"         // env file, permissions
+	MessageDoctorWorldWritableHint   MessageID = "doctor_world_writable_hint"    // env file
+	MessageDoctorUnreadableHint      MessageID = "doctor_unreadable_hint"        // env file
+	MessageDoctorPassphraseHint      MessageID = "doctor_passphrase_hint"        // passphrase environment variable
+	MessageDoctorReadable            MessageID = "doctor_readable"               // env file, permissions
+	MessageDoctorInvalidPackageName  MessageID = "doctor_invalid_package_name"   // package name
+	MessageDoctorInvalidPackageHint  MessageID = "doctor_invalid_package_hint"   // no arguments
+	MessageDoctorOutsideModule       MessageID = "doctor_outside_module"         // output directory
+	MessageDoctorOutsideModuleHint   MessageID = "doctor_outside_module_hint"    // no arguments
+	MessageDoctorUnsafePathsHint     MessageID = "doctor_unsafe_paths_hint"      // no arguments
+	MessageDoctorParseFailed         MessageID = "doctor_parse_failed"           // file, error
+	MessageDoctorParseFailedHint     MessageID = "doctor_parse_failed_hint"      // no arguments
+	MessageDoctorOtherPackage        MessageID = "doctor_other_package"          // file, package, package name
+	MessageDoctorOtherPackageHint    MessageID = "doctor_other_package_hint"     // package
+	MessageDoctorPackage             MessageID = "doctor_package"                // package name, module directory
+	MessageDoctorPackageCreated      MessageID = "doctor_package_created"        // package name, module directory
+	MessageDoctorNoGo                MessageID = "doctor_no_go"                  // no arguments
+	MessageDoctorNoGoHint            MessageID = "doctor_no_go_hint"             // no arguments
+	MessageDoctorGoVersionFailed     MessageID = "doctor_go_version_failed"      // go command, error
+	MessageDoctorGoVersionFailedHint MessageID = "doctor_go_version_failed_hint" // no arguments
+	MessageDoctorGoVersion           MessageID = "doctor_go_version"             // version, go command
+	MessageDoctorCredentialsHint     MessageID = "doctor_credentials_hint"       // no arguments
+	MessageDoctorRead                MessageID = "doctor_read"                   // variable count, duration
+	MessageDoctorTimeoutHint         MessageID = "doctor_timeout_hint"           // source
+	MessageDoctorConnectHint         MessageID = "doctor_connect_hint"           // source
+	MessageDoctorRefusedHint         MessageID = "doctor_refused_hint"           // source
+	MessageDoctorCredential          MessageID = "doctor_credential"             // credential, environment variable
+	MessageDoctorAnonymous           MessageID = "doctor_anonymous"              // environment variable
+	MessageDoctorNoAuthentication    MessageID = "doctor_no_authentication"      // no arguments
+	MessageDoctorToken               MessageID = "doctor_token"                  // no arguments
+	MessageDoctorPassword            MessageID = "doctor_password"               // no arguments
+	MessageDoctorUserPassword        MessageID = "doctor_user_password"          // user
+	MessageDoctorDSN                 MessageID = "doctor_dsn"                    // no arguments
+	MessageDoctorConfigDSN           MessageID = "doctor_config_dsn"             // no arguments
+)
+
+// doctorMessages holds the English doctor messages, part of DefaultCatalog
+var doctorMessages = Catalog{
+	MessageDoctorConfigFound:         "%s with %d environments",
+	MessageDoctorConfigHint:          "run 'envied init' in the project root, or pass -config with the path of the configuration",
+	MessageDoctorEnvFileMissing:      "%s does not exist",
+	MessageDoctorEnvFileMissingHint:  "create %s or fix its path in the configuration, relative paths are resolved from the working directory",
+	MessageDoctorEnvFileHint:         "create %s or fix its path in the configuration",
+	MessageDoctorNotRegularFile:      "%s is not a regular file",
+	MessageDoctorNotRegularFileHint:  "point env_file at a file",
+	MessageDoctorWorldWritable:       "%s is writable by all users (%s)",
+	MessageDoctorWorldWritableHint:   "run 'chmod o-w %s', anyone on this machine can change the generated values",
+	MessageDoctorUnreadableHint:      "make %s readable by the user running generation",
+	MessageDoctorPassphraseHint:      "set %s to the passphrase the file was encrypted with",
+	MessageDoctorReadable:            "%s is readable (%s)",
+	MessageDoctorInvalidPackageName:  "package_name %q is not a Go identifier",
+	MessageDoctorInvalidPackageHint:  "set package_name to a lowercase name such as config",
+	MessageDoctorOutsideModule:       "%s is not inside a Go module",
+	MessageDoctorOutsideModuleHint:   "run 'go mod init' in the project root, or set output_dir inside the module",
+	MessageDoctorUnsafePathsHint:     "move the env files or output_dir, generation refuses these paths",
+	MessageDoctorParseFailed:         "failed to parse %s: %v",
+	MessageDoctorParseFailedHint:     "fix the syntax of the file, the generated file is compiled with it",
+	MessageDoctorOtherPackage:        "%s holds package %s, package_name is %s",
+	MessageDoctorOtherPackageHint:    "set package_name to %s or choose another output_dir",
+	MessageDoctorPackage:             "package %s in module %s",
+	MessageDoctorPackageCreated:      "package %s in module %s, the directory is created by generation",
+	MessageDoctorNoGo:                "the go command is not in PATH",
+	MessageDoctorNoGoHint:            "install Go from https://go.dev/dl and add its bin directory to PATH",
+	MessageDoctorGoVersionFailed:     "%s env GOVERSION failed: %v",
+	MessageDoctorGoVersionFailedHint: "reinstall Go, the toolchain is broken",
+	MessageDoctorGoVersion:           "%s at %s",
+	MessageDoctorCredentialsHint:     "set it up in the environment running generation, e.g. as a CI secret",
+	MessageDoctorRead:                "read %d variables in %s",
+	MessageDoctorTimeoutHint:         "no answer in time, check the address of %s and that firewalls or VPNs let this machine reach it",
+	MessageDoctorConnectHint:         "can't connect, check the address of %s and that the server is running",
+	MessageDoctorRefusedHint:         "the server answered but refused the read, check that the credentials may read %s",
+	MessageDoctorCredential:          "%s from %s",
+	MessageDoctorAnonymous:           "%s is not set, requests are anonymous",
+	MessageDoctorNoAuthentication:    "no authentication",
+	MessageDoctorToken:               "token",
+	MessageDoctorPassword:            "password",
+	MessageDoctorUserPassword:        "password of user %s",
+	MessageDoctorDSN:                 "DSN",
+	MessageDoctorConfigDSN:           "DSN from the configuration",
+}
+
+// doctorSource is a remote source checked by Doctor
+type doctorSource struct {
+	describe    string
+	credentials func(Catalog) (string, error) // Checks the setup without network access and describes the credentials
+	fetch       func(ctx context.Context) (map[string]string, error)
+}
+
+// doctorSources returns the remote sources generation reads for an environment
+func (e EnvironmentConfig) doctorSources(valuesFrom string) []doctorSource {
+	var sources []doctorSource
+	if c := e.Consul; c != nil {
+		sources = append(sources, doctorSource{
+			describe: c.describe(),
+			credentials: func(catalog Catalog) (string, error) {
+				return optionalCredential(catalog, c.TokenEnv, DefaultConsulTokenEnv, catalog.Format(MessageDoctorToken))
+			},
+			fetch: func(ctx context.Context) (map[string]string, error) {
+				values, _, err := c.fetch(ctx, http.DefaultClient, 0)
+				return values, err
+			},
+		})
+	}
+	if c := e.Etcd; c != nil {
+		sources = append(sources, doctorSource{
+			describe: c.describe(),
+			credentials: func(catalog Catalog) (string, error) {
+				if _, err := c.httpClient(); err != nil {
+					return "", err
+				}
+				if c.Username == "" {
+					return catalog.Format(MessageDoctorNoAuthentication), nil
+				}
+				return requiredCredential(catalog, c.PasswordEnv, DefaultEtcdPasswordEnv, catalog.Format(MessageDoctorUserPassword, c.Username))
+			},
+			fetch: func(ctx context.Context) (map[string]string, error) {
+				client, err := newEtcdClient(ctx, *c)
+				if err != nil {
+					return nil, err
+				}
+				values, _, err := client.fetch(ctx)
+				return values, err
+			},
+		})
+	}
+	if c := e.Redis; c != nil {
+		sources = append(sources, doctorSource{
+			describe: c.describe(),
+			credentials: func(catalog Catalog) (string, error) {
+				if _, err := c.tlsConfig(); err != nil {
+					return "", err
+				}
+				if c.Username == "" {
+					return optionalCredential(catalog, c.PasswordEnv, DefaultRedisPasswordEnv, catalog.Format(MessageDoctorPassword))
+				}
+				return requiredCredential(catalog, c.PasswordEnv, DefaultRedisPasswordEnv, catalog.Format(MessageDoctorUserPassword, c.Username))
+			},
+			fetch: c.fetch,
+		})
+	}
+	if c := e.SQL; c != nil {
+		sources = append(sources, doctorSource{
+			describe: c.describe(),
+			credentials: func(catalog Catalog) (string, error) {
+				if !slices.Contains(sql.Drivers(), c.Driver) {
+					return "", newError(MessageDoctorSQLDriverNotRegistered, c.Driver)
+				}
+				if _, err := c.dsn(); err != nil {
+					return "", err
+				}
+				if c.DSNEnv != "" {
+					return catalog.Format(MessageDoctorCredential, catalog.Format(MessageDoctorDSN), c.DSNEnv), nil
+				}
+				return catalog.Format(MessageDoctorConfigDSN), nil
+			},
+			fetch: c.fetch,
+		})
+	}
+	if c := e.Vault; c != nil && valuesFrom == ValuesFromVault {
+		sources = append(sources, doctorSource{
+			describe: c.describe(),
+			credentials: func(catalog Catalog) (string, error) {
+				return requiredCredential(catalog, c.TokenEnv, DefaultVaultTokenEnv, catalog.Format(MessageDoctorToken))
+			},
+			fetch: func(ctx context.Context) (map[string]string, error) {
+				return c.fetch(ctx, http.DefaultClient)
+			},
+		})
+	}
+	return sources
+}
+
+// requiredCredential describes a credential read from an environment variable, which must be set
+func requiredCredential(catalog Catalog, envName, defaultEnv, what string) (string, error) {
+	if envName == "" {
+		envName = defaultEnv
+	}
+	if os.Getenv(envName) == "" {
+		return "", newError(MessageHoldingVariableNotSet, envName, what)
+	}
+	return catalog.Format(MessageDoctorCredential, what, envName), nil
+}
+
+// optionalCredential describes a credential read from an environment variable, which may be unset
+func optionalCredential(catalog Catalog, envName, defaultEnv, what string) (string, error) {
+	if envName == "" {
+		envName = defaultEnv
+	}
+	if os.Getenv(envName) == "" {
+		return catalog.Format(MessageDoctorAnonymous, envName), nil
+	}
+	return catalog.Format(MessageDoctorCredential, what, envName), nil
+}
+
+// accessHint returns setup guidance for a failed read of a source
+func accessHint(ctx context.Context, catalog Catalog, source string, err error) string {
+	var netErr *net.OpError
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded) || errors.Is(err, context.DeadlineExceeded):
+		return catalog.Format(MessageDoctorTimeoutHint, source)
+	case errors.As(err, &netErr):
+		return catalog.Format(MessageDoctorConnectHint, source)
+	}
+	return catalog.Format(MessageDoctorRefusedHint, source)
+}
+
+// Doctor runs the diagnostics of a project as a checklist: that the configuration is found and
+// loads, that env files are readable and not writable by all users, that the output directory
+// holds only the configured package inside a Go module, and that the Go toolchain is installed.
+// It then checks the remote sources generation would read: required environment variables and
+// files first and then, with a time limit per source, a read of every source whose setup is
+// complete. It turns failures in the middle of generation into setup guidance. Checks of the
+// project have no environment. Messages and hints are formatted with opts.Catalog. timeout is
+// DefaultDoctorTimeout if zero.
+func Doctor(opts GenerateOptions, timeout time.Duration) ([]DoctorCheck, error) {
+	if timeout == 0 {
+		timeout = DefaultDoctorTimeout
+	}
+	catalog := opts.Catalog
+	var checks []DoctorCheck
+	err := opts.run(func() error {
+		configFile, configPath, outputFile, err := opts.load()
+		if err != nil {
+			checks = append(checks, DoctorCheck{
+				Source:  opts.ConfigPath,
+				Check:   DoctorConfig,
+				Message: strings.TrimPrefix(catalog.Error(err), "❌ ERROR: "),
+				Hint:    catalog.Format(MessageDoctorConfigHint),
+			})
+			return nil
+		}
+		checks = append(checks, DoctorCheck{
+			Source:  configPath,
+			Check:   DoctorConfig,
+			OK:      true,
+			Message: catalog.Format(MessageDoctorConfigFound, configPath, len(configFile.Environments)),
+		})
+
+		checks = append(checks, doctorEnvFiles(configFile, catalog)...)
+		checks = append(checks, doctorOutput(configFile, configPath, outputFile, catalog), doctorToolchain(catalog))
+		checks = append(checks, doctorRemoteSources(configFile, timeout, catalog)...)
+		return nil
+	})
+	return checks, err
+}
+
+// doctorEnvFiles checks that the local env files of all environments can be read, decrypting
+// encrypted ones, and that not every user can change them
+func doctorEnvFiles(configFile *ConfigFile, catalog Catalog) []DoctorCheck {
+	var checks []DoctorCheck
+	checked := make(map[string]bool)
+	for _, envName := range configFile.environmentNames() {
+		for _, envFile := range configFile.Environments[envName].envFiles() {
+			if checked[envFile] {
+				continue
+			}
+			checked[envFile] = true
+
+			check := DoctorCheck{Environment: envName, Source: envFile, Check: DoctorEnvFile}
+			info, err := os.Stat(envFile)
+			switch {
+			case errors.Is(err, os.ErrNotExist):
+				check.Message = catalog.Format(MessageDoctorEnvFileMissing, envFile)
+				check.Hint = catalog.Format(MessageDoctorEnvFileMissingHint, envFile)
+			case err != nil:
+				check.Message = err.Error()
+				check.Hint = catalog.Format(MessageDoctorEnvFileHint, envFile)
+			case !info.Mode().IsRegular():
+				check.Message = catalog.Format(MessageDoctorNotRegularFile, envFile)
+				check.Hint = catalog.Format(MessageDoctorNotRegularFileHint)
+			case runtime.GOOS != "windows" && info.Mode().Perm()&0o002 != 0:
+				check.Message = catalog.Format(MessageDoctorWorldWritable, envFile, info.Mode().Perm())
+				check.Hint = catalog.Format(MessageDoctorWorldWritableHint, envFile)
+			default:
+				if _, err := readEnvFileContent(envFile); err != nil {
+					check.Message = strings.TrimPrefix(catalog.Error(err), "❌ ERROR: ")
+					check.Hint = catalog.Format(MessageDoctorUnreadableHint, envFile)
+					if errors.Is(err, os.ErrPermission) {
+						break
+					}
+					if content, _ := os.ReadFile(envFile); IsEncryptedEnv(content) {
+						check.Hint = catalog.Format(MessageDoctorPassphraseHint, PassphraseEnv)
+					}
+					break
+				}
+				check.OK = true
+				check.Message = catalog.Format(MessageDoctorReadable, envFile, info.Mode().Perm())
+			}
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
+
+// doctorOutput checks that the output directory is inside a Go module and holds no other package
+func doctorOutput(configFile *ConfigFile, configPath, outputFile string, catalog Catalog) DoctorCheck {
+	outputDir := filepath.Dir(outputFile)
+	check := DoctorCheck{Source: outputDir, Check: DoctorOutput}
+	if !token.IsIdentifier(configFile.PackageName) {
+		check.Message = catalog.Format(MessageDoctorInvalidPackageName, configFile.PackageName)
+		check.Hint = catalog.Format(MessageDoctorInvalidPackageHint)
+		return check
+	}
+	moduleRoot := findModuleRoot(outputDir)
+	if moduleRoot == "" {
+		check.Message = catalog.Format(MessageDoctorOutsideModule, outputDir)
+		check.Hint = catalog.Format(MessageDoctorOutsideModuleHint)
+		return check
+	}
+	if err := checkPathSafety(configFile, configPath); err != nil {
+		check.Message = strings.TrimPrefix(catalog.Error(err), "❌ ERROR: ")
+		check.Hint = catalog.Format(MessageDoctorUnsafePathsHint)
+		return check
+	}
+
+	files, _ := filepath.Glob(filepath.Join(outputDir, "*.go"))
+	for _, file := range files {
+		if filepath.Base(file) == filepath.Base(outputFile) {
+			continue
+		}
+		parsed, err := parser.ParseFile(token.NewFileSet(), file, nil, parser.PackageClauseOnly)
+		if err != nil {
+			check.Message = catalog.Format(MessageDoctorParseFailed, file, err)
+			check.Hint = catalog.Format(MessageDoctorParseFailedHint)
+			return check
+		}
+		if name := parsed.Name.Name; name != configFile.PackageName && name != configFile.PackageName+"_test" {
+			check.Message = catalog.Format(MessageDoctorOtherPackage, file, name, configFile.PackageName)
+			check.Hint = catalog.Format(MessageDoctorOtherPackageHint, name)
+			return check
+		}
+	}
+
+	check.OK = true
+	check.Message = catalog.Format(MessageDoctorPackage, configFile.PackageName, moduleRoot)
+	if _, err := os.Stat(outputDir); err != nil {
+		check.Message = catalog.Format(MessageDoctorPackageCreated, configFile.PackageName, moduleRoot)
+	}
+	return check
+}
+
+// doctorToolchain checks that the go command, which builds the generated package and runs
+// prune -analyze and vet, is installed
+func doctorToolchain(catalog Catalog) DoctorCheck {
+	check := DoctorCheck{Source: "go", Check: DoctorToolchain}
+	path, err := exec.LookPath("go")
+	if err != nil {
+		check.Message = catalog.Format(MessageDoctorNoGo)
+		check.Hint = catalog.Format(MessageDoctorNoGoHint)
+		return check
+	}
+	version, err := exec.Command(path, "env", "GOVERSION").Output()
+	if err != nil {
+		check.Message = catalog.Format(MessageDoctorGoVersionFailed, path, err)
+		check.Hint = catalog.Format(MessageDoctorGoVersionFailedHint)
+		return check
+	}
+	check.OK = true
+	check.Message = catalog.Format(MessageDoctorGoVersion, strings.TrimSpace(string(version)), path)
+	return check
+}
+
+// doctorRemoteSources checks the credentials of every remote source and reads the sources whose
+// credentials are present within timeout
+func doctorRemoteSources(configFile *ConfigFile, timeout time.Duration, catalog Catalog) []DoctorCheck {
+	var checks []DoctorCheck
+	for _, envName := range configFile.environmentNames() {
+		for _, source := range configFile.Environments[envName].doctorSources(configFile.valuesFrom) {
+			credentials, err := source.credentials(catalog)
+			if err != nil {
+				checks = append(checks, DoctorCheck{
+					Environment: envName,
+					Source:      source.describe,
+					Check:       DoctorCredentials,
+					Message:     strings.TrimPrefix(catalog.Error(err), "❌ ERROR: "),
+					Hint:        catalog.Format(MessageDoctorCredentialsHint),
+				})
+				continue
+			}
+			checks = append(checks, DoctorCheck{Environment: envName, Source: source.describe, Check: DoctorCredentials, OK: true, Message: credentials})
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
+			start := time.Now()
+			values, err := source.fetch(ctx)
+			check := DoctorCheck{Environment: envName, Source: source.describe, Check: DoctorAccess}
+			if err != nil {
+				check.Message = strings.TrimPrefix(catalog.Error(err), "❌ ERROR: ")
+				check.Hint = accessHint(ctx, catalog, source.describe, err)
+			} else {
+				check.OK = true
+				check.Message = catalog.Format(MessageDoctorRead, len(values), time.Since(start).Round(time.Millisecond))
+			}
+			cancel()
+			checks = append(checks, check)
+		}
+	}
+	return checks
+}
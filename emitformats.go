@@ -0,0 +1,83 @@
+package envied
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+)
+
+// escapeProperty escapes a key or value of a Java .properties file. Files are read as
+// ISO-8859-1 by java.util.Properties.load, so other characters are written as \uXXXX.
+func escapeProperty(s string, key bool) string {
+	var buf strings.Builder
+	for i, r := range s {
+		switch {
+		case r == '\\':
+			buf.WriteString(`\\`)
+		case r == '\n':
+			buf.WriteString(`\n`)
+		case r == '\r':
+			buf.WriteString(`\r`)
+		case r == '\t':
+			buf.WriteString(`\t`)
+		case r == '\f':
+			buf.WriteString(`\f`)
+		case r == ' ' && (key || i == 0):
+			buf.WriteString(`\ `)
+		case strings.ContainsRune("=:#!", r) && key:
+			buf.WriteByte('\\')
+			buf.WriteRune(r)
+		case r < 0x20 || r > 0x7e:
+			for _, unit := range utf16.Encode([]rune{r}) {
+				fmt.Fprintf(&buf, `\u%04X`, unit)
+			}
+		default:
+			buf.WriteRune(r)
+		}
+	}
+	return buf.String()
+}
+
+// renderProperties renders the variables of an environment as a Java .properties file
+func renderProperties(envName string, envData mergedEnvironment, names map[string]string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", strings.TrimPrefix(generatedHeader, "// "))
+	fmt.Fprintf(&buf, "# Environment: %s\n", envName)
+	for _, field := range envData.Fields {
+		fmt.Fprintln(&buf)
+		writeDescription(&buf, "# ", field.Description)
+		fmt.Fprintf(&buf, "%s=%s\n", escapeProperty(exportedName(names, field.EnvName), true), escapeProperty(field.Value, false))
+	}
+	return buf.Bytes()
+}
+
+// propertiesPath returns the path of the .properties file of an environment in the properties directory
+func propertiesPath(dir, envName string) string {
+	return filepath.Join(dir, envName+".properties")
+}
+
+// iniValue returns a value of an INI file, double-quoted with backslash escapes when it has
+// characters INI readers would strip or take for comments
+func iniValue(value string) string {
+	if value == strings.TrimSpace(value) && !strings.ContainsAny(value, "\";#\\\n\r") {
+		return value
+	}
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+	return `"` + replacer.Replace(value) + `"`
+}
+
+// renderINI renders the variables of all environments as an INI file with a section per environment
+func renderINI(data *mergedConfig, names map[string]string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "; %s\n", strings.TrimPrefix(generatedHeader, "// "))
+	for _, envName := range sortedEnvironmentNames(data.Environments) {
+		fmt.Fprintf(&buf, "\n[%s]\n", envName)
+		for _, field := range data.Environments[envName].Fields {
+			writeDescription(&buf, "; ", field.Description)
+			fmt.Fprintf(&buf, "%s = %s\n", exportedName(names, field.EnvName), iniValue(field.Value))
+		}
+	}
+	return buf.Bytes()
+}
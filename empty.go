@@ -0,0 +1,28 @@
+package envied
+
+import "sort"
+
+// checkEmptyEnvironments fails for environments without variables, which almost always means a
+// wrong env file path or a file in another format. With allow_empty_environments they are
+// reported as warnings instead.
+func checkEmptyEnvironments(configFile *ConfigFile, allEnvVars map[string]map[string]EnvValue, warnings *warningLog) error {
+	envNames := make([]string, 0, len(allEnvVars))
+	for envName, envVars := range allEnvVars {
+		if len(envVars) == 0 {
+			envNames = append(envNames, envName)
+		}
+	}
+	sort.Strings(envNames)
+
+	for _, envName := range envNames {
+		source := configFile.Environments[envName].sourceName()
+		if !configFile.AllowEmptyEnvironments {
+			return newError(MessageEmptyEnvironment, envName, source)
+		}
+		warnings.warn(Warning{
+			Code:        WarningEmptyEnvironment,
+			Environment: envName,
+		}, MessageWarningEmptyEnvironment, envName, source)
+	}
+	return nil
+}
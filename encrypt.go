@@ -0,0 +1,78 @@
+package envied
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"os"
+	"strings"
+)
+
+// encryptedArtifactMagic starts every encrypted artifact and identifies the format version
+const encryptedArtifactMagic = "ENVIED-AES256GCM-1\n"
+
+// ArtifactKey reads a base64 encoded 32-byte AES-256 key from an environment variable,
+// generate one with: openssl rand -base64 32
+func ArtifactKey(keyEnv string) ([]byte, error) {
+	encoded := strings.TrimSpace(os.Getenv(keyEnv))
+	if encoded == "" {
+		return nil, newError(MessageEncryptionKeyNotSet, keyEnv)
+	}
+	key, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil || len(key) != 32 {
+		return nil, newError(MessageInvalidEncryptionKeyEncoding, keyEnv)
+	}
+	return key, nil
+}
+
+// EncryptArtifact encrypts an emitted file with AES-256-GCM
+func EncryptArtifact(plaintext, key []byte) ([]byte, error) {
+	gcm, err := newArtifactCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	// The magic is authenticated, so the format version cannot be swapped
+	out := append([]byte(encryptedArtifactMagic), nonce...)
+	return gcm.Seal(out, nonce, plaintext, []byte(encryptedArtifactMagic)), nil
+}
+
+// DecryptArtifact decrypts a file written by EncryptArtifact
+func DecryptArtifact(data, key []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(encryptedArtifactMagic)) {
+		return nil, newError(MessageNotEncryptedArtifact)
+	}
+	gcm, err := newArtifactCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data = data[len(encryptedArtifactMagic):]
+	if len(data) < gcm.NonceSize() {
+		return nil, newError(MessageEncryptedArtifactTruncated)
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(encryptedArtifactMagic))
+	if err != nil {
+		return nil, newError(MessageDecryptArtifactFailed)
+	}
+	return plaintext, nil
+}
+
+// newArtifactCipher returns the AES-256-GCM cipher for key
+func newArtifactCipher(key []byte) (cipher.AEAD, error) {
+	if len(key) != 32 {
+		return nil, newError(MessageInvalidEncryptionKeySize, len(key))
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
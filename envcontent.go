@@ -0,0 +1,86 @@
+package envied
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxEnvLineLength is the longest accepted line of an env file, longer lines are
+// almost certainly not configuration, such as a binary or minified file read by mistake
+const maxEnvLineLength = 1 << 20
+
+// errMissingQuote reports a quoted value without its closing quote
+var errMissingQuote error = &Error{ID: MessageMissingQuote}
+
+// checkEnvFileContent rejects env files with NUL bytes, invalid UTF-8 or enormous lines,
+// which would otherwise be parsed into mangled values or misdetected types
+func checkEnvFileContent(filename string, content []byte) error {
+	for i, line := range bytes.Split(content, []byte("\n")) {
+		if len(line) > maxEnvLineLength {
+			return newError(MessageLineTooLong, filename, i+1, maxEnvLineLength)
+		}
+		if bytes.IndexByte(line, 0) >= 0 {
+			return newError(MessageNULByte, filename, i+1)
+		}
+		if !utf8.Valid(line) {
+			return newError(MessageInvalidUTF8, filename, i+1)
+		}
+	}
+	return nil
+}
+
+// utf8BOM is the byte order mark some Windows editors write at the start of UTF-8 files
+var utf8BOM = []byte("\ufeff")
+
+// normalizeEnvFileContent strips a UTF-8 byte order mark and converts Windows line endings, so
+// files saved on Windows leave neither \r in values nor a BOM in the first variable name
+func normalizeEnvFileContent(content []byte) []byte {
+	content = bytes.TrimPrefix(content, utf8BOM)
+	return bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+}
+
+// envFileLine is a logical line of an env file
+type envFileLine struct {
+	text   string // Trimmed text, the lines of a quoted value spanning lines joined with \n
+	number int    // Number of the first line
+	count  int    // Number of lines
+}
+
+// envFileLines splits an env file into logical lines. A variable whose quoted value isn't closed
+// on its line continues up to the line with the closing quote, for PEM keys and JSON blobs.
+// If the quote is never closed the line stands alone, so the error points at it.
+func envFileLines(content string) []envFileLine {
+	lines := strings.Split(content, "\n")
+	logical := make([]envFileLine, 0, len(lines))
+	for i := 0; i < len(lines); i++ {
+		line := envFileLine{text: strings.TrimSpace(lines[i]), number: i + 1, count: 1}
+		if openQuote(line.text) {
+			text := line.text
+			for j := i + 1; j < len(lines); j++ {
+				text += "\n" + lines[j]
+				if !openQuote(text) {
+					line.text, line.count = strings.TrimSpace(text), j-i+1
+					i = j
+					break
+				}
+			}
+		}
+		logical = append(logical, line)
+	}
+	return logical
+}
+
+// openQuote reports whether a variable line has a quoted value missing its closing quote
+func openQuote(text string) bool {
+	if strings.HasPrefix(text, "#") {
+		return false
+	}
+	_, value, found := strings.Cut(text, "=")
+	if !found {
+		return false
+	}
+	_, _, err := unquoteValue(value)
+	return errors.Is(err, errMissingQuote)
+}
@@ -0,0 +1,184 @@
+package envied
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"os"
+	"slices"
+	"strings"
+)
+
+// PassphraseEnv is the environment variable holding the passphrase of encrypted env files
+const PassphraseEnv = "ENVIED_PASSPHRASE"
+
+// Encrypted env file format: a comment header identifying the version followed by the base64
+// salt, nonce and AES-256-GCM ciphertext in lines of encryptedEnvLineLength, so files stay text
+const (
+	encryptedEnvMagic      = "# envied:encrypted:v1"
+	encryptedEnvHeader     = encryptedEnvMagic + " - decrypt with: envied decrypt-env\n"
+	encryptedEnvLineLength = 76
+	encryptedEnvSaltSize   = 16
+	encryptedEnvIterations = 600000 // PBKDF2-HMAC-SHA256 iterations recommended by OWASP
+)
+
+// IsEncryptedEnv reports whether content is an env file encrypted by EncryptEnv
+func IsEncryptedEnv(content []byte) bool {
+	return bytes.HasPrefix(content, []byte(encryptedEnvMagic))
+}
+
+// EncryptEnv encrypts the content of an env file with a key derived from passphrase, so the file
+// can be committed. Every call uses a new salt and nonce, so the output differs each time.
+func EncryptEnv(content []byte, passphrase string) ([]byte, error) {
+	if passphrase == "" {
+		return nil, newError(MessagePassphraseEmpty)
+	}
+	salt := make([]byte, encryptedEnvSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := newEnvCipher(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	payload := append(append(salt, nonce...), gcm.Seal(nil, nonce, content, []byte(encryptedEnvMagic))...)
+	encoded := base64.StdEncoding.EncodeToString(payload)
+	var out bytes.Buffer
+	out.WriteString(encryptedEnvHeader)
+	for len(encoded) > 0 {
+		line := encoded[:min(len(encoded), encryptedEnvLineLength)]
+		encoded = encoded[len(line):]
+		out.WriteString(line + "\n")
+	}
+	return out.Bytes(), nil
+}
+
+// DecryptEnv decrypts an env file encrypted by EncryptEnv
+func DecryptEnv(content []byte, passphrase string) ([]byte, error) {
+	if !IsEncryptedEnv(content) {
+		return nil, newError(MessageNotEncryptedEnvFile)
+	}
+	_, body, _ := bytes.Cut(content, []byte("\n"))
+	payload, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(string(body)), ""))
+	if err != nil || len(payload) < encryptedEnvSaltSize {
+		return nil, newError(MessageEncryptedEnvFileCorrupted)
+	}
+	gcm, err := newEnvCipher(passphrase, payload[:encryptedEnvSaltSize])
+	if err != nil {
+		return nil, err
+	}
+	payload = payload[encryptedEnvSaltSize:]
+	if len(payload) < gcm.NonceSize() {
+		return nil, newError(MessageEncryptedEnvFileTruncated)
+	}
+	plaintext, err := gcm.Open(nil, payload[:gcm.NonceSize()], payload[gcm.NonceSize():], []byte(encryptedEnvMagic))
+	if err != nil {
+		return nil, newError(MessageDecryptEnvFileFailed)
+	}
+	return plaintext, nil
+}
+
+// newEnvCipher returns the AES-256-GCM cipher of a passphrase and salt
+func newEnvCipher(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, encryptedEnvIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// readEnvFileContent reads an env file, decrypting it with the passphrase in PassphraseEnv if it
+// is encrypted, so all readers and generation handle encrypted files transparently
+func readEnvFileContent(filename string) ([]byte, error) {
+	content, _, err := readEnvFileDecrypted(filename)
+	return content, err
+}
+
+// readEnvFileDecrypted is readEnvFileContent also reporting whether the file is encrypted
+func readEnvFileDecrypted(filename string) ([]byte, bool, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil || !IsEncryptedEnv(content) {
+		return content, false, err
+	}
+	passphrase := os.Getenv(PassphraseEnv)
+	if passphrase == "" {
+		return nil, true, newError(MessagePassphraseNotSet, filename, PassphraseEnv)
+	}
+	content, err = DecryptEnv(content, passphrase)
+	if err != nil {
+		return nil, true, newError(MessageFileError, filename, err)
+	}
+	return content, true, nil
+}
+
+// EncryptEnvFile encrypts an env file in place, see EncryptEnv. It returns false without
+// changing files that are already encrypted.
+func EncryptEnvFile(filename, passphrase string) (bool, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil || IsEncryptedEnv(content) {
+		return false, err
+	}
+	encrypted, err := EncryptEnv(content, passphrase)
+	if err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(filename, encrypted, 0644)
+}
+
+// DecryptEnvFile decrypts an env file encrypted by EncryptEnvFile in place. It returns false
+// without changing files that aren't encrypted.
+func DecryptEnvFile(filename, passphrase string) (bool, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil || !IsEncryptedEnv(content) {
+		return false, err
+	}
+	plaintext, err := DecryptEnv(content, passphrase)
+	if err != nil {
+		return false, newError(MessageFileError, filename, err)
+	}
+	return true, os.WriteFile(filename, plaintext, 0644)
+}
+
+// writeEnvFileContent writes an env file read by readEnvFileContent, encrypted with the
+// passphrase in PassphraseEnv if the file it was read from is encrypted
+func writeEnvFileContent(filename string, content []byte, encrypted bool) error {
+	if encrypted {
+		var err error
+		if content, err = EncryptEnv(content, os.Getenv(PassphraseEnv)); err != nil {
+			return err
+		}
+	}
+	return os.WriteFile(filename, content, 0644)
+}
+
+// ConfigEnvFiles returns the local env files of all environments of a configuration sorted and
+// without duplicates, the files encrypt-env and decrypt-env change by default
+func ConfigEnvFiles(configFilePath string) ([]string, error) {
+	configFile, err := LoadConfigFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+	var files []string
+	for _, envConfig := range configFile.Environments {
+		for _, envFile := range envConfig.envFiles() {
+			if !slices.Contains(files, envFile) {
+				files = append(files, envFile)
+			}
+		}
+	}
+	slices.Sort(files)
+	return files, nil
+}
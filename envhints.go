@@ -0,0 +1,79 @@
+package envied
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// envHintsPrefix starts a comment annotating the variable on the next line
+const envHintsPrefix = "envied:"
+
+// EnvHints holds the settings of a variable annotated in its env file with a comment
+// such as "# envied: type=string, sensitive=true" on the line above it
+type EnvHints struct {
+	Type      string // Declared type, as the type of a variable in the configuration
+	Sensitive *bool  // Forces obfuscation on (true) or off (false) regardless of the environment
+}
+
+// parseEnvHints parses a comment line, ok is false for comments that are not hints
+func parseEnvHints(comment string) (hints EnvHints, ok bool, err error) {
+	text := strings.TrimSpace(strings.TrimPrefix(comment, "#"))
+	if !strings.HasPrefix(text, envHintsPrefix) {
+		return EnvHints{}, false, nil
+	}
+
+	for _, setting := range strings.Split(strings.TrimPrefix(text, envHintsPrefix), ",") {
+		key, value, found := strings.Cut(setting, "=")
+		key, value = strings.TrimSpace(key), strings.TrimSpace(value)
+		if !found || value == "" {
+			return EnvHints{}, true, newError(MessageInvalidHint, strings.TrimSpace(setting))
+		}
+
+		switch key {
+		case "type":
+			if !isDeclaredType(FieldType(value)) {
+				return EnvHints{}, true, newError(MessageUnsupportedHintType, value)
+			}
+			hints.Type = value
+		case "sensitive":
+			sensitive, err := strconv.ParseBool(value)
+			if err != nil {
+				return EnvHints{}, true, newError(MessageInvalidSensitiveHint, value)
+			}
+			hints.Sensitive = &sensitive
+		default:
+			return EnvHints{}, true, newError(MessageUnknownHint, key)
+		}
+	}
+	return hints, true, nil
+}
+
+// applyEnvHints returns the variable settings of an environment with the types hinted in its
+// env file. A hinted type can't differ from the type declared in the configuration.
+func applyEnvHints(variables map[string]VariableConfig, envVars map[string]EnvValue) (map[string]VariableConfig, error) {
+	merged := make(map[string]VariableConfig, len(variables))
+	for name, variable := range variables {
+		merged[name] = variable
+	}
+
+	names := make([]string, 0, len(envVars))
+	for name := range envVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		hinted := envVars[name].Hints.Type
+		if hinted == "" {
+			continue
+		}
+		variable := merged[name]
+		if variable.Type != "" && variable.Type != hinted {
+			return nil, newError(MessageHintTypeConflict, name, hinted, variable.Type)
+		}
+		variable.Type = hinted
+		merged[name] = variable
+	}
+	return merged, nil
+}
@@ -0,0 +1,21 @@
+package envied
+
+import "strings"
+
+// ErrUnknownEnvironment is returned by generated factories when the requested
+// environment name does not match any configured environment
+type ErrUnknownEnvironment struct {
+	Name  string   // Requested environment name
+	Valid []string // Names of the generated environments
+}
+
+// Error implements the error interface
+func (e *ErrUnknownEnvironment) Error() string {
+	id, args := e.message()
+	return DefaultCatalog.Format(id, args...)
+}
+
+// message returns the message ID and arguments of the error
+func (e *ErrUnknownEnvironment) message() (MessageID, []any) {
+	return MessageUnknownEnvironment, []any{e.Name, strings.Join(e.Valid, ", ")}
+}
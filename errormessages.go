@@ -0,0 +1,683 @@
+package envied
+
+// Error messages, with the arguments of their format. Errors among the arguments are wrapped by
+// Error and formatted with %v.
+const (
+	MessageInvalidNolintLinter                MessageID = "invalid_nolint_linter"                  // linter
+	MessageInvalidAnnotation                  MessageID = "invalid_annotation"                     // annotation
+	MessageFailedReadBaseEnvFile              MessageID = "failed_read_base_env_file"              // file, error
+	MessageFailedHashBaseEnvFile              MessageID = "failed_hash_base_env_file"              // file, error
+	MessageBatchTarget                        MessageID = "batch_target"                           // target, error
+	MessageInvalidBuildTagPrefix              MessageID = "invalid_build_tag_prefix"               // prefix
+	MessageInvalidBuildTagEnvironment         MessageID = "invalid_build_tag_environment"          // environment
+	MessageReservedBuildTag                   MessageID = "reserved_build_tag"                     // tag, environment
+	MessageSplitEnvironmentsRequiresBuildTags MessageID = "split_environments_requires_build_tags" // build tag prefix
+	MessageSplitWithInternalPackage           MessageID = "split_with_internal_package"            // no arguments
+	MessageFailedWrite                        MessageID = "failed_write"                           // file, error
+	MessageUnknownEncoding                    MessageID = "unknown_encoding"                       // encoding, supported encodings
+	MessageInvalidEncodedValue                MessageID = "invalid_encoded_value"                  // variable, encoding
+	MessageOutdatedMissingFile                MessageID = "outdated_missing_file"                  // ErrOutdated, file
+	MessageOutdatedDiffers                    MessageID = "outdated_differs"                       // ErrOutdated, file, line, expected line, found line, hint
+	MessageEnvironmentExists                  MessageID = "environment_exists"                     // environment
+	MessageCloneRemoteSource                  MessageID = "clone_remote_source"                    // source environment, environment
+	MessageCloneStructNameUsed                MessageID = "clone_struct_name_used"                 // struct name, environment, other environment
+	MessageAlreadyExists                      MessageID = "already_exists"                         // file
+	MessageCloneValuesOnlyCopied              MessageID = "clone_values_only_copied"               // source environment, source
+	MessageEnabledForUnknownEnvironment       MessageID = "enabled_for_unknown_environment"        // variable, environment
+	MessageVariableMissing                    MessageID = "variable_missing"                       // variable, environment
+	MessageAtPath                             MessageID = "at_path"                                // path, error
+	MessageTabIndentationAtLine               MessageID = "tab_indentation_at_line"                // line
+	MessageUnexpectedIndentationAtLine        MessageID = "unexpected_indentation_at_line"         // line
+	MessageExpectedMapping                    MessageID = "expected_mapping"                       // line
+	MessageNestedSequencesNotSupported        MessageID = "nested_sequences_not_supported"         // line
+	MessageSequencesOfMappingsNotSupported    MessageID = "sequences_of_mappings_not_supported"    // line
+	MessageAtLine                             MessageID = "at_line"                                // line, error
+	MessageUnexpectedSequenceItem             MessageID = "unexpected_sequence_item"               // line
+	MessageKeyAlreadyDefinedAtLine            MessageID = "key_already_defined_at_line"            // line, key
+	MessageKeyErrorAtLine                     MessageID = "key_error_at_line"                      // line, key, error
+	MessageUnexpectedAfterFlowCollection      MessageID = "unexpected_after_flow_collection"       // unexpected text
+	MessageUnterminatedFlowCollection         MessageID = "unterminated_flow_collection"           // no arguments
+	MessageExpectedColonAfterKey              MessageID = "expected_colon_after_key"               // key
+	MessageEmptyFlowCollectionEntry           MessageID = "empty_flow_collection_entry"            // no arguments
+	MessageArraysOfTablesAtLine               MessageID = "arrays_of_tables_at_line"               // line
+	MessageUnterminatedTableNameAtLine        MessageID = "unterminated_table_name_at_line"        // line
+	MessageExpectedTOMLKeyValueAtLine         MessageID = "expected_toml_key_value_at_line"        // line
+	MessageUnexpectedAfterValue               MessageID = "unexpected_after_value"                 // line, key, unexpected text
+	MessageKeyNotTable                        MessageID = "key_not_table"                          // key
+	MessageMissingValue                       MessageID = "missing_value"                          // no arguments
+	MessageMultiLineStringsNotSupported       MessageID = "multi_line_strings_not_supported"       // no arguments
+	MessageUnterminatedLiteralString          MessageID = "unterminated_literal_string"            // no arguments
+	MessageExpectedArraySeparator             MessageID = "expected_array_separator"               // no arguments
+	MessageExpectedInlineTableEquals          MessageID = "expected_inline_table_equals"           // no arguments
+	MessageExpectedInlineTableSeparator       MessageID = "expected_inline_table_separator"        // no arguments
+	MessageInvalidNumber                      MessageID = "invalid_number"                         // number
+	MessageInvalidUnquotedValue               MessageID = "invalid_unquoted_value"                 // value
+	MessageDuplicateFieldName                 MessageID = "duplicate_field_name"                   // variable, other variable, field name
+	MessageDuplicateDeclaration               MessageID = "duplicate_declaration"                  // origin, other origin, name
+	MessageUnknownConflictStrategy            MessageID = "unknown_conflict_strategy"              // strategy, supported strategies
+	MessagePackageConflicts                   MessageID = "package_conflicts"                      // conflicting names, rename strategy
+	MessageFailedParse                        MessageID = "failed_parse"                           // file, error
+	MessageFailedQuery                        MessageID = "failed_query"                           // source, error
+	MessageQueryStatus                        MessageID = "query_status"                           // source, status, response body
+	MessageInvalidResponse                    MessageID = "invalid_response"                       // source, error
+	MessageSourceDateEpochRequired            MessageID = "source_date_epoch_required"             // no arguments
+	MessageNotDeterministic                   MessageID = "not_deterministic"                      // line, line of run 1, line of run 2
+	MessageFailedRenderManifest               MessageID = "failed_render_manifest"                 // error
+	MessageFailedEncrypt                      MessageID = "failed_encrypt"                         // file, error
+	MessageFailedCreateDirectory              MessageID = "failed_create_directory"                // file, error
+	MessageDoctorSQLDriverNotRegistered       MessageID = "doctor_sql_driver_not_registered"       // driver
+	MessageHoldingVariableNotSet              MessageID = "holding_variable_not_set"               // environment variable, what it holds
+	MessageEncryptionKeyNotSet                MessageID = "encryption_key_not_set"                 // environment variable
+	MessageInvalidEncryptionKeyEncoding       MessageID = "invalid_encryption_key_encoding"        // environment variable
+	MessageNotEncryptedArtifact               MessageID = "not_encrypted_artifact"                 // no arguments
+	MessageEncryptedArtifactTruncated         MessageID = "encrypted_artifact_truncated"           // no arguments
+	MessageDecryptArtifactFailed              MessageID = "decrypt_artifact_failed"                // no arguments
+	MessageInvalidEncryptionKeySize           MessageID = "invalid_encryption_key_size"            // key size
+	MessageLineTooLong                        MessageID = "line_too_long"                          // file, line, maximum length
+	MessageNULByte                            MessageID = "nul_byte"                               // file, line
+	MessageInvalidUTF8                        MessageID = "invalid_utf8"                           // file, line
+	MessagePassphraseEmpty                    MessageID = "passphrase_empty"                       // no arguments
+	MessageNotEncryptedEnvFile                MessageID = "not_encrypted_env_file"                 // no arguments
+	MessageEncryptedEnvFileCorrupted          MessageID = "encrypted_env_file_corrupted"           // no arguments
+	MessageEncryptedEnvFileTruncated          MessageID = "encrypted_env_file_truncated"           // no arguments
+	MessageDecryptEnvFileFailed               MessageID = "decrypt_env_file_failed"                // no arguments
+	MessagePassphraseNotSet                   MessageID = "passphrase_not_set"                     // file, environment variable
+	MessageFileError                          MessageID = "file_error"                             // file, error
+	MessageInvalidHint                        MessageID = "invalid_hint"                           // hint
+	MessageUnsupportedHintType                MessageID = "unsupported_hint_type"                  // type
+	MessageInvalidSensitiveHint               MessageID = "invalid_sensitive_hint"                 // value
+	MessageUnknownHint                        MessageID = "unknown_hint"                           // hint
+	MessageHintTypeConflict                   MessageID = "hint_type_conflict"                     // variable, hinted type, configured type
+	MessageFailedReadEtcdCACertificate        MessageID = "failed_read_etcd_ca_certificate"        // error
+	MessageNoCertificatesFound                MessageID = "no_certificates_found"                  // file
+	MessageFailedLoadEtcdClientCertificate    MessageID = "failed_load_etcd_client_certificate"    // error
+	MessageRequestFailed                      MessageID = "request_failed"                         // method, endpoint, status, response body
+	MessageErrorCause                         MessageID = "error_cause"                            // error, cause
+	MessageFailedReach                        MessageID = "failed_reach"                           // source, error
+	MessageWatchFailed                        MessageID = "watch_failed"                           // source, reason
+	MessageUndefinedVariable                  MessageID = "undefined_variable"                     // variable
+	MessageFieldTypeConflict                  MessageID = "field_type_conflict"                    // variable, fields type, variables type
+	MessageUnknownCryptoMode                  MessageID = "unknown_crypto_mode"                    // mode, supported mode
+	MessageCryptoModeForbidsObfuscation       MessageID = "crypto_mode_forbids_obfuscation"        // crypto mode, obfuscation, supported obfuscations
+	MessageUnknownObfuscationMode             MessageID = "unknown_obfuscation_mode"               // mode, supported modes
+	MessageRuntimeSealedValueTruncated        MessageID = "runtime_sealed_value_truncated"         // variable
+	MessageRuntimeSealedValueOpenFailed       MessageID = "runtime_sealed_value_open_failed"       // variable
+	MessageUnknownGetterReceiver              MessageID = "unknown_getter_receiver"                // receiver, supported receivers
+	MessageInvalidGetterName                  MessageID = "invalid_getter_name"                    // variable, getter
+	MessageGetterCollidesWithField            MessageID = "getter_collides_with_field"             // variable, getter, field
+	MessageGetterCollidesWithMethod           MessageID = "getter_collides_with_method"            // variable, getter
+	MessageDuplicateGetter                    MessageID = "duplicate_getter"                       // variable, other variable, getter
+	MessageInvalidGeneratedCodeLine           MessageID = "invalid_generated_code_line"            // error, generated line
+	MessageInvalidGeneratedCode               MessageID = "invalid_generated_code"                 // error
+	MessageNoNetworkRemoteSource              MessageID = "no_network_remote_source"               // environment, source
+	MessageNoNetworkVault                     MessageID = "no_network_vault"                       // environment
+	MessageEnvFileUnknownEnvironment          MessageID = "env_file_unknown_environment"           // environment
+	MessageHermeticConfigPathRequired         MessageID = "hermetic_config_path_required"          // no arguments
+	MessageHermeticOutputFileRequired         MessageID = "hermetic_output_file_required"          // no arguments
+	MessageHermeticInternalPackage            MessageID = "hermetic_internal_package"              // no arguments
+	MessageHermeticBuildTags                  MessageID = "hermetic_build_tags"                    // no arguments
+	MessageHermeticRemoteSource               MessageID = "hermetic_remote_source"                 // environment, sources
+	MessageHermeticVault                      MessageID = "hermetic_vault"                         // environment
+	MessageGoModWithoutModulePath             MessageID = "go_mod_without_module_path"             // module directory
+	MessageInternalPackageOutsideModule       MessageID = "internal_package_outside_module"        // output directory
+	MessageInternalPackageOutputDir           MessageID = "internal_package_output_dir"            // output directory
+	MessageFailedCreateOutputDirectory        MessageID = "failed_create_output_directory"         // error
+	MessageFailedCreateOutputFile             MessageID = "failed_create_output_file"              // error
+	MessageReferenceCycle                     MessageID = "reference_cycle"                        // cycle, variable
+	MessageUndefinedReference                 MessageID = "undefined_reference"                    // variable, reference
+	MessageUnterminatedJSONComment            MessageID = "unterminated_json_comment"              // line
+	MessageExpectedJSONObject                 MessageID = "expected_json_object"                   // file, line
+	MessageAtFileLine                         MessageID = "at_file_line"                           // file, line, error
+	MessageKeyError                           MessageID = "key_error"                              // file, line, key, error
+	MessageNestedJSONValue                    MessageID = "nested_json_value"                      // file, line, key
+	MessageKeyAlreadyDefined                  MessageID = "key_already_defined"                    // file, line, key, line of the definition
+	MessageUnexpectedDataAfterJSON            MessageID = "unexpected_data_after_json"             // file, line
+	MessageInvalidJSONValue                   MessageID = "invalid_json_value"                     // variable, error
+	MessageFailedReadEnvFile                  MessageID = "failed_read_env_file"                   // file, error
+	MessageInvalidIntValue                    MessageID = "invalid_int_value"                      // variable, value
+	MessageInvalidInt64Value                  MessageID = "invalid_int64_value"                    // variable, value
+	MessageInvalidUint64Value                 MessageID = "invalid_uint64_value"                   // variable, value
+	MessageInvalidBoolValue                   MessageID = "invalid_bool_value"                     // variable, value
+	MessageInvalidFloat64Value                MessageID = "invalid_float64_value"                  // variable, value
+	MessageRuntimeKeyCountMismatch            MessageID = "runtime_key_count_mismatch"             // key count, value count
+	MessageUnexpectedAfterClosingQuote        MessageID = "unexpected_after_closing_quote"         // unexpected text
+	MessageEnvFileValueError                  MessageID = "env_file_value_error"                   // file, line, key, error
+	MessageFailedReadConfigFile               MessageID = "failed_read_config_file"                // file, error
+	MessageFailedParseConfigFile              MessageID = "failed_parse_config_file"               // file, error
+	MessageInvalidConfigFile                  MessageID = "invalid_config_file"                    // file, problems
+	MessageFailedEncodeConfigFile             MessageID = "failed_encode_config_file"              // file, error
+	MessageFailedWriteConfigFile              MessageID = "failed_write_config_file"               // file, error
+	MessageConfigNotJSON                      MessageID = "config_not_json"                        // file
+	MessageConfigHasComments                  MessageID = "config_has_comments"                    // file
+	MessageFailedGenerateMergedConfiguration  MessageID = "failed_generate_merged_configuration"   // error
+	MessageFailedGenerateBuildTagFiles        MessageID = "failed_generate_build_tag_files"        // error
+	MessageFailedGenerateReExportShim         MessageID = "failed_generate_re_export_shim"         // error
+	MessageEnvironmentError                   MessageID = "environment_error"                      // environment, error
+	MessageConsistencyCheckFailed             MessageID = "consistency_check_failed"               // error
+	MessageAllEnvironmentsFromVault           MessageID = "all_environments_from_vault"            // no arguments
+	MessageFailedObfuscateField               MessageID = "failed_obfuscate_field"                 // variable, error
+	MessageConfigNotFound                     MessageID = "config_not_found"                       // file
+	MessageEnvVariableEmpty                   MessageID = "env_variable_empty"                     // environment variable
+	MessageEnvVariableNotFound                MessageID = "env_variable_not_found"                 // environment variable
+	MessageFailedParseTemplate                MessageID = "failed_parse_template"                  // error
+	MessageManifestServerToken                MessageID = "manifest_server_token"                  // no arguments
+	MessageNoManifests                        MessageID = "no_manifests"                           // no arguments
+	MessageManifestUnreadable                 MessageID = "manifest_unreadable"                    // service
+	MessageFailedExecuteTemplate              MessageID = "failed_execute_template"                // error
+	MessageFailedExecuteEnvironmentTemplate   MessageID = "failed_execute_environment_template"    // error
+	MessageUnknownNamingStrategy              MessageID = "unknown_naming_strategy"                // strategy, supported strategies
+	MessageUnknownSanitizeStrategy            MessageID = "unknown_sanitize_strategy"              // strategy, supported strategies
+	MessageInvalidFieldName                   MessageID = "invalid_field_name"                     // variable, field name
+	MessageFieldCollidesWithMethod            MessageID = "field_collides_with_method"             // variable, field name
+	MessageInvalidConstantSuffix              MessageID = "invalid_constant_suffix"                // variable, suffix
+	MessageDuplicateConstantSuffix            MessageID = "duplicate_constant_suffix"              // variable, other variable, suffix
+	MessageInvalidFileName                    MessageID = "invalid_file_name"                      // environment, file name
+	MessageDuplicateFileName                  MessageID = "duplicate_file_name"                    // environment, other environment, file name
+	MessageUnknownNameValidationMode          MessageID = "unknown_name_validation_mode"           // mode, supported modes
+	MessageInvalidNamePattern                 MessageID = "invalid_name_pattern"                   // pattern, error
+	MessageNameMismatchesPattern              MessageID = "name_mismatches_pattern"                // variable, environment, pattern
+	MessageConfusableNames                    MessageID = "confusable_names"                       // variables
+	MessageHooksRemovedEnvironments           MessageID = "hooks_removed_environments"             // stage
+	MessageHookUnknownEnvironment             MessageID = "hook_unknown_environment"               // stage, environment
+	MessageUnknownPipelineStage               MessageID = "unknown_pipeline_stage"                 // stage
+	MessageStageFailed                        MessageID = "stage_failed"                           // stage, error
+	MessagePolicyWithoutPattern               MessageID = "policy_without_pattern"                 // policy
+	MessageInvalidPolicyPattern               MessageID = "invalid_policy_pattern"                 // policy, pattern
+	MessageInvalidPolicyEnvironmentPattern    MessageID = "invalid_policy_environment_pattern"     // policy, pattern
+	MessagePolicyMatchesNoEnvironment         MessageID = "policy_matches_no_environment"          // pattern, policy
+	MessageProfileStructNameUsed              MessageID = "profile_struct_name_used"               // profile, struct name, other profile
+	MessageProfileWithoutVariables            MessageID = "profile_without_variables"              // profile
+	MessageProfileDuplicateVariable           MessageID = "profile_duplicate_variable"             // profile, variable
+	MessageProfileUnknownVariable             MessageID = "profile_unknown_variable"               // profile, variable
+	MessageFailedReadOverlay                  MessageID = "failed_read_overlay"                    // file, error
+	MessageFailedHashOverlay                  MessageID = "failed_hash_overlay"                    // file, error
+	MessageFailedHashEnvFile                  MessageID = "failed_hash_env_file"                   // file, error
+	MessageGoListFailed                       MessageID = "go_list_failed"                         // patterns, error, output
+	MessageFailedParseGoListOutput            MessageID = "failed_parse_go_list_output"            // error
+	MessageGoListPackageError                 MessageID = "go_list_package_error"                  // package, error
+	MessageGoListExportFailed                 MessageID = "go_list_export_failed"                  // packages, error, output
+	MessageNoExportData                       MessageID = "no_export_data"                         // package
+	MessagePruneRemoteSource                  MessageID = "prune_remote_source"                    // environment
+	MessagePruneYAMLFile                      MessageID = "prune_yaml_file"                        // environment, file
+	MessagePruneTOMLFile                      MessageID = "prune_toml_file"                        // environment, file
+	MessagePruneJSONFile                      MessageID = "prune_json_file"                        // environment, file
+	MessageFailedPrune                        MessageID = "failed_prune"                           // file, error
+	MessageInvalidRedisAddress                MessageID = "invalid_redis_address"                  // address, error
+	MessageFailedReadRedisCACertificate       MessageID = "failed_read_redis_ca_certificate"       // error
+	MessageFailedConnect                      MessageID = "failed_connect"                         // source, error
+	MessageTLSHandshakeFailed                 MessageID = "tls_handshake_failed"                   // source, error
+	MessageAuthenticationFailed               MessageID = "authentication_failed"                  // source, error
+	MessageFailedSelectDatabase               MessageID = "failed_select_database"                 // source, error
+	MessageInvalidRedisReply                  MessageID = "invalid_redis_reply"                    // no arguments
+	MessageInvalidRedisReplyLine              MessageID = "invalid_redis_reply_line"               // reply
+	MessageFailedRead                         MessageID = "failed_read"                            // source, error
+	MessageInvalidHGETALLReply                MessageID = "invalid_hgetall_reply"                  // source
+	MessageManifestKeyMissing                 MessageID = "manifest_key_missing"                   // no arguments
+	MessageManifestNotDecrypted               MessageID = "manifest_not_decrypted"                 // no arguments
+	MessageNotManifest                        MessageID = "not_manifest"                           // no arguments
+	MessageGitShowFailed                      MessageID = "git_show_failed"                        // revision, file, error, output
+	MessageReleaseFileError                   MessageID = "release_file_error"                     // file, revision, error
+	MessageUnknownReportFormat                MessageID = "unknown_report_format"                  // format, supported formats
+	MessageRenameUnknownEmitter               MessageID = "rename_unknown_emitter"                 // emitter, supported emitters
+	MessageRenameUnknownVariable              MessageID = "rename_unknown_variable"                // emitter, variable
+	MessageRenameInvalidName                  MessageID = "rename_invalid_name"                    // emitter, name, variable
+	MessageRenameDuplicateName                MessageID = "rename_duplicate_name"                  // emitter, variable, other variable, name
+	MessageFailedResolveOutputDirectory       MessageID = "failed_resolve_output_directory"        // directory, error
+	MessageFailedResolveEnvFile               MessageID = "failed_resolve_env_file"                // file, error
+	MessageEnvFileInsideOutputDir             MessageID = "env_file_inside_output_dir"             // file, environment, output directory
+	MessageOutputDirOutsideModule             MessageID = "output_dir_outside_module"              // output directory, module directory
+	MessageSealedSecretsCertRequired          MessageID = "sealed_secrets_cert_required"           // no arguments
+	MessageFailedReadSealedSecretsCert        MessageID = "failed_read_sealed_secrets_cert"        // error
+	MessageNotPEMFile                         MessageID = "not_pem_file"                           // file
+	MessageInvalidCertificate                 MessageID = "invalid_certificate"                    // file, error
+	MessageInvalidPublicKey                   MessageID = "invalid_public_key"                     // file, error
+	MessageUnexpectedPEMBlock                 MessageID = "unexpected_pem_block"                   // file, block type
+	MessageNotRSAPublicKey                    MessageID = "not_rsa_public_key"                     // file
+	MessageFailedSeal                         MessageID = "failed_seal"                            // variable, error
+	MessageInvalidRandomSeed                  MessageID = "invalid_random_seed"                    // seed, error
+	MessageRandomSeedOutRange                 MessageID = "random_seed_out_range"                  // seed, minimum, maximum
+	MessageRandomSeedNotInteger               MessageID = "random_seed_not_integer"                // seed
+	MessageNegativeSizeBudget                 MessageID = "negative_size_budget"                   // no arguments
+	MessageVariableOverSizeLimit              MessageID = "variable_over_size_limit"               // variable, environment, size, limit
+	MessageEnvironmentOverSizeBudget          MessageID = "environment_over_size_budget"           // environment, size, budget, largest variables
+	MessageInvalidSourceTimeout               MessageID = "invalid_source_timeout"                 // timeout
+	MessageSourceTimeout                      MessageID = "source_timeout"                         // error, timeout
+	MessageEnvFileAndSource                   MessageID = "env_file_and_source"                    // sources
+	MessageEnvFileNotSet                      MessageID = "env_file_not_set"                       // no arguments
+	MessageDSNAndDSNEnv                       MessageID = "dsn_and_dsn_env"                        // no arguments
+	MessageDSNMissing                         MessageID = "dsn_missing"                            // no arguments
+	MessageDSNEnvNotSet                       MessageID = "dsn_env_not_set"                        // environment variable
+	MessageSQLDriverNotRegistered             MessageID = "sql_driver_not_registered"              // driver, registered drivers
+	MessageSQLQueryNotSet                     MessageID = "sql_query_not_set"                      // no arguments
+	MessageFailedOpen                         MessageID = "failed_open"                            // source, error
+	MessageSQLQueryColumns                    MessageID = "sql_query_columns"                      // column count
+	MessageFailedReadRow                      MessageID = "failed_read_row"                        // source, error
+	MessageSQLDuplicateVariable               MessageID = "sql_duplicate_variable"                 // variable
+	MessageSeparatorAndTimeLayout             MessageID = "separator_and_time_layout"              // variable
+	MessageRuntimeObfuscatedListMismatch      MessageID = "runtime_obfuscated_list_mismatch"       // key count, value count
+	MessageRuntimeSealedListMismatch          MessageID = "runtime_sealed_list_mismatch"           // variable, key count, value count
+	MessageUndefinedSubstitution              MessageID = "undefined_substitution"                 // variable, substitution
+	MessageTimeLayoutMismatch                 MessageID = "time_layout_mismatch"                   // variable, value, layout
+	MessageInvalidTimeValue                   MessageID = "invalid_time_value"                     // variable, value, layout
+	MessageArraysOfTables                     MessageID = "arrays_of_tables"                       // file, line
+	MessageUnterminatedTableName              MessageID = "unterminated_table_name"                // file, line
+	MessageTableAlreadyDefined                MessageID = "table_already_defined"                  // file, line, table, line of the definition
+	MessageExpectedTOMLKeyValue               MessageID = "expected_toml_key_value"                // file, line
+	MessageExpectedKey                        MessageID = "expected_key"                           // no arguments
+	MessageUnexpectedInKey                    MessageID = "unexpected_in_key"                      // unexpected text
+	MessageUnexpectedAfterString              MessageID = "unexpected_after_string"                // unexpected text
+	MessageTOMLArraysNotSupported             MessageID = "toml_arrays_not_supported"              // no arguments
+	MessageInlineTablesNotSupported           MessageID = "inline_tables_not_supported"            // no arguments
+	MessageInvalidBase64                      MessageID = "invalid_base64"                         // error
+	MessageUnknownTransform                   MessageID = "unknown_transform"                      // transform
+	MessageTransformFailed                    MessageID = "transform_failed"                       // transform, error
+	MessageTransformVariableError             MessageID = "transform_variable_error"               // variable, error
+	MessageValueNotBool                       MessageID = "value_not_bool"                         // value
+	MessageValueNotInt                        MessageID = "value_not_int"                          // value
+	MessageValueNotFloat64                    MessageID = "value_not_float64"                      // value
+	MessageValueNotValid                      MessageID = "value_not_valid"                        // encoding
+	MessageValueNotInt64                      MessageID = "value_not_int64"                        // value
+	MessageValueNotUint64                     MessageID = "value_not_uint64"                       // value
+	MessageValueNotJSONObject                 MessageID = "value_not_json_object"                  // type, error
+	MessageEncodingRequiresType               MessageID = "encoding_requires_type"                 // variable, type
+	MessageUnsupportedVariableType            MessageID = "unsupported_variable_type"              // variable, type, supported types
+	MessageTypeWithSeparator                  MessageID = "type_with_separator"                    // variable
+	MessageVariableError                      MessageID = "variable_error"                         // variable, error
+	MessageIntOverflows32Bit                  MessageID = "int_overflows32_bit"                    // variable, value
+	MessageIntOverflowsInt64                  MessageID = "int_overflows_int64"                    // variable, value
+	MessageIntOverflows64Bit                  MessageID = "int_overflows64_bit"                    // variable, value
+	MessageUnknownUnsafeCharactersPolicy      MessageID = "unknown_unsafe_characters_policy"       // policy, supported policies
+	MessageUnsafeCharacters                   MessageID = "unsafe_characters"                      // environment, variable, characters, byte offset, escape policy
+	MessageVaultPathNotSet                    MessageID = "vault_path_not_set"                     // no arguments
+	MessageVaultTokenNotSet                   MessageID = "vault_token_not_set"                    // environment variable, source
+	MessageUnknownValuesSource                MessageID = "unknown_values_source"                  // source, supported source
+	MessageVaultUndeclaredVariable            MessageID = "vault_undeclared_variable"              // source, variable, file
+	MessageVerifyFailedParse                  MessageID = "verify_failed_parse"                    // file, error
+	MessageVerifyEnvironmentError             MessageID = "verify_environment_error"               // environment, error
+	MessageVerifyStale                        MessageID = "verify_stale"                           // ErrOutdated, file, differences
+	MessageTabIndentation                     MessageID = "tab_indentation"                        // file, line
+	MessageSequencesNotSupported              MessageID = "sequences_not_supported"                // file, line
+	MessageUnexpectedIndentation              MessageID = "unexpected_indentation"                 // file, line
+	MessageExpectedYAMLKeyValue               MessageID = "expected_yaml_key_value"                // no arguments
+	MessageUnexpectedAfterQuotedValue         MessageID = "unexpected_after_quoted_value"          // unexpected text
+	MessageFlowCollectionsNotSupported        MessageID = "flow_collections_not_supported"         // no arguments
+	MessageBlockScalarsNotSupported           MessageID = "block_scalars_not_supported"            // no arguments
+	MessageAnchorsNotSupported                MessageID = "anchors_not_supported"                  // no arguments
+	MessageUnterminatedSingleQuotedString     MessageID = "unterminated_single_quoted_string"      // no arguments
+	MessageInvalidDoubleQuotedString          MessageID = "invalid_double_quoted_string"           // string
+	MessageUnterminatedDoubleQuotedString     MessageID = "unterminated_double_quoted_string"      // no arguments
+	MessageUnknownEnvironment                 MessageID = "unknown_environment"                    // environment, valid environments
+	MessageMissingLinkedValues                MessageID = "missing_linked_values"                  // environment, variables
+	MessageOutdated                           MessageID = "outdated"                               // no arguments
+	MessageMissingQuote                       MessageID = "missing_quote"                          // no arguments
+	MessageEtcdUnauthenticated                MessageID = "etcd_unauthenticated"                   // no arguments
+	MessageEmptyEnvironment                   MessageID = "empty_environment"                      // environment, source
+	MessagePolicyViolation                    MessageID = "policy_violation"                       // environment, variable, policy, policy description, policy message
+	MessagePolicyViolationSource              MessageID = "policy_violation_source"                // environment, variable, source, policy, policy description, policy message
+)
+
+// errorMessages holds the English error messages, part of DefaultCatalog
+var errorMessages = Catalog{
+	MessageInvalidNolintLinter:                "❌ ERROR: invalid linter name %q in annotations.nolint",
+	MessageInvalidAnnotation:                  "❌ ERROR: annotation %q must be a single-line // comment",
+	MessageFailedReadBaseEnvFile:              "failed to read base env file %s: %v",
+	MessageFailedHashBaseEnvFile:              "failed to hash base env file %s: %v",
+	MessageBatchTarget:                        "target '%s': %v",
+	MessageInvalidBuildTagPrefix:              "❌ ERROR: build_tag_prefix must be made of letters, digits, '_' and '.', got '%s'",
+	MessageInvalidBuildTagEnvironment:         "❌ ERROR: build_tags requires environment names made of letters, digits, '_' and '.', got '%s'",
+	MessageReservedBuildTag:                   "❌ ERROR: build tag '%s' of environment '%s' is set by the go command, set build_tag_prefix",
+	MessageSplitEnvironmentsRequiresBuildTags: "❌ ERROR: split_environments requires build_tags, the environment of a build is selected with -tags %s<environment>",
+	MessageSplitWithInternalPackage:           "❌ ERROR: split_environments can't be used with internal_package",
+	MessageFailedWrite:                        "failed to write %s: %v",
+	MessageUnknownEncoding:                    "unknown encoding %q, expected '%s', '%s' or '%s'",
+	MessageInvalidEncodedValue:                "❌ ERROR: variable %s: invalid %s value",
+	MessageOutdatedMissingFile:                "❌ ERROR: %v: %s does not exist",
+	MessageOutdatedDiffers:                    "❌ ERROR: %v: %s differs at line %d:\n  expected: %s\n  found:    %s%s",
+	MessageEnvironmentExists:                  "❌ ERROR: environment '%s' already exists",
+	MessageCloneRemoteSource:                  "❌ ERROR: environment '%s' is read from a remote source, add '%s' by hand",
+	MessageCloneStructNameUsed:                "❌ ERROR: struct name %s of environment '%s' is already used by environment '%s'",
+	MessageAlreadyExists:                      "❌ ERROR: %s already exists",
+	MessageCloneValuesOnlyCopied:              "❌ ERROR: environment '%s' is read from %s, whose values can only be copied",
+	MessageEnabledForUnknownEnvironment:       "❌ ERROR: variable '%s' is enabled for unknown environment '%s'",
+	MessageVariableMissing:                    "❌ ERROR: variable '%s' is missing in environment '%s'",
+	MessageAtPath:                             "%s:%v",
+	MessageTabIndentationAtLine:               "%d: tabs can't be used for indentation",
+	MessageUnexpectedIndentationAtLine:        "%d: unexpected indentation",
+	MessageExpectedMapping:                    "%d: expected a mapping",
+	MessageNestedSequencesNotSupported:        "%d: nested sequence items are not supported",
+	MessageSequencesOfMappingsNotSupported:    "%d: sequences of mappings are not supported",
+	MessageAtLine:                             "%d: %v",
+	MessageUnexpectedSequenceItem:             "%d: unexpected sequence item",
+	MessageKeyAlreadyDefinedAtLine:            "%d: key %s is already defined",
+	MessageKeyErrorAtLine:                     "%d: key %s: %v",
+	MessageUnexpectedAfterFlowCollection:      "unexpected %q after flow collection",
+	MessageUnterminatedFlowCollection:         "unterminated flow collection",
+	MessageExpectedColonAfterKey:              "expected ':' after key %q",
+	MessageEmptyFlowCollectionEntry:           "empty flow collection entry",
+	MessageArraysOfTablesAtLine:               "%d: arrays of tables are not supported",
+	MessageUnterminatedTableNameAtLine:        "%d: expected ']' after table name",
+	MessageExpectedTOMLKeyValueAtLine:         "%d: expected 'key = value'",
+	MessageUnexpectedAfterValue:               "%d: key %s: unexpected %q after value",
+	MessageKeyNotTable:                        "key %s is not a table",
+	MessageMissingValue:                       "missing value",
+	MessageMultiLineStringsNotSupported:       "multi-line strings are not supported",
+	MessageUnterminatedLiteralString:          "unterminated literal string",
+	MessageExpectedArraySeparator:             "expected ',' or ']' in array",
+	MessageExpectedInlineTableEquals:          "expected '=' in inline table",
+	MessageExpectedInlineTableSeparator:       "expected ',' or '}' in inline table",
+	MessageInvalidNumber:                      "invalid number %q",
+	MessageInvalidUnquotedValue:               "invalid value %q, quote strings",
+	MessageDuplicateFieldName:                 "❌ ERROR: variables %s and %s have the same field name %s, rename one of them",
+	MessageDuplicateDeclaration:               "❌ ERROR: %s and %s both declare %s, rename one of them",
+	MessageUnknownConflictStrategy:            "❌ ERROR: unknown conflict strategy '%s', expected '%s', '%s' or '%s'",
+	MessagePackageConflicts:                   "❌ ERROR: generated declarations conflict with existing package files: %s (rename struct_name or set on_conflict to '%s')",
+	MessageFailedParse:                        "failed to parse %s: %v",
+	MessageFailedQuery:                        "❌ ERROR: failed to query %s: %v",
+	MessageQueryStatus:                        "❌ ERROR: failed to query %s: %s: %s",
+	MessageInvalidResponse:                    "❌ ERROR: invalid response from %s: %v",
+	MessageSourceDateEpochRequired:            "❌ ERROR: the generated output embeds the generation time, so it can't be deterministic without SOURCE_DATE_EPOCH\n💡 Set SOURCE_DATE_EPOCH, e.g. to the time of the last commit: git log -1 --format=%%ct",
+	MessageNotDeterministic:                   "❌ ERROR: generated output is not deterministic, first difference at line %d:\n  run 1: %s\n  run 2: %s",
+	MessageFailedRenderManifest:               "failed to render manifest: %v",
+	MessageFailedEncrypt:                      "failed to encrypt %s: %v",
+	MessageFailedCreateDirectory:              "failed to create directory for %s: %v",
+	MessageDoctorSQLDriverNotRegistered:       "sql driver %q is not registered, import it in the generator",
+	MessageHoldingVariableNotSet:              "%s holding the %s is not set",
+	MessageEncryptionKeyNotSet:                "❌ ERROR: environment variable %s holding the encryption key is not set",
+	MessageInvalidEncryptionKeyEncoding:       "❌ ERROR: %s must hold a base64 encoded 32-byte key",
+	MessageNotEncryptedArtifact:               "❌ ERROR: not an encrypted go-envied artifact",
+	MessageEncryptedArtifactTruncated:         "❌ ERROR: encrypted artifact is truncated",
+	MessageDecryptArtifactFailed:              "❌ ERROR: failed to decrypt artifact, wrong key or modified file",
+	MessageInvalidEncryptionKeySize:           "❌ ERROR: encryption key must be 32 bytes, got %d",
+	MessageLineTooLong:                        "❌ ERROR: %s:%d: line is longer than %d bytes",
+	MessageNULByte:                            "❌ ERROR: %s:%d: NUL byte, the file is not a text file",
+	MessageInvalidUTF8:                        "❌ ERROR: %s:%d: invalid UTF-8, save the file as UTF-8",
+	MessagePassphraseEmpty:                    "❌ ERROR: the passphrase is empty",
+	MessageNotEncryptedEnvFile:                "❌ ERROR: not an encrypted env file",
+	MessageEncryptedEnvFileCorrupted:          "❌ ERROR: encrypted env file is corrupted",
+	MessageEncryptedEnvFileTruncated:          "❌ ERROR: encrypted env file is truncated",
+	MessageDecryptEnvFileFailed:               "❌ ERROR: failed to decrypt env file, wrong passphrase or modified file",
+	MessagePassphraseNotSet:                   "❌ ERROR: %s is encrypted, set %s to the passphrase",
+	MessageFileError:                          "%s: %v",
+	MessageInvalidHint:                        "invalid hint %q, expected key=value",
+	MessageUnsupportedHintType:                "unsupported type %q",
+	MessageInvalidSensitiveHint:               "sensitive must be true or false, got %q",
+	MessageUnknownHint:                        "unknown hint %q, expected type or sensitive",
+	MessageHintTypeConflict:                   "❌ ERROR: variable %s: env file hint type %q conflicts with %q in the configuration",
+	MessageFailedReadEtcdCACertificate:        "failed to read etcd CA certificate: %v",
+	MessageNoCertificatesFound:                "❌ ERROR: no certificates found in %s",
+	MessageFailedLoadEtcdClientCertificate:    "failed to load etcd client certificate: %v",
+	MessageRequestFailed:                      "❌ ERROR: %s request to %s failed: %s: %s",
+	MessageErrorCause:                         "%v: %v",
+	MessageFailedReach:                        "❌ ERROR: failed to reach %s: %v",
+	MessageWatchFailed:                        "❌ ERROR: watch of %s failed: %s",
+	MessageUndefinedVariable:                  "❌ ERROR: variable %s is not defined in any environment",
+	MessageFieldTypeConflict:                  "❌ ERROR: variable %s: fields pins type %q but variables declares %q",
+	MessageUnknownCryptoMode:                  "❌ ERROR: unknown crypto mode '%s', expected '%s'",
+	MessageCryptoModeForbidsObfuscation:       "❌ ERROR: crypto_mode '%s' does not allow '%s' obfuscation, use '%s' or '%s'",
+	MessageUnknownObfuscationMode:             "❌ ERROR: unknown obfuscation mode '%s', expected '%s', '%s' or '%s'",
+	MessageRuntimeSealedValueTruncated:        "go-envied: sealed value of %s is truncated",
+	MessageRuntimeSealedValueOpenFailed:       "go-envied: failed to open sealed value of %s",
+	MessageUnknownGetterReceiver:              "❌ ERROR: unknown getter receiver '%s', expected '%s' or '%s'",
+	MessageInvalidGetterName:                  "❌ ERROR: variable %s: getter %q is not a Go identifier",
+	MessageGetterCollidesWithField:            "❌ ERROR: variable %s: getter %s collides with the field %s, set a getter name",
+	MessageGetterCollidesWithMethod:           "❌ ERROR: variable %s: getter %s collides with a generated method",
+	MessageDuplicateGetter:                    "❌ ERROR: variables %s and %s have the same getter %s",
+	MessageInvalidGeneratedCodeLine:           "❌ ERROR: generated code is not valid Go: %v\n   %s",
+	MessageInvalidGeneratedCode:               "❌ ERROR: generated code is not valid Go: %v",
+	MessageNoNetworkRemoteSource:              "❌ ERROR: environment '%s' reads %s, which needs network access in no-network mode",
+	MessageNoNetworkVault:                     "❌ ERROR: environment '%s' reads its values from vault, which needs network access in no-network mode",
+	MessageEnvFileUnknownEnvironment:          "❌ ERROR: env file given for unknown environment '%s'",
+	MessageHermeticConfigPathRequired:         "❌ ERROR: hermetic mode requires a configuration file path",
+	MessageHermeticOutputFileRequired:         "❌ ERROR: hermetic mode requires an output file path",
+	MessageHermeticInternalPackage:            "❌ ERROR: internal_package writes outside the declared output and can't be used in hermetic mode",
+	MessageHermeticBuildTags:                  "❌ ERROR: build_tags writes files besides the declared output and can't be used in hermetic mode",
+	MessageHermeticRemoteSource:               "❌ ERROR: environment '%s' reads %s over the network and can't be used in hermetic mode, give its env file instead",
+	MessageHermeticVault:                      "❌ ERROR: environment '%s' takes its values from Vault and can't be used in hermetic mode",
+	MessageGoModWithoutModulePath:             "❌ ERROR: %s/go.mod does not declare a module path",
+	MessageInternalPackageOutsideModule:       "❌ ERROR: internal_package requires the output directory '%s' to be inside a Go module",
+	MessageInternalPackageOutputDir:           "❌ ERROR: internal_package can't be used with output directory '%s', which is the internal package itself",
+	MessageFailedCreateOutputDirectory:        "failed to create output directory: %v",
+	MessageFailedCreateOutputFile:             "failed to create output file: %v",
+	MessageReferenceCycle:                     "❌ ERROR: reference cycle %s -> %s",
+	MessageUndefinedReference:                 "❌ ERROR: %s references undefined variable %s",
+	MessageUnterminatedJSONComment:            "%d: unterminated /* comment",
+	MessageExpectedJSONObject:                 "%s:%d: expected an object of variables",
+	MessageAtFileLine:                         "%s:%d: %v",
+	MessageKeyError:                           "%s:%d: key %s: %v",
+	MessageNestedJSONValue:                    "%s:%d: key %s: nested objects and arrays are not supported, quote the value",
+	MessageKeyAlreadyDefined:                  "%s:%d: key %s is already defined at line %d",
+	MessageUnexpectedDataAfterJSON:            "%s:%d: unexpected data after the object",
+	MessageInvalidJSONValue:                   "❌ ERROR: variable %s: invalid JSON value: %v",
+	MessageFailedReadEnvFile:                  "failed to read env file %s: %v",
+	MessageInvalidIntValue:                    "❌ ERROR: variable %s: invalid int value %q",
+	MessageInvalidInt64Value:                  "❌ ERROR: variable %s: invalid int64 value %q",
+	MessageInvalidUint64Value:                 "❌ ERROR: variable %s: invalid uint64 value %q",
+	MessageInvalidBoolValue:                   "❌ ERROR: variable %s: invalid bool value %q",
+	MessageInvalidFloat64Value:                "❌ ERROR: variable %s: invalid float64 value %q",
+	MessageRuntimeKeyCountMismatch:            "go-envied: %d keys for %d obfuscated values",
+	MessageUnexpectedAfterClosingQuote:        "unexpected %q after the closing quote",
+	MessageEnvFileValueError:                  "❌ ERROR: %s:%d: %s: %v",
+	MessageFailedReadConfigFile:               "failed to read config file %s: %v",
+	MessageFailedParseConfigFile:              "failed to parse config file %s: %v",
+	MessageInvalidConfigFile:                  "❌ ERROR: invalid config file %s:\n  %s",
+	MessageFailedEncodeConfigFile:             "failed to encode config file %s: %v",
+	MessageFailedWriteConfigFile:              "failed to write config file %s: %v",
+	MessageConfigNotJSON:                      "❌ ERROR: %s is not a JSON configuration file, only JSON files are written, edit it by hand",
+	MessageConfigHasComments:                  "❌ ERROR: %s has comments, which would be lost by rewriting it, edit it by hand",
+	MessageFailedGenerateMergedConfiguration:  "failed to generate merged configuration: %v",
+	MessageFailedGenerateBuildTagFiles:        "failed to generate build tag files: %v",
+	MessageFailedGenerateReExportShim:         "failed to generate re-export shim: %v",
+	MessageEnvironmentError:                   "environment '%s': %v",
+	MessageConsistencyCheckFailed:             "environment consistency check failed: %v",
+	MessageAllEnvironmentsFromVault:           "❌ ERROR: all environments take their values from vault, generate with -values-from vault",
+	MessageFailedObfuscateField:               "failed to obfuscate field %s: %v",
+	MessageConfigNotFound:                     "configuration file %s not found",
+	MessageEnvVariableEmpty:                   "❌ ERROR: environment variable '%s' is empty",
+	MessageEnvVariableNotFound:                "❌ ERROR: required environment variable '%s' not found",
+	MessageFailedParseTemplate:                "failed to parse template: %v",
+	MessageManifestServerToken:                "❌ ERROR: the manifest server requires a token",
+	MessageNoManifests:                        "❌ ERROR: no manifests to serve",
+	MessageManifestUnreadable:                 "manifest of %s can't be read",
+	MessageFailedExecuteTemplate:              "❌ ERROR: failed to execute merged configuration template: %v",
+	MessageFailedExecuteEnvironmentTemplate:   "❌ ERROR: failed to execute environment file template: %v",
+	MessageUnknownNamingStrategy:              "❌ ERROR: unknown naming strategy '%s', expected '%s', '%s' or '%s'",
+	MessageUnknownSanitizeStrategy:            "❌ ERROR: unknown sanitize strategy '%s', expected '%s' or '%s'",
+	MessageInvalidFieldName:                   "❌ ERROR: variable %s: field name %q is not a Go identifier, set naming.sanitize to fix such names",
+	MessageFieldCollidesWithMethod:            "❌ ERROR: variable %s: field %s collides with a generated method",
+	MessageInvalidConstantSuffix:              "❌ ERROR: variable %s: constant suffix %q is not part of a Go identifier",
+	MessageDuplicateConstantSuffix:            "❌ ERROR: variables %s and %s have the same constant suffix %s",
+	MessageInvalidFileName:                    "❌ ERROR: environment '%s': file name %q is not a plain file name",
+	MessageDuplicateFileName:                  "❌ ERROR: environments '%s' and '%s' have the same file name %s",
+	MessageUnknownNameValidationMode:          "❌ ERROR: unknown name validation mode '%s', expected '%s', '%s' or '%s'",
+	MessageInvalidNamePattern:                 "❌ ERROR: invalid name pattern '%s': %v",
+	MessageNameMismatchesPattern:              "❌ ERROR: variable name '%s' in environment '%s' does not match %s",
+	MessageConfusableNames:                    "❌ ERROR: variable names %s differ only by case or confusable characters",
+	MessageHooksRemovedEnvironments:           "❌ ERROR: %s stage: hooks removed every environment",
+	MessageHookUnknownEnvironment:             "❌ ERROR: %s stage: environment '%s' is not in the configuration",
+	MessageUnknownPipelineStage:               "❌ ERROR: unknown pipeline stage '%s'",
+	MessageStageFailed:                        "❌ ERROR: %s stage: %v",
+	MessagePolicyWithoutPattern:               "❌ ERROR: policy %d has no variable pattern",
+	MessageInvalidPolicyPattern:               "❌ ERROR: policy %d has invalid variable pattern '%s'",
+	MessageInvalidPolicyEnvironmentPattern:    "❌ ERROR: policy %d has invalid environment pattern '%s'",
+	MessagePolicyMatchesNoEnvironment:         "❌ ERROR: environment pattern '%s' of policy %d matches no environment",
+	MessageProfileStructNameUsed:              "❌ ERROR: %s: struct name %s is already used by %s",
+	MessageProfileWithoutVariables:            "❌ ERROR: %s: no variables listed",
+	MessageProfileDuplicateVariable:           "❌ ERROR: %s: variable %s is listed twice",
+	MessageProfileUnknownVariable:             "❌ ERROR: %s: variable %s is not defined in the environment",
+	MessageFailedReadOverlay:                  "failed to read overlay %s: %v",
+	MessageFailedHashOverlay:                  "failed to hash overlay %s: %v",
+	MessageFailedHashEnvFile:                  "failed to hash env file %s: %v",
+	MessageGoListFailed:                       "❌ ERROR: go list %s failed: %v\n%s",
+	MessageFailedParseGoListOutput:            "❌ ERROR: failed to parse go list output: %v",
+	MessageGoListPackageError:                 "❌ ERROR: package %s: %s",
+	MessageGoListExportFailed:                 "❌ ERROR: go list -export %s failed: %v\n%s",
+	MessageNoExportData:                       "no export data for %s",
+	MessagePruneRemoteSource:                  "❌ ERROR: environment '%s' is read from a remote source, remove the variables there",
+	MessagePruneYAMLFile:                      "❌ ERROR: environment '%s' is read from YAML file %s, remove the variables there",
+	MessagePruneTOMLFile:                      "❌ ERROR: environment '%s' is read from TOML file %s, remove the variables there",
+	MessagePruneJSONFile:                      "❌ ERROR: environment '%s' is read from JSON file %s, remove the variables there",
+	MessageFailedPrune:                        "❌ ERROR: failed to prune %s: %v",
+	MessageInvalidRedisAddress:                "❌ ERROR: invalid redis address %s: %v",
+	MessageFailedReadRedisCACertificate:       "failed to read redis CA certificate: %v",
+	MessageFailedConnect:                      "❌ ERROR: failed to connect to %s: %v",
+	MessageTLSHandshakeFailed:                 "❌ ERROR: TLS handshake with %s failed: %v",
+	MessageAuthenticationFailed:               "❌ ERROR: authentication to %s failed: %v",
+	MessageFailedSelectDatabase:               "❌ ERROR: failed to select database of %s: %v",
+	MessageInvalidRedisReply:                  "❌ ERROR: invalid redis reply",
+	MessageInvalidRedisReplyLine:              "❌ ERROR: invalid redis reply %q",
+	MessageFailedRead:                         "❌ ERROR: failed to read %s: %v",
+	MessageInvalidHGETALLReply:                "❌ ERROR: invalid HGETALL reply from %s",
+	MessageManifestKeyMissing:                 "manifest is encrypted and no key is given",
+	MessageManifestNotDecrypted:               "manifest can't be decrypted",
+	MessageNotManifest:                        "file is not a manifest",
+	MessageGitShowFailed:                      "❌ ERROR: git show %s:%s failed: %v\n%s",
+	MessageReleaseFileError:                   "❌ ERROR: %s at %s: %v",
+	MessageUnknownReportFormat:                "❌ ERROR: unknown report format '%s', expected '%s' or '%s'",
+	MessageRenameUnknownEmitter:               "❌ ERROR: emit.rename: unknown emitter '%s', expected one of %s",
+	MessageRenameUnknownVariable:              "❌ ERROR: emit.rename.%s: unknown variable '%s'",
+	MessageRenameInvalidName:                  "❌ ERROR: emit.rename.%s: '%s' is not a valid variable name for %s",
+	MessageRenameDuplicateName:                "❌ ERROR: emit.rename.%s: %s and %s are both written as %s",
+	MessageFailedResolveOutputDirectory:       "failed to resolve output directory %s: %v",
+	MessageFailedResolveEnvFile:               "failed to resolve env file %s: %v",
+	MessageEnvFileInsideOutputDir:             "❌ ERROR: env file '%s' of environment '%s' is inside output directory '%s', plaintext secrets would be committed next to generated code (set allow_unsafe_paths to override)",
+	MessageOutputDirOutsideModule:             "❌ ERROR: output directory '%s' is outside module '%s' (set allow_unsafe_paths to override)",
+	MessageSealedSecretsCertRequired:          "❌ ERROR: emit.sealed_secrets requires emit.sealed_secrets_cert, the certificate printed by kubeseal --fetch-cert",
+	MessageFailedReadSealedSecretsCert:        "❌ ERROR: failed to read sealed secrets certificate: %v",
+	MessageNotPEMFile:                         "❌ ERROR: %s is not a PEM file",
+	MessageInvalidCertificate:                 "❌ ERROR: invalid certificate %s: %v",
+	MessageInvalidPublicKey:                   "❌ ERROR: invalid public key %s: %v",
+	MessageUnexpectedPEMBlock:                 "❌ ERROR: %s holds a %s, expected a CERTIFICATE or PUBLIC KEY",
+	MessageNotRSAPublicKey:                    "❌ ERROR: %s does not hold an RSA public key",
+	MessageFailedSeal:                         "failed to seal %s: %v",
+	MessageInvalidRandomSeed:                  "invalid random_seed %s: %v",
+	MessageRandomSeedOutRange:                 "random_seed %s is out of range [%d, %d]",
+	MessageRandomSeedNotInteger:               "invalid random_seed %s: must be an integer",
+	MessageNegativeSizeBudget:                 "❌ ERROR: size_budget limits must not be negative",
+	MessageVariableOverSizeLimit:              "❌ ERROR: variable %s of environment '%s' embeds %s, over its limit of %s",
+	MessageEnvironmentOverSizeBudget:          "❌ ERROR: environment '%s' embeds %s, over the size budget of %s:%s",
+	MessageInvalidSourceTimeout:               "❌ ERROR: invalid source_timeout '%s', expected a positive duration such as 30s",
+	MessageSourceTimeout:                      "%v\n💡 No answer within %s, raise source_timeout if the source is slow",
+	MessageEnvFileAndSource:                   "❌ ERROR: only one of env_file and %s can be set",
+	MessageEnvFileNotSet:                      "❌ ERROR: env_file is not set",
+	MessageDSNAndDSNEnv:                       "❌ ERROR: only one of dsn and dsn_env can be set",
+	MessageDSNMissing:                         "❌ ERROR: one of dsn and dsn_env must be set",
+	MessageDSNEnvNotSet:                       "❌ ERROR: environment variable %s holding the DSN is not set",
+	MessageSQLDriverNotRegistered:             "❌ ERROR: sql driver %q is not registered, import it in the generator (registered: %v)",
+	MessageSQLQueryNotSet:                     "❌ ERROR: sql query is not set",
+	MessageFailedOpen:                         "❌ ERROR: failed to open %s: %v",
+	MessageSQLQueryColumns:                    "❌ ERROR: sql query must return 2 columns (name, value), got %d",
+	MessageFailedReadRow:                      "❌ ERROR: failed to read row of %s: %v",
+	MessageSQLDuplicateVariable:               "❌ ERROR: variable %s is returned more than once by the sql query",
+	MessageSeparatorAndTimeLayout:             "❌ ERROR: variable %s: only one of separator and time_layout can be set",
+	MessageRuntimeObfuscatedListMismatch:      "go-envied: obfuscated list has %d keys and %d values",
+	MessageRuntimeSealedListMismatch:          "go-envied: sealed list %s has %d keys and %d values",
+	MessageUndefinedSubstitution:              "❌ ERROR: %s references undefined substitution %s",
+	MessageTimeLayoutMismatch:                 "❌ ERROR: variable %s: value %q does not match time layout %q",
+	MessageInvalidTimeValue:                   "❌ ERROR: variable %s: invalid time value %q, expected layout %q",
+	MessageArraysOfTables:                     "%s:%d: arrays of tables are not supported",
+	MessageUnterminatedTableName:              "%s:%d: expected ']' after table name",
+	MessageTableAlreadyDefined:                "%s:%d: table %s is already defined at line %d",
+	MessageExpectedTOMLKeyValue:               "%s:%d: expected 'key = value'",
+	MessageExpectedKey:                        "expected a key",
+	MessageUnexpectedInKey:                    "unexpected %q in key",
+	MessageUnexpectedAfterString:              "unexpected %q after string",
+	MessageTOMLArraysNotSupported:             "arrays are not supported, quote the value",
+	MessageInlineTablesNotSupported:           "inline tables are not supported, use a [table]",
+	MessageInvalidBase64:                      "invalid base64: %v",
+	MessageUnknownTransform:                   "unknown transform '%s'",
+	MessageTransformFailed:                    "transform '%s' failed: %v",
+	MessageTransformVariableError:             "❌ ERROR: variable '%s': %v",
+	MessageValueNotBool:                       "value %q is not a bool",
+	MessageValueNotInt:                        "value %q is not an int",
+	MessageValueNotFloat64:                    "value %q is not a float64",
+	MessageValueNotValid:                      "value is not valid %s",
+	MessageValueNotInt64:                      "value %q is not an int64",
+	MessageValueNotUint64:                     "value %q is not a uint64",
+	MessageValueNotJSONObject:                 "value is not a JSON object of %s: %v",
+	MessageEncodingRequiresType:               "❌ ERROR: variable %s: encoding requires \"type\": \"%s\"",
+	MessageUnsupportedVariableType:            "❌ ERROR: variable %s: unsupported type %q, expected one of %s",
+	MessageTypeWithSeparator:                  "❌ ERROR: variable %s: type can't be combined with separator or time_layout",
+	MessageVariableError:                      "❌ ERROR: variable %s: %v",
+	MessageIntOverflows32Bit:                  "❌ ERROR: variable %s: value %s overflows int on 32-bit targets, declare \"type\": \"int64\"",
+	MessageIntOverflowsInt64:                  "❌ ERROR: variable %s: value %s overflows int64, declare \"type\": \"uint64\"",
+	MessageIntOverflows64Bit:                  "❌ ERROR: variable %s: value %s overflows 64-bit integers, quote it to keep it a string",
+	MessageUnknownUnsafeCharactersPolicy:      "❌ ERROR: unknown unsafe characters policy '%s', expected '%s', '%s' or '%s'",
+	MessageUnsafeCharacters:                   "❌ ERROR: environment '%s': %s contains %s at byte %d, fix the value or set on_unsafe_characters to '%s'",
+	MessageVaultPathNotSet:                    "❌ ERROR: vault.path is not set",
+	MessageVaultTokenNotSet:                   "❌ ERROR: set %s to read %s",
+	MessageUnknownValuesSource:                "❌ ERROR: unknown values source '%s', expected '%s'",
+	MessageVaultUndeclaredVariable:            "❌ ERROR: %s has variable %s, which is not declared in %s",
+	MessageVerifyFailedParse:                  "❌ ERROR: failed to parse %s: %v",
+	MessageVerifyEnvironmentError:             "❌ ERROR: environment '%s': %v",
+	MessageVerifyStale:                        "❌ ERROR: %v, regenerate %s:\n%s",
+	MessageTabIndentation:                     "%s:%d: tabs can't be used for indentation",
+	MessageSequencesNotSupported:              "%s:%d: sequences are not supported",
+	MessageUnexpectedIndentation:              "%s:%d: unexpected indentation",
+	MessageExpectedYAMLKeyValue:               "expected 'key: value'",
+	MessageUnexpectedAfterQuotedValue:         "unexpected %q after quoted value",
+	MessageFlowCollectionsNotSupported:        "flow collections are not supported, quote the value",
+	MessageBlockScalarsNotSupported:           "block scalars are not supported, use a quoted value",
+	MessageAnchorsNotSupported:                "anchors, aliases and tags are not supported",
+	MessageUnterminatedSingleQuotedString:     "unterminated single-quoted string",
+	MessageInvalidDoubleQuotedString:          "invalid double-quoted string %s",
+	MessageUnterminatedDoubleQuotedString:     "unterminated double-quoted string",
+	MessageUnknownEnvironment:                 "unknown environment '%s', valid environments: %s",
+	MessageMissingLinkedValues:                "environment '%s' is missing values injected with -ldflags -X: %s",
+	MessageOutdated:                           "generated configuration is out of date",
+	MessageMissingQuote:                       "missing closing quote",
+	MessageEtcdUnauthenticated:                "etcd authentication token is invalid or expired",
+	MessageEmptyEnvironment:                   "❌ ERROR: environment '%s' has no variables, check that %s is the intended source",
+	MessagePolicyViolation:                    "❌ ERROR: environment '%s' defines %s, forbidden by policy %d: %s%s",
+	MessagePolicyViolationSource:              "❌ ERROR: environment '%s' defines %s (from %s), forbidden by policy %d: %s%s",
+}
+
+// Error is an error with a message ID. Its text is formatted with DefaultCatalog, Catalog.Error
+// formats it with another catalog. Errors among its arguments are wrapped, so errors.Is and
+// errors.As see them.
+type Error struct {
+	ID   MessageID
+	Args []any // Arguments of the format of the ID
+}
+
+// newError returns an error with a message ID
+func newError(id MessageID, args ...any) error {
+	return &Error{ID: id, Args: args}
+}
+
+// Error formats the error with DefaultCatalog
+func (e *Error) Error() string {
+	return DefaultCatalog.Format(e.ID, e.Args...)
+}
+
+// message returns the message ID and arguments of the error
+func (e *Error) message() (MessageID, []any) {
+	return e.ID, e.Args
+}
+
+// Unwrap returns the errors among the arguments
+func (e *Error) Unwrap() []error {
+	var wrapped []error
+	for _, arg := range e.Args {
+		if err, ok := arg.(error); ok {
+			wrapped = append(wrapped, err)
+		}
+	}
+	return wrapped
+}
+
+// messageError is an error with a message ID, such as *Error and *ErrUnknownEnvironment
+type messageError interface {
+	error
+	message() (MessageID, []any)
+}
+
+// Error formats an error with the catalog. Errors without a message ID, such as those of the
+// standard library, keep their text.
+func (c Catalog) Error(err error) string {
+	messageErr, ok := err.(messageError)
+	if !ok {
+		return err.Error()
+	}
+	id, args := messageErr.message()
+	return c.Format(id, args...)
+}
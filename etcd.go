@@ -0,0 +1,368 @@
+package envied
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Etcd defaults, following etcdctl
+const (
+	DefaultEtcdEndpoint    = "http://127.0.0.1:2379"
+	DefaultEtcdPasswordEnv = "ETCD_PASSWORD"
+)
+
+// etcdRetryDelay is the delay before retrying a failed watch
+var etcdRetryDelay = time.Second
+
+// EtcdConfig configures reading variables from etcd v3 under a key prefix through the
+// etcd JSON gateway. Keys below the prefix become variable names, nested keys are joined with '_'.
+type EtcdConfig struct {
+	Endpoints   []string `json:"endpoints,omitempty"`    // Client URLs tried in order (DefaultEtcdEndpoint if empty)
+	Prefix      string   `json:"prefix"`                 // Key prefix holding the variables
+	Username    string   `json:"username,omitempty"`     // User for authentication (no authentication if empty)
+	PasswordEnv string   `json:"password_env,omitempty"` // Environment variable holding the password (DefaultEtcdPasswordEnv if empty)
+	CACert      string   `json:"ca_cert,omitempty"`      // CA bundle verifying the server certificate
+	Cert        string   `json:"cert,omitempty"`         // Client certificate for TLS authentication
+	Key         string   `json:"key,omitempty"`          // Client certificate key for TLS authentication
+}
+
+// etcdKeyValue is a key-value pair of the etcd JSON gateway, bytes are base64 in JSON
+type etcdKeyValue struct {
+	Key   []byte `json:"key"`
+	Value []byte `json:"value"`
+}
+
+// etcdHeader is the response header of the etcd JSON gateway, int64 values are strings in JSON
+type etcdHeader struct {
+	Revision string `json:"revision"`
+}
+
+// prefix returns the key prefix ending with '/', so sibling keys sharing the name are not matched
+func (c EtcdConfig) prefix() string {
+	if strings.HasSuffix(c.Prefix, "/") {
+		return c.Prefix
+	}
+	return c.Prefix + "/"
+}
+
+// endpoints returns the configured client URLs with scheme
+func (c EtcdConfig) endpoints() []string {
+	endpoints := c.Endpoints
+	if len(endpoints) == 0 {
+		endpoints = []string{DefaultEtcdEndpoint}
+	}
+
+	scheme := "http://"
+	if c.CACert != "" || c.Cert != "" {
+		scheme = "https://"
+	}
+	urls := make([]string, len(endpoints))
+	for i, endpoint := range endpoints {
+		if !strings.Contains(endpoint, "://") {
+			endpoint = scheme + endpoint
+		}
+		urls[i] = strings.TrimSuffix(endpoint, "/")
+	}
+	return urls
+}
+
+// describe returns a human-readable location of the variables
+func (c EtcdConfig) describe() string {
+	return fmt.Sprintf("etcd %s%s", strings.Join(c.endpoints(), ","), c.prefix())
+}
+
+// httpClient returns a client using the configured TLS files
+func (c EtcdConfig) httpClient() (*http.Client, error) {
+	if c.CACert == "" && c.Cert == "" && c.Key == "" {
+		return &http.Client{}, nil
+	}
+
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12}
+	if c.CACert != "" {
+		pem, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, newError(MessageFailedReadEtcdCACertificate, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, newError(MessageNoCertificatesFound, c.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if c.Cert != "" || c.Key != "" {
+		certificate, err := tls.LoadX509KeyPair(c.Cert, c.Key)
+		if err != nil {
+			return nil, newError(MessageFailedLoadEtcdClientCertificate, err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{certificate}
+	}
+
+	return &http.Client{Transport: &http.Transport{DialContext: dialContext, TLSClientConfig: tlsConfig}}, nil
+}
+
+// prefixRangeEnd returns the end of the key range covering all keys with prefix
+func prefixRangeEnd(prefix string) []byte {
+	end := []byte(prefix)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+			return end[:i+1]
+		}
+	}
+	// All bytes are 0xff, the range covers all keys from prefix
+	return []byte{0}
+}
+
+// etcdClient performs requests against the first reachable endpoint
+type etcdClient struct {
+	config EtcdConfig
+	http   *http.Client
+	token  string // Authentication token, empty without authentication
+}
+
+// etcdAuthenticatePath is the gateway path of authentication requests
+const etcdAuthenticatePath = "/v3/auth/authenticate"
+
+// errEtcdUnauthenticated is wrapped by errors of requests refused for an invalid or expired token
+var errEtcdUnauthenticated error = &Error{ID: MessageEtcdUnauthenticated}
+
+// newEtcdClient creates a client and authenticates if a username is configured
+func newEtcdClient(ctx context.Context, config EtcdConfig) (*etcdClient, error) {
+	httpClient, err := config.httpClient()
+	if err != nil {
+		return nil, err
+	}
+	client := &etcdClient{config: config, http: httpClient}
+
+	if config.Username != "" {
+		if err := client.authenticate(ctx); err != nil {
+			return nil, err
+		}
+	}
+	return client, nil
+}
+
+// authenticate requests a new token for the configured user
+func (c *etcdClient) authenticate(ctx context.Context) error {
+	passwordEnv := c.config.PasswordEnv
+	if passwordEnv == "" {
+		passwordEnv = DefaultEtcdPasswordEnv
+	}
+	var response struct {
+		Token string `json:"token"`
+	}
+	request := map[string]string{"name": c.config.Username, "password": os.Getenv(passwordEnv)}
+	c.token = ""
+	if err := c.call(ctx, etcdAuthenticatePath, request, &response); err != nil {
+		return err
+	}
+	c.token = response.Token
+	return nil
+}
+
+// post sends a JSON request to the first endpoint accepting the connection. Tokens expire,
+// so a request refused for its token is sent again once after authenticating anew.
+func (c *etcdClient) post(ctx context.Context, path string, request any) (*http.Response, error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.send(ctx, path, body)
+	if errors.Is(err, errEtcdUnauthenticated) && c.config.Username != "" && path != etcdAuthenticatePath {
+		if err := c.authenticate(ctx); err != nil {
+			return nil, err
+		}
+		response, err = c.send(ctx, path, body)
+	}
+	return response, err
+}
+
+// send posts a JSON body to the first endpoint accepting the connection
+func (c *etcdClient) send(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	var lastErr error
+	for _, endpoint := range c.config.endpoints() {
+		httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint+path, bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
+		httpRequest.Header.Set("Content-Type", "application/json")
+		if c.token != "" {
+			httpRequest.Header.Set("Authorization", c.token)
+		}
+
+		response, err := c.http.Do(httpRequest)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if response.StatusCode != http.StatusOK {
+			message, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+			response.Body.Close()
+			err := newError(MessageRequestFailed, path, endpoint, response.Status, strings.TrimSpace(string(message)))
+			if response.StatusCode == http.StatusUnauthorized {
+				err = newError(MessageErrorCause, err, errEtcdUnauthenticated)
+			}
+			return nil, err
+		}
+		return response, nil
+	}
+	return nil, newError(MessageFailedReach, c.config.describe(), lastErr)
+}
+
+// call sends a JSON request and decodes the JSON response
+func (c *etcdClient) call(ctx context.Context, path string, request any, response any) error {
+	httpResponse, err := c.post(ctx, path, request)
+	if err != nil {
+		return err
+	}
+	defer httpResponse.Body.Close()
+
+	if err := json.NewDecoder(httpResponse.Body).Decode(response); err != nil {
+		return newError(MessageInvalidResponse, c.config.describe(), err)
+	}
+	return nil
+}
+
+// fetch reads all variables under the prefix and returns them with the store revision
+func (c *etcdClient) fetch(ctx context.Context) (map[string]string, int64, error) {
+	prefix := c.config.prefix()
+	request := map[string][]byte{
+		"key":       []byte(prefix),
+		"range_end": prefixRangeEnd(prefix),
+	}
+	var response struct {
+		Header etcdHeader     `json:"header"`
+		Kvs    []etcdKeyValue `json:"kvs"`
+	}
+	if err := c.call(ctx, "/v3/kv/range", request, &response); err != nil {
+		return nil, 0, err
+	}
+
+	values := make(map[string]string)
+	for _, kv := range response.Kvs {
+		if name, ok := prefixVariableName(prefix, string(kv.Key)); ok {
+			values[name] = string(kv.Value)
+		}
+	}
+	revision, _ := strconv.ParseInt(response.Header.Revision, 10, 64)
+	return values, revision, nil
+}
+
+// waitForChange blocks until a key under the prefix changes after revision
+func (c *etcdClient) waitForChange(ctx context.Context, revision int64) error {
+	prefix := c.config.prefix()
+	request := map[string]any{
+		"create_request": map[string]any{
+			"key":            []byte(prefix),
+			"range_end":      prefixRangeEnd(prefix),
+			"start_revision": strconv.FormatInt(revision+1, 10),
+		},
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	response, err := c.post(ctx, "/v3/watch", request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	// The gateway streams one JSON object per watch response, the first one confirms creation
+	decoder := json.NewDecoder(response.Body)
+	for {
+		var message struct {
+			Result struct {
+				Events []json.RawMessage `json:"events"`
+			} `json:"result"`
+			Error *struct {
+				Code    int    `json:"code"`
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		if err := decoder.Decode(&message); err != nil {
+			return err
+		}
+		if message.Error != nil {
+			err := newError(MessageWatchFailed, c.config.describe(), message.Error.Message)
+			// gRPC code 16 is Unauthenticated
+			if message.Error.Code == 16 || strings.Contains(message.Error.Message, "auth token") {
+				err = newError(MessageErrorCause, err, errEtcdUnauthenticated)
+			}
+			return err
+		}
+		if len(message.Result.Events) > 0 {
+			return nil
+		}
+	}
+}
+
+// EtcdSource is a runtime Source reading etcd, see NewEtcdSource
+type EtcdSource struct {
+	watchedValues
+
+	client   *etcdClient
+	revision int64 // Store revision of values, changes are watched after it
+}
+
+// NewEtcdSource reads the variables under the configured prefix.
+// Call Watch to keep them up to date.
+func NewEtcdSource(ctx context.Context, config EtcdConfig) (*EtcdSource, error) {
+	client, err := newEtcdClient(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	values, revision, err := client.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source := &EtcdSource{client: client, revision: revision}
+	source.update(values)
+	return source, nil
+}
+
+// Watch follows changes under the prefix with an etcd watch and calls onChange with the
+// new variables after every change. Failed requests are retried, expired authentication
+// tokens are renewed first. Watch returns when ctx is done.
+func (s *EtcdSource) Watch(ctx context.Context, onChange ChangeFunc) error {
+	for {
+		err := s.client.waitForChange(ctx, s.revision)
+		if err == nil {
+			var values map[string]string
+			var revision int64
+			values, revision, err = s.client.fetch(ctx)
+			if err == nil {
+				s.revision = revision
+				if s.update(values) && onChange != nil {
+					onChange(s.Values())
+				}
+			}
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(etcdRetryDelay):
+			}
+			if errors.Is(err, errEtcdUnauthenticated) && s.client.config.Username != "" {
+				// A failed renewal is retried with the next attempt
+				_ = s.client.authenticate(ctx)
+			}
+		}
+	}
+}
@@ -18,16 +18,13 @@ type Config struct {
 }
 
 func NewConfig(env string) (*Config, error) {
-	// Create configurations for different environments
-	var currentConfig ConfigInterface
-	switch env {
-	case EnvDev:
-		currentConfig = NewDevConfigConfig()
-		fmt.Println("  Using development configuration")
-	default:
-		currentConfig = NewProdConfigConfig()
-		fmt.Printf("  Unknown environment '%s', using development configuration\n", env)
+	// Create configuration for the requested environment,
+	// unknown environment names are reported as *ErrUnknownEnvironment
+	currentConfig, err := NewEnvironmentConfig(env)
+	if err != nil {
+		return nil, err
 	}
+	fmt.Printf("  Using %s configuration\n", env)
 
 	return &Config{
 		DATABASE_URL: currentConfig.GetDATABASE_URL(),
@@ -3,22 +3,143 @@
 
 package config
 
-import "github.com/petrovyuri/go-envied"
+import (
+	"time"
+
+	"github.com/petrovyuri/go-envied"
+)
 
 // ConfigInterface defines the interface for all generated configurations
 type ConfigInterface interface {
+	GetDATABASE_URL() string
 	GetDEBUG_MODE() bool
+	GetMAX_TOKENS() string
 	GetPORT() int
 	GetTEMPERATURE() float64
-	GetMAX_TOKENS() string
-	GetDATABASE_URL() string
+	// Lookup returns the value of a variable formatted as in a .env file
+	Lookup(name string) (string, bool)
+	// Environment returns the environment name the configuration was generated for
+	Environment() string
+	// GeneratedAt returns the time the configuration was generated
+	GeneratedAt() time.Time
+	// SourceHash returns the SHA-256 of the env file the configuration was generated from
+	SourceHash() string
 }
 
-// Static key for MAX_TOKENS in dev environment
-var dev_enviedkeyMAX_TOKENS = []int{1449781530, 4028288318}
+// enviedObfuscationVersion is the obfuscation algorithm version of the embedded values
+const enviedObfuscationVersion = 1
 
-// Static encrypted data for MAX_TOKENS in dev environment
-var dev_envieddataMAX_TOKENS = []int{1449781547, 4028288270}
+var _ = envied.CheckObfuscationVersion(enviedObfuscationVersion)
+
+// ErrUnknownEnvironment is returned by NewEnvironmentConfig for unknown environment names
+type ErrUnknownEnvironment = envied.ErrUnknownEnvironment
+
+// Environments lists the names of all generated environments
+var Environments = []string{"dev", "prod"}
+
+// NewEnvironmentConfig creates the configuration for the named environment with overrides applied.
+// It returns *ErrUnknownEnvironment if the name does not match any environment.
+func NewEnvironmentConfig(env string, opts ...Override) (ConfigInterface, error) {
+	switch env {
+	case "dev":
+		return NewDevConfigConfig(opts...), nil
+	case "prod":
+		return NewProdConfigConfig(opts...), nil
+	}
+	return nil, &ErrUnknownEnvironment{Name: env, Valid: Environments}
+}
+
+// Override replaces an embedded value when a configuration is constructed,
+// overrides of variables missing in the environment are ignored
+type Override func(*enviedOverrides)
+
+// enviedOverrides holds the values set by overrides
+type enviedOverrides struct {
+	DATABASE_URL *string
+	DEBUG_MODE   *bool
+	MAX_TOKENS   *string
+	PORT         *int
+	TEMPERATURE  *float64
+}
+
+// WithDATABASE_URL overrides the embedded DATABASE_URL value
+func WithDATABASE_URL(value string) Override {
+	return func(o *enviedOverrides) {
+		o.DATABASE_URL = &value
+	}
+}
+
+// WithDEBUG_MODE overrides the embedded DEBUG_MODE value
+func WithDEBUG_MODE(value bool) Override {
+	return func(o *enviedOverrides) {
+		o.DEBUG_MODE = &value
+	}
+}
+
+// WithMAX_TOKENS overrides the embedded MAX_TOKENS value
+func WithMAX_TOKENS(value string) Override {
+	return func(o *enviedOverrides) {
+		o.MAX_TOKENS = &value
+	}
+}
+
+// WithPORT overrides the embedded PORT value
+func WithPORT(value int) Override {
+	return func(o *enviedOverrides) {
+		o.PORT = &value
+	}
+}
+
+// WithTEMPERATURE overrides the embedded TEMPERATURE value
+func WithTEMPERATURE(value float64) Override {
+	return func(o *enviedOverrides) {
+		o.TEMPERATURE = &value
+	}
+}
+
+// NewLayeredConfig creates the configuration for the named environment with values of the
+// sources layered over the embedded ones, later sources take precedence.
+// Values are converted to the variable types, conversion failures are returned as errors.
+func NewLayeredConfig(env string, sources ...envied.Source) (ConfigInterface, error) {
+	layered := envied.Layer(sources...)
+	var opts []Override
+	if value, exists := layered.Lookup("DATABASE_URL"); exists {
+		parsed, err := envied.CoerceString("DATABASE_URL", value)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithDATABASE_URL(parsed))
+	}
+	if value, exists := layered.Lookup("DEBUG_MODE"); exists {
+		parsed, err := envied.CoerceBool("DEBUG_MODE", value)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithDEBUG_MODE(parsed))
+	}
+	if value, exists := layered.Lookup("MAX_TOKENS"); exists {
+		parsed, err := envied.CoerceString("MAX_TOKENS", value)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithMAX_TOKENS(parsed))
+	}
+	if value, exists := layered.Lookup("PORT"); exists {
+		parsed, err := envied.CoerceInt("PORT", value)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithPORT(parsed))
+	}
+	if value, exists := layered.Lookup("TEMPERATURE"); exists {
+		parsed, err := envied.CoerceFloat("TEMPERATURE", value)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, WithTEMPERATURE(parsed))
+	}
+	return NewEnvironmentConfig(env, opts...)
+}
 
 // Static key for DATABASE_URL in dev environment
 var dev_enviedkeyDATABASE_URL = []int{1449781530, 4028288318, 417819965, 358674232, 1112285527, 3123658374, 3694091696, 2501759624, 468961263, 292956477, 2265301925, 334514377, 121595214, 4089868386, 2296291581, 3756391431}
@@ -26,24 +147,51 @@ var dev_enviedkeyDATABASE_URL = []int{1449781530, 4028288318, 417819965, 3586742
 // Static encrypted data for DATABASE_URL in dev environment
 var dev_envieddataDATABASE_URL = []int{1449781630, 4028288347, 417819979, 358674197, 1112285491, 3123658471, 3694091716, 2501759721, 468961165, 292956508, 2265301974, 334514348, 121595235, 4089868311, 2296291471, 3756391531}
 
+// Static key for MAX_TOKENS in dev environment
+var dev_enviedkeyMAX_TOKENS = []int{1449781530, 4028288318}
+
+// Static encrypted data for MAX_TOKENS in dev environment
+var dev_envieddataMAX_TOKENS = []int{1449781547, 4028288270}
+
 // DevConfigConfig - generated configuration for dev environment
 type DevConfigConfig struct {
 	DATABASE_URL string
-	DEBUG_MODE bool
-	PORT int
-	TEMPERATURE float64
-	MAX_TOKENS string
+	DEBUG_MODE   bool
+	MAX_TOKENS   string
+	PORT         int
+	TEMPERATURE  float64
 }
 
-// NewDevConfigConfig creates a new configuration for dev environment
-func NewDevConfigConfig() *DevConfigConfig {
-	return &DevConfigConfig{
-		DATABASE_URL: envied.DeobfuscateString(dev_enviedkeyDATABASE_URL, dev_envieddataDATABASE_URL),
-		DEBUG_MODE: envied.ParseBool("true"),
-		PORT: envied.ParseInt("10000"),
-		TEMPERATURE: envied.ParseFloat("0.1"),
-		MAX_TOKENS: envied.DeobfuscateString(dev_enviedkeyMAX_TOKENS, dev_envieddataMAX_TOKENS),
+// NewDevConfigConfig creates a new configuration for dev environment,
+// overrides replace the embedded values
+func NewDevConfigConfig(opts ...Override) *DevConfigConfig {
+	c := &DevConfigConfig{
+		DATABASE_URL: envied.MustDecodeString(dev_enviedkeyDATABASE_URL, dev_envieddataDATABASE_URL),
+		DEBUG_MODE:   envied.ParseBool("true"),
+		MAX_TOKENS:   envied.MustDecodeString(dev_enviedkeyMAX_TOKENS, dev_envieddataMAX_TOKENS),
+		PORT:         envied.ParseInt("10000"),
+		TEMPERATURE:  envied.ParseFloat("0.1"),
+	}
+	var o enviedOverrides
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.DATABASE_URL != nil {
+		c.DATABASE_URL = *o.DATABASE_URL
 	}
+	if o.DEBUG_MODE != nil {
+		c.DEBUG_MODE = *o.DEBUG_MODE
+	}
+	if o.MAX_TOKENS != nil {
+		c.MAX_TOKENS = *o.MAX_TOKENS
+	}
+	if o.PORT != nil {
+		c.PORT = *o.PORT
+	}
+	if o.TEMPERATURE != nil {
+		c.TEMPERATURE = *o.TEMPERATURE
+	}
+	return c
 }
 
 // Getter methods for DevConfigConfig
@@ -55,6 +203,10 @@ func (c *DevConfigConfig) GetDEBUG_MODE() bool {
 	return c.DEBUG_MODE
 }
 
+func (c *DevConfigConfig) GetMAX_TOKENS() string {
+	return c.MAX_TOKENS
+}
+
 func (c *DevConfigConfig) GetPORT() int {
 	return c.PORT
 }
@@ -63,8 +215,37 @@ func (c *DevConfigConfig) GetTEMPERATURE() float64 {
 	return c.TEMPERATURE
 }
 
-func (c *DevConfigConfig) GetMAX_TOKENS() string {
-	return c.MAX_TOKENS
+// Lookup returns the value of a variable formatted as in a .env file,
+// so the configuration can be used as the embedded layer of envied.Layer
+func (c *DevConfigConfig) Lookup(name string) (string, bool) {
+	switch name {
+	case "DATABASE_URL":
+		return envied.FormatValue(c.DATABASE_URL), true
+	case "DEBUG_MODE":
+		return envied.FormatValue(c.DEBUG_MODE), true
+	case "MAX_TOKENS":
+		return envied.FormatValue(c.MAX_TOKENS), true
+	case "PORT":
+		return envied.FormatValue(c.PORT), true
+	case "TEMPERATURE":
+		return envied.FormatValue(c.TEMPERATURE), true
+	}
+	return "", false
+}
+
+// Environment returns the environment name the configuration was generated for
+func (c *DevConfigConfig) Environment() string {
+	return "dev"
+}
+
+// GeneratedAt returns the time the configuration was generated
+func (c *DevConfigConfig) GeneratedAt() time.Time {
+	return time.Unix(1792181107, 0).UTC()
+}
+
+// SourceHash returns the SHA-256 of the env file the configuration was generated from
+func (c *DevConfigConfig) SourceHash() string {
+	return "e04be5e2abe0b57b0ff44e04a5771c56c21b5da3b3ae36bf8fa07a2e1f4cdfbc"
 }
 
 // Static key for DATABASE_URL in prod environment
@@ -81,33 +262,46 @@ var prod_envieddataMAX_TOKENS = []int{1449781547, 4028288270, 417819917, 3586741
 
 // ProdConfigConfig - generated configuration for prod environment
 type ProdConfigConfig struct {
-	TEMPERATURE float64
-	MAX_TOKENS string
 	DATABASE_URL string
-	DEBUG_MODE bool
-	PORT int
+	DEBUG_MODE   bool
+	MAX_TOKENS   string
+	PORT         int
+	TEMPERATURE  float64
 }
 
-// NewProdConfigConfig creates a new configuration for prod environment
-func NewProdConfigConfig() *ProdConfigConfig {
-	return &ProdConfigConfig{
-		TEMPERATURE: envied.ParseFloat("0.8"),
-		MAX_TOKENS: envied.DeobfuscateString(prod_enviedkeyMAX_TOKENS, prod_envieddataMAX_TOKENS),
-		DATABASE_URL: envied.DeobfuscateString(prod_enviedkeyDATABASE_URL, prod_envieddataDATABASE_URL),
-		DEBUG_MODE: envied.ParseBool("false"),
-		PORT: envied.ParseInt("80"),
+// NewProdConfigConfig creates a new configuration for prod environment,
+// overrides replace the embedded values
+func NewProdConfigConfig(opts ...Override) *ProdConfigConfig {
+	c := &ProdConfigConfig{
+		DATABASE_URL: envied.MustDecodeString(prod_enviedkeyDATABASE_URL, prod_envieddataDATABASE_URL),
+		DEBUG_MODE:   envied.ParseBool("false"),
+		MAX_TOKENS:   envied.MustDecodeString(prod_enviedkeyMAX_TOKENS, prod_envieddataMAX_TOKENS),
+		PORT:         envied.ParseInt("80"),
+		TEMPERATURE:  envied.ParseFloat("0.8"),
+	}
+	var o enviedOverrides
+	for _, opt := range opts {
+		opt(&o)
 	}
+	if o.DATABASE_URL != nil {
+		c.DATABASE_URL = *o.DATABASE_URL
+	}
+	if o.DEBUG_MODE != nil {
+		c.DEBUG_MODE = *o.DEBUG_MODE
+	}
+	if o.MAX_TOKENS != nil {
+		c.MAX_TOKENS = *o.MAX_TOKENS
+	}
+	if o.PORT != nil {
+		c.PORT = *o.PORT
+	}
+	if o.TEMPERATURE != nil {
+		c.TEMPERATURE = *o.TEMPERATURE
+	}
+	return c
 }
 
 // Getter methods for ProdConfigConfig
-func (c *ProdConfigConfig) GetTEMPERATURE() float64 {
-	return c.TEMPERATURE
-}
-
-func (c *ProdConfigConfig) GetMAX_TOKENS() string {
-	return c.MAX_TOKENS
-}
-
 func (c *ProdConfigConfig) GetDATABASE_URL() string {
 	return c.DATABASE_URL
 }
@@ -116,7 +310,47 @@ func (c *ProdConfigConfig) GetDEBUG_MODE() bool {
 	return c.DEBUG_MODE
 }
 
+func (c *ProdConfigConfig) GetMAX_TOKENS() string {
+	return c.MAX_TOKENS
+}
+
 func (c *ProdConfigConfig) GetPORT() int {
 	return c.PORT
 }
 
+func (c *ProdConfigConfig) GetTEMPERATURE() float64 {
+	return c.TEMPERATURE
+}
+
+// Lookup returns the value of a variable formatted as in a .env file,
+// so the configuration can be used as the embedded layer of envied.Layer
+func (c *ProdConfigConfig) Lookup(name string) (string, bool) {
+	switch name {
+	case "DATABASE_URL":
+		return envied.FormatValue(c.DATABASE_URL), true
+	case "DEBUG_MODE":
+		return envied.FormatValue(c.DEBUG_MODE), true
+	case "MAX_TOKENS":
+		return envied.FormatValue(c.MAX_TOKENS), true
+	case "PORT":
+		return envied.FormatValue(c.PORT), true
+	case "TEMPERATURE":
+		return envied.FormatValue(c.TEMPERATURE), true
+	}
+	return "", false
+}
+
+// Environment returns the environment name the configuration was generated for
+func (c *ProdConfigConfig) Environment() string {
+	return "prod"
+}
+
+// GeneratedAt returns the time the configuration was generated
+func (c *ProdConfigConfig) GeneratedAt() time.Time {
+	return time.Unix(1792181107, 0).UTC()
+}
+
+// SourceHash returns the SHA-256 of the env file the configuration was generated from
+func (c *ProdConfigConfig) SourceHash() string {
+	return "fd2e4d24da02d6c7378925058486f6f952a93e397e762b7aa42e124421123346"
+}
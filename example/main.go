@@ -41,12 +41,7 @@ func getEnv() (string, error) {
 
 	// Check positional arguments (for compatibility with go run main.go dev)
 	if len(flag.Args()) > 0 {
-		switch flag.Args()[0] {
-		case configPkg.EnvDev:
-			env = configPkg.EnvDev
-		default:
-			env = configPkg.EnvProd
-		}
+		env = flag.Args()[0]
 	} else {
 		// Default to production environment
 		env = configPkg.EnvProd
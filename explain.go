@@ -0,0 +1,140 @@
+package envied
+
+import "strings"
+
+// VariableExplanation describes where a variable comes from and what is generated for it.
+// Values are not included, they may be secrets.
+type VariableExplanation struct {
+	Name         string
+	Description  string
+	Transforms   []string                 // Transforms applied before typing and obfuscation
+	Only         []string                 // Environments the variable is restricted to, empty if not conditional
+	Environments []EnvironmentExplanation // Environments defining the variable, sorted by name
+	Missing      []string                 // Environments not defining the variable
+	Identifiers  []string                 // Generated identifiers shared by environments, e.g. ConfigInterface.GetPORT
+	ValuesDiffer bool                     // Whether the value or type differs between the defining environments
+}
+
+// EnvironmentExplanation describes a variable in a single environment
+type EnvironmentExplanation struct {
+	Environment string
+	Source      string    // file:line of the definition, or the remote source name
+	Overrides   []string  // Sources of the values replaced by overlays, in layering order
+	Type        FieldType // Generated type
+	Declared    bool      // Type set by type, separator or time_layout instead of detected from the value
+	Obfuscation string    // Obfuscation algorithm of the embedded value, ObfuscationNone if embedded in plain text
+	ValueGroup  int       // Environments with the same value and type share a group, numbered from 1
+	Identifiers []string  // Generated identifiers of the environment and its profiles, e.g. ProdConfig.GetPORT
+}
+
+// Explain describes a variable across all environments: where it is defined, its type,
+// whether it is obfuscated, which generated identifiers hold it and which environments differ
+func Explain(opts GenerateOptions, name string) (*VariableExplanation, error) {
+	var explanation *VariableExplanation
+	err := opts.run(func() error {
+		var err error
+		explanation, err = explain(opts, name)
+		return err
+	})
+	return explanation, err
+}
+
+// explain implements Explain
+func explain(opts GenerateOptions, name string) (*VariableExplanation, error) {
+	configFile, configPath, _, err := opts.load()
+	if err != nil {
+		return nil, err
+	}
+
+	// A variable missing in some environments is worth explaining, not an error
+	configFile.AllowExtraVariables = true
+	mergedData, err := buildMergedConfig(configFile, configPath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	variable := configFile.Variables[name]
+	explanation := &VariableExplanation{
+		Name:       name,
+		Transforms: variable.Transform,
+		Only:       variable.Only,
+	}
+	declared := variable.Type != "" || variable.Separator != "" || variable.TimeLayout != ""
+
+	type valueKey struct {
+		fieldType FieldType
+		value     string
+	}
+	groups := make(map[valueKey]int)
+
+	for _, envName := range sortedEnvironmentNames(mergedData.Environments) {
+		envData := mergedData.Environments[envName]
+		field, found := findField(envData.Fields, name)
+		if !found {
+			explanation.Missing = append(explanation.Missing, envName)
+			continue
+		}
+		if explanation.Description == "" {
+			explanation.Description = field.Description
+		}
+
+		provenance := envData.Provenance[name]
+
+		key := valueKey{field.Type, field.Value}
+		if _, exists := groups[key]; !exists {
+			groups[key] = len(groups) + 1
+		}
+
+		structName := envData.StructName + "Config"
+		env := EnvironmentExplanation{
+			Environment: envName,
+			Source:      provenance.Source,
+			Overrides:   provenance.Overrides,
+			Type:        field.Type,
+			Declared:    declared,
+			Obfuscation: ObfuscationNone,
+			ValueGroup:  groups[key],
+			Identifiers: []string{structName + "." + field.FieldName, structName + "." + field.Getter},
+		}
+		if obfuscated := envData.Obfuscated[name]; obfuscated != nil {
+			envPrefix := strings.ToLower(envName)
+			env.Obfuscation = mergedData.Obfuscation
+			env.Identifiers = append(env.Identifiers, envPrefix+obfuscated.KeyName, envPrefix+obfuscated.ValueName)
+		}
+		if variable, linked := envData.Linked[name]; linked {
+			env.Identifiers = append(env.Identifiers, variable)
+		}
+		if _, extra := findField(envData.Extras, name); extra {
+			env.Identifiers = append(env.Identifiers, envData.StructName+"Interface."+field.Getter)
+		}
+		for _, profile := range envData.Profiles {
+			if _, selected := findField(profile.Fields, name); selected {
+				env.Identifiers = append(env.Identifiers, profile.StructName+"Config."+field.Getter)
+			}
+		}
+		explanation.Environments = append(explanation.Environments, env)
+	}
+
+	if len(explanation.Environments) == 0 {
+		return nil, newError(MessageUndefinedVariable, name)
+	}
+	explanation.ValuesDiffer = len(groups) > 1
+
+	if field, shared := findField(mergedData.AllFields, name); shared {
+		explanation.Identifiers = append(explanation.Identifiers, "ConfigInterface."+field.Getter)
+	}
+	if _, overridable := findField(overridableFields(mergedData), name); overridable {
+		explanation.Identifiers = append(explanation.Identifiers, "With"+name)
+	}
+	return explanation, nil
+}
+
+// findField returns the field with the given variable name
+func findField(fields []Field, name string) (Field, bool) {
+	for _, field := range fields {
+		if field.EnvName == name {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
@@ -0,0 +1,32 @@
+package envied
+
+import "sort"
+
+// mergeFieldTypes returns the variable settings with the types pinned in the fields section.
+// A variable can't be pinned to a type different from its own type setting.
+func mergeFieldTypes(fields map[string]FieldType, variables map[string]VariableConfig) (map[string]VariableConfig, error) {
+	if len(fields) == 0 {
+		return variables, nil
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	merged := make(map[string]VariableConfig, len(variables)+len(fields))
+	for name, variable := range variables {
+		merged[name] = variable
+	}
+	for _, name := range names {
+		fieldType := fields[name]
+		variable := merged[name]
+		if variable.Type != "" && variable.Type != string(fieldType) {
+			return nil, newError(MessageFieldTypeConflict, name, fieldType, variable.Type)
+		}
+		variable.Type = string(fieldType)
+		merged[name] = variable
+	}
+	return merged, nil
+}
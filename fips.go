@@ -0,0 +1,159 @@
+package envied
+
+import (
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"strings"
+)
+
+// Crypto modes restricting the primitives used by the generator and generated code
+const (
+	CryptoModeDefault = ""     // All obfuscation modes are allowed
+	CryptoModeFIPS    = "fips" // Only FIPS-approved primitives: AES-256-GCM and HKDF-SHA256
+)
+
+// Algorithm names reported in the manifest
+const (
+	algorithmXOR        = "XOR"
+	algorithmAESGCM     = "AES-256-GCM"
+	algorithmHKDFSHA256 = "HKDF-SHA256"
+)
+
+// obfuscationMode returns the obfuscation algorithm used for obfuscated environments and
+// whether obfuscation is enabled by default, validating it against the crypto mode
+func (c *ConfigFile) obfuscationMode() (string, bool, error) {
+	var fips bool
+	switch c.CryptoMode {
+	case CryptoModeDefault:
+	case CryptoModeFIPS:
+		fips = true
+	default:
+		return "", false, newError(MessageUnknownCryptoMode, c.CryptoMode, CryptoModeFIPS)
+	}
+
+	algorithm := ObfuscationXOR
+	if fips {
+		algorithm = ObfuscationAESGCM
+	}
+
+	switch c.Obfuscation {
+	case "":
+		return algorithm, true, nil
+	case ObfuscationXOR:
+		if fips {
+			return "", false, newError(MessageCryptoModeForbidsObfuscation, CryptoModeFIPS, ObfuscationXOR, ObfuscationAESGCM, ObfuscationNone)
+		}
+		return ObfuscationXOR, true, nil
+	case ObfuscationAESGCM:
+		return ObfuscationAESGCM, true, nil
+	case ObfuscationNone:
+		return algorithm, false, nil
+	default:
+		return "", false, newError(MessageUnknownObfuscationMode, c.Obfuscation, ObfuscationXOR, ObfuscationAESGCM, ObfuscationNone)
+	}
+}
+
+// SealString encrypts a value with AES-256-GCM under a key derived with HKDF-SHA256 and returns
+// the key and nonce-prefixed ciphertext. With a non-zero seed the output is reproducible: the key
+// is derived from the seed and name, the nonce additionally from the value, so equal nonces only
+// occur for equal plaintexts. Without a seed key and nonce are random.
+func SealString(name, value string, seed int64) ([]byte, []byte, error) {
+	secret := make([]byte, 32)
+	if seed == 0 {
+		if _, err := rand.Read(secret); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		binary.BigEndian.PutUint64(secret, uint64(seed))
+	}
+
+	key, err := hkdf.Key(sha256.New, secret, nil, "go-envied aes-gcm key "+name, 32)
+	if err != nil {
+		return nil, nil, err
+	}
+	gcm, err := newArtifactCipher(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if seed == 0 {
+		if _, err := rand.Read(nonce); err != nil {
+			return nil, nil, err
+		}
+	} else if nonce, err = hkdf.Key(sha256.New, secret, []byte(value), "go-envied aes-gcm nonce "+name, gcm.NonceSize()); err != nil {
+		return nil, nil, err
+	}
+
+	return key, gcm.Seal(nonce, nonce, []byte(value), []byte(name)), nil
+}
+
+// OpenString decrypts a value sealed by SealString
+func OpenString(name string, key, data []byte) (string, error) {
+	gcm, err := newArtifactCipher(key)
+	if err != nil {
+		return "", err
+	}
+	if len(data) < gcm.NonceSize() {
+		return "", newError(MessageRuntimeSealedValueTruncated, name)
+	}
+	plaintext, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], []byte(name))
+	if err != nil {
+		return "", newError(MessageRuntimeSealedValueOpenFailed, name)
+	}
+	return string(plaintext), nil
+}
+
+// MustOpenString is like OpenString but panics on error.
+// Generated code uses it, since a failure means the generated file is corrupted.
+func MustOpenString(name string, key, data []byte) string {
+	value, err := OpenString(name, key, data)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// joinBytes formats bytes as "0x01, 0x02" for slice literals
+func joinBytes(data []byte) string {
+	var b strings.Builder
+	for i, v := range data {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		fmt.Fprintf(&b, "0x%02x", v)
+	}
+	return b.String()
+}
+
+// ManifestCrypto reports the algorithms protecting a generated configuration
+type ManifestCrypto struct {
+	Mode               string `json:"mode"`                          // default or fips
+	Obfuscation        string `json:"obfuscation"`                   // XOR, AES-256-GCM or none
+	KeyDerivation      string `json:"key_derivation,omitempty"`      // HKDF-SHA256 for AES-256-GCM obfuscation
+	ArtifactEncryption string `json:"artifact_encryption,omitempty"` // AES-256-GCM if emitted files are encrypted
+}
+
+// manifestCrypto describes the algorithms of the merged configuration
+func manifestCrypto(data *mergedConfig) *ManifestCrypto {
+	crypto := &ManifestCrypto{Mode: "default", Obfuscation: "none"}
+	if data.CryptoMode != CryptoModeDefault {
+		crypto.Mode = data.CryptoMode
+	}
+
+	for _, envData := range data.Environments {
+		if len(envData.Obfuscated) == 0 {
+			continue
+		}
+		if data.Obfuscation == ObfuscationAESGCM {
+			crypto.Obfuscation = algorithmAESGCM
+			crypto.KeyDerivation = algorithmHKDFSHA256
+		} else {
+			crypto.Obfuscation = algorithmXOR
+		}
+	}
+	return crypto
+}
@@ -0,0 +1,121 @@
+package envied
+
+import (
+	"bytes"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// generatedHeader marks files generated by go-envied
+const generatedHeader = "// Code generated by go-envied. DO NOT EDIT."
+
+// fixRenames maps deprecated runtime helpers to their replacements
+var fixRenames = map[string]string{
+	"DeobfuscateString": "MustDecodeString",
+}
+
+// runtimeImportNames returns the local names under which a file imports the envied runtime
+func runtimeImportNames(file *ast.File) map[string]bool {
+	names := make(map[string]bool)
+	for _, spec := range file.Imports {
+		importPath, err := strconv.Unquote(spec.Path.Value)
+		if err != nil {
+			continue
+		}
+		if spec.Name != nil {
+			if spec.Name.Name == "envied" || importPath == RuntimeImportPath {
+				names[spec.Name.Name] = true
+			}
+			continue
+		}
+		switch path.Base(importPath) {
+		case "envied", "go-envied":
+			names["envied"] = true
+		}
+	}
+	return names
+}
+
+// FixFile rewrites a file generated by go-envied to the current runtime API,
+// replacing calls of deprecated helpers. It returns whether the file was changed.
+// Files not generated by go-envied are left untouched.
+func FixFile(filename string) (bool, error) {
+	src, err := os.ReadFile(filename)
+	if err != nil {
+		return false, err
+	}
+	if !bytes.HasPrefix(src, []byte(generatedHeader)) {
+		return false, nil
+	}
+
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return false, err
+	}
+
+	localNames := runtimeImportNames(file)
+	changed := false
+	ast.Inspect(file, func(node ast.Node) bool {
+		selector, ok := node.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		ident, ok := selector.X.(*ast.Ident)
+		if !ok || !localNames[ident.Name] {
+			return true
+		}
+		if replacement, exists := fixRenames[selector.Sel.Name]; exists {
+			selector.Sel.Name = replacement
+			changed = true
+		}
+		return true
+	})
+
+	if !changed {
+		return false, nil
+	}
+
+	var buf bytes.Buffer
+	if err := format.Node(&buf, fset, file); err != nil {
+		return false, err
+	}
+	return true, os.WriteFile(filename, buf.Bytes(), 0644)
+}
+
+// FixDir applies FixFile to every Go file under dir and returns the changed files
+func FixDir(dir string) ([]string, error) {
+	var changed []string
+	err := filepath.WalkDir(dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if entry.IsDir() {
+			if path != dir && (strings.HasPrefix(entry.Name(), ".") || entry.Name() == "vendor") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if !strings.HasSuffix(path, ".go") {
+			return nil
+		}
+
+		fixed, err := FixFile(path)
+		if err != nil {
+			return err
+		}
+		if fixed {
+			changed = append(changed, path)
+		}
+		return nil
+	})
+	return changed, err
+}
@@ -0,0 +1,67 @@
+package envied
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// fixtureTime is the value of time.Time variables in fixtures
+var fixtureTime = time.Date(2000, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// fixtureValue returns a fake value of the type of a field, derived from its name only, so
+// fixtures are reproducible and never contain real values. Values are typed the same way as the
+// original when the fixture is read as an env file.
+func fixtureValue(field Field) string {
+	fake := "fake-" + strings.ToLower(strings.ReplaceAll(field.EnvName, "_", "-"))
+
+	switch field.Type {
+	case FieldTypeBool:
+		return "true"
+	case FieldTypeInt, FieldTypeInt64, FieldTypeUint64:
+		// Not 0 or 1, which are detected as bool
+		return "42"
+	case FieldTypeFloat:
+		return "1.5"
+	case FieldTypeTime:
+		return fixtureTime.Format(field.Layout)
+	case FieldTypeStringSlice:
+		separator := field.Separator
+		if separator == "" {
+			separator = DefaultSeparator
+		}
+		return strconv.Quote(fake + "-1" + separator + fake + "-2")
+	case FieldTypeBytes:
+		return FormatBytes(field.Encoding, []byte(fake))
+	case FieldTypeStringMap, FieldTypeJSON:
+		return `{"key":"value"}`
+	case FieldTypeBoolMap:
+		return `{"key":true}`
+	case FieldTypeIntMap:
+		return `{"key":42}`
+	case FieldTypeFloatMap:
+		return `{"key":1.5}`
+	default:
+		// Quoted, so the value stays a string whatever the name
+		return strconv.Quote(fake)
+	}
+}
+
+// renderFixture renders a .env fixture of an environment with the variables of the model and
+// fake values, for integration tests that must not see real values
+func renderFixture(envData mergedEnvironment, names map[string]string) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", strings.TrimPrefix(generatedHeader, "// "))
+	for _, field := range envData.Fields {
+		fmt.Fprintf(&buf, "%s=%s\n", exportedName(names, field.EnvName), fixtureValue(field))
+	}
+	return buf.Bytes()
+}
+
+// fixturePath returns the path of the fixture of an environment in the fixtures directory
+func fixturePath(dir, envName string) string {
+	return filepath.Join(dir, envName+".env")
+}
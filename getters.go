@@ -0,0 +1,94 @@
+package envied
+
+import (
+	"go/token"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Getter receivers
+const (
+	GetterReceiverPointer = "pointer" // func (c *DevConfig) GetPORT() (default)
+	GetterReceiverValue   = "value"   // func (c DevConfig) GetPORT()
+)
+
+// DefaultGetterPrefix is prepended to variable names to name getters
+const DefaultGetterPrefix = "Get"
+
+// reservedMethodNames are the methods every generated configuration has besides getters
+var reservedMethodNames = map[string]bool{"Lookup": true, "Environment": true, "GeneratedAt": true, "SourceHash": true}
+
+// GettersConfig customizes the names and receivers of generated getters
+type GettersConfig struct {
+	Prefix     *string `json:"prefix,omitempty"`     // Prepended to variable names, DefaultGetterPrefix if not set
+	Receiver   string  `json:"receiver,omitempty"`   // GetterReceiverPointer (default) or GetterReceiverValue
+	Unexported bool    `json:"unexported,omitempty"` // Lowercases the first letter of getter names derived from variable names
+}
+
+// validate checks the receiver, nil means the defaults
+func (g *GettersConfig) validate() error {
+	if g == nil {
+		return nil
+	}
+	switch g.Receiver {
+	case "", GetterReceiverPointer, GetterReceiverValue:
+		return nil
+	default:
+		return newError(MessageUnknownGetterReceiver, g.Receiver, GetterReceiverPointer, GetterReceiverValue)
+	}
+}
+
+// receiver returns the receiver type prefix of getters: "*" for pointer receivers
+func (g *GettersConfig) receiver() string {
+	if g != nil && g.Receiver == GetterReceiverValue {
+		return ""
+	}
+	return "*"
+}
+
+// getterName returns the getter of a variable: its getter setting, or the name given by namer
+// with the configured prefix, lowercased for unexported getters
+func (g *GettersConfig) getterName(name string, variable VariableConfig, namer Namer) string {
+	if variable.Getter != "" {
+		return variable.Getter
+	}
+
+	prefix := DefaultGetterPrefix
+	if g != nil && g.Prefix != nil {
+		prefix = *g.Prefix
+	}
+	getter := prefix + namer.Getter(name)
+	if g != nil && g.Unexported {
+		first, size := utf8.DecodeRuneInString(getter)
+		getter = string(unicode.ToLower(first)) + getter[size:]
+	}
+	return getter
+}
+
+// applyGetterNames names the getters of fields and fails for names that aren't identifiers or
+// that collide with a field, another getter or a generated method
+func applyGetterNames(fields []Field, variables map[string]VariableConfig, getters *GettersConfig, namer Namer) error {
+	fieldNames := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		fieldNames[field.FieldName] = true
+	}
+
+	owners := make(map[string]string, len(fields))
+	for i := range fields {
+		name := fields[i].EnvName
+		getter := getters.getterName(name, variables[name], namer)
+		switch {
+		case !token.IsIdentifier(getter):
+			return newError(MessageInvalidGetterName, name, getter)
+		case fieldNames[getter]:
+			return newError(MessageGetterCollidesWithField, name, getter, getter)
+		case reservedMethodNames[getter]:
+			return newError(MessageGetterCollidesWithMethod, name, getter)
+		case owners[getter] != "":
+			return newError(MessageDuplicateGetter, owners[getter], name, getter)
+		}
+		owners[getter] = name
+		fields[i].Getter = getter
+	}
+	return nil
+}
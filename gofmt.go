@@ -0,0 +1,26 @@
+package envied
+
+import (
+	"bytes"
+	"errors"
+	"go/format"
+	"go/scanner"
+)
+
+// formatGenerated formats generated Go source like gofmt. Source that doesn't parse is a bug of
+// the generator, so it fails generation with the first error and its line instead of writing it.
+func formatGenerated(src []byte) ([]byte, error) {
+	formatted, err := format.Source(src)
+	if err == nil {
+		return formatted, nil
+	}
+
+	var errs scanner.ErrorList
+	if errors.As(err, &errs) && len(errs) > 0 {
+		lines := bytes.Split(src, []byte("\n"))
+		if line := errs[0].Pos.Line; line >= 1 && line <= len(lines) {
+			return nil, newError(MessageInvalidGeneratedCodeLine, errs[0], bytes.TrimSpace(lines[line-1]))
+		}
+	}
+	return nil, newError(MessageInvalidGeneratedCode, err)
+}
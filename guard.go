@@ -0,0 +1,108 @@
+package envied
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+)
+
+// networkDisabled is set while any WithoutNetwork call runs
+var networkDisabled atomic.Bool
+
+// networkGuard counts the running WithoutNetwork calls and holds the process-wide defaults
+// they replaced, the first call installs the guard and the last one restores the defaults
+var networkGuard struct {
+	mu           sync.Mutex
+	active       int
+	transport    http.RoundTripper
+	preferGo     bool
+	resolverDial func(ctx context.Context, network, address string) (net.Conn, error)
+}
+
+// networkViolation panics with the attempted network access
+func networkViolation(network, address string) {
+	panic(fmt.Sprintf("❌ envied: network access to %s %s attempted in no-network mode", network, address))
+}
+
+// dialContext dials like net.Dialer unless the network is disabled, used by all remote sources
+func dialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	if networkDisabled.Load() {
+		networkViolation(network, address)
+	}
+	var dialer net.Dialer
+	return dialer.DialContext(ctx, network, address)
+}
+
+// guardTransport is the http.DefaultTransport installed by WithoutNetwork
+type guardTransport struct{}
+
+func (guardTransport) RoundTrip(request *http.Request) (*http.Response, error) {
+	networkViolation("http", request.URL.Host)
+	return nil, nil
+}
+
+// WithoutNetwork runs fn with network access turned into panics: the dialer of all go-envied
+// sources, http.DefaultTransport and the pure Go DNS resolver panic when used, so a
+// generation run provably cannot send secrets anywhere. Process-wide defaults are restored
+// when the last of concurrent or nested calls returns, so every fn runs guarded until it
+// returns; other goroutines using the network meanwhile panic as well.
+//
+// http.DefaultTransport and net.DefaultResolver are plain variables: replacing them is a data
+// race with goroutines reading them at the same time, which the race detector reports. Call
+// WithoutNetwork before starting goroutines that use the default HTTP client or resolver.
+func WithoutNetwork(fn func() error) error {
+	installNetworkGuard()
+	defer removeNetworkGuard()
+	return fn()
+}
+
+// installNetworkGuard replaces the process-wide defaults unless a running call already did
+func installNetworkGuard() {
+	networkGuard.mu.Lock()
+	defer networkGuard.mu.Unlock()
+
+	networkGuard.active++
+	if networkGuard.active > 1 {
+		return
+	}
+	networkGuard.transport = http.DefaultTransport
+	networkGuard.preferGo, networkGuard.resolverDial = net.DefaultResolver.PreferGo, net.DefaultResolver.Dial
+	http.DefaultTransport = guardTransport{}
+	net.DefaultResolver.PreferGo = true
+	net.DefaultResolver.Dial = func(ctx context.Context, network, address string) (net.Conn, error) {
+		networkViolation(network, address)
+		return nil, nil
+	}
+	networkDisabled.Store(true)
+}
+
+// removeNetworkGuard restores the process-wide defaults when the last running call returns
+func removeNetworkGuard() {
+	networkGuard.mu.Lock()
+	defer networkGuard.mu.Unlock()
+
+	networkGuard.active--
+	if networkGuard.active > 0 {
+		return
+	}
+	networkDisabled.Store(false)
+	http.DefaultTransport = networkGuard.transport
+	net.DefaultResolver.PreferGo, net.DefaultResolver.Dial = networkGuard.preferGo, networkGuard.resolverDial
+	networkGuard.transport, networkGuard.resolverDial = nil, nil
+}
+
+// checkOffline fails for environments whose source needs network access
+func checkOffline(configFile *ConfigFile) error {
+	for _, envName := range configFile.environmentNames() {
+		if remote := configFile.Environments[envName].remoteSources(); len(remote) > 0 {
+			return newError(MessageNoNetworkRemoteSource, envName, remote[0])
+		}
+		if configFile.valuesFrom == ValuesFromVault && configFile.Environments[envName].Vault != nil {
+			return newError(MessageNoNetworkVault, envName)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,66 @@
+package envied
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// helmEnvironmentPlaceholder is replaced with the environment name in emit.helm_secret
+const helmEnvironmentPlaceholder = "{environment}"
+
+// invalidSecretNameChars matches characters not allowed in Kubernetes Secret names
+var invalidSecretNameChars = regexp.MustCompile(`[^a-z0-9.-]+`)
+
+// helmSecretName returns the name of the Kubernetes Secret holding the sensitive values of an
+// environment: emit.helm_secret with {environment} replaced, <package_name>-<environment> if empty
+func helmSecretName(emit *EmitConfig, packageName, envName string) string {
+	pattern := emit.HelmSecret
+	if pattern == "" {
+		pattern = packageName + "-" + helmEnvironmentPlaceholder
+	}
+	name := strings.ToLower(strings.ReplaceAll(pattern, helmEnvironmentPlaceholder, envName))
+	return strings.Trim(invalidSecretNameChars.ReplaceAllString(name, "-"), "-.")
+}
+
+// renderHelmValues renders a Helm values file of an environment with the env section of a
+// Deployment in env and references to a Secret for sensitive values in secretEnv. Sensitive
+// values, obfuscated in generated code or marked sensitive, are never written. Variables are
+// named by names, Secret keys by secretNames like in the SealedSecret.
+func renderHelmValues(envName string, envData mergedEnvironment, secretName string, names, secretNames map[string]string) []byte {
+	var env, secretEnv bytes.Buffer
+	for _, field := range envData.Fields {
+		name := exportedName(names, field.EnvName)
+		if envData.isSensitive(field.EnvName) {
+			fmt.Fprintf(&secretEnv, "  - name: %s\n    valueFrom:\n      secretKeyRef:\n        name: %s\n        key: %s\n", name, secretName, exportedName(secretNames, field.EnvName))
+			continue
+		}
+		// Kubernetes env values are strings, Go quoting is valid YAML
+		fmt.Fprintf(&env, "  - name: %s\n    value: %s\n", name, strconv.Quote(field.Value))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", strings.TrimPrefix(generatedHeader, "// "))
+	fmt.Fprintf(&buf, "# Environment: %s\n", envName)
+	writeHelmList(&buf, "env", env.Bytes())
+	writeHelmList(&buf, "secretEnv", secretEnv.Bytes())
+	return buf.Bytes()
+}
+
+// writeHelmList writes a list of a values file, [] if it has no entries
+func writeHelmList(buf *bytes.Buffer, name string, entries []byte) {
+	if len(entries) == 0 {
+		fmt.Fprintf(buf, "%s: []\n", name)
+		return
+	}
+	fmt.Fprintf(buf, "%s:\n", name)
+	buf.Write(entries)
+}
+
+// helmValuesPath returns the path of the Helm values file of an environment in the Helm directory
+func helmValuesPath(dir, envName string) string {
+	return filepath.Join(dir, "values-"+envName+".yaml")
+}
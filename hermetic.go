@@ -0,0 +1,111 @@
+package envied
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// applyEnvFileOverrides replaces the sources of the named environments with the given env files
+func applyEnvFileOverrides(configFile *ConfigFile, envFiles map[string]string) error {
+	overrides := make([]string, 0, len(envFiles))
+	for envName := range envFiles {
+		overrides = append(overrides, envName)
+	}
+	sort.Strings(overrides)
+
+	for _, envName := range overrides {
+		envConfig, exists := configFile.Environments[envName]
+		if !exists {
+			return newError(MessageEnvFileUnknownEnvironment, envName)
+		}
+		configFile.Environments[envName] = envConfig.withEnvFile(envFiles[envName])
+		configFile.envFileOverrides = append(configFile.envFileOverrides, envName)
+	}
+	return nil
+}
+
+// HermeticOptions configures GenerateHermetic
+type HermeticOptions struct {
+	ConfigPath string            // Path to the configuration file (required)
+	OutputFile string            // Declared path of the generated file (required)
+	EnvFiles   map[string]string // Env file paths by environment name, overriding the configuration file
+	Set        map[string]string // Substitutions of {{.Name}} placeholders, overriding the configuration file
+
+	AssertDeterministic bool // Fail if two in-memory generations produce different output
+}
+
+// GenerateHermetic generates the merged configuration for build systems such as Bazel.
+// There is no configuration discovery and no dependence on the working directory:
+// relative paths inside the configuration file are resolved against its directory,
+// the result is written to OutputFile only and nothing is printed, errors are returned.
+// Environments read from remote sources or Vault are refused unless EnvFiles replaces them.
+func GenerateHermetic(opts HermeticOptions) error {
+	if opts.ConfigPath == "" {
+		return newError(MessageHermeticConfigPathRequired)
+	}
+	if opts.OutputFile == "" {
+		return newError(MessageHermeticOutputFileRequired)
+	}
+
+	configFile, err := LoadConfigFile(opts.ConfigPath)
+	if err != nil {
+		return err
+	}
+	if configFile.InternalPackage {
+		return newError(MessageHermeticInternalPackage)
+	}
+	if configFile.BuildTags {
+		return newError(MessageHermeticBuildTags)
+	}
+
+	configDir := filepath.Dir(opts.ConfigPath)
+	environments := make(map[string]EnvironmentConfig, len(configFile.Environments))
+	for envName, envConfig := range configFile.Environments {
+		if envConfig.EnvFile != "" && !filepath.IsAbs(envConfig.EnvFile) {
+			envConfig.EnvFile = filepath.Join(configDir, envConfig.EnvFile)
+		}
+		if envConfig.BaseEnvFile != "" && !filepath.IsAbs(envConfig.BaseEnvFile) {
+			envConfig.BaseEnvFile = filepath.Join(configDir, envConfig.BaseEnvFile)
+		}
+		overlays := make([]string, len(envConfig.Overlays))
+		for i, overlay := range envConfig.Overlays {
+			if !filepath.IsAbs(overlay) {
+				overlay = filepath.Join(configDir, overlay)
+			}
+			overlays[i] = overlay
+		}
+		envConfig.Overlays = overlays
+		// Local overlays are not declared inputs
+		envConfig.NoLocal = true
+		environments[envName] = envConfig
+	}
+
+	configFile.Environments = environments
+	if err := applyEnvFileOverrides(configFile, opts.EnvFiles); err != nil {
+		return err
+	}
+	// Remote sources are undeclared network inputs, env files given in EnvFiles replace them
+	for _, envName := range configFile.environmentNames() {
+		envConfig := configFile.Environments[envName]
+		if remote := envConfig.remoteSources(); len(remote) > 0 {
+			return newError(MessageHermeticRemoteSource, envName, strings.Join(remote, ", "))
+		}
+		if envConfig.Vault != nil {
+			return newError(MessageHermeticVault, envName)
+		}
+	}
+	configFile.substitutionOverrides = opts.Set
+	configFile.OutputDir = filepath.Dir(opts.OutputFile)
+	configFile.Emit = nil // Only the declared output is written
+
+	if opts.AssertDeterministic {
+		if err := assertDeterministic(configFile, ""); err != nil {
+			return err
+		}
+	}
+
+	// Outputs are declared by the build system, so the module location check does not apply
+	_, err = generateFromConfig(configFile, "", opts.OutputFile, nil)
+	return err
+}
@@ -0,0 +1,54 @@
+package envied
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// starterEnvFiles are the env files written by InitProject
+var starterEnvFiles = map[string]string{
+	"dev":  "# Development environment configuration\nAPP_NAME=myapp\nPORT=8080\nDEBUG_MODE=true\n",
+	"prod": "# Production environment configuration\nAPP_NAME=myapp\nPORT=80\nDEBUG_MODE=false\n",
+}
+
+// InitProject writes a starter go-envied-config.json with dev and prod environments into dir,
+// generating into internal/config. Existing env files are kept, an existing configuration
+// file is an error. It returns the written files.
+func InitProject(dir string) ([]string, error) {
+	configPath := filepath.Join(dir, DefaultConfigFileName)
+	if _, err := os.Stat(configPath); err == nil {
+		return nil, newError(MessageAlreadyExists, configPath)
+	}
+
+	configFile := &ConfigFile{
+		PackageName:  "config",
+		OutputDir:    filepath.Join("internal", "config"),
+		Environments: make(map[string]EnvironmentConfig),
+	}
+
+	var written []string
+	for _, envName := range []string{"dev", "prod"} {
+		envFile := filepath.Join("env", envName+".env")
+		configFile.Environments[envName] = EnvironmentConfig{
+			EnvFile:    envFile,
+			StructName: capitalize(envName),
+		}
+
+		path := filepath.Join(dir, envFile)
+		if _, err := os.Stat(path); err == nil {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return written, err
+		}
+		if err := os.WriteFile(path, []byte(starterEnvFiles[envName]), 0644); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	if err := configFile.Save(configPath); err != nil {
+		return written, err
+	}
+	return append(written, configPath), nil
+}
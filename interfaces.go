@@ -0,0 +1,75 @@
+package envied
+
+import "sort"
+
+// sharedFields returns the fields defined with the same type in every environment,
+// sorted by name. Conditional variables are never shared.
+func sharedFields(envFields map[string][]Field, variables map[string]VariableConfig) []Field {
+	types := make(map[string]FieldType)
+	layouts := make(map[string]string)
+	separators := make(map[string]string)
+	encodings := make(map[string]string)
+	getters := make(map[string]string)
+	fieldNames := make(map[string]string)
+	counts := make(map[string]int)
+	mismatched := make(map[string]bool)
+
+	for _, fields := range envFields {
+		for _, field := range fields {
+			if fieldType, seen := types[field.EnvName]; seen && fieldType != field.Type {
+				mismatched[field.EnvName] = true
+			}
+			types[field.EnvName] = field.Type
+			layouts[field.EnvName] = field.Layout
+			separators[field.EnvName] = field.Separator
+			encodings[field.EnvName] = field.Encoding
+			getters[field.EnvName] = field.Getter
+			fieldNames[field.EnvName] = field.FieldName
+			counts[field.EnvName]++
+		}
+	}
+
+	names := make([]string, 0, len(types))
+	for name := range types {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var shared []Field
+	for _, name := range names {
+		if counts[name] != len(envFields) || mismatched[name] || variables[name].isConditional() {
+			continue
+		}
+		shared = append(shared, Field{EnvName: name, FieldName: fieldNames[name], Type: types[name], Layout: layouts[name], Separator: separators[name], Encoding: encodings[name], Getter: getters[name], Description: variables[name].Description})
+	}
+	return shared
+}
+
+// extraFields returns the fields not part of the shared interface, sorted by name
+func extraFields(fields []Field, shared []Field) []Field {
+	sharedNames := make(map[string]bool, len(shared))
+	for _, field := range shared {
+		sharedNames[field.EnvName] = true
+	}
+
+	var extras []Field
+	for _, field := range fields {
+		if !sharedNames[field.EnvName] {
+			extras = append(extras, field)
+		}
+	}
+	sort.Slice(extras, func(i, j int) bool {
+		return extras[i].EnvName < extras[j].EnvName
+	})
+	return extras
+}
+
+// sortedEnvironmentNames returns environment names in sorted order
+func sortedEnvironmentNames(environments map[string]mergedEnvironment) []string {
+	names := make([]string, 0, len(environments))
+	for name := range environments {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,128 @@
+package envied
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// internalPackageDir is the directory below the module root receiving generated code with internal_package
+const internalPackageDir = "internal/envied"
+
+// shimImportName is the name the re-export shim imports the internal package as
+const shimImportName = "enviedinternal"
+
+// readModulePath returns the module path declared in the go.mod of moduleRoot
+func readModulePath(moduleRoot string) (string, error) {
+	file, err := os.Open(filepath.Join(moduleRoot, "go.mod"))
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if modulePath, found := strings.CutPrefix(line, "module "); found {
+			modulePath = strings.TrimSpace(modulePath)
+			if unquoted, err := strconv.Unquote(modulePath); err == nil {
+				modulePath = unquoted
+			}
+			return modulePath, nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", newError(MessageGoModWithoutModulePath, moduleRoot)
+}
+
+// generatedCodeFile returns the path the configuration code is generated to. With internal_package
+// it is internal/envied/<package_name> of the module containing outputFile and the import path of
+// that package is returned as well, outputFile then receives the re-export shim.
+func (c *ConfigFile) generatedCodeFile(outputFile string) (string, string, error) {
+	if !c.InternalPackage {
+		return outputFile, "", nil
+	}
+
+	moduleRoot := findModuleRoot(filepath.Dir(outputFile))
+	if moduleRoot == "" {
+		return "", "", newError(MessageInternalPackageOutsideModule, filepath.Dir(outputFile))
+	}
+	modulePath, err := readModulePath(moduleRoot)
+	if err != nil {
+		return "", "", err
+	}
+
+	codeFile := filepath.Join(moduleRoot, filepath.FromSlash(internalPackageDir), c.PackageName, filepath.Base(outputFile))
+	if absOutput, err := filepath.Abs(outputFile); err == nil && absOutput == codeFile {
+		return "", "", newError(MessageInternalPackageOutputDir, filepath.Dir(outputFile))
+	}
+	return codeFile, path.Join(modulePath, internalPackageDir, c.PackageName), nil
+}
+
+// writeShim writes the file re-exporting the exported declarations of the configuration
+// generated into the internal package importPath
+func writeShim(w io.Writer, data *mergedConfig, importPath string) {
+	fmt.Fprintf(w, "%s\n", generatedHeader)
+	fmt.Fprintf(w, "// Re-exports the configuration generated into %s\n\n", importPath)
+	writeAnnotations(w, data.Annotations)
+	fmt.Fprintf(w, "package %s\n\n", data.PackageName)
+	fmt.Fprintf(w, "import %s %q\n\n", shimImportName, importPath)
+
+	types := []string{"ConfigInterface", "ErrUnknownEnvironment", "Override"}
+	values := []string{"Environments", "NewEnvironmentConfig", "NewLayeredConfig"}
+	if data.BuildTags {
+		values = append(values, "NewConfig")
+	}
+	if renamedFields(data) != nil {
+		values = append(values, "FieldEnvNames")
+	}
+	if data.Ldflags {
+		values = append(values, "CheckLinkedValues")
+	}
+	for _, field := range overridableFields(data) {
+		values = append(values, "With"+field.FieldName)
+	}
+	for _, envName := range sortedEnvironmentNames(data.Environments) {
+		envData := data.Environments[envName]
+		if len(envData.Extras) > 0 {
+			types = append(types, envData.StructName+"Interface")
+		}
+		types = append(types, envData.StructName+"Config")
+		values = append(values, "New"+envData.StructName+"Config")
+		for _, profile := range envData.Profiles {
+			types = append(types, profile.StructName+"Config")
+			values = append(values, "New"+profile.StructName+"Config")
+		}
+	}
+
+	for _, name := range types {
+		fmt.Fprintf(w, "type %s = %s.%s\n", name, shimImportName, name)
+	}
+	fmt.Fprintf(w, "\n")
+	for _, name := range values {
+		fmt.Fprintf(w, "var %s = %s.%s\n", name, shimImportName, name)
+	}
+}
+
+// generateShimFile writes the re-export shim to outputFile
+func generateShimFile(outputFile string, data *mergedConfig, importPath string) error {
+	if err := os.MkdirAll(filepath.Dir(outputFile), 0755); err != nil {
+		return newError(MessageFailedCreateOutputDirectory, err)
+	}
+
+	file, err := os.Create(outputFile)
+	if err != nil {
+		return newError(MessageFailedCreateOutputFile, err)
+	}
+	defer file.Close()
+
+	writeShim(file, data, importPath)
+	return nil
+}
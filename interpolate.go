@@ -0,0 +1,133 @@
+package envied
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// referencePattern matches $$, an escaped dollar sign, and ${NAME} references with an optional
+// shell-style default: ${NAME:-fallback} if NAME is unset or empty, ${NAME-fallback} if unset
+var referencePattern = regexp.MustCompile(`\$\$|\$\{([A-Za-z_][A-Za-z0-9_]*)(?:(:?-)([^}]*))?\}`)
+
+// interpolator resolves the references of the layered variables of an environment
+type interpolator struct {
+	envVars    map[string]EnvValue
+	provenance map[string]Provenance
+	lookup     func(name string) (string, bool) // Resolves references to undefined variables, nil if they are errors
+	resolved   map[string]bool
+	chain      []string // Variables being resolved, outermost first
+}
+
+// newInterpolator returns an interpolator of variables with the provenance of their values
+func newInterpolator(envVars map[string]EnvValue, provenance map[string]Provenance) *interpolator {
+	return &interpolator{envVars: envVars, provenance: provenance, resolved: make(map[string]bool, len(envVars))}
+}
+
+// interpolateVariables replaces ${NAME} references in the values of an environment with the
+// values of the referenced variables and $$ with $. References are resolved after layering, so
+// they see the value of the file with the highest precedence whichever file they are in.
+// Undefined references and cycles are errors listing the reference chain.
+func interpolateVariables(envVars map[string]EnvValue, provenance map[string]Provenance) error {
+	return newInterpolator(envVars, provenance).resolveAll()
+}
+
+// resolveAll resolves the references of all variables in name order, so errors are deterministic
+func (in *interpolator) resolveAll() error {
+	names := make([]string, 0, len(in.envVars))
+	for name := range in.envVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := in.resolve(name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// link describes a variable of the reference chain with where its value is defined
+func (in *interpolator) link(name string) string {
+	if source := in.provenance[name].Source; source != "" {
+		return fmt.Sprintf("%s (%s)", name, source)
+	}
+	return name
+}
+
+// describeChain formats the reference chain from a variable, e.g. "A (dev.env:1) -> B (base.env:2)"
+func (in *interpolator) describeChain(from int) string {
+	links := make([]string, 0, len(in.chain)-from)
+	for _, name := range in.chain[from:] {
+		links = append(links, in.link(name))
+	}
+	return strings.Join(links, " -> ")
+}
+
+// resolve replaces the references in the value of a variable after resolving the referenced variables
+func (in *interpolator) resolve(name string) error {
+	if in.resolved[name] {
+		return nil
+	}
+	for i, pending := range in.chain {
+		if pending == name {
+			return newError(MessageReferenceCycle, in.describeChain(i), name)
+		}
+	}
+
+	in.chain = append(in.chain, name)
+	defer func() { in.chain = in.chain[:len(in.chain)-1] }()
+
+	envValue := in.envVars[name]
+	var err error
+	envValue.Value = referencePattern.ReplaceAllStringFunc(envValue.Value, func(reference string) string {
+		if err != nil {
+			return reference
+		}
+		if reference == "$$" {
+			return "$"
+		}
+		match := referencePattern.FindStringSubmatch(reference)
+		referenced, operator, fallback := match[1], match[2], match[3]
+		value, found, resolveErr := in.value(name, referenced, operator != "")
+		switch {
+		case resolveErr != nil:
+			err = resolveErr
+			return reference
+		case found && !(operator == ":-" && value == ""):
+			return value
+		case operator != "":
+			return fallback
+		default:
+			err = newError(MessageUndefinedReference, in.describeChain(0), reference)
+			return reference
+		}
+	})
+	if err != nil {
+		return err
+	}
+
+	in.envVars[name] = envValue
+	in.resolved[name] = true
+	return nil
+}
+
+// value returns the value a variable being resolved references, false if it is not defined.
+// A variable referencing itself with a default, like PORT=${PORT:-8080} in docker-compose files,
+// refers to the value outside the file: the lookup if there is one, otherwise it is not defined.
+func (in *interpolator) value(name, referenced string, hasDefault bool) (string, bool, error) {
+	selfReference := referenced == name && (hasDefault || in.lookup != nil)
+	if _, exists := in.envVars[referenced]; exists && !selfReference {
+		if err := in.resolve(referenced); err != nil {
+			return "", false, err
+		}
+		return in.envVars[referenced].Value, true, nil
+	}
+	if in.lookup != nil {
+		value, found := in.lookup(referenced)
+		return value, found, nil
+	}
+	return "", false, nil
+}
@@ -0,0 +1,83 @@
+package envied
+
+import (
+	"bytes"
+	"os"
+)
+
+// stripJSONC converts JSON with comments (JSONC) to JSON: // and /* */ comments outside strings
+// are replaced by spaces, keeping newlines so decoding errors keep their positions, and commas
+// before a closing } or ] are removed. Plain JSON is returned unchanged.
+func stripJSONC(data []byte) ([]byte, error) {
+	out := make([]byte, 0, len(data))
+	pendingComma := -1 // Position in out of a comma that is trailing if } or ] comes next
+	line := 1
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c == '"':
+			// Copy the string as is
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			end := min(i+1, len(data))
+			out = append(out, data[start:end]...)
+			pendingComma = -1
+
+		case c == '/' && i+1 < len(data) && data[i+1] == '/':
+			for ; i < len(data) && data[i] != '\n'; i++ {
+				out = append(out, ' ')
+			}
+			i-- // The newline is copied by the next iteration
+
+		case c == '/' && i+1 < len(data) && data[i+1] == '*':
+			end := bytes.Index(data[i+2:], []byte("*/"))
+			if end < 0 {
+				return nil, newError(MessageUnterminatedJSONComment, line)
+			}
+			for _, b := range data[i : i+2+end+2] {
+				if b == '\n' {
+					line++
+					out = append(out, '\n')
+				} else {
+					out = append(out, ' ')
+				}
+			}
+			i += 2 + end + 1
+
+		case c == ',':
+			pendingComma = len(out)
+			out = append(out, c)
+
+		case c == '}' || c == ']':
+			if pendingComma >= 0 {
+				out[pendingComma] = ' '
+				pendingComma = -1
+			}
+			out = append(out, c)
+
+		default:
+			if c == '\n' {
+				line++
+			}
+			if c != ' ' && c != '\t' && c != '\n' && c != '\r' {
+				pendingComma = -1
+			}
+			out = append(out, c)
+		}
+	}
+	return out, nil
+}
+
+// hasJSONComments reports whether an existing JSON file has comments or trailing commas
+func hasJSONComments(filename string) bool {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return false
+	}
+	stripped, err := stripJSONC(data)
+	return err != nil || !bytes.Equal(stripped, data)
+}
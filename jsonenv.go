@@ -0,0 +1,82 @@
+package envied
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// isJSONFile reports whether an env file is read as JSON, by its extension
+func isJSONFile(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".json"
+}
+
+// ReadJSONFile reads variables from a flat JSON object. Strings are quoted values like quoted
+// values of .env files, numbers and booleans are read as written and null is empty. Nested
+// objects and arrays are not supported.
+func ReadJSONFile(filename string) (map[string]EnvValue, error) {
+	content, err := readEnvFileContent(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEnvFileContent(filename, content); err != nil {
+		return nil, err
+	}
+	content = normalizeEnvFileContent(content)
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	decoder.UseNumber()
+	// lineAt returns the line of the last token read
+	lineAt := func() int {
+		return bytes.Count(content[:decoder.InputOffset()], []byte("\n")) + 1
+	}
+
+	if token, err := decoder.Token(); err != nil {
+		return nil, newError(MessageFileError, filename, err)
+	} else if token != json.Delim('{') {
+		return nil, newError(MessageExpectedJSONObject, filename, lineAt())
+	}
+
+	envVars := make(map[string]EnvValue)
+	for decoder.More() {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, newError(MessageAtFileLine, filename, lineAt(), err)
+		}
+		name := token.(string)
+		line := lineAt()
+
+		token, err = decoder.Token()
+		if err != nil {
+			return nil, newError(MessageKeyError, filename, line, name, err)
+		}
+		value := EnvValue{Line: line}
+		switch v := token.(type) {
+		case string:
+			value.Value, value.WasQuoted = v, true
+		case json.Number:
+			value.Value = v.String()
+		case bool:
+			value.Value = fmt.Sprint(v)
+		case nil:
+		default:
+			return nil, newError(MessageNestedJSONValue, filename, line, name)
+		}
+
+		if previous, exists := envVars[name]; exists {
+			return nil, newError(MessageKeyAlreadyDefined, filename, line, name, previous.Line)
+		}
+		envVars[name] = value
+	}
+
+	if _, err := decoder.Token(); err != nil {
+		return nil, newError(MessageAtFileLine, filename, lineAt(), err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, newError(MessageUnexpectedDataAfterJSON, filename, lineAt())
+	}
+	return envVars, nil
+}
@@ -0,0 +1,80 @@
+package envied
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Map field types declared with a variable's type, values are JSON objects
+const (
+	FieldTypeStringMap FieldType = "map[string]string"
+	FieldTypeBoolMap   FieldType = "map[string]bool"
+	FieldTypeIntMap    FieldType = "map[string]int"
+	FieldTypeFloatMap  FieldType = "map[string]float64"
+	FieldTypeJSON      FieldType = "map[string]any" // Generic JSON object
+)
+
+// mapFieldTypes lists the map types accepted in a variable's type
+var mapFieldTypes = []FieldType{FieldTypeStringMap, FieldTypeBoolMap, FieldTypeIntMap, FieldTypeFloatMap, FieldTypeJSON}
+
+// isMapType reports whether a field type is a map decoded from JSON
+func isMapType(fieldType FieldType) bool {
+	for _, mapType := range mapFieldTypes {
+		if fieldType == mapType {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeJSONValue unmarshals a value into a new value of a map field type
+func decodeJSONValue(fieldType FieldType, value string) error {
+	var err error
+	switch fieldType {
+	case FieldTypeStringMap:
+		_, err = decodeJSON[map[string]string](value)
+	case FieldTypeBoolMap:
+		_, err = decodeJSON[map[string]bool](value)
+	case FieldTypeIntMap:
+		_, err = decodeJSON[map[string]int](value)
+	case FieldTypeFloatMap:
+		_, err = decodeJSON[map[string]float64](value)
+	default:
+		_, err = decodeJSON[map[string]any](value)
+	}
+	return err
+}
+
+// decodeJSON unmarshals a JSON value, empty values decode to the zero value
+func decodeJSON[T any](value string) (T, error) {
+	var result T
+	if strings.TrimSpace(value) == "" {
+		return result, nil
+	}
+	err := json.Unmarshal([]byte(value), &result)
+	return result, err
+}
+
+// ParseJSON converts a JSON string to T
+func ParseJSON[T any](value string) T {
+	result, _ := decodeJSON[T](value)
+	return result
+}
+
+// CoerceJSON converts a layered JSON value to T
+func CoerceJSON[T any](name, value string) (T, error) {
+	result, err := decodeJSON[T](value)
+	if err != nil {
+		return result, newError(MessageInvalidJSONValue, name, err)
+	}
+	return result, nil
+}
+
+// FormatJSON formats a value as compact JSON with sorted map keys, as in a .env file
+func FormatJSON(value any) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
@@ -0,0 +1,184 @@
+package envied
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Source provides raw values of variables by name at runtime
+type Source interface {
+	Lookup(name string) (string, bool)
+}
+
+// SourceFunc adapts a lookup function to Source
+type SourceFunc func(name string) (string, bool)
+
+// Lookup calls f(name)
+func (f SourceFunc) Lookup(name string) (string, bool) {
+	return f(name)
+}
+
+// MapSource is a Source backed by a map
+type MapSource map[string]string
+
+// Lookup returns the value stored for name
+func (m MapSource) Lookup(name string) (string, bool) {
+	value, exists := m[name]
+	return value, exists
+}
+
+// OSEnv returns a Source reading the process environment
+func OSEnv() Source {
+	return SourceFunc(os.LookupEnv)
+}
+
+// FileSource reads a .env file into a Source
+func FileSource(filename string) (Source, error) {
+	envVars, err := ReadEnvFile(filename)
+	if err != nil {
+		return nil, newError(MessageFailedReadEnvFile, filename, err)
+	}
+	return MapSource(envVars), nil
+}
+
+// ChangeFunc is called by watching sources with all variables after a change
+type ChangeFunc func(values MapSource)
+
+// Layered combines sources, see Layer
+type Layered struct {
+	sources []Source
+}
+
+// Layer combines sources with increasing precedence: a value of a later source
+// replaces the values of all earlier ones. The usual order is embedded values,
+// then a deploy-time file, then the process environment:
+//
+//	envied.Layer(config.NewProdConfig(), fileSource, envied.OSEnv())
+//
+// Nil sources are skipped.
+func Layer(sources ...Source) *Layered {
+	layered := &Layered{}
+	for _, source := range sources {
+		if source != nil {
+			layered.sources = append(layered.sources, source)
+		}
+	}
+	return layered
+}
+
+// Lookup returns the value of the source with the highest precedence defining name
+func (l *Layered) Lookup(name string) (string, bool) {
+	for i := len(l.sources) - 1; i >= 0; i-- {
+		if value, exists := l.sources[i].Lookup(name); exists {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// FormatValue formats a typed value as it would be written in a .env file
+func FormatValue(value any) string {
+	return fmt.Sprint(value)
+}
+
+// CoerceString returns the value unchanged, it exists for symmetry with the other coercions
+func CoerceString(name, value string) (string, error) {
+	return value, nil
+}
+
+// CoerceInt converts a layered value to int
+func CoerceInt(name, value string) (int, error) {
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, newError(MessageInvalidIntValue, name, value)
+	}
+	return parsed, nil
+}
+
+// CoerceInt64 converts a layered value to int64
+func CoerceInt64(name, value string) (int64, error) {
+	parsed, err := strconv.ParseInt(value, 10, 64)
+	if err != nil {
+		return 0, newError(MessageInvalidInt64Value, name, value)
+	}
+	return parsed, nil
+}
+
+// CoerceUint64 converts a layered value to uint64
+func CoerceUint64(name, value string) (uint64, error) {
+	parsed, err := strconv.ParseUint(value, 10, 64)
+	if err != nil {
+		return 0, newError(MessageInvalidUint64Value, name, value)
+	}
+	return parsed, nil
+}
+
+// CoerceBool converts a layered value to bool
+func CoerceBool(name, value string) (bool, error) {
+	parsed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, newError(MessageInvalidBoolValue, name, value)
+	}
+	return parsed, nil
+}
+
+// CoerceFloat converts a layered value to float64
+func CoerceFloat(name, value string) (float64, error) {
+	parsed, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, newError(MessageInvalidFloat64Value, name, value)
+	}
+	return parsed, nil
+}
+
+// coerceFuncs maps field types to the runtime coercion used by generated layering glue
+var coerceFuncs = map[FieldType]string{
+	FieldTypeString:      "CoerceString",
+	FieldTypeInt:         "CoerceInt",
+	FieldTypeInt64:       "CoerceInt64",
+	FieldTypeUint64:      "CoerceUint64",
+	FieldTypeBool:        "CoerceBool",
+	FieldTypeFloat:       "CoerceFloat",
+	FieldTypeTime:        "CoerceTime",
+	FieldTypeStringSlice: "CoerceStringSlice",
+	FieldTypeBytes:       "CoerceBytes",
+}
+
+// coerceCall returns the conversion of a layered value to the type of a field, false for
+// types that can't be layered
+func coerceCall(field Field) (string, bool) {
+	coerce, exists := coerceFuncs[field.Type]
+	if isMapType(field.Type) {
+		coerce, exists = fmt.Sprintf("CoerceJSON[%s]", field.Type), true
+	}
+	if !exists {
+		return "", false
+	}
+	switch field.Type {
+	case FieldTypeTime:
+		return fmt.Sprintf("envied.%s(%q, %q, value)", coerce, field.EnvName, field.Layout), true
+	case FieldTypeStringSlice:
+		return fmt.Sprintf("envied.%s(%q, %q, value)", coerce, field.EnvName, field.Separator), true
+	case FieldTypeBytes:
+		return fmt.Sprintf("envied.%s(%q, %q, value)", coerce, field.EnvName, field.Encoding), true
+	default:
+		return fmt.Sprintf("envied.%s(%q, value)", coerce, field.EnvName), true
+	}
+}
+
+// lookupExpression returns the value of a field of c formatted as in a .env file
+func lookupExpression(field Field) string {
+	switch field.Type {
+	case FieldTypeTime:
+		return fmt.Sprintf("c.%s.Format(%q)", field.FieldName, field.Layout)
+	case FieldTypeStringSlice:
+		return fmt.Sprintf("envied.JoinList(c.%s, %q)", field.FieldName, field.Separator)
+	case FieldTypeBytes:
+		return fmt.Sprintf("envied.FormatBytes(%q, c.%s)", field.Encoding, field.FieldName)
+	case FieldTypeStringMap, FieldTypeBoolMap, FieldTypeIntMap, FieldTypeFloatMap, FieldTypeJSON:
+		return fmt.Sprintf("envied.FormatJSON(c.%s)", field.FieldName)
+	default:
+		return fmt.Sprintf("envied.FormatValue(c.%s)", field.FieldName)
+	}
+}
@@ -0,0 +1,102 @@
+package envied
+
+import (
+	"fmt"
+	"maps"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// ErrMissingLinkedValues is returned by generated CheckLinkedValues functions when the build did
+// not inject values of the environment with -ldflags -X
+type ErrMissingLinkedValues struct {
+	Environment string   // Environment the values are missing for
+	Missing     []string // Variables without an injected value, sorted by name
+}
+
+// Error implements the error interface
+func (e *ErrMissingLinkedValues) Error() string {
+	id, args := e.message()
+	return DefaultCatalog.Format(id, args...)
+}
+
+// message returns the message ID and arguments of the error
+func (e *ErrMissingLinkedValues) message() (MessageID, []any) {
+	return MessageMissingLinkedValues, []any{e.Environment, strings.Join(e.Missing, ", ")}
+}
+
+// CheckLinked returns *ErrMissingLinkedValues for the empty values of an environment, values maps
+// variable names to the package variables set with -ldflags -X
+func CheckLinked(env string, values map[string]string) error {
+	var missing []string
+	for name, value := range values {
+		if value == "" {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return &ErrMissingLinkedValues{Environment: env, Missing: missing}
+}
+
+// linkedVariable returns the package variable receiving a value of an environment injected with
+// -ldflags -X, named like obfuscated data
+func linkedVariable(envName, constant string) string {
+	return strings.ToLower(envName) + "_enviedlinked" + constant
+}
+
+// linkedInitializer returns the expression converting an injected value to the field type,
+// only types that are obfuscated are injected
+func linkedInitializer(field Field, variable string) string {
+	switch {
+	case field.Type == FieldTypeStringSlice:
+		return fmt.Sprintf("envied.SplitList(%s, %q)", variable, field.Separator)
+	case isMapType(field.Type):
+		return fmt.Sprintf("envied.ParseJSON[%s](%s)", field.Type, variable)
+	case field.Type == FieldTypeBytes:
+		return fmt.Sprintf("envied.ParseBytes(%q, %s)", field.Encoding, variable)
+	default:
+		return variable
+	}
+}
+
+// linkImportPlaceholder stands for the import path of a package outside of a Go module in -X flags
+const linkImportPlaceholder = "<import path>"
+
+// linkImportPath returns the import path of the package generated to codeFile, which -X flags
+// name variables by, or a placeholder outside of a Go module
+func linkImportPath(codeFile string) string {
+	dir := filepath.Dir(codeFile)
+	moduleRoot := findModuleRoot(dir)
+	if moduleRoot == "" {
+		return linkImportPlaceholder
+	}
+	modulePath, err := readModulePath(moduleRoot)
+	if err != nil {
+		return linkImportPlaceholder
+	}
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return linkImportPlaceholder
+	}
+	rel, err := filepath.Rel(moduleRoot, absDir)
+	if err != nil {
+		return linkImportPlaceholder
+	}
+	return path.Join(modulePath, filepath.ToSlash(rel))
+}
+
+// ldflagsRecipe returns the -ldflags value injecting the linked values of an environment from
+// environment variables of the build named like the variables
+func ldflagsRecipe(importPath string, linked map[string]string) string {
+	flags := make([]string, 0, len(linked))
+	for _, name := range slices.Sorted(maps.Keys(linked)) {
+		flags = append(flags, fmt.Sprintf("-X '%s.%s=$%s'", importPath, linked[name], name))
+	}
+	return strings.Join(flags, " ")
+}
@@ -0,0 +1,57 @@
+package envied
+
+import (
+	"math"
+	"strconv"
+)
+
+// literalValue returns the typed Go literal of a bool, int or float value embedded without a Parse
+// call, false for other types and values that do not parse, which keep their Parse call
+func literalValue(field Field) (string, bool) {
+	switch field.Type {
+	case FieldTypeBool:
+		value, err := strconv.ParseBool(field.Value)
+		return strconv.FormatBool(value), err == nil
+	case FieldTypeInt:
+		value, err := strconv.Atoi(field.Value)
+		return strconv.Itoa(value), err == nil
+	case FieldTypeInt64:
+		value, err := strconv.ParseInt(field.Value, 10, 64)
+		return strconv.FormatInt(value, 10), err == nil
+	case FieldTypeUint64:
+		value, err := strconv.ParseUint(field.Value, 10, 64)
+		return strconv.FormatUint(value, 10), err == nil
+	case FieldTypeFloat:
+		// Infinities and NaN have no literal
+		value, err := strconv.ParseFloat(field.Value, 64)
+		return strconv.FormatFloat(value, 'g', -1, 64), err == nil && !math.IsInf(value, 0) && !math.IsNaN(value)
+	default:
+		return "", false
+	}
+}
+
+// fieldLiterals returns the literals of the fields of an environment by variable name,
+// fields marked sensitive keep their Parse call
+func fieldLiterals(fields []Field, sensitive map[string]bool) map[string]string {
+	literals := make(map[string]string)
+	for _, field := range fields {
+		if sensitive[field.EnvName] {
+			continue
+		}
+		if literal, ok := literalValue(field); ok {
+			literals[field.EnvName] = literal
+		}
+	}
+	return literals
+}
+
+// initializer returns the expression initializing a field in the constructors of an environment
+func (envData mergedEnvironment) initializer(envName string, field Field) string {
+	if literal, exists := envData.Literals[field.EnvName]; exists {
+		return literal
+	}
+	if variable, exists := envData.Linked[field.EnvName]; exists {
+		return linkedInitializer(field, variable)
+	}
+	return fieldInitializer(envName, field, envData.Obfuscated[field.EnvName])
+}
@@ -0,0 +1,31 @@
+package envied
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// localOverlayPath returns the path of the local overlay of an env file: dev.env.local for
+// dev.env, and dev.local.yaml for dev.yaml so YAML, TOML and JSON files keep their extension
+func localOverlayPath(envFile string) string {
+	if isYAMLFile(envFile) || isTOMLFile(envFile) || isJSONFile(envFile) {
+		ext := filepath.Ext(envFile)
+		return strings.TrimSuffix(envFile, ext) + ".local" + ext
+	}
+	return envFile + ".local"
+}
+
+// layers returns the overlays of an environment followed by the local overlay of its env file
+// if it exists and no_local is not set. Local overlays hold the values of individual developers
+// and are not committed.
+func (e EnvironmentConfig) layers() []string {
+	if e.EnvFile == "" || e.NoLocal {
+		return e.Overlays
+	}
+	local := localOverlayPath(e.EnvFile)
+	if _, err := os.Stat(local); err != nil {
+		return e.Overlays
+	}
+	return append(append([]string{}, e.Overlays...), local)
+}
@@ -3,12 +3,15 @@
 package envied
 
 import (
+	"bytes"
 	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"io"
 	"math/rand"
 	"os"
 	"path/filepath"
+	"sort"
 	"strconv"
 	"strings"
 	"text/template"
@@ -19,19 +22,37 @@ import (
 type FieldType string
 
 const (
-	FieldTypeString FieldType = "string"
-	FieldTypeInt    FieldType = "int"
-	FieldTypeBool   FieldType = "bool"
-	FieldTypeFloat  FieldType = "float64"
+	FieldTypeString      FieldType = "string"
+	FieldTypeInt         FieldType = "int"
+	FieldTypeInt64       FieldType = "int64"
+	FieldTypeUint64      FieldType = "uint64"
+	FieldTypeBool        FieldType = "bool"
+	FieldTypeFloat       FieldType = "float64"
+	FieldTypeTime        FieldType = "time.Time"
+	FieldTypeStringSlice FieldType = "[]string"
+	FieldTypeBytes       FieldType = "[]byte"
+)
+
+// Obfuscation modes of the generated configuration
+const (
+	ObfuscationXOR    = "xor"     // String values are XOR obfuscated (default)
+	ObfuscationAESGCM = "aes-gcm" // String values are sealed with AES-256-GCM, see SealString
+	ObfuscationNone   = "none"    // Values are emitted as plain constants
 )
 
 // Field represents a configuration field
 type Field struct {
-	EnvName      string    // Environment variable name (used as field name)
+	EnvName      string    // Environment variable name (used as field name by Generator)
 	Type         FieldType // Field type
 	Value        string    // Field value
 	DefaultValue string    // Default value if env var is not set
 	Optional     bool      // Whether the field is optional
+	Description  string    // Human-readable description of the variable
+	Layout       string    // Time layout of time.Time fields
+	Separator    string    // Element separator of []string fields (DefaultSeparator if empty)
+	Encoding     string    // Text encoding of []byte fields: EncodingBase64, EncodingBase64URL or EncodingHex
+	Getter       string    // Name of the getter method in merged configurations
+	FieldName    string    // Name of the struct field in merged configurations
 }
 
 // ObfuscationResult contains the obfuscated field data
@@ -48,24 +69,133 @@ type Config struct {
 	Environment string  // Environment name (dev, prod, etc.)
 	Fields      []Field // Configuration fields
 	OutputDir   string  // Output directory for generated files
+
+	// Template overrides the built-in configuration template when not empty
+	Template string
+	// Funcs adds or overrides template functions (e.g. sprig.TxtFuncMap())
+	Funcs template.FuncMap
+	// RuntimeImport is the import path of the envied runtime in generated code (RuntimeImportPath if empty)
+	RuntimeImport string
 }
 
-// Generator handles configuration file generation
+// Generator handles configuration file generation.
+// A Generator never modifies its Config: every Generate call works on a copy,
+// so one instance can be reused and is safe for concurrent Generate calls
+// (concurrent calls for the same output file overwrite each other).
 type Generator struct {
 	config *Config
 }
 
 // ConfigFile structure for configuration file
 type ConfigFile struct {
-	PackageName  string                       `json:"package_name"`
-	OutputDir    string                       `json:"output_dir"`
-	RandomSeed   int                          `json:"random_seed,omitempty"`
-	Environments map[string]EnvironmentConfig `json:"environments"`
+	PackageName            string                       `json:"package_name"`
+	OutputDir              string                       `json:"output_dir"`
+	RandomSeed             Seed                         `json:"random_seed,omitempty"`
+	Obfuscation            string                       `json:"obfuscation,omitempty"`
+	CryptoMode             string                       `json:"crypto_mode,omitempty"`              // Restricts primitives, CryptoModeFIPS allows only FIPS-approved ones
+	AllowUnsafePaths       bool                         `json:"allow_unsafe_paths,omitempty"`       // Disables env file and output directory location checks
+	AllowExtraVariables    bool                         `json:"allow_extra_variables,omitempty"`    // Allows environments to define different variables
+	AllowEmptyEnvironments bool                         `json:"allow_empty_environments,omitempty"` // Warns instead of failing for environments without variables
+	RuntimeImport          string                       `json:"runtime_import,omitempty"`           // Import path of the envied runtime in generated code
+	OnConflict             string                       `json:"on_conflict,omitempty"`              // Strategy for declarations duplicated by other package files
+	NameValidation         string                       `json:"name_validation,omitempty"`          // Variable name validation: warn (default), error or off
+	NamePattern            string                       `json:"name_pattern,omitempty"`             // Regular expression for variable names (DefaultNamePattern if empty)
+	OnUnsafeCharacters     string                       `json:"on_unsafe_characters,omitempty"`     // Control characters and invalid UTF-8 in values: allow (default), reject or escape
+	Environments           map[string]EnvironmentConfig `json:"environments"`
+	Variables              map[string]VariableConfig    `json:"variables,omitempty"`          // Per-variable settings keyed by env var name
+	Fields                 map[string]FieldType         `json:"fields,omitempty"`             // Types pinned by env var name, overriding type detection
+	Emit                   *EmitConfig                  `json:"emit,omitempty"`               // Documentation files written next to the generated code
+	Annotations            *AnnotationsConfig           `json:"annotations,omitempty"`        // Lint and coverage directives of the generated file
+	InternalPackage        bool                         `json:"internal_package,omitempty"`   // Generates into internal/envied/<package_name> with a re-export shim in output_dir
+	BuildTags              bool                         `json:"build_tags,omitempty"`         // Generates NewConfig selected by envied_<env> build tags, builds without exactly one tag fail
+	BuildTagPrefix         *string                      `json:"build_tag_prefix,omitempty"`   // Prefix of environment build tags, DefaultBuildTagPrefix if unset; empty for tags such as dev
+	SplitEnvironments      bool                         `json:"split_environments,omitempty"` // Writes every environment into its own file compiled with its build tag only, requires build_tags
+	Getters                *GettersConfig               `json:"getters,omitempty"`            // Getter naming and receivers
+	Interpolate            bool                         `json:"interpolate,omitempty"`        // Expands ${NAME} references in values after layering env files
+	Literals               bool                         `json:"literals,omitempty"`           // Embeds bool, int and float values as typed literals instead of Parse calls
+	Naming                 *NamingConfig                `json:"naming,omitempty"`             // Naming strategy of generated identifiers
+	Substitutions          map[string]string            `json:"substitutions,omitempty"`      // Values of {{.Name}} placeholders in env values
+	Policies               []PolicyConfig               `json:"policies,omitempty"`           // Organizational rules forbidding variables or values in environments
+	SizeBudget             *SizeBudgetConfig            `json:"size_budget,omitempty"`        // Limits of the bytes embedded per environment and variable
+	Ldflags                bool                         `json:"ldflags,omitempty"`            // Injects the values selected for obfuscation with -ldflags -X instead of embedding them
+	SourceTimeout          string                       `json:"source_timeout,omitempty"`     // Bounds the read of each remote source, e.g. "1m", DefaultSourceTimeout if empty
+
+	envFileOverrides      []string          // Environments whose source was replaced by applyEnvFileOverrides
+	substitutionOverrides map[string]string // Substitutions set by GenerateOptions.Set, taking precedence
+	valuesFrom            string            // Source of the values of environments declaring one, set by GenerateOptions.ValuesFrom
+	verbose               bool              // Logs the provenance of every value, set by GenerateOptions.Verbose
+	timer                 *phaseTimer       // Measures the phases of the run, set by GenerateOptions.Timings
+	namer                 Namer             // Names generated identifiers instead of Naming, set by GenerateOptions.Namer
+	hooks                 map[Stage][]Hook  // Steps injected by Pipeline.Hook
 }
 
 type EnvironmentConfig struct {
-	EnvFile    string `json:"env_file"`
-	StructName string `json:"struct_name"`
+	EnvFile    string        `json:"env_file,omitempty"`
+	StructName string        `json:"struct_name"`
+	Obfuscate  *bool         `json:"obfuscate,omitempty"` // Overrides ConfigFile.Obfuscation for this environment
+	Consul     *ConsulConfig `json:"consul,omitempty"`    // Reads variables from Consul KV instead of env_file
+	Etcd       *EtcdConfig   `json:"etcd,omitempty"`      // Reads variables from etcd instead of env_file
+	Redis      *RedisConfig  `json:"redis,omitempty"`     // Reads variables from a Redis hash instead of env_file
+	SQL        *SQLConfig    `json:"sql,omitempty"`       // Reads variables from a database table instead of env_file
+	Vault      *VaultConfig  `json:"vault,omitempty"`     // Reads the values of the variables of env_file from Vault, see GenerateOptions.ValuesFrom
+
+	KeySeparator string   `json:"key_separator,omitempty"` // Joins nested keys of a .yaml, .yml or .toml env_file, DefaultKeySeparator if empty
+	BaseEnvFile  string   `json:"base_env_file,omitempty"` // Env file of shared variables layered under the source, whose values win
+	Overlays     []string `json:"overlays,omitempty"`      // Env files layered over the source in order, later values replace earlier ones
+	NoLocal      bool     `json:"no_local,omitempty"`      // Ignores the local overlay of env_file, e.g. dev.env.local for dev.env
+
+	Profiles map[string][]string `json:"profiles,omitempty"` // Structs generated from subsets of the variables, keyed by struct name
+
+	Substitutions map[string]string `json:"substitutions,omitempty"` // Values of {{.Name}} placeholders, over the configuration substitutions
+}
+
+// VariableConfig holds per-variable settings
+type VariableConfig struct {
+	Transform   []string `json:"transform,omitempty"`   // Transforms applied to the value before typing and obfuscation
+	Only        []string `json:"only,omitempty"`        // Environments the variable is generated for (all if empty)
+	Description string   `json:"description,omitempty"` // Human-readable description used in generated docs
+	TimeLayout  string   `json:"time_layout,omitempty"` // Parses the value as time.Time with this layout or time package layout name
+	Separator   string   `json:"separator,omitempty"`   // Splits the value into a []string at this separator
+	Type        string   `json:"type,omitempty"`        // Declares the type instead of detecting it, e.g. string, int64, []byte or a map type decoded from a JSON object value
+	Encoding    string   `json:"encoding,omitempty"`    // Text encoding of []byte values: base64 (default), base64url or hex
+	Getter      string   `json:"getter,omitempty"`      // Getter method name, overriding the name derived from getters settings
+	MaxBytes    int      `json:"max_bytes,omitempty"`   // Maximum embedded bytes of the value, overriding size_budget.variable
+}
+
+// mergedEnvironment holds generation data for a single environment
+type mergedEnvironment struct {
+	StructName string
+	Fields     []Field
+	Obfuscated map[string]*ObfuscationResult
+	SourceHash string  // SHA-256 of the env file contents
+	Extras     []Field // Fields not part of the shared interface
+	Profiles   []mergedProfile
+	Provenance map[string]Provenance // Sources of the values by variable name
+	Literals   map[string]string     // Typed literals of values embedded without a Parse call, by variable name
+	Sensitive  map[string]bool       // Variables marked with a sensitive=true hint
+	Linked     map[string]string     // Package variables receiving values injected with -ldflags -X, by variable name
+}
+
+// mergedConfig holds generation data for the merged configuration file
+type mergedConfig struct {
+	PackageName   string
+	RuntimeImport string
+	RandomSeed    int64
+	Obfuscation   string // Algorithm of obfuscated environments, ObfuscationXOR or ObfuscationAESGCM
+	CryptoMode    string
+	GeneratedAt   time.Time
+	Environments  map[string]mergedEnvironment
+	AllFields     []Field
+	Annotations   *AnnotationsConfig
+	BuildTags     bool
+	TagPrefix     string    // Prefix of the build tags of environments
+	Split         bool      // Environments are written into their own files, see ConfigFile.SplitEnvironments
+	Ldflags       bool      // Values selected for obfuscation are injected with -ldflags -X, see ConfigFile.Ldflags
+	Receiver      string    // Receiver type prefix of generated methods, "*" for pointer receivers
+	Warnings      []Warning // Warnings reported while building, in the order they were logged
+	namer         Namer     // Names the files generated per environment
+
+	state *PipelineState // Passed to the hooks of StageEmit
 }
 
 // ObfuscateString obfuscates a string value using XOR with random keys for each character
@@ -90,10 +220,11 @@ func ObfuscateString(value string, seed int64) ([]int, []int) {
 	return keys, encryptedValues
 }
 
-// DeobfuscateString deobfuscates a string value using XOR with the keys
-func DeobfuscateString(keys, encryptedValues []int) string {
+// DecodeString deobfuscates a string value using XOR with the keys.
+// It returns an error if the numbers of keys and values don't match.
+func DecodeString(keys, encryptedValues []int) (string, error) {
 	if len(keys) != len(encryptedValues) {
-		return ""
+		return "", newError(MessageRuntimeKeyCountMismatch, len(keys), len(encryptedValues))
 	}
 
 	runes := make([]rune, len(keys))
@@ -101,7 +232,26 @@ func DeobfuscateString(keys, encryptedValues []int) string {
 		runes[i] = rune(keys[i] ^ encryptedValues[i])
 	}
 
-	return string(runes)
+	return string(runes), nil
+}
+
+// MustDecodeString is like DecodeString but panics on error.
+// Generated code uses it, since a mismatch means the generated file is corrupted.
+func MustDecodeString(keys, encryptedValues []int) string {
+	value, err := DecodeString(keys, encryptedValues)
+	if err != nil {
+		panic(err)
+	}
+	return value
+}
+
+// DeobfuscateString deobfuscates a string value using XOR with the keys.
+// It returns an empty string if keys and values don't match.
+//
+// Deprecated: use DecodeString or MustDecodeString. Run "envied fix" to update generated files.
+func DeobfuscateString(keys, encryptedValues []int) string {
+	value, _ := DecodeString(keys, encryptedValues)
+	return value
 }
 
 // ParseInt converts a string to int
@@ -110,6 +260,18 @@ func ParseInt(value string) int {
 	return result
 }
 
+// ParseInt64 converts a string to int64
+func ParseInt64(value string) int64 {
+	result, _ := strconv.ParseInt(value, 10, 64)
+	return result
+}
+
+// ParseUint64 converts a string to uint64
+func ParseUint64(value string) uint64 {
+	result, _ := strconv.ParseUint(value, 10, 64)
+	return result
+}
+
 // ParseBool converts a string to bool
 func ParseBool(value string) bool {
 	result, _ := strconv.ParseBool(value)
@@ -122,8 +284,11 @@ func ParseFloat(value string) float64 {
 	return result
 }
 
+// defaultObfuscationKey is the key of Obfuscate and Deobfuscate used when the key is empty
+const defaultObfuscationKey = "go-envied-obfuscation"
+
 // Deobfuscate deobfuscates a value using simple XOR obfuscation
-// Similar to the original envied package for Dart/Flutter
+// Similar to the original envied package for Dart/Flutter, an empty key uses the default key
 func Deobfuscate(obfuscatedValue string, key string) string {
 	if obfuscatedValue == "" {
 		return ""
@@ -137,6 +302,9 @@ func Deobfuscate(obfuscatedValue string, key string) string {
 	}
 
 	// Simple XOR deobfuscation with provided key
+	if key == "" {
+		key = defaultObfuscationKey
+	}
 	keyBytes := []byte(key)
 	result := make([]byte, len(data))
 
@@ -150,17 +318,20 @@ func Deobfuscate(obfuscatedValue string, key string) string {
 // DeobfuscateWithDefaultKey deobfuscates a value using default key
 // For backward compatibility
 func DeobfuscateWithDefaultKey(obfuscatedValue string) string {
-	return Deobfuscate(obfuscatedValue, "go-envied-obfuscation")
+	return Deobfuscate(obfuscatedValue, defaultObfuscationKey)
 }
 
 // Obfuscate obfuscates a value using simple XOR obfuscation
-// Similar to the original envied package for Dart/Flutter
+// Similar to the original envied package for Dart/Flutter, an empty key uses the default key
 func Obfuscate(value string, key string) string {
 	if value == "" {
 		return ""
 	}
 
 	// Simple XOR obfuscation with provided key
+	if key == "" {
+		key = defaultObfuscationKey
+	}
 	keyBytes := []byte(key)
 	data := []byte(value)
 	result := make([]byte, len(data))
@@ -174,9 +345,28 @@ func Obfuscate(value string, key string) string {
 }
 
 // generateObfuscatedField generates obfuscated field data based on type and value
-func generateObfuscatedField(fieldName string, fieldType FieldType, value string, seed int64) (*ObfuscationResult, error) {
-	switch fieldType {
-	case FieldTypeString:
+func generateObfuscatedField(algorithm string, fieldName string, fieldType FieldType, value, separator string, seed int64) (*ObfuscationResult, error) {
+	switch {
+	case fieldType == FieldTypeStringSlice:
+		return obfuscateList(algorithm, fieldName, value, separator, seed)
+
+	case isMapType(fieldType), fieldType == FieldTypeBytes:
+		// JSON and binary values are obfuscated in their text form and decoded after deobfuscation
+		return generateObfuscatedField(algorithm, fieldName, FieldTypeString, value, separator, seed)
+
+	case fieldType == FieldTypeString && algorithm == ObfuscationAESGCM:
+		key, sealed, err := SealString(fieldName, value, seed)
+		if err != nil {
+			return nil, err
+		}
+		return &ObfuscationResult{
+			KeyName:   fmt.Sprintf("_enviedkey%s", fieldName),
+			ValueName: fmt.Sprintf("_envieddata%s", fieldName),
+			Key:       key,
+			Value:     sealed,
+		}, nil
+
+	case fieldType == FieldTypeString:
 		keys, encryptedValues := ObfuscateString(value, seed)
 		return &ObfuscationResult{
 			KeyName:   fmt.Sprintf("_enviedkey%s", fieldName),
@@ -186,7 +376,7 @@ func generateObfuscatedField(fieldName string, fieldType FieldType, value string
 		}, nil
 
 	default:
-		// Only strings are obfuscated, other types (int, bool, float64) are not obfuscated
+		// Only strings and string slices are obfuscated, other types (int, bool, float64) are not obfuscated
 		return nil, nil
 	}
 }
@@ -208,10 +398,28 @@ func DetectFieldType(value string) FieldType {
 		return FieldTypeFloat
 	}
 
+	// Try to parse as an RFC 3339 timestamp
+	if _, err := time.Parse(time.RFC3339, value); err == nil {
+		return FieldTypeTime
+	}
+
 	// Default to string
 	return FieldTypeString
 }
 
+// newField creates a field with a detected type, detected timestamps use time.RFC3339
+func newField(envName string, fieldType FieldType, value string) Field {
+	field := Field{
+		EnvName: envName,
+		Type:    fieldType,
+		Value:   value,
+	}
+	if fieldType == FieldTypeTime {
+		field.Layout = time.RFC3339
+	}
+	return field
+}
+
 // extractFieldsFromEnvVars extracts fields from environment variables
 func extractFieldsFromEnvVars(envVars map[string]string) []Field {
 	var fields []Field
@@ -224,13 +432,10 @@ func extractFieldsFromEnvVars(envVars map[string]string) []Field {
 			fieldType = DetectFieldType(value)
 		}
 
-		fields = append(fields, Field{
-			EnvName: envName,
-			Type:    fieldType,
-			Value:   value,
-		})
+		fields = append(fields, newField(envName, fieldType, value))
 	}
 
+	sortFields(fields)
 	return fields
 }
 
@@ -249,40 +454,31 @@ func extractFieldsFromEnvVarsWithMetadata(envVars map[string]EnvValue) []Field {
 			fieldType = DetectFieldType(envValue.Value)
 		}
 
-		fields = append(fields, Field{
-			EnvName: envName,
-			Type:    fieldType,
-			Value:   envValue.Value,
-		})
+		fields = append(fields, newField(envName, fieldType, envValue.Value))
 	}
 
+	sortFields(fields)
 	return fields
 }
 
+// sortFields sorts fields by name, so generated code does not depend on map order
+func sortFields(fields []Field) {
+	sort.Slice(fields, func(i, j int) bool {
+		return fields[i].EnvName < fields[j].EnvName
+	})
+}
+
 // checkEnvironmentConsistency checks if all environments have the same variables
-func checkEnvironmentConsistency(allEnvVars map[string]map[string]string) error {
+func checkEnvironmentConsistency(allEnvVars map[string]map[string]string, log *messageLog) error {
 	if len(allEnvVars) < 2 {
 		return nil // No need to check consistency with only one environment
 	}
 
-	// Get all variable names from all environments
-	allVars := make(map[string]bool)
-	for _, envVars := range allEnvVars {
-		for varName := range envVars {
-			allVars[varName] = true
-		}
-	}
-
-	// Check that each environment has all variables
-	for envName, envVars := range allEnvVars {
-		for varName := range allVars {
-			if _, exists := envVars[varName]; !exists {
-				return fmt.Errorf("❌ ERROR: variable '%s' is missing in environment '%s'", varName, envName)
-			}
-		}
+	if _, err := CheckConsistency(allEnvVars); err != nil {
+		return err
 	}
 
-	fmt.Println("✅ Environment consistency check passed - all environments have the same variables")
+	log.say(MessageConsistencyPassed)
 	return nil
 }
 
@@ -296,58 +492,135 @@ func LoadEnvFile(filePath string) ([]Field, error) {
 	return extractFieldsFromEnvVars(envVars), nil
 }
 
-// unquoteValue removes surrounding quotes from a string value
-// Returns the unquoted value and whether it was originally quoted
-func unquoteValue(value string) (string, bool) {
+// unquoteValue parses the value of an env file line with dotenv quoting: single-quoted values
+// are literal, double-quoted values support the escapes \n, \r, \t, \" and \\, and unquoted
+// values end at a comment starting with whitespace and #, so quoted values can contain # and =.
+// Returns the value and its quote character, 0 if it was not quoted.
+func unquoteValue(value string) (string, byte, error) {
+	if trimmed := strings.TrimSpace(value); trimmed == "" || (trimmed[0] != '"' && trimmed[0] != '\'') {
+		// The comment of KEY= # comment starts right after the separator
+		for i := 1; i < len(value); i++ {
+			if value[i] == '#' && (value[i-1] == ' ' || value[i-1] == '\t') {
+				return strings.TrimSpace(value[:i]), 0, nil
+			}
+		}
+		return trimmed, 0, nil
+	}
 	value = strings.TrimSpace(value)
-	// Check if value is quoted (both single and double)
-	if len(value) >= 2 {
-		if (value[0] == '"' && value[len(value)-1] == '"') ||
-			(value[0] == '\'' && value[len(value)-1] == '\'') {
-			return value[1 : len(value)-1], true
+
+	quote := value[0]
+	var unquoted strings.Builder
+	for i := 1; i < len(value); i++ {
+		switch c := value[i]; {
+		case c == quote:
+			if rest := strings.TrimSpace(value[i+1:]); rest != "" && !strings.HasPrefix(rest, "#") {
+				return "", 0, newError(MessageUnexpectedAfterClosingQuote, rest)
+			}
+			return unquoted.String(), quote, nil
+		case c == '\\' && quote == '"' && i+1 < len(value):
+			i++
+			switch escaped := value[i]; escaped {
+			case 'n':
+				unquoted.WriteByte('\n')
+			case 'r':
+				unquoted.WriteByte('\r')
+			case 't':
+				unquoted.WriteByte('\t')
+			case '"', '\\':
+				unquoted.WriteByte(escaped)
+			default:
+				// Unknown escapes are kept, e.g. Windows paths
+				unquoted.WriteByte('\\')
+				unquoted.WriteByte(escaped)
+			}
+		default:
+			unquoted.WriteByte(c)
 		}
 	}
-	return value, false
+	return "", 0, errMissingQuote
+}
+
+// envKey returns the variable name of the key of an env file line, without the export prefix
+// of files written to be sourced by a shell
+func envKey(key string) string {
+	key = strings.TrimSpace(key)
+	if name, found := strings.CutPrefix(key, "export"); found && name != strings.TrimLeft(name, " \t") {
+		return strings.TrimSpace(name)
+	}
+	return key
 }
 
 // EnvValue stores environment variable value with metadata
 type EnvValue struct {
 	Value     string
 	WasQuoted bool
+	Line      int      // Line in the env file, 0 for remote sources
+	Hints     EnvHints // Settings from an "# envied:" comment on the line above
 }
 
-// ReadEnvFile reads environment variables from a file
+// ReadEnvFile reads environment variables from a file, expanding ${NAME} references to other
+// variables of the file like dotenv tools. $$ is a literal $ and single-quoted values are not
+// expanded. References to undefined variables and cycles are errors.
 func ReadEnvFile(filename string) (map[string]string, error) {
+	return ReadEnvFileWithLookup(filename, nil)
+}
+
+// ReadEnvFileWithLookup reads environment variables from a file like ReadEnvFile, resolving
+// references to variables not defined in the file with lookup, e.g. os.LookupEnv
+func ReadEnvFileWithLookup(filename string, lookup func(name string) (string, bool)) (map[string]string, error) {
 	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
 	defer file.Close()
 
-	envVars := make(map[string]string)
+	envVars := make(map[string]EnvValue)
+	provenance := make(map[string]Provenance)
+	literal := make(map[string]bool)
 
 	// Simple line-by-line reading
-	content, err := os.ReadFile(filename)
+	content, err := readEnvFileContent(filename)
 	if err != nil {
 		return nil, err
 	}
+	if err := checkEnvFileContent(filename, content); err != nil {
+		return nil, err
+	}
+	content = normalizeEnvFileContent(content)
 
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
+	for _, envLine := range envFileLines(string(content)) {
+		line := envLine.text
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
 		}
 
 		parts := strings.SplitN(line, "=", 2)
 		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value, _ := unquoteValue(parts[1])
-			envVars[key] = value
+			key := envKey(parts[0])
+			value, quote, err := unquoteValue(parts[1])
+			if err != nil {
+				return nil, newError(MessageEnvFileValueError, filename, envLine.number, key, err)
+			}
+			envVars[key] = EnvValue{Value: value, WasQuoted: quote != 0, Line: envLine.number}
+			provenance[key] = Provenance{Source: fmt.Sprintf("%s:%d", filename, envLine.number)}
+			literal[key] = quote == '\''
 		}
 	}
 
-	return envVars, nil
+	in := newInterpolator(envVars, provenance)
+	in.lookup = lookup
+	for key, isLiteral := range literal {
+		in.resolved[key] = isLiteral
+	}
+	if err := in.resolveAll(); err != nil {
+		return nil, err
+	}
+
+	values := make(map[string]string, len(envVars))
+	for key, envValue := range envVars {
+		values[key] = envValue.Value
+	}
+	return values, nil
 }
 
 // ReadEnvFileWithMetadata reads environment variables from a file with quote information
@@ -361,25 +634,47 @@ func ReadEnvFileWithMetadata(filename string) (map[string]EnvValue, error) {
 	envVars := make(map[string]EnvValue)
 
 	// Simple line-by-line reading
-	content, err := os.ReadFile(filename)
+	content, err := readEnvFileContent(filename)
 	if err != nil {
 		return nil, err
 	}
-
-	lines := strings.Split(string(content), "\n")
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if line == "" || strings.HasPrefix(line, "#") {
+	if err := checkEnvFileContent(filename, content); err != nil {
+		return nil, err
+	}
+	content = normalizeEnvFileContent(content)
+
+	// Hints apply to the variable on the next line only
+	var hints EnvHints
+	for _, envLine := range envFileLines(string(content)) {
+		line := envLine.text
+		lineHints := hints
+		hints = EnvHints{}
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "#") {
+			parsed, ok, err := parseEnvHints(line)
+			if err != nil {
+				return nil, newError(MessageAtFileLine, filename, envLine.number, err)
+			}
+			if ok {
+				hints = parsed
+			}
 			continue
 		}
 
 		parts := strings.SplitN(line, "=", 2)
 		if len(parts) == 2 {
-			key := strings.TrimSpace(parts[0])
-			value, wasQuoted := unquoteValue(parts[1])
+			key := envKey(parts[0])
+			value, quote, err := unquoteValue(parts[1])
+			if err != nil {
+				return nil, newError(MessageEnvFileValueError, filename, envLine.number, key, err)
+			}
 			envVars[key] = EnvValue{
 				Value:     value,
-				WasQuoted: wasQuoted,
+				WasQuoted: quote != 0,
+				Line:      envLine.number,
+				Hints:     lineHints,
 			}
 		}
 	}
@@ -387,23 +682,33 @@ func ReadEnvFileWithMetadata(filename string) (map[string]EnvValue, error) {
 	return envVars, nil
 }
 
+// NewGenerator creates a generator for the given configuration settings
 func NewGenerator(config *Config) *Generator {
 	return &Generator{
 		config: config,
 	}
 }
 
+// snapshot returns a copy of the generator configuration that generation can modify
+func (g *Generator) snapshot() *Config {
+	config := *g.config
+	config.Fields = append([]Field(nil), g.config.Fields...)
+	config.RuntimeImport = resolveRuntimeImport(config.RuntimeImport)
+	return &config
+}
+
 // GenerateFromEnvFile reads environment variables from a .env file and generates configuration
 func (g *Generator) GenerateFromEnvFile(envFilePath string) error {
 	envVars, err := ReadEnvFile(envFilePath)
 	if err != nil {
-		return fmt.Errorf("failed to read env file %s: %w", envFilePath, err)
+		return newError(MessageFailedReadEnvFile, envFilePath, err)
 	}
 
 	// Extract fields from environment variables
-	g.config.Fields = extractFieldsFromEnvVars(envVars)
+	config := g.snapshot()
+	config.Fields = extractFieldsFromEnvVars(envVars)
 
-	return g.generateConfigFile()
+	return g.generateConfigFile(config)
 }
 
 // LoadConfigFile loads configuration from JSON file
@@ -411,18 +716,81 @@ func LoadConfigFile(configFilePath string) (*ConfigFile, error) {
 	// Read configuration file
 	configData, err := os.ReadFile(configFilePath)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read config file %s: %w", configFilePath, err)
+		return nil, newError(MessageFailedReadConfigFile, configFilePath, err)
+	}
+
+	// YAML and TOML files are converted to JSON
+	configData, err = configJSON(configFilePath, configData)
+	if err != nil {
+		return nil, newError(MessageFailedParseConfigFile, configFilePath, err)
+	}
+
+	// Check against ConfigSchema first, its violations are more precise than decoding errors
+	var document any
+	if err := decodeJSONNumbers(configData, &document); err != nil {
+		return nil, newError(MessageFailedParseConfigFile, configFilePath, err)
+	}
+	if violations := validateConfigSchema(document); len(violations) > 0 {
+		return nil, newError(MessageInvalidConfigFile, configFilePath, strings.Join(violations, "\n  "))
 	}
 
 	var configFile ConfigFile
 	err = json.Unmarshal(configData, &configFile)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse config file %s: %w", configFilePath, err)
+		return nil, newError(MessageFailedParseConfigFile, configFilePath, err)
 	}
 
 	return &configFile, nil
 }
 
+// Save writes the configuration to a JSON file.
+// Output is deterministic (fixed field order, sorted environment names, two-space
+// indentation and a trailing newline) so tools modifying the config produce minimal diffs.
+// YAML and TOML files and JSON files with comments are not written, they keep their comments and layout.
+func (c *ConfigFile) Save(configFilePath string) error {
+	if err := checkWritableConfigFile(configFilePath); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return newError(MessageFailedEncodeConfigFile, configFilePath, err)
+	}
+	data = append(data, '\n')
+
+	if err := os.WriteFile(configFilePath, data, 0644); err != nil {
+		return newError(MessageFailedWriteConfigFile, configFilePath, err)
+	}
+
+	return nil
+}
+
+// checkWritableConfigFile fails for configuration files Save doesn't write
+func checkWritableConfigFile(configFilePath string) error {
+	if !isJSONConfigFile(configFilePath) {
+		return newError(MessageConfigNotJSON, configFilePath)
+	}
+	if hasJSONComments(configFilePath) {
+		return newError(MessageConfigHasComments, configFilePath)
+	}
+	return nil
+}
+
+// environmentNames returns the names of the environments sorted, so environments are read,
+// checked and reported in the same order on every run
+func (c *ConfigFile) environmentNames() []string {
+	envNames := make([]string, 0, len(c.Environments))
+	for envName := range c.Environments {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+	return envNames
+}
+
+// outputFile returns the path of the generated merged configuration file
+func (c *ConfigFile) outputFile() string {
+	return filepath.Join(c.OutputDir, "config_env.gen.go")
+}
+
 // GenerateFromConfigFile generates configurations from JSON file
 func GenerateFromConfigFile(configFilePath string) error {
 	configFile, err := LoadConfigFile(configFilePath)
@@ -430,126 +798,408 @@ func GenerateFromConfigFile(configFilePath string) error {
 		return err
 	}
 
+	_, err = generateFromConfig(configFile, configFilePath, configFile.outputFile(), stdoutLog())
+	return err
+}
+
+// generateFromConfig generates the merged configuration file of a loaded config,
+// progress messages and warnings are written to log and the warnings are returned
+func generateFromConfig(configFile *ConfigFile, configFilePath string, outputFile string, log *messageLog) ([]Warning, error) {
+	mergedData, err := buildMergedConfig(configFile, configFilePath, log)
+	if err != nil {
+		return nil, err
+	}
+
+	codeFile, internalImport, err := configFile.generatedCodeFile(outputFile)
+	if err != nil {
+		return nil, err
+	}
+
+	// Generate merged file
+	if err := checkPackageConflicts(outputFile, mergedData, configFile.OnConflict, log); err != nil {
+		return nil, err
+	}
+	if internalImport != "" {
+		if err := checkPackageConflicts(codeFile, mergedData, configFile.OnConflict, log); err != nil {
+			return nil, err
+		}
+	}
+	err = generateMergedFile(codeFile, mergedData)
+	if err != nil {
+		return nil, newError(MessageFailedGenerateMergedConfiguration, err)
+	}
+	log.say(MessageGenerated)
+
+	if mergedData.BuildTags {
+		if err := generateBuildTagFiles(codeFile, mergedData); err != nil {
+			return nil, newError(MessageFailedGenerateBuildTagFiles, err)
+		}
+		log.say(MessageBuildTags, mergedData.TagPrefix)
+	}
+
+	if internalImport != "" {
+		if err := generateShimFile(outputFile, mergedData, internalImport); err != nil {
+			return nil, newError(MessageFailedGenerateReExportShim, err)
+		}
+		log.say(MessageInternalPackage, internalImport, outputFile)
+	}
+	if mergedData.Ldflags {
+		importPath := linkImportPath(codeFile)
+		for _, envName := range sortedEnvironmentNames(mergedData.Environments) {
+			if linked := mergedData.Environments[envName].Linked; len(linked) > 0 {
+				log.say(MessageLdflags, envName, ldflagsRecipe(importPath, linked))
+			}
+		}
+	}
+	configFile.timer.mark("write code")
+
+	if err := emitDocs(configFile.Emit, mergedData, log); err != nil {
+		return nil, err
+	}
+	configFile.timer.mark("emit files")
+	mergedData.state.CodeFile = codeFile
+	if err := configFile.runHooks(StageEmit, mergedData.state); err != nil {
+		return nil, err
+	}
+	configFile.timer.report(log)
+
+	log.say(MessageAllGenerated)
+	log.say(MessageOutputLocation, filepath.Dir(outputFile))
+	log.say(MessageUsageHint)
+
+	return mergedData.Warnings, nil
+}
+
+// buildMergedConfig reads and validates the env files of a loaded config
+// and prepares the data of the merged configuration file
+func buildMergedConfig(configFile *ConfigFile, configFilePath string, log *messageLog) (*mergedConfig, error) {
+	algorithm, obfuscate, err := configFile.obfuscationMode()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkPathSafety(configFile, configFilePath); err != nil {
+		return nil, err
+	}
+	if err := configFile.Annotations.validate(); err != nil {
+		return nil, err
+	}
+	if err := checkBuildTagNames(configFile); err != nil {
+		return nil, err
+	}
+	if err := checkSplitEnvironments(configFile); err != nil {
+		return nil, err
+	}
+	if err := configFile.Getters.validate(); err != nil {
+		return nil, err
+	}
+	sourceTimeout, err := configFile.sourceTimeout()
+	if err != nil {
+		return nil, err
+	}
+	warnings := &warningLog{log: log}
+	warnEnvFileOverrides(configFile, warnings)
+	if configFile.Variables, err = mergeFieldTypes(configFile.Fields, configFile.Variables); err != nil {
+		return nil, err
+	}
+
 	// Collect all environment variables from all environments for consistency check and metadata
-	allEnvVars := make(map[string]map[string]string)
 	allEnvVarsWithMetadata := make(map[string]map[string]EnvValue)
-	for envName, envConfig := range configFile.Environments {
-		envVarsWithMetadata, err := ReadEnvFileWithMetadata(envConfig.EnvFile)
+	sourceHashes := make(map[string]string)
+	provenances := make(map[string]map[string]Provenance)
+	structureOnly := make(map[string]bool) // Environments whose values are only read with valuesFrom
+	for _, envName := range configFile.environmentNames() {
+		envConfig := configFile.Environments[envName]
+		envVarsWithMetadata, provenance, sourceHash, err := readEnvironment(envConfig, sourceTimeout)
 		if err != nil {
-			return fmt.Errorf("failed to read env file %s: %w", envConfig.EnvFile, err)
+			return nil, newError(MessageEnvironmentError, envName, err)
+		}
+		if envConfig.Vault != nil {
+			if configFile.valuesFrom != ValuesFromVault {
+				structureOnly[envName] = true
+				log.say(MessageStructureOnly, envName, envConfig.Vault.describe())
+			} else {
+				valuesHash, err := mergeVaultValues(envConfig, sourceTimeout, envVarsWithMetadata, provenance)
+				if err != nil {
+					return nil, newError(MessageEnvironmentError, envName, err)
+				}
+				sourceHash = layeredSourceHash(sourceHash, []string{valuesHash})
+			}
+		}
+		provenances[envName] = provenance
+		if err := applySubstitutions(envVarsWithMetadata, provenance, configFile.substitutionValues(envConfig)); err != nil {
+			return nil, newError(MessageEnvironmentError, envName, err)
+		}
+		if configFile.Interpolate {
+			if err := interpolateVariables(envVarsWithMetadata, provenance); err != nil {
+				return nil, newError(MessageEnvironmentError, envName, err)
+			}
+		}
+		if err := applyVariableTransforms(envVarsWithMetadata, configFile.Variables); err != nil {
+			return nil, newError(MessageEnvironmentError, envName, err)
 		}
 		allEnvVarsWithMetadata[envName] = envVarsWithMetadata
 
-		// Convert to simple map for consistency check
+		sourceHashes[envName] = sourceHash
+	}
+	configFile.timer.mark("read environments")
+
+	state := &PipelineState{Config: configFile, Variables: allEnvVarsWithMetadata}
+	if err := configFile.runHooks(StageSources, state); err != nil {
+		return nil, err
+	}
+	allEnvVarsWithMetadata = state.Variables
+
+	// Convert to simple maps for consistency check, conditional variables are checked separately
+	allEnvVars := make(map[string]map[string]string)
+	for envName, envVarsWithMetadata := range allEnvVarsWithMetadata {
 		envVars := make(map[string]string)
 		for k, v := range envVarsWithMetadata {
-			envVars[k] = v.Value
+			if !configFile.Variables[k].isConditional() {
+				envVars[k] = v.Value
+			}
 		}
 		allEnvVars[envName] = envVars
 	}
 
-	// Check consistency between environments
-	if err := checkEnvironmentConsistency(allEnvVars); err != nil {
-		return fmt.Errorf("environment consistency check failed: %w", err)
+	if err := validateVariableNames(configFile, allEnvVarsWithMetadata, warnings); err != nil {
+		return nil, err
+	}
+	if err := checkUnsafeCharacters(configFile, allEnvVarsWithMetadata, provenances, warnings); err != nil {
+		return nil, err
+	}
+
+	if err := applyConditionalVariables(configFile, allEnvVarsWithMetadata); err != nil {
+		return nil, newError(MessageConsistencyCheckFailed, err)
+	}
+	if err := checkEmptyEnvironments(configFile, allEnvVarsWithMetadata, warnings); err != nil {
+		return nil, err
+	}
+	if err := checkPolicies(configFile, allEnvVarsWithMetadata, provenances); err != nil {
+		return nil, err
 	}
 
+	// Check consistency between environments unless extra variables are allowed
+	if !configFile.AllowExtraVariables {
+		if err := checkEnvironmentConsistency(allEnvVars, log); err != nil {
+			return nil, newError(MessageConsistencyCheckFailed, err)
+		}
+	}
+	configFile.timer.mark("checks")
+
 	// Generate single merged configuration file
-	fmt.Println("🔄 Generating merged configuration file...")
+	log.say(MessageGenerating)
 
 	// Prepare data for merged template
-	mergedData := struct {
-		PackageName  string
-		RandomSeed   int64
-		Environments map[string]struct {
-			StructName string
-			Fields     []Field
-			Obfuscated map[string]*ObfuscationResult
-		}
-		AllFields []Field
-	}{
-		PackageName: configFile.PackageName,
-		RandomSeed:  int64(configFile.RandomSeed),
-		Environments: make(map[string]struct {
-			StructName string
-			Fields     []Field
-			Obfuscated map[string]*ObfuscationResult
-		}),
-		AllFields: extractFieldsFromEnvVarsWithMetadata(allEnvVarsWithMetadata["dev"]), // Use dev as reference for interface
+	mergedData := &mergedConfig{
+		PackageName:   configFile.PackageName,
+		RuntimeImport: resolveRuntimeImport(configFile.RuntimeImport),
+		RandomSeed:    int64(configFile.RandomSeed),
+		Obfuscation:   algorithm,
+		CryptoMode:    configFile.CryptoMode,
+		GeneratedAt:   generationTime(),
+		Environments:  make(map[string]mergedEnvironment),
+		Annotations:   configFile.Annotations,
+		BuildTags:     configFile.BuildTags,
+		TagPrefix:     configFile.buildTagPrefix(),
+		Split:         configFile.SplitEnvironments,
+		Ldflags:       configFile.Ldflags,
+		Receiver:      configFile.Getters.receiver(),
+	}
+
+	// Shared interface is built from variables common to all environments
+	envFields := make(map[string][]Field)
+	// Environments without their values are checked above but not generated
+	var envNames []string
+	for _, envName := range configFile.environmentNames() {
+		if !structureOnly[envName] {
+			envNames = append(envNames, envName)
+		}
+	}
+	if len(envNames) == 0 {
+		return nil, newError(MessageAllEnvironmentsFromVault)
+	}
+	namer, err := configFile.effectiveNamer()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkFileNames(envNames, namer); err != nil {
+		return nil, err
+	}
+	mergedData.namer = namer
+	for _, envName := range envNames {
+		variables, err := applyEnvHints(configFile.Variables, allEnvVarsWithMetadata[envName])
+		if err != nil {
+			return nil, newError(MessageEnvironmentError, envName, err)
+		}
+		envFields[envName] = extractFieldsFromEnvVarsWithMetadata(allEnvVarsWithMetadata[envName])
+		if err := applyTimeLayouts(envFields[envName], variables); err != nil {
+			return nil, newError(MessageEnvironmentError, envName, err)
+		}
+		if err := applySeparators(envFields[envName], variables); err != nil {
+			return nil, newError(MessageEnvironmentError, envName, err)
+		}
+		if err := applyDeclaredTypes(envFields[envName], variables); err != nil {
+			return nil, newError(MessageEnvironmentError, envName, err)
+		}
+		if err := checkIntegerRanges(envFields[envName], variables); err != nil {
+			return nil, newError(MessageEnvironmentError, envName, err)
+		}
+		applyDescriptions(envFields[envName], variables)
+		if err := applyFieldNames(envFields[envName], namer); err != nil {
+			return nil, newError(MessageEnvironmentError, envName, err)
+		}
+		if err := applyGetterNames(envFields[envName], variables, configFile.Getters, namer); err != nil {
+			return nil, newError(MessageEnvironmentError, envName, err)
+		}
+		warnSuspiciousValues(envName, envFields[envName], variables, warnings)
+	}
+	state.Environments, state.Fields = envNames, envFields
+	for _, stage := range []Stage{StageModel, StageValidate} {
+		if err := configFile.runHooks(stage, state); err != nil {
+			return nil, err
+		}
+		if err := state.checkEnvironments(stage); err != nil {
+			return nil, err
+		}
+		allEnvVarsWithMetadata, envNames, envFields = state.Variables, state.Environments, state.Fields
+	}
+	mergedData.AllFields = sharedFields(envFields, configFile.Variables)
+
+	// Struct names of environments can't be reused by profiles
+	structNames := make(map[string]string, len(envNames))
+	for _, envName := range envNames {
+		structNames[configFile.Environments[envName].StructName] = fmt.Sprintf("environment '%s'", envName)
 	}
 
 	// Prepare fields for each environment
-	for envName, envConfig := range configFile.Environments {
-		envVarsWithMetadata := allEnvVarsWithMetadata[envName]
-		fields := extractFieldsFromEnvVarsWithMetadata(envVarsWithMetadata)
+	for _, envName := range envNames {
+		envConfig := configFile.Environments[envName]
+		fields := envFields[envName]
 		obfuscated := make(map[string]*ObfuscationResult)
+		linked := make(map[string]string)
+
+		obfuscateEnv := obfuscate
+		if envConfig.Obfuscate != nil {
+			obfuscateEnv = *envConfig.Obfuscate
+		}
 
-		// Generate obfuscated data for each field
+		// Generate obfuscated data for each field, sensitive hints override the environment
 		for _, field := range fields {
-			if field.Value != "" {
-				result, err := generateObfuscatedField(field.EnvName, field.Type, field.Value, mergedData.RandomSeed)
+			obfuscateField := obfuscateEnv
+			if sensitive := allEnvVarsWithMetadata[envName][field.EnvName].Hints.Sensitive; sensitive != nil {
+				obfuscateField = *sensitive
+			}
+			if obfuscateField && field.Value != "" {
+				result, err := generateObfuscatedField(algorithm, field.EnvName, field.Type, field.Value, field.Separator, mergedData.RandomSeed)
 				if err != nil {
-					return fmt.Errorf("failed to obfuscate field %s: %w", field.EnvName, err)
+					return nil, newError(MessageFailedObfuscateField, field.EnvName, err)
 				}
 				// Only add to map if result is not nil (i.e., field was actually obfuscated)
-				if result != nil {
+				if result != nil && configFile.Ldflags {
+					linked[field.EnvName] = linkedVariable(envName, namer.Constant(field.EnvName))
+				} else if result != nil {
+					constant := namer.Constant(field.EnvName)
+					result.KeyName, result.ValueName = "_enviedkey"+constant, "_envieddata"+constant
 					obfuscated[field.EnvName] = result
 				}
 			}
 		}
 
-		mergedData.Environments[envName] = struct {
-			StructName string
-			Fields     []Field
-			Obfuscated map[string]*ObfuscationResult
-		}{
+		profiles, err := buildProfiles(envName, envConfig, fields, structNames)
+		if err != nil {
+			return nil, err
+		}
+
+		sensitive := make(map[string]bool)
+		for _, field := range fields {
+			if hint := allEnvVarsWithMetadata[envName][field.EnvName].Hints.Sensitive; hint != nil && *hint {
+				sensitive[field.EnvName] = true
+			}
+		}
+
+		var literals map[string]string
+		if configFile.Literals {
+			literals = fieldLiterals(fields, sensitive)
+		}
+
+		mergedData.Environments[envName] = mergedEnvironment{
 			StructName: envConfig.StructName,
 			Fields:     fields,
 			Obfuscated: obfuscated,
+			SourceHash: sourceHashes[envName],
+			Extras:     extraFields(fields, mergedData.AllFields),
+			Profiles:   profiles,
+			Provenance: provenances[envName],
+			Literals:   literals,
+			Sensitive:  sensitive,
+			Linked:     linked,
+		}
+		if configFile.verbose {
+			logProvenance(log, envName, fields, provenances[envName])
 		}
 	}
 
-	// Generate merged file
-	outputFile := filepath.Join(configFile.OutputDir, "config_env.gen.go")
-	err = generateMergedFile(outputFile, mergedData)
-	if err != nil {
-		return fmt.Errorf("failed to generate merged configuration: %w", err)
+	state.Obfuscated = make(map[string]map[string]*ObfuscationResult, len(envNames))
+	for _, envName := range envNames {
+		state.Obfuscated[envName] = mergedData.Environments[envName].Obfuscated
+	}
+	if err := configFile.runHooks(StageObfuscate, state); err != nil {
+		return nil, err
+	}
+	for _, envName := range envNames {
+		envData := mergedData.Environments[envName]
+		envData.Obfuscated = state.Obfuscated[envName]
+		if envData.Obfuscated == nil {
+			envData.Obfuscated = make(map[string]*ObfuscationResult)
+		}
+		mergedData.Environments[envName] = envData
 	}
-	fmt.Println("✅ Merged configuration file generated successfully!")
 
-	fmt.Println("\n🎉 All configurations generated!")
-	fmt.Printf("📁 Files are located in %s\n", configFile.OutputDir)
-	fmt.Println("🔧 You can now use the generated configurations directly")
+	if err := checkGeneratedCollisions(mergedData); err != nil {
+		return nil, err
+	}
+	if err := checkSizeBudget(configFile, mergedData); err != nil {
+		return nil, err
+	}
+	mergedData.state = state
 
-	return nil
+	mergedData.Warnings = warnings.warnings
+	configFile.timer.mark("prepare fields")
+	return mergedData, nil
 }
 
 // AutoGenerate automatically generates configurations
 // Searches for configuration file in current directory and parent directories
 func AutoGenerate() error {
-	configFile := findConfigFile()
+	configFile := FindConfigFile()
 	if configFile == "" {
-		return fmt.Errorf("configuration file go-envied-config.json not found")
+		return newError(MessageConfigNotFound, DefaultConfigFileName)
 	}
 
-	fmt.Printf("🔧 Automatic configuration generation from file: %s\n", configFile)
+	stdoutLog().say(MessageAutoGenerate, configFile)
 	return GenerateFromConfigFile(configFile)
 }
 
-// findConfigFile searches for configuration file in current directory and parent directories
-func findConfigFile() string {
-	configFileName := "go-envied-config.json"
-
+// FindConfigFile searches for configuration file in current directory and parent directories.
+// Each directory is searched for go-envied-config.json, .yaml, .yml and .toml in this order.
+// It returns an empty string if the file is not found.
+func FindConfigFile() string {
 	// Check current directory
-	if _, err := os.Stat(configFileName); err == nil {
-		return configFileName
+	for _, configFileName := range configFileNames {
+		if _, err := os.Stat(configFileName); err == nil {
+			return configFileName
+		}
 	}
 
 	// Check parent directories (maximum 3 levels up)
 	currentDir, _ := os.Getwd()
 	for i := 0; i < 3; i++ {
-		parentPath := filepath.Join(currentDir, strings.Repeat("../", i+1), configFileName)
-		if _, err := os.Stat(parentPath); err == nil {
-			return parentPath
+		for _, configFileName := range configFileNames {
+			parentPath := filepath.Join(currentDir, strings.Repeat("../", i+1), configFileName)
+			if _, err := os.Stat(parentPath); err == nil {
+				return parentPath
+			}
 		}
 	}
 
@@ -560,224 +1210,161 @@ func findConfigFile() string {
 func Init() {
 	err := AutoGenerate()
 	if err != nil {
-		fmt.Printf("⚠️ Warning: failed to generate configurations: %v\n", err)
-		fmt.Println("💡 Make sure go-envied-config.json file exists in the project root")
+		log := stdoutLog()
+		log.say(MessageInitFailed, err)
+		log.say(MessageInitHint, DefaultConfigFileName)
 	}
 }
 
 // GenerateFromEnvVars generates configuration from environment variables with strict validation
 func (g *Generator) GenerateFromEnvVars() error {
-	for i, field := range g.config.Fields {
+	config := g.snapshot()
+	for i, field := range config.Fields {
 		if value := os.Getenv(field.EnvName); value != "" {
-			g.config.Fields[i].Value = value
+			config.Fields[i].Value = value
 		} else if os.Getenv(field.EnvName) == "" {
 			// Check if variable exists but is empty
 			if _, exists := os.LookupEnv(field.EnvName); exists {
-				return fmt.Errorf("❌ ERROR: environment variable '%s' is empty", field.EnvName)
+				return newError(MessageEnvVariableEmpty, field.EnvName)
 			}
 		} else if field.DefaultValue != "" {
 			// Only use default value if explicitly provided
-			g.config.Fields[i].Value = field.DefaultValue
+			config.Fields[i].Value = field.DefaultValue
 		} else if !field.Optional {
-			return fmt.Errorf("❌ ERROR: required environment variable '%s' not found", field.EnvName)
+			return newError(MessageEnvVariableNotFound, field.EnvName)
 		}
 	}
 
-	return g.generateConfigFile()
+	return g.generateConfigFile(config)
 }
 
 // generateConfigFile generates the Go configuration file
-func (g *Generator) generateConfigFile() error {
+func (g *Generator) generateConfigFile(config *Config) error {
 	// Extract environment name from Environment (e.g., "DevConfig" -> "dev")
-	envName := strings.ToLower(g.config.Environment)
+	envName := strings.ToLower(config.Environment)
 	envName = strings.TrimSuffix(envName, "config")
-	outputFile := filepath.Join(g.config.OutputDir, fmt.Sprintf("config_%s.go", envName))
+	outputFile := filepath.Join(config.OutputDir, fmt.Sprintf("config_%s.go", envName))
 
 	// Create output directory if it doesn't exist
-	if err := os.MkdirAll(g.config.OutputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+	if err := os.MkdirAll(config.OutputDir, 0755); err != nil {
+		return newError(MessageFailedCreateOutputDirectory, err)
 	}
 
 	// Obfuscate all string fields before generating the file
-	for i, field := range g.config.Fields {
+	for i, field := range config.Fields {
 		if field.Type == FieldTypeString && field.Value != "" {
-			obfuscatedValue := Obfuscate(field.Value, "go-envied-obfuscation")
-			g.config.Fields[i].Value = obfuscatedValue
+			obfuscatedValue := Obfuscate(field.Value, defaultObfuscationKey)
+			config.Fields[i].Value = obfuscatedValue
 		}
 	}
 
 	// Generate configuration file
-	return g.generateFile(outputFile, configTemplate)
+	templateStr := configTemplate
+	if config.Template != "" {
+		templateStr = config.Template
+	}
+	return g.generateFile(config, outputFile, templateStr)
 }
 
 // generateFile generates a file from template
-func (g *Generator) generateFile(outputFile string, templateStr string) error {
+func (g *Generator) generateFile(config *Config, outputFile string, templateStr string) error {
 	file, err := os.Create(outputFile)
 	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+		return newError(MessageFailedCreateOutputFile, err)
 	}
 	defer file.Close()
 
-	tmpl, err := template.New("config").Parse(templateStr)
+	tmpl, err := newTemplate("config", config.Funcs).Parse(templateStr)
 	if err != nil {
-		return fmt.Errorf("failed to parse template: %w", err)
+		return newError(MessageFailedParseTemplate, err)
 	}
 
-	return tmpl.Execute(file, g.config)
+	return tmpl.Execute(file, config)
 }
 
 // generateMergedFile generates a single merged configuration file
-func generateMergedFile(outputFile string, data interface{}) error {
+func generateMergedFile(outputFile string, data *mergedConfig) error {
 	// Create output directory if it doesn't exist
 	outputDir := filepath.Dir(outputFile)
 	if err := os.MkdirAll(outputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+		return newError(MessageFailedCreateOutputDirectory, err)
 	}
 
-	file, err := os.Create(outputFile)
-	if err != nil {
-		return fmt.Errorf("failed to create output file: %w", err)
+	// Generate code directly instead of using template, nothing is written if it fails
+	var code bytes.Buffer
+	if err := generateCodeDirectly(&code, data); err != nil {
+		return err
 	}
-	defer file.Close()
-
-	// Generate code directly instead of using template
-	return generateCodeDirectly(file, data)
+	if err := os.WriteFile(outputFile, code.Bytes(), 0644); err != nil {
+		return newError(MessageFailedCreateOutputFile, err)
+	}
+	return nil
 }
 
-// generateCodeDirectly generates the Go code directly
-func generateCodeDirectly(file *os.File, data interface{}) error {
-	// Type assertion to get the data
-	mergedData, ok := data.(struct {
-		PackageName  string
-		RandomSeed   int64
-		Environments map[string]struct {
-			StructName string
-			Fields     []Field
-			Obfuscated map[string]*ObfuscationResult
-		}
-		AllFields []Field
-	})
-	if !ok {
-		return fmt.Errorf("invalid data type for code generation")
-	}
-
-	// Write package header
-	fmt.Fprintf(file, "// Code generated by go-envied. DO NOT EDIT.\n")
-	fmt.Fprintf(file, "// Generated merged configuration file for all environments\n\n")
-	fmt.Fprintf(file, "package %s\n\n", mergedData.PackageName)
-	fmt.Fprintf(file, "import \"github.com/petrovyuri/go-envied\"\n\n")
-
-	// Write interface
-	fmt.Fprintf(file, "// ConfigInterface defines the interface for all generated configurations\n")
-	fmt.Fprintf(file, "type ConfigInterface interface {\n")
-	for _, field := range mergedData.AllFields {
-		fmt.Fprintf(file, "\tGet%s() %s\n", field.EnvName, field.Type)
-	}
-	fmt.Fprintf(file, "}\n\n")
-
-	// Write each environment
-	for envName, envData := range mergedData.Environments {
-		// Write static constants for keys and values with environment prefix
-		for fieldName, obfuscated := range envData.Obfuscated {
-			if obfuscated == nil {
-				continue // Skip fields that don't need obfuscation
-			}
-			// Write key constant with environment prefix (private variable - starts with lowercase)
-			envPrefixLower := strings.ToLower(envName)
-			keyConstName := fmt.Sprintf("%s%s", envPrefixLower, obfuscated.KeyName)
-			fmt.Fprintf(file, "// Static key for %s in %s environment\n", fieldName, envName)
-			fmt.Fprintf(file, "var %s = ", keyConstName)
-
-			switch key := obfuscated.Key.(type) {
-			case []int:
-				fmt.Fprintf(file, "[]int{")
-				for i, v := range key {
-					if i > 0 {
-						fmt.Fprintf(file, ", ")
-					}
-					fmt.Fprintf(file, "%d", v)
-				}
-				fmt.Fprintf(file, "}\n\n")
-			case bool:
-				fmt.Fprintf(file, "%t\n\n", key)
-			case int:
-				fmt.Fprintf(file, "%d\n\n", key)
-			default:
-				fmt.Fprintf(file, "%v\n\n", key)
-			}
-
-			// Write value constant if different from field name (private variable - starts with lowercase)
-			if obfuscated.ValueName != fieldName {
-				envPrefixLower := strings.ToLower(envName)
-				valueConstName := fmt.Sprintf("%s%s", envPrefixLower, obfuscated.ValueName)
-				fmt.Fprintf(file, "// Static encrypted data for %s in %s environment\n", fieldName, envName)
-				fmt.Fprintf(file, "var %s = []int{", valueConstName)
-
-				switch value := obfuscated.Value.(type) {
-				case []int:
-					for i, v := range value {
-						if i > 0 {
-							fmt.Fprintf(file, ", ")
-						}
-						fmt.Fprintf(file, "%d", v)
-					}
-				default:
-					fmt.Fprintf(file, "%v", value)
-				}
-				fmt.Fprintf(file, "}\n\n")
-			}
-		}
-
-		// Write struct
-		fmt.Fprintf(file, "// %sConfig - generated configuration for %s environment\n", envData.StructName, envName)
-		fmt.Fprintf(file, "type %sConfig struct {\n", envData.StructName)
-		for _, field := range envData.Fields {
-			fmt.Fprintf(file, "\t%s %s\n", field.EnvName, field.Type)
-		}
-		fmt.Fprintf(file, "}\n\n")
-
-		// Write constructor
-		fmt.Fprintf(file, "// New%sConfig creates a new configuration for %s environment\n", envData.StructName, envName)
-		fmt.Fprintf(file, "func New%sConfig() *%sConfig {\n", envData.StructName, envData.StructName)
-		fmt.Fprintf(file, "\treturn &%sConfig{\n", envData.StructName)
+// generateCodeDirectly generates the Go code of the merged configuration from its template
+func generateCodeDirectly(w io.Writer, mergedData *mergedConfig) error {
+	code, err := newMergedData(mergedData).Render()
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(code)
+	return err
+}
 
-		for _, field := range envData.Fields {
-			if obfuscated, exists := envData.Obfuscated[field.EnvName]; exists && obfuscated != nil {
-				// Only strings can be obfuscated
-				envPrefixLower := strings.ToLower(envName)
-				keyConstName := fmt.Sprintf("%s%s", envPrefixLower, obfuscated.KeyName)
-				valueConstName := fmt.Sprintf("%s%s", envPrefixLower, obfuscated.ValueName)
-				fmt.Fprintf(file, "\t\t%s: envied.DeobfuscateString(%s, %s),\n", field.EnvName, keyConstName, valueConstName)
-			} else {
-				// For non-obfuscated fields (int, bool, float64, string), use simple parsing functions
-				switch field.Type {
-				case FieldTypeInt:
-					fmt.Fprintf(file, "\t\t%s: envied.ParseInt(\"%s\"),\n", field.EnvName, field.Value)
-				case FieldTypeBool:
-					fmt.Fprintf(file, "\t\t%s: envied.ParseBool(\"%s\"),\n", field.EnvName, field.Value)
-				case FieldTypeFloat:
-					fmt.Fprintf(file, "\t\t%s: envied.ParseFloat(\"%s\"),\n", field.EnvName, field.Value)
-				case FieldTypeString:
-					// String should be obfuscated, but if not, use as-is
-					fmt.Fprintf(file, "\t\t%s: \"%s\",\n", field.EnvName, field.Value)
-				default:
-					fmt.Fprintf(file, "\t\t%s: \"%s\",\n", field.EnvName, field.Value)
-				}
-			}
+// fieldInitializer returns the expression initializing a field in a generated constructor,
+// obfuscated is nil for fields embedded in plain text
+func fieldInitializer(envName string, field Field, obfuscated *ObfuscationResult) string {
+	if obfuscated != nil {
+		// Only strings and string slices are obfuscated
+		envPrefixLower := strings.ToLower(envName)
+		keyConstName := fmt.Sprintf("%s%s", envPrefixLower, obfuscated.KeyName)
+		valueConstName := fmt.Sprintf("%s%s", envPrefixLower, obfuscated.ValueName)
+		var value string
+		switch obfuscated.Key.(type) {
+		case [][]byte:
+			value = fmt.Sprintf("envied.MustOpenStrings(%q, %s, %s)", field.EnvName, keyConstName, valueConstName)
+		case [][]int:
+			value = fmt.Sprintf("envied.MustDecodeStrings(%s, %s)", keyConstName, valueConstName)
+		case []byte:
+			value = fmt.Sprintf("envied.MustOpenString(%q, %s, %s)", field.EnvName, keyConstName, valueConstName)
+		default:
+			value = fmt.Sprintf("envied.MustDecodeString(%s, %s)", keyConstName, valueConstName)
 		}
-		fmt.Fprintf(file, "\t}\n")
-		fmt.Fprintf(file, "}\n\n")
-
-		// Write getter methods
-		fmt.Fprintf(file, "// Getter methods for %sConfig\n", envData.StructName)
-		for _, field := range envData.Fields {
-			fmt.Fprintf(file, "func (c *%sConfig) Get%s() %s {\n", envData.StructName, field.EnvName, field.Type)
-			fmt.Fprintf(file, "\treturn c.%s\n", field.EnvName)
-			fmt.Fprintf(file, "}\n\n")
+		switch {
+		case isMapType(field.Type):
+			value = fmt.Sprintf("envied.ParseJSON[%s](%s)", field.Type, value)
+		case field.Type == FieldTypeBytes:
+			value = fmt.Sprintf("envied.ParseBytes(%q, %s)", field.Encoding, value)
 		}
+		return value
 	}
 
-	return nil
+	// For non-obfuscated fields (int, bool, float64, string), use simple parsing functions
+	switch field.Type {
+	case FieldTypeInt:
+		return fmt.Sprintf("envied.ParseInt(%q)", field.Value)
+	case FieldTypeInt64:
+		return fmt.Sprintf("envied.ParseInt64(%q)", field.Value)
+	case FieldTypeUint64:
+		return fmt.Sprintf("envied.ParseUint64(%q)", field.Value)
+	case FieldTypeBool:
+		return fmt.Sprintf("envied.ParseBool(%q)", field.Value)
+	case FieldTypeFloat:
+		return fmt.Sprintf("envied.ParseFloat(%q)", field.Value)
+	case FieldTypeTime:
+		return fmt.Sprintf("envied.ParseTime(%q, %q)", field.Layout, field.Value)
+	case FieldTypeStringSlice:
+		return fmt.Sprintf("%#v", SplitList(field.Value, field.Separator))
+	case FieldTypeStringMap, FieldTypeBoolMap, FieldTypeIntMap, FieldTypeFloatMap, FieldTypeJSON:
+		return fmt.Sprintf("envied.ParseJSON[%s](%q)", field.Type, field.Value)
+	case FieldTypeBytes:
+		return fmt.Sprintf("envied.ParseBytes(%q, %q)", field.Encoding, field.Value)
+	case FieldTypeString:
+		// Strings are emitted as plain constants when obfuscation is disabled
+		return fmt.Sprintf("%q", field.Value)
+	default:
+		return fmt.Sprintf("%q", field.Value)
+	}
 }
 
 // Template for generated configuration file
@@ -786,7 +1373,12 @@ const configTemplate = `// Code generated by go-envied. DO NOT EDIT.
 
 package {{.PackageName}}
 
-import "github.com/petrovyuri/go-envied"
+import (
+	{{runtimeImport .RuntimeImport}}
+{{- if usesTime .Fields}}
+	"time"
+{{- end}}
+)
 
 // {{.Environment}}Config - generated configuration for {{.Environment}} environment
 type {{.Environment}}Config struct {
@@ -796,11 +1388,17 @@ type {{.Environment}}Config struct {
 // New{{.Environment}}Config creates a new configuration for {{.Environment}} environment
 func New{{.Environment}}Config() *{{.Environment}}Config {
 	return &{{.Environment}}Config{
-{{range .Fields}}{{if eq .Type "string"}}		{{.EnvName}}: envied.Deobfuscate("{{.Value}}"),
-{{else if eq .Type "int"}}		{{.EnvName}}: envied.ParseInt("{{.Value}}"),
-{{else if eq .Type "bool"}}		{{.EnvName}}: envied.ParseBool("{{.Value}}"),
-{{else if eq .Type "float64"}}		{{.EnvName}}: envied.ParseFloat("{{.Value}}"),
-{{else}}		{{.EnvName}}: "{{.Value}}",
+{{range .Fields}}{{if eq .Type "string"}}		{{.EnvName}}: envied.Deobfuscate({{quote .Value}}),
+{{else if eq .Type "int"}}		{{.EnvName}}: envied.ParseInt({{quote .Value}}),
+{{else if eq .Type "int64"}}		{{.EnvName}}: envied.ParseInt64({{quote .Value}}),
+{{else if eq .Type "uint64"}}		{{.EnvName}}: envied.ParseUint64({{quote .Value}}),
+{{else if eq .Type "bool"}}		{{.EnvName}}: envied.ParseBool({{quote .Value}}),
+{{else if eq .Type "float64"}}		{{.EnvName}}: envied.ParseFloat({{quote .Value}}),
+{{else if eq .Type "time.Time"}}		{{.EnvName}}: envied.ParseTime({{quote .Layout}}, {{quote .Value}}),
+{{else if eq .Type "[]string"}}		{{.EnvName}}: envied.SplitList({{quote .Value}}, {{quote .Separator}}),
+{{else if eq .Type "[]byte"}}		{{.EnvName}}: envied.ParseBytes({{quote .Encoding}}, {{quote .Value}}),
+{{else if isMapType .Type}}		{{.EnvName}}: envied.ParseJSON[{{.Type}}]({{quote .Value}}),
+{{else}}		{{.EnvName}}: {{quote .Value}},
 {{end}}{{end}}	}
 }
 
@@ -0,0 +1,140 @@
+package envied
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestServerOptions configures NewManifestHandler
+type ManifestServerOptions struct {
+	Manifests map[string]string // Paths of the manifests written by emit.manifest, keyed by service name
+	Token     string            // Bearer token every request must present
+	Key       []byte            // AES-256 key of manifests encrypted with emit.encrypt_key_env, nil if they are plain
+}
+
+// ManifestSummary describes a served manifest in the service listing
+type ManifestSummary struct {
+	Service     string    `json:"service"`
+	PackageName string    `json:"package_name"`
+	GeneratedAt time.Time `json:"generated_at"`
+	Variables   int       `json:"variables"`
+}
+
+// NewManifestHandler returns a read-only HTTP handler serving the manifests of services for fleet
+// audits: GET /manifests lists the services and GET /manifests/{service} returns the manifest of
+// its latest build. Files are read on every request, so regenerated manifests are served without
+// a restart. Only the fields of Manifest are served, which never include values; files that
+// aren't manifests are refused rather than passed through.
+func NewManifestHandler(opts ManifestServerOptions) (http.Handler, error) {
+	if opts.Token == "" {
+		return nil, newError(MessageManifestServerToken)
+	}
+	if len(opts.Manifests) == 0 {
+		return nil, newError(MessageNoManifests)
+	}
+	server := &manifestServer{opts: opts, tokenHash: sha256.Sum256([]byte(opts.Token))}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /manifests", server.list)
+	mux.HandleFunc("GET /manifests/{service}", server.manifest)
+	return server.authorize(mux), nil
+}
+
+// manifestServer serves the manifests of ManifestServerOptions
+type manifestServer struct {
+	opts      ManifestServerOptions
+	tokenHash [sha256.Size]byte
+}
+
+// authorize rejects requests without the bearer token and requests that aren't reads
+func (s *manifestServer) authorize(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		// Hashes have the same length, so the comparison takes the same time for every token
+		tokenHash := sha256.Sum256([]byte(token))
+		if subtle.ConstantTimeCompare(tokenHash[:], s.tokenHash[:]) != 1 {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="envied"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			http.Error(w, "read-only server", http.StatusMethodNotAllowed)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// list serves the summaries of all manifests sorted by service
+func (s *manifestServer) list(w http.ResponseWriter, r *http.Request) {
+	services := make([]string, 0, len(s.opts.Manifests))
+	for service := range s.opts.Manifests {
+		services = append(services, service)
+	}
+	sort.Strings(services)
+
+	summaries := make([]ManifestSummary, 0, len(services))
+	for _, service := range services {
+		manifest, err := s.read(service)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		summaries = append(summaries, ManifestSummary{
+			Service:     service,
+			PackageName: manifest.PackageName,
+			GeneratedAt: manifest.GeneratedAt,
+			Variables:   len(manifest.Variables),
+		})
+	}
+	writeJSONResponse(w, summaries)
+}
+
+// manifest serves the manifest of one service
+func (s *manifestServer) manifest(w http.ResponseWriter, r *http.Request) {
+	service := r.PathValue("service")
+	if _, exists := s.opts.Manifests[service]; !exists {
+		http.Error(w, fmt.Sprintf("unknown service %q", service), http.StatusNotFound)
+		return
+	}
+	manifest, err := s.read(service)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSONResponse(w, manifest)
+}
+
+// read reads and decodes the manifest of a service, decrypting it if it is encrypted.
+// ParseManifest refuses other files, so a misconfigured path never serves them.
+func (s *manifestServer) read(service string) (*Manifest, error) {
+	data, err := os.ReadFile(s.opts.Manifests[service])
+	if err != nil {
+		return nil, newError(MessageManifestUnreadable, service)
+	}
+	manifest, err := ParseManifest(data, s.opts.Key)
+	if err != nil {
+		return nil, newError(MessageFileError, service, err)
+	}
+	return manifest, nil
+}
+
+// writeJSONResponse writes a value as an indented JSON response
+func writeJSONResponse(w http.ResponseWriter, value any) {
+	data, err := json.MarshalIndent(value, "", "  ")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.Write(append(data, '\n'))
+}
@@ -0,0 +1,424 @@
+package envied
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// MergedData is the data of the template of the merged configuration file
+type MergedData struct {
+	Annotations        string                  // Directives written above the package clause
+	PackageName        string                  // Go package name
+	RuntimeImport      string                  // Import spec of the runtime package
+	Fields             []Field                 // Variables of ConfigInterface, defined in every environment
+	Renamed            []Field                 // Variables of all environments by field name if fields aren't named like them
+	ObfuscationVersion int                     // Version of the obfuscation algorithm, 0 if nothing is obfuscated
+	Environments       []MergedEnvironmentData // Environments sorted by name
+	Overridable        []Field                 // Variables with a With<Field> override, sorted by name
+	Layered            []MergedFieldData       // Overridable variables converted by NewLayeredConfig
+	Split              bool                    // Environments are rendered into their own files by RenderEnvironment
+	GeneratedAt        int64                   // Unix time of the generation, declared once if Split
+	Linked             bool                    // Values are injected with -ldflags -X and checked by CheckLinkedValues
+}
+
+// MergedEnvironmentData is an environment of MergedData
+type MergedEnvironmentData struct {
+	Name       string               // Environment name
+	StructName string               // Struct name without the Config suffix
+	Extras     []Field              // Variables of the <StructName>Interface extension interface
+	Data       []MergedVariableData // Variables holding obfuscated data
+	Types      []MergedTypeData     // The configuration of the environment followed by its profiles
+	BuildTag   string               // Build tag compiling the file of the environment if split
+	Linked     []MergedLinkedData   // Variables receiving values injected with -ldflags -X
+}
+
+// MergedVariableData is a package variable holding obfuscated data
+type MergedVariableData struct {
+	Name    string
+	Comment string
+	Value   string // Go expression of the value
+}
+
+// MergedLinkedData is a package variable receiving a value injected with -ldflags -X
+type MergedLinkedData struct {
+	Name    string // Package variable name
+	EnvName string // Variable the value is injected for
+}
+
+// MergedTypeData is a generated configuration type with its constructor and methods
+type MergedTypeData struct {
+	Name           string // Type name, such as DevConfig
+	Doc            string // Description following the type name in its doc comment
+	ConstructorDoc string // What the constructor creates, in its doc comment
+	Receiver       string // "*" for pointer receivers
+	Environment    string
+	GeneratedAt    int64 // Unix time of the generation
+	Split          bool  // GeneratedAt returns the time declared in the shared file
+	SourceHash     string
+	Fields         []MergedFieldData
+}
+
+// MergedFieldData is a field of a generated type with the Go expressions using it
+type MergedFieldData struct {
+	Field
+	Initializer string // Value of the field in the constructor
+	Overridable bool   // Whether a With<Field> override applies to the field
+	Lookup      string // Value of the field formatted as in a .env file, for Lookup
+	Coerce      string // Conversion of a layered value to the field type, for NewLayeredConfig
+}
+
+// mergedTemplate is the template of the merged configuration file, formatted with gofmt after execution
+var mergedTemplate = template.Must(template.New("merged").Funcs(template.FuncMap{
+	"quote": strconv.Quote,
+	"comment": func(prefix, text string) string {
+		var buf bytes.Buffer
+		writeDescription(&buf, prefix, text)
+		return buf.String()
+	},
+	"getterDoc": func(indent string, field Field) string {
+		var buf bytes.Buffer
+		writeGetterDoc(&buf, indent, field)
+		return buf.String()
+	},
+}).Parse(`{{define "type"}}// {{.Name}} - {{.Doc}}
+type {{.Name}} struct {
+{{range .Fields}}{{comment "\t// " .Description}}	{{.FieldName}} {{.Type}}
+{{end}}}
+
+// New{{.Name}} creates {{.ConstructorDoc}},
+// overrides replace the embedded values
+func New{{.Name}}(opts ...Override) *{{.Name}} {
+	c := &{{.Name}}{
+{{range .Fields}}		{{.FieldName}}: {{.Initializer}},
+{{end}}	}
+	var o enviedOverrides
+	for _, opt := range opts {
+		opt(&o)
+	}
+{{range .Fields}}{{if .Overridable}}	if o.{{.FieldName}} != nil {
+		c.{{.FieldName}} = *o.{{.FieldName}}
+	}
+{{end}}{{end}}	return c
+}
+
+// Getter methods for {{.Name}}
+{{range .Fields}}{{getterDoc "" .Field}}func (c {{$.Receiver}}{{$.Name}}) {{.Getter}}() {{.Type}} {
+	return c.{{.FieldName}}
+}
+
+{{end}}// Lookup returns the value of a variable formatted as in a .env file,
+// so the configuration can be used as the embedded layer of envied.Layer
+func (c {{.Receiver}}{{.Name}}) Lookup(name string) (string, bool) {
+	switch name {
+{{range .Fields}}	case {{quote .EnvName}}:
+		return {{.Lookup}}, true
+{{end}}	}
+	return "", false
+}
+
+// Environment returns the environment name the configuration was generated for
+func (c {{.Receiver}}{{.Name}}) Environment() string {
+	return {{quote .Environment}}
+}
+
+// GeneratedAt returns the time the configuration was generated
+func (c {{.Receiver}}{{.Name}}) GeneratedAt() time.Time {
+{{if .Split}}	return enviedGeneratedAt()
+{{else}}	return time.Unix({{.GeneratedAt}}, 0).UTC()
+{{end}}}
+
+// SourceHash returns the SHA-256 of the env file the configuration was generated from
+func (c {{.Receiver}}{{.Name}}) SourceHash() string {
+	return {{quote .SourceHash}}
+}
+
+{{end}}{{define "extras"}}{{if .Extras}}// {{.StructName}}Interface extends ConfigInterface with variables specific to {{.Name}} environment
+type {{.StructName}}Interface interface {
+	ConfigInterface
+{{range .Extras}}{{getterDoc "\t" .}}	{{.Getter}}() {{.Type}}
+{{end}}}
+
+{{end}}{{end}}{{define "linked values"}}// enviedLinked maps the variables of the environment to the values injected with -ldflags -X
+var enviedLinked = map[string]string{
+{{range .Linked}}	{{quote .EnvName}}: {{.Name}},
+{{end}}}
+
+{{end}}{{define "environment"}}{{if .Linked}}// Values of the {{.Name}} environment injected at link time with
+// -ldflags "-X '<import path>.<variable>=<value>'", checked by CheckLinkedValues
+var (
+{{range .Linked}}	{{.Name}} string // {{.EnvName}}
+{{end}})
+
+{{end}}{{range .Data}}// {{.Comment}}
+var {{.Name}} = {{.Value}}
+
+{{end}}{{range .Types}}{{template "type" .}}{{end}}{{end}}{{define "environment file"}}// enviedEnvironment is the environment compiled with the {{.BuildTag}} build tag
+const enviedEnvironment = {{quote .Name}}
+
+// NewConfig creates the configuration of the {{.Name}} environment selected by the {{.BuildTag}} build tag
+func NewConfig(opts ...Override) ConfigInterface {
+	return New{{.StructName}}Config(opts...)
+}
+
+{{template "extras" .}}{{template "environment" .}}{{end}}` + generatedHeader + `
+// Generated merged configuration file for all environments
+
+{{.Annotations}}package {{.PackageName}}
+
+import (
+	"time"
+
+	{{.RuntimeImport}}
+)
+
+// ConfigInterface defines the interface for all generated configurations
+type ConfigInterface interface {
+{{range .Fields}}{{getterDoc "\t" .}}	{{.Getter}}() {{.Type}}
+{{end}}	// Lookup returns the value of a variable formatted as in a .env file
+	Lookup(name string) (string, bool)
+	// Environment returns the environment name the configuration was generated for
+	Environment() string
+	// GeneratedAt returns the time the configuration was generated
+	GeneratedAt() time.Time
+	// SourceHash returns the SHA-256 of the env file the configuration was generated from
+	SourceHash() string
+}
+
+{{if .ObfuscationVersion}}// enviedObfuscationVersion is the obfuscation algorithm version of the embedded values
+const enviedObfuscationVersion = {{.ObfuscationVersion}}
+
+var _ = envied.CheckObfuscationVersion(enviedObfuscationVersion)
+
+{{end}}{{if not .Split}}{{range .Environments}}{{template "extras" .}}{{end}}{{end}}// ErrUnknownEnvironment is returned by NewEnvironmentConfig for unknown environment names
+type ErrUnknownEnvironment = envied.ErrUnknownEnvironment
+
+// Environments lists the names of all generated environments
+var Environments = []string{ {{- range $i, $env := .Environments}}{{if $i}}, {{end}}{{quote $env.Name}}{{end -}} }
+
+{{if .Renamed}}// FieldEnvNames maps the struct fields of the configurations to the variables they are generated from
+var FieldEnvNames = map[string]string{
+{{range .Renamed}}	{{quote .FieldName}}: {{quote .EnvName}},
+{{end}}}
+
+{{end}}{{if .Split}}// NewEnvironmentConfig creates the configuration for the named environment with overrides applied.
+// Only the environment selected by the build tag is compiled in, for other names it returns
+// *ErrUnknownEnvironment.
+func NewEnvironmentConfig(env string, opts ...Override) (ConfigInterface, error) {
+	if env == enviedEnvironment {
+		return NewConfig(opts...), nil
+	}
+	return nil, &ErrUnknownEnvironment{Name: env, Valid: []string{enviedEnvironment}}
+}
+{{else}}// NewEnvironmentConfig creates the configuration for the named environment with overrides applied.
+// It returns *ErrUnknownEnvironment if the name does not match any environment.
+func NewEnvironmentConfig(env string, opts ...Override) (ConfigInterface, error) {
+	switch env {
+{{range .Environments}}	case {{quote .Name}}:
+		return New{{.StructName}}Config(opts...), nil
+{{end}}	}
+	return nil, &ErrUnknownEnvironment{Name: env, Valid: Environments}
+}
+{{end}}
+
+// Override replaces an embedded value when a configuration is constructed,
+// overrides of variables missing in the environment are ignored
+type Override func(*enviedOverrides)
+
+// enviedOverrides holds the values set by overrides
+type enviedOverrides struct {
+{{range .Overridable}}	{{.FieldName}} *{{.Type}}
+{{end}}}
+
+{{range .Overridable}}// With{{.FieldName}} overrides the embedded {{.EnvName}} value
+func With{{.FieldName}}(value {{.Type}}) Override {
+	return func(o *enviedOverrides) {
+		o.{{.FieldName}} = &value
+	}
+}
+
+{{end}}// NewLayeredConfig creates the configuration for the named environment with values of the
+// sources layered over the embedded ones, later sources take precedence.
+// Values are converted to the variable types, conversion failures are returned as errors.
+func NewLayeredConfig(env string, sources ...envied.Source) (ConfigInterface, error) {
+{{if .Overridable}}	layered := envied.Layer(sources...)
+	var opts []Override
+{{range .Layered}}	if value, exists := layered.Lookup({{quote .EnvName}}); exists {
+		parsed, err := {{.Coerce}}
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, With{{.FieldName}}(parsed))
+	}
+{{end}}	return NewEnvironmentConfig(env, opts...)
+{{else}}	return NewEnvironmentConfig(env)
+{{end}}}
+
+{{if .Linked}}// CheckLinkedValues returns *envied.ErrMissingLinkedValues if the build did not inject values
+// of the environment with -ldflags -X, call it at startup before creating the configuration
+func CheckLinkedValues(env string) error {
+{{if .Split}}	if env != enviedEnvironment {
+		return &ErrUnknownEnvironment{Name: env, Valid: []string{enviedEnvironment}}
+	}
+	return envied.CheckLinked(env, enviedLinked)
+{{else}}	switch env {
+{{range .Environments}}	case {{quote .Name}}:
+{{if .Linked}}		return envied.CheckLinked(env, map[string]string{
+{{range .Linked}}			{{quote .EnvName}}: {{.Name}},
+{{end}}		})
+{{else}}		return nil
+{{end}}{{end}}	}
+	return &ErrUnknownEnvironment{Name: env, Valid: Environments}
+{{end}}}
+
+{{end}}{{if .Split}}// enviedGeneratedAt returns the time the configurations were generated
+func enviedGeneratedAt() time.Time {
+	return time.Unix({{.GeneratedAt}}, 0).UTC()
+}
+{{else}}{{range .Environments}}{{template "environment" .}}{{end}}{{end}}`))
+
+// Render executes the template of the merged configuration file and formats the result like gofmt
+func (d *MergedData) Render() ([]byte, error) {
+	var code bytes.Buffer
+	if err := mergedTemplate.Execute(&code, d); err != nil {
+		return nil, newError(MessageFailedExecuteTemplate, err)
+	}
+	return formatGenerated(code.Bytes())
+}
+
+// RenderEnvironment renders the file of an environment of a split configuration, compiled with its
+// build tag only, and formats the result like gofmt
+func (d *MergedData) RenderEnvironment(env MergedEnvironmentData) ([]byte, error) {
+	var body bytes.Buffer
+	if err := mergedTemplate.ExecuteTemplate(&body, "environment file", env); err != nil {
+		return nil, newError(MessageFailedExecuteEnvironmentTemplate, err)
+	}
+	if d.Linked {
+		if err := mergedTemplate.ExecuteTemplate(&body, "linked values", env); err != nil {
+			return nil, newError(MessageFailedExecuteEnvironmentTemplate, err)
+		}
+	}
+
+	var code bytes.Buffer
+	fmt.Fprintf(&code, "%s\n\n//go:build %s\n\n%spackage %s\n\n", generatedHeader, env.BuildTag, d.Annotations, d.PackageName)
+	fmt.Fprintf(&code, "import (\n\t\"time\"\n\n\t%s\n)\n\n", d.RuntimeImport)
+	code.Write(body.Bytes())
+	return formatGenerated(code.Bytes())
+}
+
+// newMergedData prepares the template data of a merged configuration
+func newMergedData(data *mergedConfig) *MergedData {
+	var annotations bytes.Buffer
+	writeAnnotations(&annotations, data.Annotations)
+
+	overridable := overridableFields(data)
+	canOverride := make(map[string]bool, len(overridable))
+	merged := &MergedData{
+		Annotations:   annotations.String(),
+		PackageName:   data.PackageName,
+		RuntimeImport: runtimeImportSpec(data.RuntimeImport),
+		Fields:        data.AllFields,
+		Renamed:       renamedFields(data),
+		Overridable:   overridable,
+		Split:         data.Split,
+		GeneratedAt:   data.GeneratedAt.Unix(),
+		Linked:        data.Ldflags,
+	}
+	for _, field := range overridable {
+		canOverride[field.EnvName] = true
+		if coerce, exists := coerceCall(field); exists {
+			merged.Layered = append(merged.Layered, MergedFieldData{Field: field, Coerce: coerce})
+		}
+	}
+
+	for _, envName := range sortedEnvironmentNames(data.Environments) {
+		envData := data.Environments[envName]
+		if len(envData.Obfuscated) > 0 {
+			merged.ObfuscationVersion = ObfuscationVersion
+		}
+
+		// Variables are prefixed with the environment name, so environments don't collide
+		envPrefix := strings.ToLower(envName)
+		env := MergedEnvironmentData{Name: envName, StructName: envData.StructName, Extras: envData.Extras}
+		if data.Split {
+			env.BuildTag = buildTag(data.TagPrefix, envName)
+		}
+		for _, field := range envData.Fields {
+			if variable, exists := envData.Linked[field.EnvName]; exists {
+				env.Linked = append(env.Linked, MergedLinkedData{Name: variable, EnvName: field.EnvName})
+			}
+			obfuscated := envData.Obfuscated[field.EnvName]
+			if obfuscated == nil {
+				continue
+			}
+			env.Data = append(env.Data, MergedVariableData{
+				Name:    envPrefix + obfuscated.KeyName,
+				Comment: fmt.Sprintf("Static key for %s in %s environment", field.EnvName, envName),
+				Value:   goLiteral(obfuscated.Key),
+			})
+			if obfuscated.ValueName != field.EnvName {
+				env.Data = append(env.Data, MergedVariableData{
+					Name:    envPrefix + obfuscated.ValueName,
+					Comment: fmt.Sprintf("Static encrypted data for %s in %s environment", field.EnvName, envName),
+					Value:   goLiteral(obfuscated.Value),
+				})
+			}
+		}
+
+		mergedType := func(name, doc, constructorDoc string, fields []Field) MergedTypeData {
+			typeData := MergedTypeData{
+				Name:           name,
+				Doc:            doc,
+				ConstructorDoc: constructorDoc,
+				Receiver:       data.Receiver,
+				Environment:    envName,
+				GeneratedAt:    data.GeneratedAt.Unix(),
+				Split:          data.Split,
+				SourceHash:     envData.SourceHash,
+			}
+			for _, field := range fields {
+				typeData.Fields = append(typeData.Fields, MergedFieldData{
+					Field:       field,
+					Initializer: envData.initializer(envName, field),
+					Overridable: canOverride[field.EnvName],
+					Lookup:      lookupExpression(field),
+				})
+			}
+			return typeData
+		}
+		env.Types = append(env.Types, mergedType(envData.StructName+"Config",
+			fmt.Sprintf("generated configuration for %s environment", envName),
+			fmt.Sprintf("a new configuration for %s environment", envName),
+			envData.Fields))
+		for _, profile := range envData.Profiles {
+			env.Types = append(env.Types, mergedType(profile.StructName+"Config",
+				fmt.Sprintf("generated subset of the %s environment configuration", envName),
+				fmt.Sprintf("a new %s profile configuration for %s environment", profile.StructName, envName),
+				profile.Fields))
+		}
+		merged.Environments = append(merged.Environments, env)
+	}
+	return merged
+}
+
+// goLiteral returns the Go expression of obfuscated data
+func goLiteral(value any) string {
+	switch value := value.(type) {
+	case [][]byte:
+		return fmt.Sprintf("[][]byte{%s}", joinNestedBytes(value))
+	case [][]int:
+		return fmt.Sprintf("[][]int{%s}", joinNestedInts(value))
+	case []byte:
+		return fmt.Sprintf("[]byte{%s}", joinBytes(value))
+	case []int:
+		values := make([]string, len(value))
+		for i, v := range value {
+			values[i] = strconv.Itoa(v)
+		}
+		return fmt.Sprintf("[]int{%s}", strings.Join(values, ", "))
+	default:
+		return fmt.Sprintf("%#v", value)
+	}
+}
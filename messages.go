@@ -0,0 +1,129 @@
+package envied
+
+import (
+	"fmt"
+	"maps"
+	"os"
+)
+
+// MessageID identifies a message in a Catalog
+type MessageID string
+
+// Progress messages of generation, with the arguments of their format
+const (
+	MessageStructureOnly     MessageID = "structure_only"     // environment, values source
+	MessageConsistencyPassed MessageID = "consistency_passed" // no arguments
+	MessageGenerating        MessageID = "generating"         // no arguments
+	MessageProvenance        MessageID = "provenance"         // environment, variable, provenance
+	MessageWarning           MessageID = "warning"            // warning message
+	MessageGenerated         MessageID = "generated"          // no arguments
+	MessageBuildTags         MessageID = "build_tags"         // build tag prefix
+	MessageInternalPackage   MessageID = "internal_package"   // internal import path, re-export shim
+	MessageLdflags           MessageID = "ldflags"            // environment, -ldflags value
+	MessageWritten           MessageID = "written"            // emitted file
+	MessageWrittenEncrypted  MessageID = "written_encrypted"  // emitted file
+	MessageTimings           MessageID = "timings"            // no arguments
+	MessageTiming            MessageID = "timing"             // phase, duration
+	MessageAllGenerated      MessageID = "all_generated"      // no arguments
+	MessageOutputLocation    MessageID = "output_location"    // output directory
+	MessageUsageHint         MessageID = "usage_hint"         // no arguments
+	MessageAutoGenerate      MessageID = "auto_generate"      // configuration file
+	MessageInitFailed        MessageID = "init_failed"        // error
+	MessageInitHint          MessageID = "init_hint"          // configuration file name
+)
+
+// Catalog maps message IDs to fmt formats of their arguments, e.g. to translate the output of
+// generation. It holds progress messages, warnings and errors. IDs missing from a catalog are
+// formatted with DefaultCatalog.
+type Catalog map[MessageID]string
+
+// DefaultCatalog holds the English messages
+var DefaultCatalog = joinCatalogs(progressMessages, warningMessages, doctorMessages, errorMessages)
+
+// progressMessages holds the English progress messages, part of DefaultCatalog
+var progressMessages = Catalog{
+	MessageStructureOnly:     "🔒 Environment '%s' takes its values from %s, generated only with -values-from vault",
+	MessageConsistencyPassed: "✅ Environment consistency check passed - all environments have the same variables",
+	MessageGenerating:        "🔄 Generating merged configuration file...",
+	MessageProvenance:        "🧭 %s/%s: %s",
+	MessageWarning:           "⚠️ Warning: %s",
+	MessageGenerated:         "✅ Merged configuration file generated successfully!",
+	MessageBuildTags:         "🏷️ NewConfig is selected with -tags %s<environment>",
+	MessageInternalPackage:   "🔒 Generated code is in internal package %s, re-exported by %s",
+	MessageLdflags:           "🔗 Values of %s are injected at link time: go build -ldflags \"%s\"",
+	MessageWritten:           "📝 Written %s",
+	MessageWrittenEncrypted:  "🔒 Written %s (encrypted)",
+	MessageTimings:           "⏱️ Timings:",
+	MessageTiming:            "   %-20s %v",
+	MessageAllGenerated:      "\n🎉 All configurations generated!",
+	MessageOutputLocation:    "📁 Files are located in %s",
+	MessageUsageHint:         "🔧 You can now use the generated configurations directly",
+	MessageAutoGenerate:      "🔧 Automatic configuration generation from file: %s",
+	MessageInitFailed:        "⚠️ Warning: failed to generate configurations: %v",
+	MessageInitHint:          "💡 Make sure %s file exists in the project root",
+}
+
+// joinCatalogs returns the messages of all catalogs
+func joinCatalogs(catalogs ...Catalog) Catalog {
+	joined := make(Catalog)
+	for _, catalog := range catalogs {
+		maps.Copy(joined, catalog)
+	}
+	return joined
+}
+
+// Format formats a message with the format of its ID. Errors among the arguments are formatted
+// with the catalog as well.
+func (c Catalog) Format(id MessageID, args ...any) string {
+	format, exists := c[id]
+	if !exists {
+		format = DefaultCatalog[id]
+	}
+	formatted := make([]any, len(args))
+	for i, arg := range args {
+		if err, ok := arg.(error); ok {
+			arg = c.Error(err)
+		}
+		formatted[i] = arg
+	}
+	return fmt.Sprintf(format, formatted...)
+}
+
+// Message is a progress message passed to GenerateOptions.Messages
+type Message struct {
+	ID   MessageID
+	Args []any  // Arguments of the format of the ID
+	Text string // Message formatted with GenerateOptions.Catalog
+}
+
+// messageLog writes progress messages, a nil log discards them
+type messageLog struct {
+	catalog Catalog       // Formats messages, DefaultCatalog if nil
+	handler func(Message) // Receives messages instead of standard output if set
+}
+
+// stdoutLog returns a log writing messages of the default catalog to standard output
+func stdoutLog() *messageLog {
+	return &messageLog{}
+}
+
+// format formats a message with the catalog of the log
+func (l *messageLog) format(id MessageID, args ...any) string {
+	if l == nil {
+		return DefaultCatalog.Format(id, args...)
+	}
+	return l.catalog.Format(id, args...)
+}
+
+// say writes a message
+func (l *messageLog) say(id MessageID, args ...any) {
+	if l == nil {
+		return
+	}
+	message := Message{ID: id, Args: args, Text: l.catalog.Format(id, args...)}
+	if l.handler != nil {
+		l.handler(message)
+		return
+	}
+	fmt.Fprintln(os.Stdout, message.Text)
+}
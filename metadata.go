@@ -0,0 +1,40 @@
+package envied
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"os"
+	"strconv"
+	"time"
+)
+
+// hashFile returns the hex encoded SHA-256 of the file contents
+func hashFile(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// generationTime returns the timestamp embedded into generated files.
+// SOURCE_DATE_EPOCH is honored for reproducible builds.
+func generationTime() time.Time {
+	if epoch, ok := sourceDateEpoch(); ok {
+		return epoch
+	}
+
+	return time.Now().UTC().Truncate(time.Second)
+}
+
+// sourceDateEpoch returns the time set by a valid SOURCE_DATE_EPOCH
+func sourceDateEpoch() (time.Time, bool) {
+	if epoch := os.Getenv("SOURCE_DATE_EPOCH"); epoch != "" {
+		if seconds, err := strconv.ParseInt(epoch, 10, 64); err == nil {
+			return time.Unix(seconds, 0).UTC(), true
+		}
+	}
+	return time.Time{}, false
+}
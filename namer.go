@@ -0,0 +1,275 @@
+package envied
+
+import (
+	"go/token"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Naming strategies of the naming setting
+const (
+	NamingPreserve  = "preserve" // Identifiers are the variable names: API_URL, GetAPI_URL (default)
+	NamingCamelCase = "camel"    // Identifiers are camel cased: ApiUrl, GetApiUrl
+	NamingGo        = "go"       // Identifiers are camel cased with Go initialisms: DatabaseURL, GetDatabaseURL
+)
+
+// Sanitization strategies of names that aren't Go identifiers, such as MY-VAR, 1ST_KEY or app.port
+const (
+	SanitizeReplace = "replace" // Invalid characters become underscores: MY_VAR, X1ST_KEY, app_port
+	SanitizeRemove  = "remove"  // Invalid characters are dropped: MYVAR, X1ST_KEY, appport
+)
+
+// Namer derives the identifiers of generated code from variable names, and the names of files
+// generated per environment from environment names, so all identifier derivation is in one place.
+// Besides the built-in namers, users can implement it and set GenerateOptions.Namer. Derived names
+// are checked: names that aren't Go identifiers or that collide fail generation.
+type Namer interface {
+	Field(variable string) string    // Struct field, also used by the With<Field> override options
+	Getter(variable string) string   // Getter method, before getters.prefix is prepended
+	Constant(variable string) string // Suffix of the variables holding obfuscated data
+	File(environment string) string  // Suffix of the file generated per environment with build_tags
+}
+
+// NamingConfig selects a built-in namer
+type NamingConfig struct {
+	Strategy    string `json:"strategy,omitempty"`     // NamingPreserve (default), NamingCamelCase or NamingGo
+	StripPrefix string `json:"strip_prefix,omitempty"` // Prefix removed from variable names before naming, e.g. APP_
+	Sanitize    string `json:"sanitize,omitempty"`     // SanitizeReplace or SanitizeRemove to fix invalid identifiers, invalid names fail if empty
+}
+
+// namer returns the namer of the settings, nil means the defaults
+func (n *NamingConfig) namer() (Namer, error) {
+	if n == nil {
+		return PreserveNamer(), nil
+	}
+
+	var namer Namer
+	switch n.Strategy {
+	case "", NamingPreserve:
+		namer = PreserveNamer()
+	case NamingCamelCase:
+		namer = CamelCaseNamer()
+	case NamingGo:
+		namer = GoNamer()
+	default:
+		return nil, newError(MessageUnknownNamingStrategy, n.Strategy, NamingPreserve, NamingCamelCase, NamingGo)
+	}
+	if n.StripPrefix != "" {
+		namer = StripPrefixNamer(n.StripPrefix, namer)
+	}
+	switch n.Sanitize {
+	case "":
+	case SanitizeReplace, SanitizeRemove:
+		namer = SanitizeNamer(n.Sanitize, namer)
+	default:
+		return nil, newError(MessageUnknownSanitizeStrategy, n.Sanitize, SanitizeReplace, SanitizeRemove)
+	}
+	return namer, nil
+}
+
+// namer returns the namer of generated identifiers: GenerateOptions.Namer or the naming settings
+func (c *ConfigFile) effectiveNamer() (Namer, error) {
+	if c.namer != nil {
+		return c.namer, nil
+	}
+	return c.Naming.namer()
+}
+
+// preserveNamer uses variable and environment names as they are
+type preserveNamer struct{}
+
+func (preserveNamer) Field(variable string) string    { return variable }
+func (preserveNamer) Getter(variable string) string   { return variable }
+func (preserveNamer) Constant(variable string) string { return variable }
+func (preserveNamer) File(environment string) string  { return environment }
+
+// PreserveNamer returns the default namer, which uses variable names as identifiers
+func PreserveNamer() Namer {
+	return preserveNamer{}
+}
+
+// camelCaseNamer converts variable names to upper camel case like the pascal template function
+type camelCaseNamer struct{}
+
+func (camelCaseNamer) Field(variable string) string    { return toPascal(variable) }
+func (camelCaseNamer) Getter(variable string) string   { return toPascal(variable) }
+func (camelCaseNamer) Constant(variable string) string { return toPascal(variable) }
+func (camelCaseNamer) File(environment string) string  { return environment }
+
+// CamelCaseNamer returns a namer converting variable names to camel case: API_URL becomes ApiUrl
+func CamelCaseNamer() Namer {
+	return camelCaseNamer{}
+}
+
+// commonInitialisms are the words golint and staticcheck expect in upper case in identifiers
+var commonInitialisms = map[string]bool{
+	"ACL": true, "API": true, "ASCII": true, "CPU": true, "CSS": true, "DNS": true, "EOF": true,
+	"GUID": true, "HTML": true, "HTTP": true, "HTTPS": true, "ID": true, "IP": true, "JSON": true,
+	"LHS": true, "QPS": true, "RAM": true, "RHS": true, "RPC": true, "SLA": true, "SMTP": true,
+	"SQL": true, "SSH": true, "TCP": true, "TLS": true, "TTL": true, "UDP": true, "UI": true,
+	"UID": true, "UUID": true, "URI": true, "URL": true, "UTF8": true, "VM": true, "XML": true,
+	"XMPP": true, "XSRF": true, "XSS": true,
+}
+
+// toGoName converts "DATABASE_URL" to "DatabaseURL", writing common initialisms in upper case
+func toGoName(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		if upper := strings.ToUpper(word); commonInitialisms[upper] {
+			b.WriteString(upper)
+		} else {
+			b.WriteString(capitalize(word))
+		}
+	}
+	return b.String()
+}
+
+// goNamer converts variable names to the identifiers Go linters expect
+type goNamer struct{}
+
+func (goNamer) Field(variable string) string    { return toGoName(variable) }
+func (goNamer) Getter(variable string) string   { return toGoName(variable) }
+func (goNamer) Constant(variable string) string { return toGoName(variable) }
+func (goNamer) File(environment string) string  { return environment }
+
+// GoNamer returns a namer converting variable names to Go style: DATABASE_URL becomes DatabaseURL
+// and API_KEY becomes APIKey, so generated code passes golint and staticcheck naming checks
+func GoNamer() Namer {
+	return goNamer{}
+}
+
+// stripPrefixNamer removes a prefix from variable names before naming them with another namer
+type stripPrefixNamer struct {
+	prefix string
+	next   Namer
+}
+
+// strip returns the variable name without the prefix, unchanged if nothing would be left
+func (n stripPrefixNamer) strip(variable string) string {
+	if stripped, found := strings.CutPrefix(variable, n.prefix); found && stripped != "" {
+		return stripped
+	}
+	return variable
+}
+
+func (n stripPrefixNamer) Field(variable string) string    { return n.next.Field(n.strip(variable)) }
+func (n stripPrefixNamer) Getter(variable string) string   { return n.next.Getter(n.strip(variable)) }
+func (n stripPrefixNamer) Constant(variable string) string { return n.next.Constant(n.strip(variable)) }
+func (n stripPrefixNamer) File(environment string) string  { return n.next.File(environment) }
+
+// StripPrefixNamer returns a namer removing prefix from variable names, such as APP_ shared by all
+// variables of an application, before naming them with next
+func StripPrefixNamer(prefix string, next Namer) Namer {
+	return stripPrefixNamer{prefix: prefix, next: next}
+}
+
+// sanitizeNamer turns the names of another namer into Go identifiers
+type sanitizeNamer struct {
+	strategy string
+	next     Namer
+}
+
+// sanitize replaces or removes the characters of name that can't be in identifiers and prefixes
+// names not starting with a letter or underscore with X, like protoc-gen-go
+func (n sanitizeNamer) sanitize(name string) string {
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		case n.strategy == SanitizeReplace:
+			b.WriteRune('_')
+		}
+	}
+	sanitized := b.String()
+	if first, _ := utf8.DecodeRuneInString(sanitized); sanitized == "" || unicode.IsDigit(first) {
+		sanitized = "X" + sanitized
+	}
+	return sanitized
+}
+
+func (n sanitizeNamer) Field(variable string) string    { return n.sanitize(n.next.Field(variable)) }
+func (n sanitizeNamer) Getter(variable string) string   { return n.sanitize(n.next.Getter(variable)) }
+func (n sanitizeNamer) Constant(variable string) string { return n.sanitize(n.next.Constant(variable)) }
+func (n sanitizeNamer) File(environment string) string  { return n.next.File(environment) }
+
+// SanitizeNamer returns a namer turning the names of next into Go identifiers with strategy
+// SanitizeReplace or SanitizeRemove, so variables such as MY-VAR or 1ST_KEY can be generated.
+// Sanitized names can collide, which fails generation like other collisions.
+func SanitizeNamer(strategy string, next Namer) Namer {
+	return sanitizeNamer{strategy: strategy, next: next}
+}
+
+// applyFieldNames names the struct fields of fields and fails for names that aren't identifiers
+// or that collide with each other or with generated methods, and for colliding constants
+func applyFieldNames(fields []Field, namer Namer) error {
+	fieldOwners := make(map[string]string, len(fields))
+	constantOwners := make(map[string]string, len(fields))
+	for i := range fields {
+		name := fields[i].EnvName
+		fieldName := namer.Field(name)
+		switch {
+		case !token.IsIdentifier(fieldName):
+			return newError(MessageInvalidFieldName, name, fieldName)
+		case reservedMethodNames[fieldName]:
+			return newError(MessageFieldCollidesWithMethod, name, fieldName)
+		case fieldOwners[fieldName] != "":
+			return newError(MessageDuplicateFieldName, fieldOwners[fieldName], name, fieldName)
+		}
+		fieldOwners[fieldName] = name
+		fields[i].FieldName = fieldName
+
+		constant := namer.Constant(name)
+		switch {
+		case !token.IsIdentifier("_envieddata" + constant):
+			return newError(MessageInvalidConstantSuffix, name, constant)
+		case constantOwners[constant] != "":
+			return newError(MessageDuplicateConstantSuffix, constantOwners[constant], name, constant)
+		}
+		constantOwners[constant] = name
+	}
+	return nil
+}
+
+// checkFileNames fails for per-environment file names that are empty or not plain file names
+func checkFileNames(envNames []string, namer Namer) error {
+	owners := make(map[string]string, len(envNames))
+	for _, envName := range envNames {
+		file := namer.File(envName)
+		switch {
+		case file == "" || file == "." || file == ".." || strings.ContainsAny(file, `/\`):
+			return newError(MessageInvalidFileName, envName, file)
+		case owners[file] != "":
+			return newError(MessageDuplicateFileName, owners[file], envName, file)
+		}
+		owners[file] = envName
+	}
+	return nil
+}
+
+// renamedFields returns the variables of all environments sorted by field name when any field is
+// named differently from its variable, for the FieldEnvNames mapping, nil otherwise
+func renamedFields(data *mergedConfig) []Field {
+	fields := make(map[string]Field)
+	renamed := false
+	for _, envData := range data.Environments {
+		for _, field := range envData.Fields {
+			fields[field.EnvName] = Field{EnvName: field.EnvName, FieldName: field.FieldName}
+			renamed = renamed || field.FieldName != field.EnvName
+		}
+	}
+	if !renamed {
+		return nil
+	}
+
+	sorted := make([]Field, 0, len(fields))
+	for _, field := range fields {
+		sorted = append(sorted, field)
+	}
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].FieldName < sorted[j].FieldName
+	})
+	return sorted
+}
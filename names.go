@@ -0,0 +1,145 @@
+package envied
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// DefaultNamePattern is the POSIX portable environment variable name pattern
+const DefaultNamePattern = `^[A-Z_][A-Z0-9_]*$`
+
+// Name validation modes
+const (
+	NameValidationWarn  = "warn"  // Print a warning for invalid names (default)
+	NameValidationError = "error" // Refuse to generate with invalid names
+	NameValidationOff   = "off"   // Don't validate names
+)
+
+// confusableRunes maps characters that are easily mistaken for each other to a common form
+var confusableRunes = map[rune]rune{
+	'0': 'O',
+	'1': 'I',
+	'l': 'I',
+}
+
+// nameSkeleton returns the form of a name used to detect confusable names:
+// names with the same skeleton differ only by case or easily-confused characters
+func nameSkeleton(name string) string {
+	return strings.Map(func(r rune) rune {
+		if replacement, exists := confusableRunes[r]; exists {
+			return replacement
+		}
+		return unicode.ToUpper(r)
+	}, name)
+}
+
+// nameValidationMode returns the effective name validation mode of the config
+func nameValidationMode(configFile *ConfigFile) (string, error) {
+	switch configFile.NameValidation {
+	case "":
+		return NameValidationWarn, nil
+	case NameValidationWarn, NameValidationError, NameValidationOff:
+		return configFile.NameValidation, nil
+	default:
+		return "", newError(MessageUnknownNameValidationMode, configFile.NameValidation, NameValidationWarn, NameValidationError, NameValidationOff)
+	}
+}
+
+// validateVariableNames checks variable names of all environments against the configured
+// pattern, so names keep working when exported to real process environments
+func validateVariableNames(configFile *ConfigFile, allEnvVars map[string]map[string]EnvValue, warnings *warningLog) error {
+	mode, err := nameValidationMode(configFile)
+	if err != nil || mode == NameValidationOff {
+		return err
+	}
+
+	pattern := configFile.NamePattern
+	if pattern == "" {
+		pattern = DefaultNamePattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return newError(MessageInvalidNamePattern, pattern, err)
+	}
+
+	envNames := make([]string, 0, len(allEnvVars))
+	for envName := range allEnvVars {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	reported := make(map[string]bool)
+	for _, envName := range envNames {
+		varNames := make([]string, 0, len(allEnvVars[envName]))
+		for varName := range allEnvVars[envName] {
+			varNames = append(varNames, varName)
+		}
+		sort.Strings(varNames)
+
+		for _, varName := range varNames {
+			if re.MatchString(varName) || reported[varName] {
+				continue
+			}
+			reported[varName] = true
+
+			if mode == NameValidationError {
+				return newError(MessageNameMismatchesPattern, varName, envName, pattern)
+			}
+			warnings.warn(Warning{
+				Code:        WarningNamePattern,
+				Environment: envName,
+				Variable:    varName,
+			}, MessageWarningNamePattern, varName, envName, pattern)
+		}
+	}
+
+	return checkConfusableNames(mode, allEnvVars, warnings)
+}
+
+// checkConfusableNames flags variables across all environments whose names differ only
+// by case or easily-confused characters, such as API_KEY and Api_Key or O and 0
+func checkConfusableNames(mode string, allEnvVars map[string]map[string]EnvValue, warnings *warningLog) error {
+	// Environments declaring each name
+	nameEnvs := make(map[string][]string)
+	for envName, envVars := range allEnvVars {
+		for varName := range envVars {
+			nameEnvs[varName] = append(nameEnvs[varName], envName)
+		}
+	}
+
+	groups := make(map[string][]string)
+	for varName := range nameEnvs {
+		skeleton := nameSkeleton(varName)
+		groups[skeleton] = append(groups[skeleton], varName)
+	}
+
+	skeletons := make([]string, 0, len(groups))
+	for skeleton, names := range groups {
+		if len(names) > 1 {
+			skeletons = append(skeletons, skeleton)
+		}
+	}
+	sort.Strings(skeletons)
+
+	for _, skeleton := range skeletons {
+		names := groups[skeleton]
+		sort.Strings(names)
+
+		described := make([]string, len(names))
+		for i, varName := range names {
+			envNames := nameEnvs[varName]
+			sort.Strings(envNames)
+			described[i] = fmt.Sprintf("'%s' (%s)", varName, strings.Join(envNames, ", "))
+		}
+
+		if mode == NameValidationError {
+			return newError(MessageConfusableNames, strings.Join(described, ", "))
+		}
+		warnings.warn(Warning{Code: WarningConfusableNames}, MessageWarningConfusableNames, strings.Join(described, ", "))
+	}
+
+	return nil
+}
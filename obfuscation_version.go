@@ -0,0 +1,18 @@
+package envied
+
+import "fmt"
+
+// ObfuscationVersion is the version of the algorithm used by ObfuscateString and
+// DecodeString. It is embedded into generated files and changes whenever
+// the encoding of generated keys and values changes.
+const ObfuscationVersion = 1
+
+// CheckObfuscationVersion panics if a generated file was produced with a different
+// obfuscation algorithm version than this runtime supports. Generated files call it
+// during package initialization, so stale files fail loudly instead of decoding garbage.
+func CheckObfuscationVersion(version int) bool {
+	if version != ObfuscationVersion {
+		panic(fmt.Sprintf("go-envied: generated configuration uses obfuscation algorithm version %d, runtime supports version %d; regenerate the configuration", version, ObfuscationVersion))
+	}
+	return true
+}
@@ -0,0 +1,106 @@
+package envied
+
+import "time"
+
+// GenerateOptions configures Generate, Validate, Check, Verify and DiffEnvironments
+type GenerateOptions struct {
+	ConfigPath string            // Path to the configuration file (searched with FindConfigFile if empty)
+	OutputFile string            // Path of the generated file (config_env.gen.go in output_dir if empty)
+	EnvFiles   map[string]string // Env file paths by environment name, overriding the configuration file
+	NoNetwork  bool              // Run under WithoutNetwork, remote sources are rejected
+	Verbose    bool              // Log which source every value comes from
+	Timings    bool              // Log how long every phase of the run took
+	Namer      Namer             // Names generated identifiers, overriding the naming setting
+	Set        map[string]string // Substitutions of {{.Name}} placeholders, overriding the configuration file
+	OnConflict string            // Strategy for declarations duplicated by other package files, overriding on_conflict
+	Catalog    Catalog           // Formats progress messages, warnings and doctor checks, e.g. translated, DefaultCatalog if nil
+	Messages   func(Message)     // Receives progress messages instead of standard output
+
+	// ValuesFrom reads the values of environments declaring the source, such as ValuesFromVault.
+	// Environments declaring a source are left out of the generated file without it. Reading the
+	// source needs its credentials, such as VAULT_TOKEN, nothing else restricts who can set it.
+	ValuesFrom string
+}
+
+// run runs fn under WithoutNetwork if NoNetwork is set
+func (opts GenerateOptions) run(fn func() error) error {
+	if opts.NoNetwork {
+		return WithoutNetwork(fn)
+	}
+	return fn()
+}
+
+// log returns the log of progress messages
+func (opts GenerateOptions) log() *messageLog {
+	return &messageLog{catalog: opts.Catalog, handler: opts.Messages}
+}
+
+// load reads the configuration file with the options applied and returns it
+// with its path and the path of the generated file
+func (opts GenerateOptions) load() (*ConfigFile, string, string, error) {
+	configPath := opts.ConfigPath
+	if configPath == "" {
+		configPath = FindConfigFile()
+	}
+	if configPath == "" {
+		return nil, "", "", newError(MessageConfigNotFound, DefaultConfigFileName)
+	}
+
+	start := time.Now()
+	configFile, err := LoadConfigFile(configPath)
+	if err != nil {
+		return nil, "", "", err
+	}
+	if opts.Timings {
+		configFile.timer = newPhaseTimer(start)
+		configFile.timer.mark("load configuration")
+	}
+	if err := applyEnvFileOverrides(configFile, opts.EnvFiles); err != nil {
+		return nil, "", "", err
+	}
+	configFile.verbose = opts.Verbose
+	configFile.namer = opts.Namer
+	configFile.substitutionOverrides = opts.Set
+	if opts.OnConflict != "" {
+		configFile.OnConflict = opts.OnConflict
+	}
+	if err := checkValuesFrom(opts.ValuesFrom); err != nil {
+		return nil, "", "", err
+	}
+	configFile.valuesFrom = opts.ValuesFrom
+	if opts.NoNetwork {
+		if err := checkOffline(configFile); err != nil {
+			return nil, "", "", err
+		}
+	}
+
+	outputFile := opts.OutputFile
+	if outputFile == "" {
+		outputFile = configFile.outputFile()
+	}
+	return configFile, configPath, outputFile, nil
+}
+
+// Generate generates the merged configuration like GenerateFromConfigFile with the output
+// file and env files optionally overridden
+func Generate(opts GenerateOptions) error {
+	_, err := GenerateWithWarnings(opts)
+	return err
+}
+
+// GenerateWithWarnings is Generate returning the warnings written to the log
+func GenerateWithWarnings(opts GenerateOptions) ([]Warning, error) {
+	return NewPipeline(opts).Generate()
+}
+
+// Validate runs all checks of generation (environment consistency, names, sources and
+// conflicts with the output package) without writing anything
+func Validate(opts GenerateOptions) error {
+	_, err := ValidateWithWarnings(opts)
+	return err
+}
+
+// ValidateWithWarnings is Validate returning the warnings written to the log
+func ValidateWithWarnings(opts GenerateOptions) ([]Warning, error) {
+	return NewPipeline(opts).Validate()
+}
@@ -0,0 +1,29 @@
+package envied
+
+import "sort"
+
+// overridableFields returns the variables that get a With<Name> override, sorted by name.
+// Variables with different types in different environments can't be overridden.
+func overridableFields(data *mergedConfig) []Field {
+	fields := make(map[string]Field)
+	mismatched := make(map[string]bool)
+	for _, envData := range data.Environments {
+		for _, field := range envData.Fields {
+			if seen, exists := fields[field.EnvName]; exists && seen.Type != field.Type {
+				mismatched[field.EnvName] = true
+			}
+			fields[field.EnvName] = Field{EnvName: field.EnvName, FieldName: field.FieldName, Type: field.Type, Layout: field.Layout, Separator: field.Separator, Encoding: field.Encoding}
+		}
+	}
+
+	var overridable []Field
+	for name, field := range fields {
+		if !mismatched[name] {
+			overridable = append(overridable, field)
+		}
+	}
+	sort.Slice(overridable, func(i, j int) bool {
+		return overridable[i].EnvName < overridable[j].EnvName
+	})
+	return overridable
+}
@@ -0,0 +1,135 @@
+package envied
+
+import "slices"
+
+// Stage is a stage of generation that a Pipeline can inject steps after
+type Stage string
+
+// Stages of generation in the order they run. The built-in checks of names, unsafe characters,
+// conditional and empty environments, policies and consistency between environments run between
+// StageSources and StageModel, so changes of StageSources hooks are checked and StageValidate
+// hooks see values that passed every built-in check. Type and range checks run before StageModel.
+const (
+	StageSources   Stage = "sources"   // Environments are read, substituted and transformed
+	StageModel     Stage = "model"     // Variables are typed and named as fields
+	StageValidate  Stage = "validate"  // Hooks check the fields after the built-in checks, before anything is obfuscated
+	StageObfuscate Stage = "obfuscate" // Values to hide are obfuscated
+	StageEmit      Stage = "emit"      // Generated files are written
+)
+
+// stages lists the stages in the order they run
+var stages = []Stage{StageSources, StageModel, StageValidate, StageObfuscate, StageEmit}
+
+// PipelineState is the generation data passed to hooks. Hooks may change or replace the maps and
+// slices, generation reads them back after every stage: Variables after StageSources are checked
+// and modeled, Environments and Fields after StageModel and StageValidate are generated, and
+// Obfuscated after StageObfuscate is embedded. Changes of Variables after StageSources only affect
+// the sensitive hints, they are not modeled again.
+type PipelineState struct {
+	Config       *ConfigFile                              // Loaded configuration
+	Variables    map[string]map[string]EnvValue           // Variables by environment and name
+	Environments []string                                 // Names of the generated environments, from StageModel
+	Fields       map[string][]Field                       // Fields by environment, from StageModel
+	Obfuscated   map[string]map[string]*ObfuscationResult // Obfuscated values by environment and variable, from StageObfuscate
+	CodeFile     string                                   // Path of the generated code, from StageEmit
+}
+
+// checkEnvironments checks that hooks of a stage only kept environments of the configuration
+func (s *PipelineState) checkEnvironments(stage Stage) error {
+	if len(s.Environments) == 0 {
+		return newError(MessageHooksRemovedEnvironments, stage)
+	}
+	for _, envName := range s.Environments {
+		if _, exists := s.Config.Environments[envName]; !exists {
+			return newError(MessageHookUnknownEnvironment, stage, envName)
+		}
+	}
+	return nil
+}
+
+// Hook is a step injected after a stage, an error stops generation
+type Hook func(state *PipelineState) error
+
+// Pipeline runs generation with steps injected after its stages, so scaffolding tools and
+// service generators can embed go-envied as one stage of their own generation. Generate and
+// Validate are pipelines without hooks.
+type Pipeline struct {
+	opts  GenerateOptions
+	hooks map[Stage][]Hook
+}
+
+// NewPipeline returns a pipeline generating with the options
+func NewPipeline(opts GenerateOptions) *Pipeline {
+	return &Pipeline{opts: opts, hooks: make(map[Stage][]Hook)}
+}
+
+// Hook adds a step run after the stage, steps of a stage run in the order they were added
+func (p *Pipeline) Hook(stage Stage, hook Hook) *Pipeline {
+	p.hooks[stage] = append(p.hooks[stage], hook)
+	return p
+}
+
+// load loads the configuration with the hooks of the pipeline
+func (p *Pipeline) load() (*ConfigFile, string, string, error) {
+	for stage := range p.hooks {
+		if !slices.Contains(stages, stage) {
+			return nil, "", "", newError(MessageUnknownPipelineStage, stage)
+		}
+	}
+	configFile, configPath, outputFile, err := p.opts.load()
+	if err != nil {
+		return nil, "", "", err
+	}
+	configFile.hooks = p.hooks
+	return configFile, configPath, outputFile, nil
+}
+
+// Generate runs all stages and returns the warnings written to the log
+func (p *Pipeline) Generate() ([]Warning, error) {
+	var warnings []Warning
+	err := p.opts.run(func() error {
+		configFile, configPath, outputFile, err := p.load()
+		if err != nil {
+			return err
+		}
+		warnings, err = generateFromConfig(configFile, configPath, outputFile, p.opts.log())
+		return err
+	})
+	return warnings, err
+}
+
+// Validate runs the stages before StageEmit and checks conflicts with the output package
+// without writing anything, returning the warnings written to the log
+func (p *Pipeline) Validate() ([]Warning, error) {
+	var warnings []Warning
+	err := p.opts.run(func() error {
+		configFile, configPath, outputFile, err := p.load()
+		if err != nil {
+			return err
+		}
+
+		log := p.opts.log()
+		mergedData, err := buildMergedConfig(configFile, configPath, log)
+		if err != nil {
+			return err
+		}
+		if err := checkPackageConflicts(outputFile, mergedData, configFile.OnConflict, log); err != nil {
+			return err
+		}
+		warnings = mergedData.Warnings
+		configFile.timer.mark("check conflicts")
+		configFile.timer.report(log)
+		return nil
+	})
+	return warnings, err
+}
+
+// runHooks runs the hooks of a stage
+func (c *ConfigFile) runHooks(stage Stage, state *PipelineState) error {
+	for _, hook := range c.hooks[stage] {
+		if err := hook(state); err != nil {
+			return newError(MessageStageFailed, stage, err)
+		}
+	}
+	return nil
+}
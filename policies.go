@@ -0,0 +1,121 @@
+package envied
+
+import (
+	"path"
+	"sort"
+	"strings"
+)
+
+// PolicyConfig is an organizational rule forbidding variables, or values of variables, in
+// environments, such as DEBUG=true in prod or *_LIVE_* variables in dev. Policies are
+// evaluated by every generation after transforms, so generated files can't break them.
+type PolicyConfig struct {
+	Environments []string `json:"environments,omitempty"` // Environments the rule applies to, as glob patterns such as prod*; all if empty
+	Variable     string   `json:"variable"`               // Forbidden variable names, as a glob pattern such as *_LIVE_*
+	Value        string   `json:"value,omitempty"`        // Forbids only this value, compared case-insensitively; any value if empty
+	Message      string   `json:"message,omitempty"`      // Reason of the rule, shown with violations
+}
+
+// describe returns a human-readable form of the rule
+func (p PolicyConfig) describe() string {
+	rule := p.Variable
+	if p.Value != "" {
+		rule += "=" + p.Value
+	}
+	if len(p.Environments) == 0 {
+		return rule + " in all environments"
+	}
+	return rule + " in " + strings.Join(p.Environments, ", ")
+}
+
+// appliesTo reports whether the rule applies to an environment
+func (p PolicyConfig) appliesTo(envName string) bool {
+	if len(p.Environments) == 0 {
+		return true
+	}
+	for _, pattern := range p.Environments {
+		if matched, _ := path.Match(pattern, envName); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// forbids reports whether the rule forbids a variable with its value
+func (p PolicyConfig) forbids(name, value string) bool {
+	if matched, _ := path.Match(p.Variable, name); !matched {
+		return false
+	}
+	return p.Value == "" || strings.EqualFold(value, p.Value)
+}
+
+// validatePolicies fails for policies with invalid patterns and for environment patterns
+// matching no environment, which would silently disable a rule after a typo or a rename
+func validatePolicies(configFile *ConfigFile) error {
+	envNames := configFile.environmentNames()
+	for i, policy := range configFile.Policies {
+		if policy.Variable == "" {
+			return newError(MessagePolicyWithoutPattern, i+1)
+		}
+		if _, err := path.Match(policy.Variable, ""); err != nil {
+			return newError(MessageInvalidPolicyPattern, i+1, policy.Variable)
+		}
+		for _, pattern := range policy.Environments {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return newError(MessageInvalidPolicyEnvironmentPattern, i+1, pattern)
+			}
+			matched := false
+			for _, envName := range envNames {
+				if ok, _ := path.Match(pattern, envName); ok {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return newError(MessagePolicyMatchesNoEnvironment, pattern, i+1)
+			}
+		}
+	}
+	return nil
+}
+
+// checkPolicies fails for the first variable, by environment and name, breaking a policy.
+// Values aren't shown unless the policy names them.
+func checkPolicies(configFile *ConfigFile, allEnvVars map[string]map[string]EnvValue, provenances map[string]map[string]Provenance) error {
+	if len(configFile.Policies) == 0 {
+		return nil
+	}
+	if err := validatePolicies(configFile); err != nil {
+		return err
+	}
+
+	for _, envName := range configFile.environmentNames() {
+		envVars := allEnvVars[envName]
+		names := make([]string, 0, len(envVars))
+		for name := range envVars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			for i, policy := range configFile.Policies {
+				if !policy.appliesTo(envName) || !policy.forbids(name, envVars[name].Value) {
+					continue
+				}
+				variable := name
+				if policy.Value != "" {
+					variable += "=" + envVars[name].Value
+				}
+				var suffix string
+				if policy.Message != "" {
+					suffix = " - " + policy.Message
+				}
+				if source := provenances[envName][name].Source; source != "" {
+					return newError(MessagePolicyViolationSource, envName, variable, source, i+1, policy.describe(), suffix)
+				}
+				return newError(MessagePolicyViolation, envName, variable, i+1, policy.describe(), suffix)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package envied
+
+import (
+	"fmt"
+	"sort"
+)
+
+// mergedProfile holds generation data for a struct generated from a subset of an environment's variables
+type mergedProfile struct {
+	StructName string
+	Fields     []Field
+}
+
+// buildProfiles selects the fields of the profiles of an environment. Struct names must not be
+// used by another environment or profile, taken maps used struct names to their owner.
+func buildProfiles(envName string, envConfig EnvironmentConfig, fields []Field, taken map[string]string) ([]mergedProfile, error) {
+	structNames := make([]string, 0, len(envConfig.Profiles))
+	for structName := range envConfig.Profiles {
+		structNames = append(structNames, structName)
+	}
+	sort.Strings(structNames)
+
+	profiles := make([]mergedProfile, 0, len(structNames))
+	for _, structName := range structNames {
+		owner := fmt.Sprintf("profile %s of environment '%s'", structName, envName)
+		if other, exists := taken[structName]; exists {
+			return nil, newError(MessageProfileStructNameUsed, owner, structName, other)
+		}
+		taken[structName] = owner
+
+		varNames := envConfig.Profiles[structName]
+		if len(varNames) == 0 {
+			return nil, newError(MessageProfileWithoutVariables, owner)
+		}
+		selected := make(map[string]bool, len(varNames))
+		for _, varName := range varNames {
+			if selected[varName] {
+				return nil, newError(MessageProfileDuplicateVariable, owner, varName)
+			}
+			if _, found := findField(fields, varName); !found {
+				return nil, newError(MessageProfileUnknownVariable, owner, varName)
+			}
+			selected[varName] = true
+		}
+
+		profile := mergedProfile{StructName: structName}
+		for _, field := range fields {
+			if selected[field.EnvName] {
+				profile.Fields = append(profile.Fields, field)
+			}
+		}
+		profiles = append(profiles, profile)
+	}
+	return profiles, nil
+}
@@ -0,0 +1,139 @@
+package envied
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// Provenance records which source the value of a variable in an environment comes from
+type Provenance struct {
+	Source    string   `json:"source"`              // file:line of the definition, or the remote source name
+	Overrides []string `json:"overrides,omitempty"` // Sources of the values replaced by overlays, in layering order
+}
+
+// String formats the provenance as the source followed by the sources it overrides
+func (p Provenance) String() string {
+	if len(p.Overrides) == 0 {
+		return p.Source
+	}
+	return fmt.Sprintf("%s (overrides %s)", p.Source, strings.Join(p.Overrides, ", "))
+}
+
+// envFiles returns the base env file, the env file and the overlays of an environment including
+// the local overlay, the files prune rewrites
+func (e EnvironmentConfig) envFiles() []string {
+	var files []string
+	if e.BaseEnvFile != "" {
+		files = append(files, e.BaseEnvFile)
+	}
+	if e.EnvFile != "" {
+		files = append(files, e.EnvFile)
+	}
+	return append(files, e.layers()...)
+}
+
+// sourceName returns the env file of an environment, or its remote source name
+func (e EnvironmentConfig) sourceName() string {
+	if remote := e.remoteSources(); len(remote) > 0 {
+		return remote[0]
+	}
+	return e.EnvFile
+}
+
+// layerOverlays reads the base env file of an environment under its variables and the overlays,
+// ending with the local overlay, over them, and returns the provenance of every value with the hashes of the base and the
+// overlays. A value of a later overlay replaces the values of the base, the source and all
+// earlier overlays.
+func layerOverlays(envConfig EnvironmentConfig, envVars map[string]EnvValue) (map[string]Provenance, []string, error) {
+	provenance := make(map[string]Provenance, len(envVars))
+	for name, value := range envVars {
+		provenance[name] = Provenance{Source: valueSource(envConfig.sourceName(), value)}
+	}
+
+	overlays := envConfig.layers()
+	hashes := make([]string, 0, len(overlays)+1)
+	if envConfig.BaseEnvFile != "" {
+		hash, err := layerBase(envConfig, envVars, provenance)
+		if err != nil {
+			return nil, nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	for _, overlay := range overlays {
+		overlayVars, err := readEnvFile(EnvironmentConfig{EnvFile: overlay, KeySeparator: envConfig.KeySeparator})
+		if err != nil {
+			return nil, nil, newError(MessageFailedReadOverlay, overlay, err)
+		}
+		hash, err := hashFile(overlay)
+		if err != nil {
+			return nil, nil, newError(MessageFailedHashOverlay, overlay, err)
+		}
+		hashes = append(hashes, hash)
+
+		for name, value := range overlayVars {
+			layered := Provenance{Source: valueSource(overlay, value)}
+			if previous, exists := provenance[name]; exists {
+				layered.Overrides = append(append(layered.Overrides, previous.Overrides...), previous.Source)
+			}
+			provenance[name] = layered
+			envVars[name] = value
+		}
+	}
+	return provenance, hashes, nil
+}
+
+// valueSource returns file:line for values read from env files, the source name otherwise
+func valueSource(source string, value EnvValue) string {
+	if value.Line == 0 {
+		return source
+	}
+	return fmt.Sprintf("%s:%d", source, value.Line)
+}
+
+// layeredSourceHash returns the source hash of an environment with a base or overlays: the SHA-256
+// of the source hash and the layer hashes, or the source hash unchanged without layers
+func layeredSourceHash(sourceHash string, layerHashes []string) string {
+	if len(layerHashes) == 0 {
+		return sourceHash
+	}
+	hash := sha256.New()
+	for _, h := range append([]string{sourceHash}, layerHashes...) {
+		fmt.Fprintf(hash, "%s\n", h)
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// hashEnvFiles returns the source hash of an environment read from an env file, a base env file
+// and overlays
+func hashEnvFiles(envConfig EnvironmentConfig) (string, error) {
+	sourceHash, err := hashFile(envConfig.EnvFile)
+	if err != nil {
+		return "", newError(MessageFailedHashEnvFile, envConfig.EnvFile, err)
+	}
+	overlays := envConfig.layers()
+	layerHashes := make([]string, 0, len(overlays)+1)
+	if envConfig.BaseEnvFile != "" {
+		hash, err := hashFile(envConfig.BaseEnvFile)
+		if err != nil {
+			return "", newError(MessageFailedHashBaseEnvFile, envConfig.BaseEnvFile, err)
+		}
+		layerHashes = append(layerHashes, hash)
+	}
+	for _, overlay := range overlays {
+		hash, err := hashFile(overlay)
+		if err != nil {
+			return "", newError(MessageFailedHashOverlay, overlay, err)
+		}
+		layerHashes = append(layerHashes, hash)
+	}
+	return layeredSourceHash(sourceHash, layerHashes), nil
+}
+
+// logProvenance writes the provenance of every field of an environment
+func logProvenance(log *messageLog, envName string, fields []Field, provenance map[string]Provenance) {
+	for _, field := range fields {
+		log.say(MessageProvenance, envName, field.EnvName, provenance[field.EnvName].String())
+	}
+}
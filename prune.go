@@ -0,0 +1,420 @@
+package envied
+
+import (
+	"bytes"
+	"encoding/json"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// UnusedVariable is a variable embedded in the generated configuration but never read by the code
+type UnusedVariable struct {
+	Name         string   // Variable name
+	Getter       string   // Name of the generated getter
+	Environments []string // Environments embedding the variable
+}
+
+// listedPackage is the part of `go list -json` output used by the analysis
+type listedPackage struct {
+	Dir          string
+	ImportPath   string
+	GoFiles      []string
+	CgoFiles     []string
+	TestGoFiles  []string
+	XTestGoFiles []string
+	ImportMap    map[string]string
+	Export       string
+	Error        *struct {
+		Err string
+	}
+}
+
+// listPackages resolves package patterns such as ./... with the go command
+func listPackages(patterns []string) ([]listedPackage, error) {
+	args := append([]string{"list", "-e", "-json"}, patterns...)
+	cmd := exec.Command("go", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, newError(MessageGoListFailed, strings.Join(patterns, " "), err, stderr.String())
+	}
+
+	var packages []listedPackage
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var pkg listedPackage
+		if err := decoder.Decode(&pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, newError(MessageFailedParseGoListOutput, err)
+		}
+		if pkg.Error != nil {
+			return nil, newError(MessageGoListPackageError, pkg.ImportPath, pkg.Error.Err)
+		}
+		packages = append(packages, pkg)
+	}
+	return packages, nil
+}
+
+// listDependencies returns the packages matching the patterns and all their dependencies,
+// including test dependencies, with the compiled export data of each, keyed by import path
+func listDependencies(patterns []string) (map[string]listedPackage, error) {
+	args := append([]string{"list", "-e", "-export", "-deps", "-test", "-json=Dir,ImportPath,Export"}, patterns...)
+	cmd := exec.Command("go", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, newError(MessageGoListExportFailed, strings.Join(patterns, " "), err, stderr.String())
+	}
+
+	dependencies := make(map[string]listedPackage)
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	for {
+		var pkg listedPackage
+		if err := decoder.Decode(&pkg); err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, newError(MessageFailedParseGoListOutput, err)
+		}
+		// Test variants are listed as "path [path.test]", the plain package is the one imported
+		if !strings.Contains(pkg.ImportPath, " ") {
+			dependencies[pkg.ImportPath] = pkg
+		}
+	}
+	return dependencies, nil
+}
+
+// mappedImporter resolves the import paths of one package through its import map (vendoring)
+type mappedImporter struct {
+	importer  types.Importer
+	importMap map[string]string
+}
+
+func (m mappedImporter) Import(path string) (*types.Package, error) {
+	if mapped, ok := m.importMap[path]; ok {
+		path = mapped
+	}
+	return m.importer.Import(path)
+}
+
+// variableUsage is what the analyzed code references of the generated configuration
+type variableUsage struct {
+	selected map[string]bool // Names of generated fields, getters and functions selected in the code
+	strings  map[string]bool // Constant strings, naming variables looked up by name
+	dynamic  bool            // Some Lookup takes a name that isn't constant, so any variable may be read
+}
+
+// analyzeUsage type-checks the packages against the export data of their dependencies and records
+// the references to declarations of the generated package in generatedDir. Selectors the type
+// checker can't resolve count by name, so a broken package never makes variables look unused.
+func analyzeUsage(packages []listedPackage, dependencies map[string]listedPackage, generatedDir, runtimeImport string) (*variableUsage, error) {
+	generated := make(map[string]bool)
+	for path, pkg := range dependencies {
+		if pkg.Dir != "" && sameDir(pkg.Dir, generatedDir) {
+			generated[path] = true
+		}
+	}
+
+	fset := token.NewFileSet()
+	gcImporter := importer.ForCompiler(fset, "gc", func(path string) (io.ReadCloser, error) {
+		pkg, ok := dependencies[path]
+		if !ok || pkg.Export == "" {
+			return nil, newError(MessageNoExportData, path)
+		}
+		return os.Open(pkg.Export)
+	})
+
+	usage := &variableUsage{selected: make(map[string]bool), strings: make(map[string]bool)}
+	for _, pkg := range packages {
+		// Internal test files belong to the package, external ones form their own
+		units := []struct {
+			path  string
+			files []string
+		}{
+			{pkg.ImportPath, append(append(append([]string{}, pkg.GoFiles...), pkg.CgoFiles...), pkg.TestGoFiles...)},
+			{pkg.ImportPath + "_test", pkg.XTestGoFiles},
+		}
+		for _, unit := range units {
+			if len(unit.files) == 0 {
+				continue
+			}
+			var files, inspected []*ast.File
+			for _, name := range unit.files {
+				filename := filepath.Join(pkg.Dir, name)
+				src, err := os.ReadFile(filename)
+				if err != nil {
+					return nil, err
+				}
+				file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+				if err != nil {
+					return nil, err
+				}
+				// Generated files are type-checked with their package but not inspected
+				files = append(files, file)
+				if !bytes.HasPrefix(src, []byte(generatedHeader)) {
+					inspected = append(inspected, file)
+				}
+			}
+
+			info := &types.Info{
+				Types:      make(map[ast.Expr]types.TypeAndValue),
+				Uses:       make(map[*ast.Ident]types.Object),
+				Selections: make(map[*ast.SelectorExpr]*types.Selection),
+			}
+			conf := types.Config{
+				Importer:    mappedImporter{gcImporter, pkg.ImportMap},
+				FakeImportC: true,
+				Error:       func(error) {}, // Keep checking, unresolved selectors count by name
+			}
+			conf.Check(unit.path, fset, files, info)
+			if generated[pkg.ImportPath] {
+				generated[unit.path] = true
+			}
+			for _, file := range inspected {
+				usage.inspect(file, info, generated, runtimeImport)
+			}
+		}
+	}
+	return usage, nil
+}
+
+// inspect records the references of a file to the generated package
+func (u *variableUsage) inspect(file *ast.File, info *types.Info, generated map[string]bool, runtimeImport string) {
+	fromGenerated := func(obj types.Object) bool {
+		return obj != nil && obj.Pkg() != nil && generated[obj.Pkg().Path()]
+	}
+	ast.Inspect(file, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.SelectorExpr:
+			if selection, ok := info.Selections[node]; ok {
+				if fromGenerated(selection.Obj()) {
+					u.selected[node.Sel.Name] = true
+				}
+			} else if obj, ok := info.Uses[node.Sel]; ok {
+				if fromGenerated(obj) {
+					u.selected[node.Sel.Name] = true
+				}
+			} else {
+				u.selected[node.Sel.Name] = true
+			}
+		case *ast.BasicLit:
+			if node.Kind == token.STRING {
+				if value, err := strconv.Unquote(node.Value); err == nil {
+					u.strings[value] = true
+				}
+			}
+		case *ast.CallExpr:
+			// Lookup of the generated configuration or of envied layers with a computed name
+			selector, ok := node.Fun.(*ast.SelectorExpr)
+			if !ok || selector.Sel.Name != "Lookup" || len(node.Args) != 1 {
+				break
+			}
+			if selection, ok := info.Selections[selector]; ok {
+				if pkg := selection.Obj().Pkg(); pkg == nil || (!generated[pkg.Path()] && pkg.Path() != runtimeImport) {
+					break
+				}
+			}
+			if tv, ok := info.Types[node.Args[0]]; !ok || tv.Value == nil {
+				u.dynamic = true
+			}
+		}
+		return true
+	})
+}
+
+// sameDir reports whether two paths name the same directory
+func sameDir(a, b string) bool {
+	if resolved, err := filepath.EvalSymlinks(a); err == nil {
+		a = resolved
+	}
+	if resolved, err := filepath.EvalSymlinks(b); err == nil {
+		b = resolved
+	}
+	return filepath.Clean(a) == filepath.Clean(b)
+}
+
+// emittedVariables returns the variables written to artifacts other programs read the values from:
+// properties, INI and Helm values carry every variable, SealedSecrets the sensitive ones
+func emittedVariables(configFile *ConfigFile, data *mergedConfig) map[string]bool {
+	emitted := make(map[string]bool)
+	emit := configFile.Emit
+	if emit == nil {
+		return emitted
+	}
+	for _, envData := range data.Environments {
+		for _, field := range envData.Fields {
+			if emit.Properties != "" || emit.INI != "" || emit.Helm != "" || (emit.SealedSecrets != "" && envData.isSensitive(field.EnvName)) {
+				emitted[field.EnvName] = true
+			}
+		}
+	}
+	return emitted
+}
+
+// FindUnusedVariables analyzes the packages matching patterns (for example ./...) and
+// returns the variables of the configuration whose getters and fields are never referenced.
+// Unused variables are still embedded in binaries, so removing them shrinks the secret surface.
+func FindUnusedVariables(configFilePath string, patterns ...string) ([]UnusedVariable, error) {
+	configFile, err := LoadConfigFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	mergedData, err := buildMergedConfig(configFile, configFilePath, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	packages, err := listPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+	dependencies, err := listDependencies(patterns)
+	if err != nil {
+		return nil, err
+	}
+	outputDir, err := filepath.Abs(configFile.OutputDir)
+	if err != nil {
+		return nil, err
+	}
+	usage, err := analyzeUsage(packages, dependencies, outputDir, resolveRuntimeImport(configFile.RuntimeImport))
+	if err != nil {
+		return nil, err
+	}
+	// Variables read by name at runtime, or delivered to deployments by emitters, are never pruned
+	if usage.dynamic {
+		return nil, nil
+	}
+	emitted := emittedVariables(configFile, mergedData)
+
+	varEnvs := make(map[string][]string)
+	getters := make(map[string]string)
+	fieldNames := make(map[string]string)
+	for _, envName := range sortedEnvironmentNames(mergedData.Environments) {
+		for _, field := range mergedData.Environments[envName].Fields {
+			varEnvs[field.EnvName] = append(varEnvs[field.EnvName], envName)
+			getters[field.EnvName] = field.Getter
+			fieldNames[field.EnvName] = field.FieldName
+		}
+	}
+
+	var unused []UnusedVariable
+	for varName, envNames := range varEnvs {
+		getter := getters[varName]
+		if usage.selected[getter] || usage.selected[fieldNames[varName]] || usage.strings[varName] || emitted[varName] {
+			continue
+		}
+		unused = append(unused, UnusedVariable{
+			Name:         varName,
+			Getter:       getter,
+			Environments: envNames,
+		})
+	}
+	sort.Slice(unused, func(i, j int) bool {
+		return unused[i].Name < unused[j].Name
+	})
+
+	return unused, nil
+}
+
+// PruneVariables removes the named variables from the env files of all environments
+// and drops their settings from the configuration file
+func PruneVariables(configFilePath string, names []string) error {
+	configFile, err := LoadConfigFile(configFilePath)
+	if err != nil {
+		return err
+	}
+
+	remove := make(map[string]bool, len(names))
+	for _, name := range names {
+		remove[name] = true
+	}
+
+	for _, envName := range configFile.environmentNames() {
+		envConfig := configFile.Environments[envName]
+		if envConfig.EnvFile == "" {
+			return newError(MessagePruneRemoteSource, envName)
+		}
+		for _, envFile := range envConfig.envFiles() {
+			if isYAMLFile(envFile) {
+				return newError(MessagePruneYAMLFile, envName, envFile)
+			}
+			if isTOMLFile(envFile) {
+				return newError(MessagePruneTOMLFile, envName, envFile)
+			}
+			if isJSONFile(envFile) {
+				return newError(MessagePruneJSONFile, envName, envFile)
+			}
+		}
+	}
+
+	configChanged := false
+	for name := range remove {
+		if _, exists := configFile.Variables[name]; exists {
+			delete(configFile.Variables, name)
+			configChanged = true
+		}
+		if _, exists := configFile.Fields[name]; exists {
+			delete(configFile.Fields, name)
+			configChanged = true
+		}
+	}
+	// Checked first, so a configuration that can't be written leaves the env files untouched
+	if configChanged {
+		if err := checkWritableConfigFile(configFilePath); err != nil {
+			return err
+		}
+	}
+
+	for _, envName := range configFile.environmentNames() {
+		for _, envFile := range configFile.Environments[envName].envFiles() {
+			if err := removeEnvFileVariables(envFile, remove); err != nil {
+				return newError(MessageFailedPrune, envFile, err)
+			}
+		}
+	}
+
+	if configChanged {
+		return configFile.Save(configFilePath)
+	}
+	return nil
+}
+
+// removeEnvFileVariables rewrites an env file without the lines defining the given variables,
+// keeping comments and all other lines as they are
+func removeEnvFileVariables(filename string, remove map[string]bool) error {
+	content, encrypted, err := readEnvFileDecrypted(filename)
+	if err != nil {
+		return err
+	}
+
+	// Variables with multiline values span several lines, line endings of kept lines are preserved
+	lines := strings.Split(string(content), "\n")
+	var kept []string
+	for _, envLine := range envFileLines(string(normalizeEnvFileContent(content))) {
+		if key, _, ok := strings.Cut(envLine.text, "="); ok && !strings.HasPrefix(envLine.text, "#") && remove[envKey(key)] {
+			continue
+		}
+		kept = append(kept, lines[envLine.number-1:envLine.number-1+envLine.count]...)
+	}
+
+	if len(kept) == len(lines) {
+		return nil
+	}
+	return writeEnvFileContent(filename, []byte(strings.Join(kept, "\n")), encrypted)
+}
@@ -0,0 +1,305 @@
+package envied
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Redis defaults, following redis-cli
+const (
+	DefaultRedisAddress     = "127.0.0.1:6379"
+	DefaultRedisPasswordEnv = "REDIS_PASSWORD"
+)
+
+// redisRetryDelay is the delay before resubscribing after a failed watch
+var redisRetryDelay = time.Second
+
+// RedisConfig configures reading variables from the fields of a Redis hash.
+// Field names become variable names.
+type RedisConfig struct {
+	Address     string `json:"address,omitempty"`      // host:port of the server (DefaultRedisAddress if empty)
+	Key         string `json:"key"`                    // Key of the hash holding the variables
+	DB          int    `json:"db,omitempty"`           // Database number
+	Username    string `json:"username,omitempty"`     // ACL user (default user if empty)
+	PasswordEnv string `json:"password_env,omitempty"` // Environment variable holding the password (DefaultRedisPasswordEnv if empty)
+	TLS         bool   `json:"tls,omitempty"`          // Connect with TLS
+	CACert      string `json:"ca_cert,omitempty"`      // CA bundle verifying the server certificate, implies tls
+}
+
+// address returns the server address
+func (c RedisConfig) address() string {
+	if c.Address == "" {
+		return DefaultRedisAddress
+	}
+	return c.Address
+}
+
+// describe returns a human-readable location of the variables
+func (c RedisConfig) describe() string {
+	return fmt.Sprintf("redis %s/%d %s", c.address(), c.DB, c.Key)
+}
+
+// channel returns the keyspace notification channel of the hash
+func (c RedisConfig) channel() string {
+	return fmt.Sprintf("__keyspace@%d__:%s", c.DB, c.Key)
+}
+
+// tlsConfig returns the TLS configuration, nil without TLS
+func (c RedisConfig) tlsConfig() (*tls.Config, error) {
+	if !c.TLS && c.CACert == "" {
+		return nil, nil
+	}
+
+	host, _, err := net.SplitHostPort(c.address())
+	if err != nil {
+		return nil, newError(MessageInvalidRedisAddress, c.address(), err)
+	}
+	tlsConfig := &tls.Config{MinVersion: tls.VersionTLS12, ServerName: host}
+	if c.CACert != "" {
+		pem, err := os.ReadFile(c.CACert)
+		if err != nil {
+			return nil, newError(MessageFailedReadRedisCACertificate, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, newError(MessageNoCertificatesFound, c.CACert)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	return tlsConfig, nil
+}
+
+// redisError is an error reply of the server
+type redisError string
+
+func (e redisError) Error() string {
+	return string(e)
+}
+
+// redisConn is a minimal RESP2 connection supporting the commands used by go-envied
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+	stop   func() bool // Stops closing the connection when the context is done
+}
+
+// dialRedis connects, authenticates and selects the configured database.
+// The connection is closed when ctx is done.
+func dialRedis(ctx context.Context, config RedisConfig) (*redisConn, error) {
+	tlsConfig, err := config.tlsConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := dialContext(ctx, "tcp", config.address())
+	if err != nil {
+		return nil, newError(MessageFailedConnect, config.describe(), err)
+	}
+	if tlsConfig != nil {
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, newError(MessageTLSHandshakeFailed, config.describe(), err)
+		}
+		conn = tlsConn
+	}
+
+	client := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+	client.stop = context.AfterFunc(ctx, func() { conn.Close() })
+
+	passwordEnv := config.PasswordEnv
+	if passwordEnv == "" {
+		passwordEnv = DefaultRedisPasswordEnv
+	}
+	if password := os.Getenv(passwordEnv); password != "" || config.Username != "" {
+		args := []string{"AUTH", password}
+		if config.Username != "" {
+			args = []string{"AUTH", config.Username, password}
+		}
+		if _, err := client.do(args...); err != nil {
+			client.close()
+			return nil, newError(MessageAuthenticationFailed, config.describe(), err)
+		}
+	}
+	if config.DB != 0 {
+		if _, err := client.do("SELECT", strconv.Itoa(config.DB)); err != nil {
+			client.close()
+			return nil, newError(MessageFailedSelectDatabase, config.describe(), err)
+		}
+	}
+	return client, nil
+}
+
+// close closes the connection
+func (c *redisConn) close() {
+	c.stop()
+	c.conn.Close()
+}
+
+// send writes a command as an array of bulk strings
+func (c *redisConn) send(args ...string) error {
+	var command strings.Builder
+	fmt.Fprintf(&command, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&command, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	_, err := c.conn.Write([]byte(command.String()))
+	return err
+}
+
+// do sends a command and reads its reply
+func (c *redisConn) do(args ...string) (any, error) {
+	if err := c.send(args...); err != nil {
+		return nil, err
+	}
+	return c.receive()
+}
+
+// receive reads a reply: string, int64, []any, nil or redisError
+func (c *redisConn) receive() (any, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, newError(MessageInvalidRedisReply)
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, redisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		length, err := strconv.Atoi(line[1:])
+		if err != nil || length < 0 {
+			return nil, err
+		}
+		data := make([]byte, length+2)
+		if _, err := io.ReadFull(c.reader, data); err != nil {
+			return nil, err
+		}
+		return string(data[:length]), nil
+	case '*':
+		count, err := strconv.Atoi(line[1:])
+		if err != nil || count < 0 {
+			return nil, err
+		}
+		items := make([]any, count)
+		for i := range items {
+			if items[i], err = c.receive(); err != nil {
+				return nil, err
+			}
+		}
+		return items, nil
+	}
+	return nil, newError(MessageInvalidRedisReplyLine, line)
+}
+
+// fetch reads all fields of the hash
+func (c RedisConfig) fetch(ctx context.Context) (map[string]string, error) {
+	client, err := dialRedis(ctx, c)
+	if err != nil {
+		return nil, err
+	}
+	defer client.close()
+
+	reply, err := client.do("HGETALL", c.Key)
+	if err != nil {
+		return nil, newError(MessageFailedRead, c.describe(), err)
+	}
+	items, ok := reply.([]any)
+	if !ok || len(items)%2 != 0 {
+		return nil, newError(MessageInvalidHGETALLReply, c.describe())
+	}
+
+	values := make(map[string]string, len(items)/2)
+	for i := 0; i < len(items); i += 2 {
+		name, _ := items[i].(string)
+		value, _ := items[i+1].(string)
+		values[name] = value
+	}
+	return values, nil
+}
+
+// RedisSource is a runtime Source reading a Redis hash, see NewRedisSource
+type RedisSource struct {
+	watchedValues
+
+	config RedisConfig
+}
+
+// NewRedisSource reads the fields of the configured hash.
+// Call Watch to keep them up to date.
+func NewRedisSource(ctx context.Context, config RedisConfig) (*RedisSource, error) {
+	values, err := config.fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	source := &RedisSource{config: config}
+	source.update(values)
+	return source, nil
+}
+
+// Watch subscribes to keyspace notifications of the hash and calls onChange with the new
+// variables after every change. The server must publish hash events, for example with
+// notify-keyspace-events set to "Kh". Failed subscriptions are retried and the hash is
+// read again after each, so changes during reconnects are not lost. Watch returns when ctx is done.
+func (s *RedisSource) Watch(ctx context.Context, onChange ChangeFunc) error {
+	for {
+		err := s.watch(ctx, onChange)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if errors.As(err, new(redisError)) {
+			// Rejected commands are not retried, the configuration must be fixed
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(redisRetryDelay):
+		}
+	}
+}
+
+// watch subscribes once and refreshes the values until the subscription fails
+func (s *RedisSource) watch(ctx context.Context, onChange ChangeFunc) error {
+	client, err := dialRedis(ctx, s.config)
+	if err != nil {
+		return err
+	}
+	defer client.close()
+
+	if _, err := client.do("SUBSCRIBE", s.config.channel()); err != nil {
+		return err
+	}
+
+	for {
+		// Read after subscribing, so every change is followed by a read
+		values, err := s.config.fetch(ctx)
+		if err != nil {
+			return err
+		}
+		if s.update(values) && onChange != nil {
+			onChange(s.Values())
+		}
+
+		if _, err := client.receive(); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,223 @@
+package envied
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Report formats of WriteReleaseReport
+const (
+	ReportMarkdown = "markdown" // Tables per environment for release notes (default)
+	ReportJSON     = "json"     // ReleaseDifference list for tooling
+)
+
+// ValueHash returns the hash of a value in the manifest, so releases can be compared without the
+// values. The environment and variable names are hashed along, so equal values of different
+// variables can't be told apart. With a key the hash is an HMAC-SHA256, which can't be checked
+// against guessed values without the key; a nil key gives a plain SHA-256, used only for values
+// that aren't sensitive.
+func ValueHash(key []byte, environment, variable, value string) string {
+	message := []byte(environment + "\x00" + variable + "\x00" + value)
+	if key == nil {
+		sum := sha256.Sum256(message)
+		return hex.EncodeToString(sum[:])
+	}
+	mac := hmac.New(sha256.New, key)
+	mac.Write(message)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ParseManifest decodes a manifest written by emit.manifest, decrypting it with key if it is
+// encrypted. Unknown fields are refused, so other files are never taken for manifests.
+func ParseManifest(data, key []byte) (*Manifest, error) {
+	if bytes.HasPrefix(data, []byte(encryptedArtifactMagic)) {
+		if key == nil {
+			return nil, newError(MessageManifestKeyMissing)
+		}
+		var err error
+		if data, err = DecryptArtifact(data, key); err != nil {
+			return nil, newError(MessageManifestNotDecrypted)
+		}
+	}
+
+	var manifest Manifest
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&manifest); err != nil {
+		return nil, newError(MessageNotManifest)
+	}
+	return &manifest, nil
+}
+
+// ReadManifestAtRef reads a manifest as committed at a git revision, such as the tag of the
+// previous release. The path is relative to the current directory.
+func ReadManifestAtRef(ref, path string, key []byte) (*Manifest, error) {
+	path = filepath.ToSlash(path)
+	if !strings.HasPrefix(path, "./") && !strings.HasPrefix(path, "../") {
+		path = "./" + path
+	}
+	cmd := exec.Command("git", "show", ref+":"+path)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	data, err := cmd.Output()
+	if err != nil {
+		return nil, newError(MessageGitShowFailed, ref, path, err, stderr.String())
+	}
+	manifest, err := ParseManifest(data, key)
+	if err != nil {
+		return nil, newError(MessageReleaseFileError, path, ref, err)
+	}
+	return manifest, nil
+}
+
+// ReleaseDifference describes a variable of an environment that changed since the previous release
+type ReleaseDifference struct {
+	Environment string `json:"environment"`
+	VariableDifference
+}
+
+// DiffRelease compares the configuration with the manifest of the previous release, see DiffReleases
+func DiffRelease(opts GenerateOptions, previous *Manifest) ([]ReleaseDifference, error) {
+	var differences []ReleaseDifference
+	err := opts.run(func() error {
+		configFile, configPath, _, err := opts.load()
+		if err != nil {
+			return err
+		}
+		hashKey, err := configFile.Emit.hashKey()
+		if err != nil {
+			return err
+		}
+		mergedData, err := buildMergedConfig(configFile, configPath, nil)
+		if err != nil {
+			return err
+		}
+		differences = DiffReleases(previous, buildManifest(mergedData, hashKey))
+		return nil
+	})
+	return differences, err
+}
+
+// DiffReleases lists the variables added, removed or changed per environment between the
+// manifests of two releases, sorted by environment and variable name. Changed values are found
+// by their hashes; variables without hashes, such as sensitive values without emit.hash_key_env
+// or in manifests written before hashes were added, only show as added, removed and retyped.
+func DiffReleases(previous, current *Manifest) []ReleaseDifference {
+	before, after := releaseVariables(previous), releaseVariables(current)
+
+	var differences []ReleaseDifference
+	for key, from := range before {
+		to, exists := after[key]
+		difference := VariableDifference{Name: key.name, FromType: from.typ, ToType: to.typ}
+		switch {
+		case !exists:
+			difference.Kind, difference.ToType = DiffRemoved, ""
+		case from.typ != to.typ:
+			difference.Kind = DiffTypeChanged
+		case from.hash != "" && to.hash != "" && from.hash != to.hash:
+			difference.Kind = DiffValueChanged
+		default:
+			continue
+		}
+		differences = append(differences, ReleaseDifference{Environment: key.environment, VariableDifference: difference})
+	}
+	for key, to := range after {
+		if _, exists := before[key]; !exists {
+			differences = append(differences, ReleaseDifference{
+				Environment:        key.environment,
+				VariableDifference: VariableDifference{Name: key.name, Kind: DiffAdded, ToType: to.typ},
+			})
+		}
+	}
+
+	sort.Slice(differences, func(i, j int) bool {
+		if differences[i].Environment != differences[j].Environment {
+			return differences[i].Environment < differences[j].Environment
+		}
+		return differences[i].Name < differences[j].Name
+	})
+	return differences
+}
+
+// releaseKey identifies a variable of an environment
+type releaseKey struct {
+	environment, name string
+}
+
+// releaseVariable is the type and value hash of a variable in an environment
+type releaseVariable struct {
+	typ  FieldType
+	hash string
+}
+
+// releaseVariables indexes the variables of a manifest by environment and name
+func releaseVariables(manifest *Manifest) map[releaseKey]releaseVariable {
+	variables := make(map[releaseKey]releaseVariable)
+	for _, variable := range manifest.Variables {
+		for _, envName := range variable.Environments {
+			variables[releaseKey{envName, variable.Name}] = releaseVariable{typ: variable.Type, hash: variable.Hashes[envName]}
+		}
+	}
+	return variables
+}
+
+// releaseChangeNames describes the kinds of differences in reports
+var releaseChangeNames = map[string]string{
+	DiffAdded:        "added",
+	DiffRemoved:      "removed",
+	DiffTypeChanged:  "type changed",
+	DiffValueChanged: "value changed",
+}
+
+// WriteReleaseReport writes the differences since the previous release as ReportMarkdown tables
+// per environment for release notes and security reviews, or as ReportJSON. Values are never
+// written, only which variables changed.
+func WriteReleaseReport(w io.Writer, differences []ReleaseDifference, format string) error {
+	switch format {
+	case "", ReportMarkdown:
+	case ReportJSON:
+		if differences == nil {
+			differences = []ReleaseDifference{}
+		}
+		data, err := json.MarshalIndent(differences, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(append(data, '\n'))
+		return err
+	default:
+		return newError(MessageUnknownReportFormat, format, ReportMarkdown, ReportJSON)
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "## Configuration changes\n")
+	if len(differences) == 0 {
+		fmt.Fprintf(&buf, "\nNo variables changed since the previous release.\n")
+	}
+	for i, difference := range differences {
+		if i == 0 || differences[i-1].Environment != difference.Environment {
+			fmt.Fprintf(&buf, "\n### %s\n\n", difference.Environment)
+			fmt.Fprintf(&buf, "| Change | Variable | Type |\n")
+			fmt.Fprintf(&buf, "|--------|----------|------|\n")
+		}
+		typ := string(difference.ToType)
+		switch difference.Kind {
+		case DiffRemoved:
+			typ = string(difference.FromType)
+		case DiffTypeChanged:
+			typ = fmt.Sprintf("%s → %s", difference.FromType, difference.ToType)
+		}
+		fmt.Fprintf(&buf, "| %s | `%s` | %s |\n", releaseChangeNames[difference.Kind], difference.Name, typ)
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
@@ -0,0 +1,68 @@
+package envied
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+)
+
+// renameTargets are the emit keys of the files whose variable names can be renamed
+var renameTargets = []string{"env_example", "fixtures", "properties", "ini", "helm", "sealed_secrets"}
+
+// exportedNamePattern matches the variable names Kubernetes accepts, which the other formats accept too
+var exportedNamePattern = regexp.MustCompile(`^[-._a-zA-Z][-._a-zA-Z0-9]*$`)
+
+// exportedName returns the name a variable is written with by an emitter, names maps variable
+// names to their emit.rename entries of the emitter
+func exportedName(names map[string]string, name string) string {
+	if renamed, exists := names[name]; exists {
+		return renamed
+	}
+	return name
+}
+
+// checkRenames validates emit.rename against the variables of the manifest: targets must be
+// known, renamed variables must exist and every emitter must write distinct names
+func checkRenames(rename map[string]map[string]string, manifest *Manifest) error {
+	variables := make(map[string]bool, len(manifest.Variables))
+	for _, variable := range manifest.Variables {
+		variables[variable.Name] = true
+	}
+
+	targets := make([]string, 0, len(rename))
+	for target := range rename {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	for _, target := range targets {
+		if !slices.Contains(renameTargets, target) {
+			return newError(MessageRenameUnknownEmitter, target, strings.Join(renameTargets, ", "))
+		}
+		names := rename[target]
+		renamed := make([]string, 0, len(names))
+		for name := range names {
+			renamed = append(renamed, name)
+		}
+		sort.Strings(renamed)
+		for _, name := range renamed {
+			if !variables[name] {
+				return newError(MessageRenameUnknownVariable, target, name)
+			}
+			if !exportedNamePattern.MatchString(names[name]) {
+				return newError(MessageRenameInvalidName, target, names[name], name)
+			}
+		}
+
+		exported := make(map[string]string, len(manifest.Variables))
+		for _, variable := range manifest.Variables {
+			as := exportedName(names, variable.Name)
+			if other, exists := exported[as]; exists {
+				return newError(MessageRenameDuplicateName, target, other, variable.Name, as)
+			}
+			exported[as] = variable.Name
+		}
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package envied
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+)
+
+// RuntimeImportPath is the import path of the envied runtime package the generator was built from.
+// It reflects forks with a different module path, so generated code imports the same package.
+var RuntimeImportPath = reflect.TypeOf(Field{}).PkgPath()
+
+// resolveRuntimeImport returns the runtime import path to use in generated code
+func resolveRuntimeImport(configured string) string {
+	if configured != "" {
+		return configured
+	}
+	return RuntimeImportPath
+}
+
+// runtimeImportSpec returns the import spec for the runtime package,
+// aliased to envied when the last path element would not be the package name
+func runtimeImportSpec(importPath string) string {
+	switch path.Base(importPath) {
+	case "envied", "go-envied":
+		return strconv.Quote(importPath)
+	default:
+		return fmt.Sprintf("envied %s", strconv.Quote(importPath))
+	}
+}
@@ -0,0 +1,77 @@
+package envied
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// findModuleRoot returns the closest directory at or above dir containing go.mod,
+// or an empty string if there is none
+func findModuleRoot(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		if info, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil && !info.IsDir() {
+			return dir
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// isWithinDir reports whether path is dir itself or located inside dir
+func isWithinDir(dir, path string) bool {
+	rel, err := filepath.Rel(dir, path)
+	if err != nil {
+		return false
+	}
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+// checkPathSafety refuses generation when env files live inside the output directory
+// (they would be committed next to generated code) or when the output directory is
+// outside the Go module containing the configuration file.
+// The module check is skipped when configFilePath is empty (declared outputs of hermetic builds),
+// all checks are skipped when AllowUnsafePaths is set.
+func checkPathSafety(configFile *ConfigFile, configFilePath string) error {
+	if configFile.AllowUnsafePaths {
+		return nil
+	}
+
+	outputDir, err := filepath.Abs(configFile.OutputDir)
+	if err != nil {
+		return newError(MessageFailedResolveOutputDirectory, configFile.OutputDir, err)
+	}
+
+	for _, envName := range configFile.environmentNames() {
+		envConfig := configFile.Environments[envName]
+		// Remote sources have no env file, overlays are checked like env files
+		for _, path := range envConfig.envFiles() {
+			envFile, err := filepath.Abs(path)
+			if err != nil {
+				return newError(MessageFailedResolveEnvFile, path, err)
+			}
+			if isWithinDir(outputDir, envFile) {
+				return newError(MessageEnvFileInsideOutputDir, path, envName, configFile.OutputDir)
+			}
+		}
+	}
+
+	if configFilePath == "" {
+		return nil
+	}
+
+	moduleRoot := findModuleRoot(filepath.Dir(configFilePath))
+	if moduleRoot != "" && !isWithinDir(moduleRoot, outputDir) {
+		return newError(MessageOutputDirOutsideModule, configFile.OutputDir, moduleRoot)
+	}
+
+	return nil
+}
@@ -0,0 +1,391 @@
+package envied
+
+// DefaultConfigFileName is the configuration file name searched by AutoGenerate
+const DefaultConfigFileName = "go-envied-config.json"
+
+// ConfigSchemaDraft is the JSON Schema dialect used by ConfigSchema
+const ConfigSchemaDraft = "http://json-schema.org/draft-07/schema#"
+
+// ConfigSchema is the JSON Schema of the go-envied-config.json file.
+// It is kept in sync with ConfigFile and can be used by external validators and editors.
+const ConfigSchema = `{
+  "$schema": "http://json-schema.org/draft-07/schema#",
+  "title": "go-envied configuration",
+  "type": "object",
+  "required": ["package_name", "output_dir", "environments"],
+  "additionalProperties": false,
+  "properties": {
+    "package_name": {
+      "type": "string",
+      "description": "Go package name of the generated file"
+    },
+    "output_dir": {
+      "type": "string",
+      "description": "Directory where config_env.gen.go is written"
+    },
+    "random_seed": {
+      "description": "Seed for deterministic obfuscation (0 means random)",
+      "oneOf": [
+        {
+          "type": "integer",
+          "minimum": -9223372036854775808,
+          "maximum": 9223372036854775807
+        },
+        {
+          "type": "string",
+          "pattern": "^\\s*-?[0-9]+\\s*$"
+        }
+      ]
+    },
+    "obfuscation": {
+      "type": "string",
+      "enum": ["xor", "aes-gcm", "none"],
+      "description": "Obfuscation of string values: xor (default), aes-gcm (AES-256-GCM with HKDF-SHA256 keys) or none for plain constants"
+    },
+    "crypto_mode": {
+      "type": "string",
+      "enum": ["fips"],
+      "description": "fips restricts generation to FIPS-approved primitives: aes-gcm obfuscation (the default in this mode) and AES-256-GCM artifact encryption"
+    },
+    "allow_unsafe_paths": {
+      "type": "boolean",
+      "description": "Allow env files inside output_dir and output_dir outside the module"
+    },
+    "allow_extra_variables": {
+      "type": "boolean",
+      "description": "Allow environments to define different variables; extras are generated in per-environment interfaces"
+    },
+    "allow_empty_environments": {
+      "type": "boolean",
+      "description": "Warns instead of failing for environments without variables, which usually means a wrong env file path or format"
+    },
+    "runtime_import": {
+      "type": "string",
+      "description": "Import path of the envied runtime used by generated code (detected if empty)"
+    },
+    "on_conflict": {
+      "type": "string",
+      "enum": ["error", "rename", "force"],
+      "description": "Strategy when other files of the output package declare generated symbols: error (default), rename prefixes conflicting environment and profile types with Envied, force generates anyway"
+    },
+    "name_validation": {
+      "type": "string",
+      "enum": ["warn", "error", "off"],
+      "description": "Validation of variable names against name_pattern"
+    },
+    "on_unsafe_characters": {
+      "type": "string",
+      "enum": ["allow", "reject", "escape"],
+      "description": "Control characters and invalid UTF-8 in values: embed them (default), fail or replace them by escape sequences"
+    },
+    "name_pattern": {
+      "type": "string",
+      "format": "regex",
+      "description": "Regular expression for variable names (default ^[A-Z_][A-Z0-9_]*$)"
+    },
+    "environments": {
+      "type": "object",
+      "description": "Environments keyed by name",
+      "additionalProperties": {
+        "type": "object",
+        "required": ["struct_name"],
+        "oneOf": [
+          {"required": ["env_file"]},
+          {"required": ["consul"]},
+          {"required": ["etcd"]},
+          {"required": ["redis"]},
+          {"required": ["sql"]}
+        ],
+        "additionalProperties": false,
+        "properties": {
+          "env_file": {
+            "type": "string",
+            "description": "Path to the .env file of the environment, files ending in .yaml or .yml are read as a YAML mapping, files ending in .toml as TOML and files ending in .json as a flat JSON object"
+          },
+          "key_separator": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Joins nested keys of a YAML or TOML env_file into variable names, \"_\" by default"
+          },
+          "base_env_file": {
+            "type": "string",
+            "description": "Env file of variables shared by environments, layered under the source whose values override it"
+          },
+          "overlays": {
+            "type": "array",
+            "items": {"type": "string"},
+            "description": "Env files layered over the source of the environment in order, later values replace earlier ones"
+          },
+          "no_local": {
+            "type": "boolean",
+            "description": "Ignores the local overlay of env_file, e.g. dev.env.local for dev.env, layered over the overlays when it exists"
+          },
+          "struct_name": {
+            "type": "string",
+            "description": "Prefix of the generated struct name"
+          },
+          "obfuscate": {
+            "type": "boolean",
+            "description": "Overrides the top-level obfuscation mode for this environment"
+          },
+          "profiles": {
+            "type": "object",
+            "description": "Additional structs generated from subsets of the variables, keyed by struct name, e.g. {\"ProdServer\": [\"API_URL\", \"PORT\"]}; they share the obfuscated data of the environment",
+            "additionalProperties": {"type": "array", "items": {"type": "string"}, "minItems": 1}
+          },
+          "substitutions": {
+            "type": "object",
+            "description": "Values of {{.Name}} placeholders in the values of the environment, replacing the configuration substitutions of the same name",
+            "additionalProperties": {"type": "string"}
+          },
+          "vault": {
+            "type": "object",
+            "description": "Reads the values of the variables declared in env_file from a Vault KV v2 secret, only when generating with --values-from vault; without it the environment is not generated",
+            "required": ["path"],
+            "additionalProperties": false,
+            "properties": {
+              "address": {"type": "string", "description": "Vault address (VAULT_ADDR or https://127.0.0.1:8200 if empty)"},
+              "mount": {"type": "string", "description": "Mount path of the KV v2 secrets engine (secret if empty)"},
+              "path": {"type": "string", "description": "Path of the secret holding the values below the mount"},
+              "namespace": {"type": "string", "description": "Vault Enterprise namespace (VAULT_NAMESPACE if empty)"},
+              "token_env": {"type": "string", "description": "Environment variable holding the token (VAULT_TOKEN if empty)"}
+            }
+          },
+          "consul": {
+            "type": "object",
+            "description": "Reads variables from Consul KV under a prefix instead of env_file",
+            "required": ["prefix"],
+            "additionalProperties": false,
+            "properties": {
+              "address": {"type": "string", "description": "Consul HTTP address (CONSUL_HTTP_ADDR or http://127.0.0.1:8500 if empty)"},
+              "prefix": {"type": "string", "description": "KV prefix holding the variables, nested keys are joined with _"},
+              "datacenter": {"type": "string", "description": "Datacenter to query"},
+              "token_env": {"type": "string", "description": "Environment variable holding the ACL token (CONSUL_HTTP_TOKEN if empty)"}
+            }
+          },
+          "etcd": {
+            "type": "object",
+            "description": "Reads variables from etcd v3 under a key prefix instead of env_file",
+            "required": ["prefix"],
+            "additionalProperties": false,
+            "properties": {
+              "endpoints": {"type": "array", "items": {"type": "string"}, "description": "Client URLs tried in order (http://127.0.0.1:2379 if empty)"},
+              "prefix": {"type": "string", "description": "Key prefix holding the variables, nested keys are joined with _"},
+              "username": {"type": "string", "description": "User for authentication"},
+              "password_env": {"type": "string", "description": "Environment variable holding the password (ETCD_PASSWORD if empty)"},
+              "ca_cert": {"type": "string", "description": "CA bundle verifying the server certificate"},
+              "cert": {"type": "string", "description": "Client certificate for TLS authentication"},
+              "key": {"type": "string", "description": "Client certificate key for TLS authentication"}
+            }
+          },
+          "redis": {
+            "type": "object",
+            "description": "Reads variables from the fields of a Redis hash instead of env_file",
+            "required": ["key"],
+            "additionalProperties": false,
+            "properties": {
+              "address": {"type": "string", "description": "host:port of the server (127.0.0.1:6379 if empty)"},
+              "key": {"type": "string", "description": "Key of the hash holding the variables"},
+              "db": {"type": "integer", "minimum": 0, "description": "Database number"},
+              "username": {"type": "string", "description": "ACL user"},
+              "password_env": {"type": "string", "description": "Environment variable holding the password (REDIS_PASSWORD if empty)"},
+              "tls": {"type": "boolean", "description": "Connect with TLS"},
+              "ca_cert": {"type": "string", "description": "CA bundle verifying the server certificate, implies tls"}
+            }
+          },
+          "sql": {
+            "type": "object",
+            "description": "Reads variables from a database table instead of env_file, the driver must be imported by the generator",
+            "required": ["driver", "query"],
+            "additionalProperties": false,
+            "properties": {
+              "driver": {"type": "string", "description": "Registered database/sql driver name, e.g. postgres or mysql"},
+              "dsn": {"type": "string", "description": "Data source name, prefer dsn_env for DSNs with passwords"},
+              "dsn_env": {"type": "string", "description": "Environment variable holding the data source name"},
+              "query": {"type": "string", "description": "Query returning name and value columns"}
+            }
+          }
+        }
+      }
+    },
+    "variables": {
+      "type": "object",
+      "description": "Per-variable settings keyed by env var name",
+      "additionalProperties": {
+        "type": "object",
+        "additionalProperties": false,
+        "properties": {
+          "type": {
+            "type": "string",
+            "enum": ["string", "bool", "int", "float64", "int64", "uint64", "[]byte", "map[string]string", "map[string]bool", "map[string]int", "map[string]float64", "map[string]any"],
+            "description": "Declares the type instead of detecting it: string, bool, int and float64 pin detected types, int64 and uint64 for large integers, []byte for base64 or hex encoded binary values, map types for values holding a JSON object"
+          },
+          "encoding": {
+            "type": "string",
+            "enum": ["base64", "base64url", "hex"],
+            "description": "Text encoding of []byte values, base64 by default"
+          },
+          "separator": {
+            "type": "string",
+            "minLength": 1,
+            "description": "Splits the value into a []string at this separator, e.g. \",\"; every element is obfuscated independently"
+          },
+          "time_layout": {
+            "type": "string",
+            "description": "Parses the value as time.Time with this Go layout or time package layout name (RFC3339, DateOnly, ...); RFC 3339 values are detected without it"
+          },
+          "transform": {
+            "type": "array",
+            "items": {"type": "string"},
+            "description": "Transforms applied to the value before typing and obfuscation (trim, lowercase, uppercase, base64decode or registered custom transforms)"
+          },
+          "only": {
+            "type": "array",
+            "items": {"type": "string"},
+            "description": "Environments the variable is generated for; it is excluded from the shared interface"
+          },
+          "getter": {
+            "type": "string",
+            "pattern": "^[A-Za-z_][A-Za-z0-9_]*$",
+            "description": "Getter method name, overriding the name derived from getters, e.g. APIURL"
+          },
+          "max_bytes": {
+            "type": "integer",
+            "minimum": 0,
+            "description": "Maximum bytes the value embeds into the binary, obfuscated data included, overriding size_budget.variable"
+          },
+          "description": {
+            "type": "string",
+            "description": "Human-readable description used in generated godoc, Markdown, .env.example and manifest"
+          }
+        }
+      }
+    },
+    "fields": {
+      "type": "object",
+      "description": "Types pinned by env var name, overriding type detection, e.g. {\"VERSION\": \"string\"}; shorthand for the type of a variable",
+      "additionalProperties": {
+        "type": "string",
+        "enum": ["string", "bool", "int", "float64", "int64", "uint64", "[]byte", "map[string]string", "map[string]bool", "map[string]int", "map[string]float64", "map[string]any"]
+      }
+    },
+    "emit": {
+      "type": "object",
+      "description": "Documentation files written next to the generated code",
+      "additionalProperties": false,
+      "properties": {
+        "markdown": {"type": "string", "description": "Markdown reference of all variables"},
+        "env_example": {"type": "string", "description": ".env.example template without values"},
+        "manifest": {"type": "string", "description": "JSON manifest of environments and variables without values"},
+        "fixtures": {"type": "string", "description": "Directory of <environment>.env fixtures with fake values for integration tests"},
+        "properties": {"type": "string", "description": "Directory of <environment>.properties files with the values, for Java services"},
+        "ini": {"type": "string", "description": "INI file with the values of every environment in its own section"},
+        "helm": {"type": "string", "description": "Directory of values-<environment>.yaml Helm values with the env section of a Deployment and secret references for sensitive values"},
+        "helm_secret": {"type": "string", "description": "Kubernetes Secret referenced for sensitive values, {environment} is replaced with the environment name; <package_name>-{environment} if empty"},
+        "sealed_secrets": {"type": "string", "description": "Directory of sealedsecret-<environment>.yaml Bitnami SealedSecrets of the sensitive values, creating the Secret named by helm_secret"},
+        "sealed_secrets_cert": {"type": "string", "description": "PEM certificate of the Sealed Secrets controller, as printed by kubeseal --fetch-cert"},
+        "namespace": {"type": "string", "description": "Kubernetes namespace of the sealed secrets, default if empty"},
+        "encrypt_key_env": {"type": "string", "description": "Environment variable holding a base64 AES-256 key encrypting all emitted files"},
+        "hash_key_env": {"type": "string", "description": "Environment variable holding a base64 32-byte key the value hashes of the manifest are keyed with"},
+        "rename": {
+          "type": "object",
+          "description": "Names variables are written with, keyed by emitter (env_example, fixtures, properties, ini, helm or sealed_secrets) and then by variable name",
+          "additionalProperties": {"type": "object", "additionalProperties": {"type": "string", "minLength": 1}}
+        }
+      }
+    },
+    "internal_package": {
+      "type": "boolean",
+      "description": "Generates the code into internal/envied/<package_name> of the module and writes a re-export shim into output_dir"
+    },
+    "getters": {
+      "type": "object",
+      "description": "Naming and receivers of generated getters",
+      "additionalProperties": false,
+      "properties": {
+        "prefix": {"type": "string", "description": "Prepended to variable names, \"Get\" by default; without a prefix every variable needs a getter name, fields use the variable names"},
+        "receiver": {"type": "string", "enum": ["pointer", "value"], "description": "Receiver of getters, pointer by default"},
+        "unexported": {"type": "boolean", "description": "Lowercases the first letter of derived getter names, e.g. getAPI_URL"}
+      }
+    },
+    "interpolate": {
+      "type": "boolean",
+      "description": "Expands ${NAME} references to other variables of the environment in values after layering env files, $$ is a literal $"
+    },
+    "ldflags": {
+      "type": "boolean",
+      "description": "Leaves the values selected for obfuscation out of the generated code, they are injected at link time with -ldflags -X and checked by CheckLinkedValues"
+    },
+    "source_timeout": {
+      "type": "string",
+      "description": "Bounds the read of each remote source (Consul, etcd, Redis, SQL, Vault) during generation as a Go duration, 30s by default"
+    },
+    "literals": {
+      "type": "boolean",
+      "description": "Embeds bool, int and float values as typed literals instead of Parse calls, except values marked sensitive"
+    },
+    "naming": {
+      "type": "object",
+      "description": "Naming of generated fields, getters, obfuscated data and per-environment files",
+      "additionalProperties": false,
+      "properties": {
+        "strategy": {"type": "string", "enum": ["preserve", "camel", "go"], "description": "preserve uses variable names (default), camel converts API_URL to ApiUrl, go converts DATABASE_URL to DatabaseURL with Go initialisms"},
+        "strip_prefix": {"type": "string", "description": "Prefix removed from variable names before naming, e.g. APP_"},
+        "sanitize": {"type": "string", "enum": ["replace", "remove"], "description": "Turns names that aren't Go identifiers, such as MY-VAR or 1ST_KEY, into identifiers: replace writes underscores for invalid characters, remove drops them; names starting with a digit get an X prefix"}
+      }
+    },
+    "substitutions": {
+      "type": "object",
+      "description": "Values of {{.Name}} placeholders in env values, e.g. ENDPOINT=https://api.{{.Region}}.example.com, resolved at generation time; --set name=value takes precedence",
+      "additionalProperties": {"type": "string"}
+    },
+    "size_budget": {
+      "type": "object",
+      "description": "Limits of the bytes embedded into the binary, obfuscated data included; generation fails with a breakdown of the largest variables when exceeded",
+      "additionalProperties": false,
+      "properties": {
+        "environment": {"type": "integer", "minimum": 0, "description": "Maximum embedded bytes of an environment, unlimited if 0"},
+        "variable": {"type": "integer", "minimum": 0, "description": "Maximum embedded bytes of a variable, unlimited if 0"}
+      }
+    },
+    "policies": {
+      "type": "array",
+      "description": "Organizational rules evaluated by every generation, e.g. {\"environments\": [\"prod\"], \"variable\": \"DEBUG\", \"value\": \"true\"}; generation fails for variables breaking one",
+      "items": {
+        "type": "object",
+        "required": ["variable"],
+        "additionalProperties": false,
+        "properties": {
+          "environments": {"type": "array", "items": {"type": "string", "minLength": 1}, "description": "Environments the rule applies to, as glob patterns such as prod*; all if empty, patterns must match an environment"},
+          "variable": {"type": "string", "minLength": 1, "description": "Forbidden variable names, as a glob pattern such as *_LIVE_*"},
+          "value": {"type": "string", "description": "Forbids only this value, compared case-insensitively; any value if empty"},
+          "message": {"type": "string", "description": "Reason of the rule, shown with violations"}
+        }
+      }
+    },
+    "build_tags": {
+      "type": "boolean",
+      "description": "Generates NewConfig once per environment behind an envied_<environment> build tag; builds without exactly one such tag fail to compile"
+    },
+    "build_tag_prefix": {
+      "type": "string",
+      "pattern": "^[A-Za-z0-9_.]*$",
+      "description": "Prefix of the build tags selecting environments, envied_ if unset; empty for plain tags such as -tags prod, which must not be set by the go command like linux or cgo"
+    },
+    "split_environments": {
+      "type": "boolean",
+      "description": "Writes the configuration of every environment into its own file behind its build tag, so binaries contain only the selected environment; requires build_tags"
+    },
+    "annotations": {
+      "type": "object",
+      "description": "Lint and coverage directives written above the package clause of the generated file",
+      "additionalProperties": false,
+      "properties": {
+        "nolint": {"type": "array", "items": {"type": "string", "pattern": "^[a-z0-9][a-z0-9-]*$"}, "description": "Linters suppressed with a file-level //nolint directive, \"all\" for all linters"},
+        "coverage_ignore": {"type": "boolean", "description": "Adds //coverage:ignore so coverage tools skip the file"},
+        "comments": {"type": "array", "items": {"type": "string", "pattern": "^//"}, "description": "Additional single-line comments such as //lint:file-ignore directives"}
+      }
+    }
+  }
+}
+`
@@ -0,0 +1,229 @@
+package envied
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// parsedConfigSchema returns ConfigSchema decoded with numbers kept exact
+var parsedConfigSchema = sync.OnceValue(func() map[string]any {
+	var schema map[string]any
+	if err := decodeJSONNumbers([]byte(ConfigSchema), &schema); err != nil {
+		panic(fmt.Sprintf("invalid ConfigSchema: %v", err))
+	}
+	return schema
+})
+
+// decodeJSONNumbers decodes JSON keeping numbers as json.Number
+func decodeJSONNumbers(data []byte, v any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(v)
+}
+
+// validateConfigSchema checks the structure of a configuration against ConfigSchema and returns
+// the violations sorted by path, e.g. "environments.dev.env_file must be a string". Only the
+// structural keywords are checked: type, properties, required, additionalProperties, items and
+// oneOf. Value rules such as enum and pattern are left to the checks of generation, which
+// report the offending value.
+func validateConfigSchema(document any) []string {
+	var violations []string
+	validateSchema(parsedConfigSchema(), document, "", &violations)
+	sort.Strings(violations)
+	return violations
+}
+
+// schemaPath joins a property name to the path of its object
+func schemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// schemaTypeNames are the names of JSON Schema types used in violations
+var schemaTypeNames = map[string]string{
+	"string":  "a string",
+	"integer": "an integer",
+	"number":  "a number",
+	"boolean": "a boolean",
+	"object":  "an object",
+	"array":   "an array",
+}
+
+// hasSchemaType reports whether a decoded JSON value has a JSON Schema type
+func hasSchemaType(value any, schemaType string) bool {
+	switch v := value.(type) {
+	case string:
+		return schemaType == "string"
+	case bool:
+		return schemaType == "boolean"
+	case map[string]any:
+		return schemaType == "object"
+	case []any:
+		return schemaType == "array"
+	case json.Number:
+		if schemaType == "number" {
+			return true
+		}
+		_, ok := new(big.Int).SetString(v.String(), 10)
+		return schemaType == "integer" && ok
+	}
+	return false
+}
+
+// validateSchema appends the violations of value against schema to violations
+func validateSchema(schema map[string]any, value any, path string, violations *[]string) {
+	subject := path
+	if subject == "" {
+		subject = "configuration"
+	}
+
+	if schemaType, ok := schema["type"].(string); ok && !hasSchemaType(value, schemaType) {
+		*violations = append(*violations, fmt.Sprintf("%s must be %s", subject, schemaTypeNames[schemaType]))
+		return
+	}
+	if branches, ok := schema["oneOf"].([]any); ok {
+		validateOneOf(branches, value, subject, violations)
+	}
+
+	switch v := value.(type) {
+	case []any:
+		if items, ok := schema["items"].(map[string]any); ok {
+			for i, item := range v {
+				validateSchema(items, item, fmt.Sprintf("%s[%d]", path, i), violations)
+			}
+		}
+	case map[string]any:
+		validateObject(schema, v, path, subject, violations)
+	}
+}
+
+// validateObject appends the violations of the properties of an object
+func validateObject(schema map[string]any, object map[string]any, path, subject string, violations *[]string) {
+	properties, _ := schema["properties"].(map[string]any)
+	if required, ok := schema["required"].([]any); ok {
+		for _, name := range required {
+			if _, exists := object[name.(string)]; !exists {
+				*violations = append(*violations, fmt.Sprintf("%s is required", schemaPath(path, name.(string))))
+			}
+		}
+	}
+
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if property, ok := properties[name].(map[string]any); ok {
+			validateSchema(property, object[name], schemaPath(path, name), violations)
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case map[string]any:
+			validateSchema(additional, object[name], schemaPath(path, name), violations)
+		case bool:
+			if additional {
+				continue
+			}
+			violation := fmt.Sprintf("%s has unknown property %q", subject, name)
+			if suggestion := closestName(name, properties); suggestion != "" {
+				violation += fmt.Sprintf(", did you mean %q?", suggestion)
+			}
+			*violations = append(*violations, violation)
+		}
+	}
+}
+
+// validateOneOf appends a violation unless exactly one branch matches. Branches only requiring
+// a property are reported by property name, e.g. "only one of env_file and consul can be set".
+func validateOneOf(branches []any, value any, subject string, violations *[]string) {
+	var matching, alternatives []string
+	requiredOnly := true
+	for _, branch := range branches {
+		branchSchema := branch.(map[string]any)
+		var branchViolations []string
+		validateSchema(branchSchema, value, "", &branchViolations)
+
+		description := describeSchema(branchSchema)
+		if required, ok := branchSchema["required"].([]any); ok && len(branchSchema) == 1 && len(required) == 1 {
+			description = required[0].(string)
+		} else {
+			requiredOnly = false
+		}
+		alternatives = append(alternatives, description)
+		if len(branchViolations) == 0 {
+			matching = append(matching, description)
+		}
+	}
+
+	switch {
+	case len(matching) == 1:
+	case requiredOnly && len(matching) == 0:
+		*violations = append(*violations, fmt.Sprintf("%s must set one of %s", subject, joinAnd(alternatives, "or")))
+	case requiredOnly:
+		*violations = append(*violations, fmt.Sprintf("%s: only one of %s can be set", subject, joinAnd(matching, "and")))
+	default:
+		*violations = append(*violations, fmt.Sprintf("%s must be %s", subject, joinAnd(alternatives, "or")))
+	}
+}
+
+// describeSchema describes the values accepted by a schema, e.g. "a string matching ^[0-9]+$"
+func describeSchema(schema map[string]any) string {
+	schemaType, _ := schema["type"].(string)
+	description := schemaTypeNames[schemaType]
+	if pattern, ok := schema["pattern"].(string); ok {
+		description += " matching " + pattern
+	}
+	return description
+}
+
+// joinAnd joins names as "a, b and c" with the given last conjunction
+func joinAnd(names []string, conjunction string) string {
+	if len(names) <= 1 {
+		return strings.Join(names, "")
+	}
+	return strings.Join(names[:len(names)-1], ", ") + " " + conjunction + " " + names[len(names)-1]
+}
+
+// closestName returns the property name within two edits of name, empty if there is none
+func closestName(name string, properties map[string]any) string {
+	best, bestDistance := "", 3
+	for candidate := range properties {
+		if distance := editDistance(name, candidate); distance < bestDistance || (distance == bestDistance && candidate < best) {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance counting a swap of adjacent characters as one edit
+func editDistance(a, b string) int {
+	rows := make([][]int, len(a)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(b)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			rows[i][j] = min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				rows[i][j] = min(rows[i][j], rows[i-2][j-2]+1)
+			}
+		}
+	}
+	return rows[len(a)][len(b)]
+}
@@ -0,0 +1,129 @@
+package envied
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// defaultNamespace is the Kubernetes namespace of sealed secrets when emit.namespace is not set
+const defaultNamespace = "default"
+
+// isSensitive reports whether the value of a variable must not be written in plain text:
+// it is obfuscated in generated code, injected at link time or marked sensitive
+func (envData mergedEnvironment) isSensitive(name string) bool {
+	return envData.Obfuscated[name] != nil || envData.Sensitive[name] || envData.Linked[name] != ""
+}
+
+// loadSealingKey reads the public key of a Sealed Secrets controller from a PEM certificate,
+// as printed by kubeseal --fetch-cert, or a PEM public key
+func loadSealingKey(path string) (*rsa.PublicKey, error) {
+	if path == "" {
+		return nil, newError(MessageSealedSecretsCertRequired)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, newError(MessageFailedReadSealedSecretsCert, err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, newError(MessageNotPEMFile, path)
+	}
+
+	var publicKey any
+	switch block.Type {
+	case "CERTIFICATE":
+		certificate, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, newError(MessageInvalidCertificate, path, err)
+		}
+		publicKey = certificate.PublicKey
+	case "PUBLIC KEY":
+		if publicKey, err = x509.ParsePKIXPublicKey(block.Bytes); err != nil {
+			return nil, newError(MessageInvalidPublicKey, path, err)
+		}
+	default:
+		return nil, newError(MessageUnexpectedPEMBlock, path, block.Type)
+	}
+
+	rsaKey, ok := publicKey.(*rsa.PublicKey)
+	if !ok {
+		return nil, newError(MessageNotRSAPublicKey, path)
+	}
+	return rsaKey, nil
+}
+
+// sealValue encrypts a value like kubeseal: a random AES-256 session key encrypted with
+// RSA-OAEP SHA-256 under label, prefixed with its 2-byte length, followed by the value
+// encrypted with AES-256-GCM using the session key and a zero nonce
+func sealValue(key *rsa.PublicKey, value, label []byte) ([]byte, error) {
+	sessionKey := make([]byte, 32)
+	if _, err := rand.Read(sessionKey); err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key, sessionKey, label)
+	if err != nil {
+		return nil, err
+	}
+	sealed := binary.BigEndian.AppendUint16(nil, uint16(len(encryptedKey)))
+	sealed = append(sealed, encryptedKey...)
+	// The session key encrypts a single value, so a zero nonce is never reused
+	return gcm.Seal(sealed, make([]byte, gcm.NonceSize()), value, nil), nil
+}
+
+// renderSealedSecret renders a Bitnami SealedSecret with the sensitive values of an environment,
+// sealed with the strict scope of the Secret name and namespace and keyed by names
+func renderSealedSecret(envName string, envData mergedEnvironment, name, namespace string, key *rsa.PublicKey, names map[string]string) ([]byte, error) {
+	label := []byte(namespace + "/" + name)
+
+	var encrypted bytes.Buffer
+	for _, field := range envData.Fields {
+		if !envData.isSensitive(field.EnvName) {
+			continue
+		}
+		sealed, err := sealValue(key, []byte(field.Value), label)
+		if err != nil {
+			return nil, newError(MessageFailedSeal, field.EnvName, err)
+		}
+		fmt.Fprintf(&encrypted, "    %s: %s\n", exportedName(names, field.EnvName), base64.StdEncoding.EncodeToString(sealed))
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "# %s\n", strings.TrimPrefix(generatedHeader, "// "))
+	fmt.Fprintf(&buf, "# Environment: %s\n", envName)
+	fmt.Fprintf(&buf, "apiVersion: bitnami.com/v1alpha1\nkind: SealedSecret\n")
+	fmt.Fprintf(&buf, "metadata:\n  name: %s\n  namespace: %s\n", name, namespace)
+	if encrypted.Len() == 0 {
+		fmt.Fprintf(&buf, "spec:\n  encryptedData: {}\n")
+	} else {
+		fmt.Fprintf(&buf, "spec:\n  encryptedData:\n")
+		buf.Write(encrypted.Bytes())
+	}
+	fmt.Fprintf(&buf, "  template:\n    metadata:\n      name: %s\n      namespace: %s\n", name, namespace)
+	return buf.Bytes(), nil
+}
+
+// sealedSecretPath returns the path of the SealedSecret of an environment in the sealed secrets directory
+func sealedSecretPath(dir, envName string) string {
+	return filepath.Join(dir, "sealedsecret-"+envName+".yaml")
+}
@@ -0,0 +1,40 @@
+package envied
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+)
+
+// Seed is a 64-bit random seed used for deterministic obfuscation.
+// In JSON it can be written either as an integer or as a decimal string,
+// the latter being safe for tools that store numbers as float64.
+type Seed int64
+
+// UnmarshalJSON decodes a seed from a JSON integer or decimal string
+func (s *Seed) UnmarshalJSON(data []byte) error {
+	text := strings.TrimSpace(string(data))
+	if text == "null" {
+		*s = 0
+		return nil
+	}
+
+	if strings.HasPrefix(text, `"`) {
+		unquoted, err := strconv.Unquote(text)
+		if err != nil {
+			return newError(MessageInvalidRandomSeed, text, err)
+		}
+		text = strings.TrimSpace(unquoted)
+	}
+
+	value, err := strconv.ParseInt(text, 10, 64)
+	if err != nil {
+		if errors.Is(err, strconv.ErrRange) {
+			return newError(MessageRandomSeedOutRange, text, int64(-1<<63), int64(1<<63-1))
+		}
+		return newError(MessageRandomSeedNotInteger, text)
+	}
+
+	*s = Seed(value)
+	return nil
+}
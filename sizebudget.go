@@ -0,0 +1,123 @@
+package envied
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxBudgetBreakdown limits the variables listed when an environment exceeds its size budget
+const maxBudgetBreakdown = 10
+
+// SizeBudgetConfig limits the size of the values embedded into generated code, protecting the
+// binary size budgets of mobile and edge targets
+type SizeBudgetConfig struct {
+	Environment int `json:"environment,omitempty"` // Maximum embedded bytes of an environment, unlimited if 0
+	Variable    int `json:"variable,omitempty"`    // Maximum embedded bytes of a variable, unlimited if 0
+}
+
+// embeddedSize returns the bytes a field embeds into the binary: the value literal, or the key
+// and data of an obfuscated value
+func embeddedSize(field Field, obfuscated *ObfuscationResult) int {
+	if obfuscated == nil {
+		return len(field.Value)
+	}
+	size := dataSize(obfuscated.Key)
+	if obfuscated.ValueName != field.EnvName {
+		size += dataSize(obfuscated.Value)
+	}
+	return size
+}
+
+// dataSize returns the size in memory of obfuscated data
+func dataSize(value any) int {
+	const intSize = 8
+	switch value := value.(type) {
+	case []byte:
+		return len(value)
+	case []int:
+		return intSize * len(value)
+	case [][]byte:
+		size := 0
+		for _, element := range value {
+			size += len(element)
+		}
+		return size
+	case [][]int:
+		size := 0
+		for _, element := range value {
+			size += intSize * len(element)
+		}
+		return size
+	default:
+		return len(fmt.Sprint(value))
+	}
+}
+
+// formatSize formats a size in bytes
+func formatSize(size int) string {
+	if size < 1024 {
+		return fmt.Sprintf("%d B", size)
+	}
+	return fmt.Sprintf("%.1f KiB", float64(size)/1024)
+}
+
+// checkSizeBudget fails for variables embedding more than their limit and for environments
+// embedding more than size_budget.environment, listing the largest variables
+func checkSizeBudget(configFile *ConfigFile, data *mergedConfig) error {
+	budget := configFile.SizeBudget
+	if budget == nil {
+		budget = &SizeBudgetConfig{}
+	}
+	if budget.Environment < 0 || budget.Variable < 0 {
+		return newError(MessageNegativeSizeBudget)
+	}
+
+	for _, envName := range sortedEnvironmentNames(data.Environments) {
+		envData := data.Environments[envName]
+		sizes := make(map[string]int, len(envData.Fields))
+		names := make([]string, 0, len(envData.Fields))
+		total := 0
+		for _, field := range envData.Fields {
+			if _, linked := envData.Linked[field.EnvName]; linked {
+				continue // Injected at link time, nothing is embedded
+			}
+			size := embeddedSize(field, envData.Obfuscated[field.EnvName])
+			limit := budget.Variable
+			if maxBytes := configFile.Variables[field.EnvName].MaxBytes; maxBytes > 0 {
+				limit = maxBytes
+			}
+			if limit > 0 && size > limit {
+				return newError(MessageVariableOverSizeLimit, field.EnvName, envName, formatSize(size), formatSize(limit))
+			}
+			sizes[field.EnvName] = size
+			names = append(names, field.EnvName)
+			total += size
+		}
+		if budget.Environment == 0 || total <= budget.Environment {
+			continue
+		}
+
+		// Largest first, so the breakdown starts with the variables worth shrinking
+		sort.SliceStable(names, func(i, j int) bool {
+			if sizes[names[i]] != sizes[names[j]] {
+				return sizes[names[i]] > sizes[names[j]]
+			}
+			return names[i] < names[j]
+		})
+		var breakdown strings.Builder
+		for i, name := range names {
+			if i == maxBudgetBreakdown {
+				fmt.Fprintf(&breakdown, "\n  ... %d more", len(names)-i)
+				break
+			}
+			obfuscated := ""
+			if envData.Obfuscated[name] != nil {
+				obfuscated = " (obfuscated)"
+			}
+			fmt.Fprintf(&breakdown, "\n  %s: %s%s", name, formatSize(sizes[name]), obfuscated)
+		}
+		return newError(MessageEnvironmentOverSizeBudget, envName, formatSize(total), formatSize(budget.Environment), breakdown.String())
+	}
+	return nil
+}
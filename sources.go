@@ -0,0 +1,221 @@
+package envied
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultSourceTimeout bounds how long generation waits for each remote source
+const DefaultSourceTimeout = 30 * time.Second
+
+// sourceTimeout returns how long generation waits for each remote source
+func (c *ConfigFile) sourceTimeout() (time.Duration, error) {
+	if c.SourceTimeout == "" {
+		return DefaultSourceTimeout, nil
+	}
+	timeout, err := time.ParseDuration(c.SourceTimeout)
+	if err != nil || timeout <= 0 {
+		return 0, newError(MessageInvalidSourceTimeout, c.SourceTimeout)
+	}
+	return timeout, nil
+}
+
+// sourceTimeoutError points at source_timeout if a remote source failed because it did not answer in time
+func sourceTimeoutError(ctx context.Context, timeout time.Duration, err error) error {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return newError(MessageSourceTimeout, err, timeout)
+	}
+	return err
+}
+
+// remoteSources returns the names of the generation-time sources configured instead of env_file
+func (e EnvironmentConfig) remoteSources() []string {
+	var sources []string
+	if e.Consul != nil {
+		sources = append(sources, "consul")
+	}
+	if e.Etcd != nil {
+		sources = append(sources, "etcd")
+	}
+	if e.Redis != nil {
+		sources = append(sources, "redis")
+	}
+	if e.SQL != nil {
+		sources = append(sources, "sql")
+	}
+	return sources
+}
+
+// withEnvFile returns the environment reading the given env file instead of its configured source,
+// values from Vault included
+func (e EnvironmentConfig) withEnvFile(envFile string) EnvironmentConfig {
+	e.EnvFile = envFile
+	e.Consul = nil
+	e.Etcd = nil
+	e.Redis = nil
+	e.SQL = nil
+	e.Vault = nil
+	return e
+}
+
+// readEnvironment reads the variables of an environment from its env file or remote source
+// layered over its base env file and with its overlays layered on top, and returns them with their provenance and the SHA-256
+// of the source contents. A remote source must answer within timeout.
+func readEnvironment(envConfig EnvironmentConfig, timeout time.Duration) (map[string]EnvValue, map[string]Provenance, string, error) {
+	envVars, sourceHash, err := readSource(envConfig, timeout)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	provenance, layerHashes, err := layerOverlays(envConfig, envVars)
+	if err != nil {
+		return nil, nil, "", err
+	}
+	return envVars, provenance, layeredSourceHash(sourceHash, layerHashes), nil
+}
+
+// readSource reads the variables of an environment from its env file or remote source
+// and returns them with the SHA-256 of the source contents
+func readSource(envConfig EnvironmentConfig, timeout time.Duration) (map[string]EnvValue, string, error) {
+	remote := envConfig.remoteSources()
+	switch {
+	case len(remote) > 1 || (len(remote) == 1 && envConfig.EnvFile != ""):
+		return nil, "", newError(MessageEnvFileAndSource, strings.Join(remote, ", "))
+	case len(remote) == 1:
+		ctx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		values, err := fetchRemoteSource(ctx, envConfig, timeout)
+		if err != nil {
+			return nil, "", sourceTimeoutError(ctx, timeout, err)
+		}
+		return remoteValues(values), hashValues(values), nil
+	case envConfig.EnvFile == "":
+		return nil, "", newError(MessageEnvFileNotSet)
+	}
+
+	envVars, err := readEnvFile(envConfig)
+	if err != nil {
+		return nil, "", newError(MessageFailedReadEnvFile, envConfig.EnvFile, err)
+	}
+	sourceHash, err := hashFile(envConfig.EnvFile)
+	if err != nil {
+		return nil, "", newError(MessageFailedHashEnvFile, envConfig.EnvFile, err)
+	}
+	return envVars, sourceHash, nil
+}
+
+// fetchRemoteSource reads the variables of the remote source of an environment, HTTP requests
+// time out after timeout besides the deadline of ctx
+func fetchRemoteSource(ctx context.Context, envConfig EnvironmentConfig, timeout time.Duration) (map[string]string, error) {
+	switch {
+	case envConfig.Consul != nil:
+		values, _, err := envConfig.Consul.fetch(ctx, &http.Client{Timeout: timeout}, 0)
+		return values, err
+	case envConfig.Etcd != nil:
+		client, err := newEtcdClient(ctx, *envConfig.Etcd)
+		if err != nil {
+			return nil, err
+		}
+		client.http.Timeout = timeout
+		values, _, err := client.fetch(ctx)
+		return values, err
+	case envConfig.Redis != nil:
+		return envConfig.Redis.fetch(ctx)
+	default:
+		return envConfig.SQL.fetch(ctx)
+	}
+}
+
+// remoteValues converts values of a remote source, which are never quoted
+func remoteValues(values map[string]string) map[string]EnvValue {
+	envVars := make(map[string]EnvValue, len(values))
+	for name, value := range values {
+		envVars[name] = EnvValue{Value: value}
+	}
+	return envVars
+}
+
+// hashValues returns the hex encoded SHA-256 of the values rendered as a sorted env file
+func hashValues(values map[string]string) string {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	hash := sha256.New()
+	for _, name := range names {
+		fmt.Fprintf(hash, "%s=%s\n", name, values[name])
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// prefixVariableName maps a key below prefix to a variable name, nested keys are joined with '_'.
+// Keys outside the prefix and folders are skipped.
+func prefixVariableName(prefix, key string) (string, bool) {
+	if prefix != "/" {
+		if !strings.HasPrefix(key, prefix) {
+			return "", false
+		}
+		key = strings.TrimPrefix(key, prefix)
+	}
+	if key == "" || strings.HasSuffix(key, "/") {
+		return "", false
+	}
+	return strings.ReplaceAll(strings.TrimPrefix(key, "/"), "/", "_"), true
+}
+
+// watchedValues holds the latest variables of a watched runtime source
+type watchedValues struct {
+	mu     sync.RWMutex
+	values MapSource
+}
+
+// Lookup returns the latest known value of a variable
+func (w *watchedValues) Lookup(name string) (string, bool) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	return w.values.Lookup(name)
+}
+
+// Values returns a copy of the latest known variables
+func (w *watchedValues) Values() MapSource {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	values := make(MapSource, len(w.values))
+	for name, value := range w.values {
+		values[name] = value
+	}
+	return values
+}
+
+// update replaces the variables and reports whether they changed
+func (w *watchedValues) update(values map[string]string) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.values != nil && sameValues(w.values, values) {
+		return false
+	}
+	w.values = values
+	return true
+}
+
+// sameValues reports whether two variable maps are equal
+func sameValues(a, b map[string]string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for name, value := range a {
+		if other, exists := b[name]; !exists || other != value {
+			return false
+		}
+	}
+	return true
+}
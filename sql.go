@@ -0,0 +1,98 @@
+package envied
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+)
+
+// SQLConfig configures reading variables from a database table at generation time.
+// The query must return two columns, the variable name and its value. The database/sql
+// driver must be registered by the generator, for example by importing _ "github.com/lib/pq"
+// or _ "github.com/go-sql-driver/mysql" in the program calling GenerateFromConfigFile.
+type SQLConfig struct {
+	Driver string `json:"driver"`            // Registered database/sql driver name, e.g. postgres or mysql
+	DSN    string `json:"dsn,omitempty"`     // Data source name, prefer dsn_env for DSNs with passwords
+	DSNEnv string `json:"dsn_env,omitempty"` // Environment variable holding the data source name
+	Query  string `json:"query"`             // Query returning name and value columns
+}
+
+// describe returns a human-readable location of the variables
+func (c SQLConfig) describe() string {
+	if c.DSNEnv != "" {
+		return fmt.Sprintf("%s database from $%s", c.Driver, c.DSNEnv)
+	}
+	return fmt.Sprintf("%s database", c.Driver)
+}
+
+// dsn returns the configured data source name
+func (c SQLConfig) dsn() (string, error) {
+	switch {
+	case c.DSN != "" && c.DSNEnv != "":
+		return "", newError(MessageDSNAndDSNEnv)
+	case c.DSN != "":
+		return c.DSN, nil
+	case c.DSNEnv == "":
+		return "", newError(MessageDSNMissing)
+	}
+
+	dsn := os.Getenv(c.DSNEnv)
+	if dsn == "" {
+		return "", newError(MessageDSNEnvNotSet, c.DSNEnv)
+	}
+	return dsn, nil
+}
+
+// fetch runs the query and returns the variables it selects
+func (c SQLConfig) fetch(ctx context.Context) (map[string]string, error) {
+	if !slices.Contains(sql.Drivers(), c.Driver) {
+		return nil, newError(MessageSQLDriverNotRegistered, c.Driver, sql.Drivers())
+	}
+	if c.Query == "" {
+		return nil, newError(MessageSQLQueryNotSet)
+	}
+	dsn, err := c.dsn()
+	if err != nil {
+		return nil, err
+	}
+
+	db, err := sql.Open(c.Driver, dsn)
+	if err != nil {
+		return nil, newError(MessageFailedOpen, c.describe(), err)
+	}
+	defer db.Close()
+
+	rows, err := db.QueryContext(ctx, c.Query)
+	if err != nil {
+		return nil, newError(MessageFailedQuery, c.describe(), err)
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) != 2 {
+		return nil, newError(MessageSQLQueryColumns, len(columns))
+	}
+
+	values := make(map[string]string)
+	for rows.Next() {
+		var name string
+		var value sql.NullString
+		if err := rows.Scan(&name, &value); err != nil {
+			return nil, newError(MessageFailedReadRow, c.describe(), err)
+		}
+		if _, exists := values[name]; exists {
+			return nil, newError(MessageSQLDuplicateVariable, name)
+		}
+		// NULL values are read as empty strings
+		values[name] = value.String
+	}
+	if err := rows.Err(); err != nil {
+		return nil, newError(MessageFailedQuery, c.describe(), err)
+	}
+	return values, nil
+}
@@ -0,0 +1,153 @@
+package envied
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DefaultSeparator separates the elements of []string variables when separator is not set
+const DefaultSeparator = ","
+
+// applySeparators types variables with a configured separator as []string
+func applySeparators(fields []Field, variables map[string]VariableConfig) error {
+	for i := range fields {
+		variable := variables[fields[i].EnvName]
+		if variable.Separator == "" {
+			continue
+		}
+		if variable.TimeLayout != "" {
+			return newError(MessageSeparatorAndTimeLayout, fields[i].EnvName)
+		}
+		fields[i].Type = FieldTypeStringSlice
+		fields[i].Separator = variable.Separator
+	}
+	return nil
+}
+
+// SplitList splits a value into the elements of a []string variable, surrounding whitespace
+// of each element is trimmed. An empty value has no elements.
+func SplitList(value, separator string) []string {
+	if value == "" {
+		return []string{}
+	}
+	if separator == "" {
+		separator = DefaultSeparator
+	}
+
+	elements := strings.Split(value, separator)
+	for i, element := range elements {
+		elements[i] = strings.TrimSpace(element)
+	}
+	return elements
+}
+
+// JoinList formats the elements of a []string variable as in a .env file
+func JoinList(elements []string, separator string) string {
+	if separator == "" {
+		separator = DefaultSeparator
+	}
+	return strings.Join(elements, separator)
+}
+
+// CoerceStringSlice converts a layered value to []string
+func CoerceStringSlice(name, separator, value string) ([]string, error) {
+	return SplitList(value, separator), nil
+}
+
+// elementName is the name sealed elements of a []string variable are bound to,
+// so elements can't be reordered without failing to open
+func elementName(name string, index int) string {
+	return fmt.Sprintf("%s[%d]", name, index)
+}
+
+// obfuscateList obfuscates every element of a []string value independently
+func obfuscateList(algorithm, name, value, separator string, seed int64) (*ObfuscationResult, error) {
+	elements := SplitList(value, separator)
+	result := &ObfuscationResult{
+		KeyName:   fmt.Sprintf("_enviedkey%s", name),
+		ValueName: fmt.Sprintf("_envieddata%s", name),
+	}
+
+	if algorithm == ObfuscationAESGCM {
+		keys := make([][]byte, len(elements))
+		data := make([][]byte, len(elements))
+		for i, element := range elements {
+			var err error
+			if keys[i], data[i], err = SealString(elementName(name, i), element, seed); err != nil {
+				return nil, err
+			}
+		}
+		result.Key, result.Value = keys, data
+		return result, nil
+	}
+
+	keys := make([][]int, len(elements))
+	data := make([][]int, len(elements))
+	for i, element := range elements {
+		// Every element gets its own key stream, also with a fixed seed
+		elementSeed := seed
+		if seed != 0 {
+			elementSeed += int64(i)
+		}
+		keys[i], data[i] = ObfuscateString(element, elementSeed)
+	}
+	result.Key, result.Value = keys, data
+	return result, nil
+}
+
+// MustDecodeStrings decodes the XOR obfuscated elements of a []string variable and panics on error.
+// Generated code uses it, since a failure means the generated file is corrupted.
+func MustDecodeStrings(keys, encryptedValues [][]int) []string {
+	if len(keys) != len(encryptedValues) {
+		panic(newError(MessageRuntimeObfuscatedListMismatch, len(keys), len(encryptedValues)))
+	}
+	elements := make([]string, len(keys))
+	for i := range keys {
+		elements[i] = MustDecodeString(keys[i], encryptedValues[i])
+	}
+	return elements
+}
+
+// MustOpenStrings opens the sealed elements of a []string variable and panics on error.
+// Generated code uses it, since a failure means the generated file is corrupted.
+func MustOpenStrings(name string, keys, data [][]byte) []string {
+	if len(keys) != len(data) {
+		panic(newError(MessageRuntimeSealedListMismatch, name, len(keys), len(data)))
+	}
+	elements := make([]string, len(keys))
+	for i := range keys {
+		elements[i] = MustOpenString(elementName(name, i), keys[i], data[i])
+	}
+	return elements
+}
+
+// joinNestedInts formats nested int slices as "{1, 2}, {3}" for [][]int literals
+func joinNestedInts(values [][]int) string {
+	var b strings.Builder
+	for i, element := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("{")
+		for j, v := range element {
+			if j > 0 {
+				b.WriteString(", ")
+			}
+			fmt.Fprintf(&b, "%d", v)
+		}
+		b.WriteString("}")
+	}
+	return b.String()
+}
+
+// joinNestedBytes formats nested byte slices as "{0x01}, {0x02}" for [][]byte literals
+func joinNestedBytes(values [][]byte) string {
+	var b strings.Builder
+	for i, element := range values {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		b.WriteString("{" + joinBytes(element) + "}")
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package envied
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// substitutionPattern matches {{.Name}} placeholders of build-time substitutions
+var substitutionPattern = regexp.MustCompile(`\{\{\s*\.([A-Za-z_][A-Za-z0-9_]*)\s*\}\}`)
+
+// substitutionValues returns the substitutions of an environment: its own over those of the
+// configuration, with values set by GenerateOptions.Set taking precedence over both
+func (c *ConfigFile) substitutionValues(envConfig EnvironmentConfig) map[string]string {
+	values := make(map[string]string, len(c.Substitutions)+len(envConfig.Substitutions)+len(c.substitutionOverrides))
+	for _, layer := range []map[string]string{c.Substitutions, envConfig.Substitutions, c.substitutionOverrides} {
+		for name, value := range layer {
+			values[name] = value
+		}
+	}
+	return values
+}
+
+// applySubstitutions replaces {{.Name}} placeholders in the values of an environment with the
+// substitution of the name. Without substitutions values are left untouched, so values holding
+// templates of their own keep working; with substitutions unknown placeholders are errors.
+func applySubstitutions(envVars map[string]EnvValue, provenance map[string]Provenance, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(envVars))
+	for name := range envVars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		envValue := envVars[name]
+		var err error
+		envValue.Value = substitutionPattern.ReplaceAllStringFunc(envValue.Value, func(placeholder string) string {
+			substitution := substitutionPattern.FindStringSubmatch(placeholder)[1]
+			value, exists := values[substitution]
+			if !exists && err == nil {
+				variable := name
+				if source := provenance[name].Source; source != "" {
+					variable = fmt.Sprintf("%s (%s)", name, source)
+				}
+				err = newError(MessageUndefinedSubstitution, variable, placeholder)
+			}
+			return value
+		})
+		if err != nil {
+			return err
+		}
+		envVars[name] = envValue
+	}
+	return nil
+}
@@ -0,0 +1,198 @@
+package envied
+
+import (
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+// TemplateFuncs returns the functions available to configuration templates.
+//
+// Case conversion:
+//
+//	upper, lower   - strings.ToUpper / strings.ToLower
+//	title          - "database url" -> "Database Url"
+//	camel, pascal  - "DATABASE_URL" -> "databaseUrl" / "DatabaseUrl"
+//	snake, kebab   - "DatabaseURL" -> "database_url" / "database-url"
+//
+// Quoting:
+//
+//	quote          - Go double-quoted string literal
+//	squote         - single-quoted string
+//	backquote      - Go raw string literal (falls back to quote if not possible)
+//
+// Chunking:
+//
+//	chunk N S      - splits S into pieces of at most N runes
+//	joinInts S     - formats []int as "1, 2, 3" for slice literals
+//
+// Obfuscation:
+//
+//	obfuscate V K           - Obfuscate(V, K)
+//	deobfuscate V K         - Deobfuscate(V, K)
+//	obfuscateString V SEED  - ObfuscationResult with per-rune Key and Value slices
+//
+// Code generation:
+//
+//	runtimeImport P         - import spec for the envied runtime package P
+//	usesTime FIELDS         - whether any field is a time.Time, so "time" must be imported
+//	isMapType TYPE          - whether a field type is a map decoded from JSON
+//
+// Additional functions (for example sprig.TxtFuncMap()) can be supplied
+// through Config.Funcs; they take precedence over the built-in ones.
+func TemplateFuncs() template.FuncMap {
+	return template.FuncMap{
+		"upper":         strings.ToUpper,
+		"lower":         strings.ToLower,
+		"title":         toTitle,
+		"camel":         toCamel,
+		"pascal":        toPascal,
+		"snake":         toSnake,
+		"kebab":         toKebab,
+		"quote":         strconv.Quote,
+		"squote":        func(s string) string { return "'" + strings.ReplaceAll(s, "'", "\\'") + "'" },
+		"backquote":     toBackquote,
+		"chunk":         chunkString,
+		"joinInts":      joinInts,
+		"obfuscate":     Obfuscate,
+		"deobfuscate":   Deobfuscate,
+		"runtimeImport": runtimeImportSpec,
+		"usesTime":      usesTime,
+		"isMapType":     isMapType,
+		"obfuscateString": func(value string, seed int64) ObfuscationResult {
+			keys, values := ObfuscateString(value, seed)
+			return ObfuscationResult{Key: keys, Value: values}
+		},
+	}
+}
+
+// newTemplate creates a template with built-in and user supplied functions
+func newTemplate(name string, funcs template.FuncMap) *template.Template {
+	tmpl := template.New(name).Funcs(TemplateFuncs())
+	if len(funcs) > 0 {
+		tmpl = tmpl.Funcs(funcs)
+	}
+	return tmpl
+}
+
+// splitWords splits an identifier into words on '_', '-', spaces and case changes
+func splitWords(s string) []string {
+	var words []string
+	var current []rune
+	runes := []rune(s)
+
+	flush := func() {
+		if len(current) > 0 {
+			words = append(words, string(current))
+			current = nil
+		}
+	}
+
+	for i, r := range runes {
+		if r == '_' || r == '-' || unicode.IsSpace(r) {
+			flush()
+			continue
+		}
+		if unicode.IsUpper(r) && len(current) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if unicode.IsLower(prev) || unicode.IsDigit(prev) || (unicode.IsUpper(prev) && nextLower) {
+				flush()
+			}
+		}
+		current = append(current, r)
+	}
+	flush()
+
+	return words
+}
+
+// capitalize upper-cases the first rune and lower-cases the rest
+func capitalize(word string) string {
+	runes := []rune(strings.ToLower(word))
+	if len(runes) > 0 {
+		runes[0] = unicode.ToUpper(runes[0])
+	}
+	return string(runes)
+}
+
+// toTitle converts "database url" to "Database Url"
+func toTitle(s string) string {
+	words := strings.Fields(s)
+	for i, word := range words {
+		words[i] = capitalize(word)
+	}
+	return strings.Join(words, " ")
+}
+
+// toPascal converts "DATABASE_URL" to "DatabaseUrl"
+func toPascal(s string) string {
+	var b strings.Builder
+	for _, word := range splitWords(s) {
+		b.WriteString(capitalize(word))
+	}
+	return b.String()
+}
+
+// toCamel converts "DATABASE_URL" to "databaseUrl"
+func toCamel(s string) string {
+	var b strings.Builder
+	for i, word := range splitWords(s) {
+		if i == 0 {
+			b.WriteString(strings.ToLower(word))
+		} else {
+			b.WriteString(capitalize(word))
+		}
+	}
+	return b.String()
+}
+
+// toSnake converts "DatabaseURL" to "database_url"
+func toSnake(s string) string {
+	words := splitWords(s)
+	for i, word := range words {
+		words[i] = strings.ToLower(word)
+	}
+	return strings.Join(words, "_")
+}
+
+// toKebab converts "DatabaseURL" to "database-url"
+func toKebab(s string) string {
+	return strings.ReplaceAll(toSnake(s), "_", "-")
+}
+
+// toBackquote returns a Go raw string literal, or a quoted literal if s contains a backquote
+func toBackquote(s string) string {
+	if strconv.CanBackquote(s) {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(s)
+}
+
+// chunkString splits s into pieces of at most size runes
+func chunkString(size int, s string) []string {
+	if size <= 0 {
+		return []string{s}
+	}
+
+	runes := []rune(s)
+	var chunks []string
+	for len(runes) > size {
+		chunks = append(chunks, string(runes[:size]))
+		runes = runes[size:]
+	}
+	if len(runes) > 0 || len(chunks) == 0 {
+		chunks = append(chunks, string(runes))
+	}
+	return chunks
+}
+
+// joinInts formats a slice of ints as a comma separated list
+func joinInts(values []int) string {
+	parts := make([]string, len(values))
+	for i, v := range values {
+		parts[i] = strconv.Itoa(v)
+	}
+	return strings.Join(parts, ", ")
+}
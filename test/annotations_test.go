@@ -0,0 +1,81 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateWithAnnotations(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.Annotations = &envied.AnnotationsConfig{
+			Nolint:         []string{"revive", "unparam"},
+			CoverageIgnore: true,
+			Comments:       []string{"//lint:file-ignore U1000 generated code"},
+		}
+	})
+
+	expected := "//coverage:ignore\n//lint:file-ignore U1000 generated code\n//nolint:revive,unparam\npackage config\n"
+	if !strings.Contains(content, expected) {
+		t.Errorf("Generated code should contain the annotations above the package clause:\n%s", content)
+	}
+
+	// The directives must not break compilation
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	fmt.Println(config.Environments)
+}
+`)
+	if err != nil {
+		t.Fatalf("Generated code failed: %v\n%s", err, output)
+	}
+}
+
+func TestGenerateWithoutAnnotations(t *testing.T) {
+	_, content := generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	if strings.Contains(content, "//nolint") || strings.Contains(content, "//coverage:ignore") {
+		t.Error("Generated code should not contain annotations unless configured")
+	}
+}
+
+func TestInvalidAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		annotations envied.AnnotationsConfig
+		expected    string
+	}{
+		{"linter name", envied.AnnotationsConfig{Nolint: []string{"all\npackage evil"}}, "invalid linter name"},
+		{"not a comment", envied.AnnotationsConfig{Comments: []string{"var x = 1"}}, "must be a single-line // comment"},
+		{"multi-line comment", envied.AnnotationsConfig{Comments: []string{"// a\nvar x = 1"}}, "must be a single-line // comment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			annotations := tt.annotations
+			_, configPath := writeConfig(t, map[string]string{
+				"dev": "API_URL=https://dev.example.com\n",
+			}, func(config *envied.ConfigFile) {
+				config.Annotations = &annotations
+			})
+
+			err := envied.GenerateFromConfigFile(configPath)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("GenerateFromConfigFile() error = %v, expected %q", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// writeBaseConfig creates dev and prod environments overriding a shared base env file
+func writeBaseConfig(t *testing.T, envs map[string]string) (string, string) {
+	t.Helper()
+
+	return writeConfig(t, envs, func(config *envied.ConfigFile) {
+		dir := filepath.Dir(config.OutputDir)
+		baseFile := filepath.Join(dir, "base.env")
+		if err := os.WriteFile(baseFile, []byte("API_URL=https://localhost\nPORT=8080\nDEBUG=false\n"), 0644); err != nil {
+			t.Fatalf("Failed to create base env file: %v", err)
+		}
+		for envName, envConfig := range config.Environments {
+			envConfig.BaseEnvFile = baseFile
+			config.Environments[envName] = envConfig
+		}
+	})
+}
+
+func TestBaseEnvFile(t *testing.T) {
+	tempDir, configPath := writeBaseConfig(t, map[string]string{
+		"dev":  "DEBUG=true\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\n",
+	})
+	baseFile := filepath.Join(tempDir, "base.env")
+	prodFile := filepath.Join(tempDir, "prod.env")
+
+	output := captureStdout(t, func() {
+		if err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath, Verbose: true}); err != nil {
+			t.Errorf("Generate() returned error: %v", err)
+		}
+	})
+	for _, want := range []string{
+		"🧭 dev/API_URL: " + baseFile + ":1\n",
+		"🧭 prod/API_URL: " + prodFile + ":1 (overrides " + baseFile + ":1)\n",
+		"🧭 prod/DEBUG: " + baseFile + ":3\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Verbose output should contain %q, got:\n%s", want, output)
+		}
+	}
+
+	if output, err := runGenerated(t, tempDir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	dev, prod := config.NewDevConfig(), config.NewProdConfig()
+	fmt.Println(dev.GetAPI_URL(), dev.GetPORT(), dev.GetDEBUG())
+	fmt.Println(prod.GetAPI_URL(), prod.GetPORT(), prod.GetDEBUG())
+}
+`); err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	} else if output != "https://localhost 8080 true\nhttps://api.example.com 80 false\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+
+	if err := envied.Verify(envied.GenerateOptions{ConfigPath: configPath}); err != nil {
+		t.Fatalf("Verify() returned error right after generation: %v", err)
+	}
+	if err := os.WriteFile(baseFile, []byte("API_URL=https://localhost\nPORT=8081\nDEBUG=false\n"), 0644); err != nil {
+		t.Fatalf("Failed to update base env file: %v", err)
+	}
+	if err := envied.Verify(envied.GenerateOptions{ConfigPath: configPath}); err == nil {
+		t.Error("Verify() should report a changed base env file")
+	}
+}
+
+func TestBaseEnvFileConsistency(t *testing.T) {
+	_, configPath := writeBaseConfig(t, map[string]string{
+		"dev":  "DEBUG=true\n",
+		"prod": "SECRET=prod-secret\n",
+	})
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "variable 'SECRET' is missing in environment 'dev'") {
+		t.Errorf("GenerateFromConfigFile() = %v, expected the merged environments to be checked", err)
+	}
+}
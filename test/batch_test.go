@@ -0,0 +1,127 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateAll(t *testing.T) {
+	firstDir, firstConfig := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://first.example.com\n",
+	}, nil)
+	secondDir, secondConfig := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://second.example.com\n",
+	}, nil)
+
+	results, err := envied.GenerateAll([]envied.Target{
+		{Name: "first", ConfigPath: firstConfig},
+		{Name: "second", ConfigPath: secondConfig},
+	})
+	if err != nil {
+		t.Fatalf("GenerateAll() returned error: %v", err)
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+
+	expectedOutputs := []string{
+		filepath.Join(firstDir, "config", "config_env.gen.go"),
+		filepath.Join(secondDir, "config", "config_env.gen.go"),
+	}
+	for i, result := range results {
+		if !result.Succeeded() {
+			t.Errorf("Result %d failed: %v", i, result.Err)
+		}
+		if result.OutputFile != expectedOutputs[i] {
+			t.Errorf("Result %d OutputFile = %q, expected %q", i, result.OutputFile, expectedOutputs[i])
+		}
+		if _, err := os.Stat(result.OutputFile); err != nil {
+			t.Errorf("Result %d output file not found: %v", i, err)
+		}
+	}
+}
+
+func TestGenerateAllWarningsAndMessages(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_KEY=changeme\n",
+	}, nil)
+
+	var messages []envied.MessageID
+	var results []envied.Result
+	var err error
+	output := captureStdout(t, func() {
+		results, err = envied.GenerateAll([]envied.Target{
+			{ConfigPath: configPath, Messages: func(message envied.Message) {
+				messages = append(messages, message.ID)
+			}},
+			{ConfigPath: configPath},
+		})
+	})
+	if err != nil {
+		t.Fatalf("GenerateAll() returned error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("GenerateAll() printed %q", output)
+	}
+	if !slices.Contains(messages, envied.MessageGenerated) {
+		t.Errorf("Messages handler got %v, expected %s", messages, envied.MessageGenerated)
+	}
+
+	for i, result := range results {
+		if len(result.Warnings) != 1 || result.Warnings[0].Code != envied.WarningPlaceholder {
+			t.Errorf("Result %d warnings = %+v, expected a placeholder warning", i, result.Warnings)
+		}
+	}
+}
+
+func TestGenerateAllStopsOnFailure(t *testing.T) {
+	_, validConfig := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+	missingConfig := filepath.Join(t.TempDir(), "missing.json")
+
+	results, err := envied.GenerateAll([]envied.Target{
+		{Name: "broken", ConfigPath: missingConfig},
+		{Name: "valid", ConfigPath: validConfig},
+	})
+	if err == nil {
+		t.Fatal("Expected error for failing target")
+	}
+	if !strings.Contains(err.Error(), "target 'broken'") {
+		t.Errorf("Error %q should name the failing target", err.Error())
+	}
+	if len(results) != 1 {
+		t.Errorf("Expected processing to stop after the failing target, got %d results", len(results))
+	}
+}
+
+func TestGenerateAllContinuesOnError(t *testing.T) {
+	_, validConfig := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+	missingConfig := filepath.Join(t.TempDir(), "missing.json")
+
+	results, err := envied.GenerateAll([]envied.Target{
+		{Name: "broken", ConfigPath: missingConfig, ContinueOnError: true},
+		{Name: "valid", ConfigPath: validConfig},
+	})
+	if err == nil {
+		t.Fatal("Expected error for failing target")
+	}
+
+	if len(results) != 2 {
+		t.Fatalf("Expected 2 results, got %d", len(results))
+	}
+	if results[0].Succeeded() || results[0].OutputFile != "" {
+		t.Error("First target should have failed")
+	}
+	if !results[1].Succeeded() {
+		t.Errorf("Second target should have succeeded: %v", results[1].Err)
+	}
+}
@@ -0,0 +1,254 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestBuildTags(t *testing.T) {
+	envs := map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}
+	dir, _ := generateConfig(t, envs, func(config *envied.ConfigFile) {
+		config.BuildTags = true
+	})
+
+	guard, err := os.ReadFile(filepath.Join(dir, "config", "config_env_buildtags.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read guard file: %v", err)
+	}
+	if !strings.Contains(string(guard), "//go:build !((envied_dev && !envied_prod) || (!envied_dev && envied_prod))") {
+		t.Errorf("Unexpected guard file:\n%s", guard)
+	}
+
+	mainSrc := `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	cfg := config.NewConfig()
+	fmt.Println(cfg.Environment(), cfg.GetAPI_URL())
+}
+`
+	tests := []struct {
+		name     string
+		tags     string
+		expected string
+	}{
+		{"dev", "envied_dev", "dev https://dev.example.com\n"},
+		{"prod", "envied_prod", "prod https://api.example.com\n"},
+		{"no tag", "", "undefined: enviedBuildRequiresExactlyOneEnvironmentTag"},
+		{"both tags", "envied_dev,envied_prod", "undefined: enviedBuildRequiresExactlyOneEnvironmentTag"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output, err := runGenerated(t, dir, mainSrc, "-tags="+tt.tags)
+			if strings.HasPrefix(tt.expected, "undefined") {
+				if err == nil || !strings.Contains(output, tt.expected) {
+					t.Errorf("Build should fail with %q, got %v:\n%s", tt.expected, err, output)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Failed to run program: %v\n%s", err, output)
+			}
+			if output != tt.expected {
+				t.Errorf("Output = %q, expected %q", output, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildTagsCheck(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.BuildTags = true
+	})
+	opts := envied.GenerateOptions{ConfigPath: configPath}
+
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+	if err := envied.Check(opts); err != nil {
+		t.Fatalf("Check() after generation returned error: %v", err)
+	}
+
+	if err := os.Remove(filepath.Join(tempDir, "config", "config_env_prod.gen.go")); err != nil {
+		t.Fatalf("Failed to remove stub: %v", err)
+	}
+	if err := envied.Check(opts); !errors.Is(err, envied.ErrOutdated) {
+		t.Errorf("Check() with a missing stub = %v, want ErrOutdated", err)
+	}
+}
+
+func TestBuildTagsRejectInvalidEnvironmentNames(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev-eu": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.BuildTags = true
+		config.Environments["dev-eu"] = envied.EnvironmentConfig{EnvFile: config.Environments["dev-eu"].EnvFile, StructName: "DevEu"}
+	})
+
+	err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+	if err == nil || !strings.Contains(err.Error(), "build_tags requires environment names") {
+		t.Errorf("Validate() = %v, expected an invalid environment name error", err)
+	}
+}
+
+func TestSplitEnvironments(t *testing.T) {
+	envs := map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nAPI_KEY=dev-key\nDEV_TOOLS=true\n",
+		"prod": "API_URL=https://api.example.com\nAPI_KEY=prod-key\n",
+	}
+	dir, content := generateConfig(t, envs, func(config *envied.ConfigFile) {
+		config.BuildTags = true
+		config.SplitEnvironments = true
+		config.AllowExtraVariables = true
+		obfuscate := false
+		dev := config.Environments["dev"]
+		dev.Obfuscate = &obfuscate
+		config.Environments["dev"] = dev
+	})
+
+	for _, unexpected := range []string{"DevConfig", "ProdConfig", "dev-key"} {
+		if strings.Contains(content, unexpected) {
+			t.Errorf("Shared file contains %q", unexpected)
+		}
+	}
+	dev, err := os.ReadFile(filepath.Join(dir, "config", "config_env_dev.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read the file of dev: %v", err)
+	}
+	for _, expected := range []string{"//go:build envied_dev", "type DevConfig struct", "type DevInterface interface", `"dev-key"`} {
+		if !strings.Contains(string(dev), expected) {
+			t.Errorf("File of dev is missing %q:\n%s", expected, dev)
+		}
+	}
+	if strings.Contains(string(dev), "ProdConfig") {
+		t.Errorf("File of dev must not contain prod:\n%s", dev)
+	}
+
+	mainSrc := `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	cfg := config.NewConfig()
+	_, err := config.NewEnvironmentConfig("prod")
+	fmt.Println(cfg.Environment(), cfg.GetAPI_KEY(), err)
+}
+`
+	tests := []struct {
+		tags     string
+		expected string
+	}{
+		{"envied_dev", "dev dev-key unknown environment 'prod', valid environments: dev\n"},
+		{"envied_prod", "prod prod-key <nil>\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tags, func(t *testing.T) {
+			output, err := runGenerated(t, dir, mainSrc, "-tags="+tt.tags)
+			if err != nil {
+				t.Fatalf("Failed to run program: %v\n%s", err, output)
+			}
+			if output != tt.expected {
+				t.Errorf("Output = %q, expected %q", output, tt.expected)
+			}
+		})
+	}
+
+	opts := envied.GenerateOptions{ConfigPath: filepath.Join(dir, envied.DefaultConfigFileName)}
+	if err := envied.Check(opts); err != nil {
+		t.Errorf("Check() after generation returned error: %v", err)
+	}
+	if err := envied.Verify(opts); err != nil {
+		t.Errorf("Verify() after generation returned error: %v", err)
+	}
+	if err := envied.Generate(opts); err != nil {
+		t.Errorf("Generate() over split files returned error: %v", err)
+	}
+}
+
+func TestSplitEnvironmentsRequiresBuildTags(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.SplitEnvironments = true
+	})
+
+	err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+	if err == nil || !strings.Contains(err.Error(), "split_environments requires build_tags") {
+		t.Errorf("Validate() = %v, expected split_environments to require build_tags", err)
+	}
+}
+
+func TestBuildTagPrefix(t *testing.T) {
+	prefix := ""
+	dir, _ := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.BuildTags = true
+		config.SplitEnvironments = true
+		config.BuildTagPrefix = &prefix
+	})
+
+	prod, err := os.ReadFile(filepath.Join(dir, "config", "config_env_prod.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read the file of prod: %v", err)
+	}
+	if !strings.Contains(string(prod), "//go:build prod\n") {
+		t.Errorf("File of prod is not selected by the prod tag:\n%s", prod)
+	}
+
+	mainSrc := `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	fmt.Println(config.NewConfig().GetAPI_URL())
+}
+`
+	output, err := runGenerated(t, dir, mainSrc, "-tags=prod")
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if output != "https://api.example.com\n" {
+		t.Errorf("Output = %q, expected the prod value", output)
+	}
+}
+
+func TestBuildTagPrefixRejectsReservedTags(t *testing.T) {
+	prefix := ""
+	_, configPath := writeConfig(t, map[string]string{
+		"linux": "API_URL=https://linux.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.BuildTags = true
+		config.BuildTagPrefix = &prefix
+	})
+
+	err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+	if err == nil || !strings.Contains(err.Error(), "build tag 'linux' of environment 'linux' is set by the go command") {
+		t.Errorf("Validate() = %v, expected a reserved build tag error", err)
+	}
+}
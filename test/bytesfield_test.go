@@ -0,0 +1,101 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// bytesFieldProgram prints the binary variables of the generated prod configuration
+const bytesFieldProgram = `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+	"github.com/petrovyuri/go-envied"
+)
+
+func main() {
+	prod := config.NewProdConfig()
+	fmt.Printf("%x %q\n", prod.GetSIGNING_KEY(), prod.GetNONCE())
+
+	value, _ := prod.Lookup("SIGNING_KEY")
+	fmt.Println(value)
+
+	cfg, err := config.NewLayeredConfig("prod", envied.MapSource{"NONCE": "00ff"})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%x\n", cfg.GetNONCE())
+
+	_, err = config.NewLayeredConfig("prod", envied.MapSource{"SIGNING_KEY": "not base64!"})
+	fmt.Println(err)
+}
+`
+
+func TestBytesFields(t *testing.T) {
+	for _, obfuscation := range []string{envied.ObfuscationNone, envied.ObfuscationXOR, envied.ObfuscationAESGCM} {
+		t.Run(obfuscation, func(t *testing.T) {
+			dir, content := generateConfig(t, map[string]string{
+				"dev":  "SIGNING_KEY=AAEC\nNONCE=6465762d6e6f6e6365\n",
+				"prod": "SIGNING_KEY=3q2+7w==\nNONCE=70726f642d6e6f6e6365\n",
+			}, func(config *envied.ConfigFile) {
+				config.Obfuscation = obfuscation
+				config.Variables = map[string]envied.VariableConfig{
+					"SIGNING_KEY": {Type: "[]byte"},
+					"NONCE":       {Type: "[]byte", Encoding: envied.EncodingHex},
+				}
+			})
+
+			if !strings.Contains(content, "GetSIGNING_KEY() []byte") {
+				t.Error("Generated code should contain a []byte getter")
+			}
+			if obfuscation != envied.ObfuscationNone && strings.Contains(content, "3q2+7w==") {
+				t.Error("Binary values should be obfuscated")
+			}
+
+			output, err := runGenerated(t, dir, bytesFieldProgram)
+			if err != nil {
+				t.Fatalf("Failed to run program: %v\n%s", err, output)
+			}
+
+			expected := "deadbeef \"prod-nonce\"\n3q2+7w==\n00ff\n❌ ERROR: variable SIGNING_KEY: invalid base64 value\n"
+			if output != expected {
+				t.Errorf("Output = %q, expected %q", output, expected)
+			}
+		})
+	}
+}
+
+func TestBytesFieldErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		variable envied.VariableConfig
+		expected string
+	}{
+		{"invalid value", envied.VariableConfig{Type: "[]byte", Encoding: envied.EncodingHex}, "value is not valid hex"},
+		{"unknown encoding", envied.VariableConfig{Type: "[]byte", Encoding: "base32"}, `unknown encoding "base32"`},
+		{"encoding without type", envied.VariableConfig{Encoding: envied.EncodingHex}, `encoding requires "type": "[]byte"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variable := tt.variable
+			_, configPath := writeConfig(t, map[string]string{
+				"dev": "SIGNING_KEY=secret-zz\n",
+			}, func(config *envied.ConfigFile) {
+				config.Variables = map[string]envied.VariableConfig{"SIGNING_KEY": variable}
+			})
+
+			err := envied.GenerateFromConfigFile(configPath)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("GenerateFromConfigFile() error = %v, expected %q", err, tt.expected)
+			}
+			if err != nil && strings.Contains(err.Error(), "secret-zz") {
+				t.Error("Errors must not contain the secret value")
+			}
+		})
+	}
+}
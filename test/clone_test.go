@@ -0,0 +1,99 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestCloneEnvironment(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "# envied: sensitive=true\nAPI_URL=https://api.example.com\nPORT=\"8\n0\"\nexport ANALYTICS_KEY=secret\n",
+	}, func(config *envied.ConfigFile) {
+		config.Variables = map[string]envied.VariableConfig{"ANALYTICS_KEY": {Only: []string{"prod"}}}
+	})
+
+	envFile, err := envied.CloneEnvironment(configPath, "prod", "staging", envied.CloneOptions{})
+	if err != nil {
+		t.Fatalf("CloneEnvironment() returned error: %v", err)
+	}
+	if expected := filepath.Join(tempDir, "staging.env"); envFile != expected {
+		t.Errorf("Env file = %s, expected %s", envFile, expected)
+	}
+
+	content, err := os.ReadFile(envFile)
+	if err != nil {
+		t.Fatalf("Failed to read cloned env file: %v", err)
+	}
+	if expected := "# envied: sensitive=true\nAPI_URL=\nPORT=\nexport ANALYTICS_KEY=\n"; string(content) != expected {
+		t.Errorf("Cloned env file = %q, expected %q", content, expected)
+	}
+
+	config, err := envied.LoadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+	staging, exists := config.Environments["staging"]
+	if !exists || staging.EnvFile != envFile || staging.StructName != "Staging" {
+		t.Errorf("Environment staging = %+v, expected to be declared with %s", staging, envFile)
+	}
+	if only := config.Variables["ANALYTICS_KEY"].Only; !slices.Equal(only, []string{"prod", "staging"}) {
+		t.Errorf("ANALYTICS_KEY only = %v, expected prod and staging", only)
+	}
+}
+
+func TestCloneEnvironmentCopyValues(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+
+	envFile := filepath.Join(tempDir, "stages", "qa.env")
+	if _, err := envied.CloneEnvironment(configPath, "prod", "qa", envied.CloneOptions{EnvFile: envFile, CopyValues: true}); err != nil {
+		t.Fatalf("CloneEnvironment() returned error: %v", err)
+	}
+	if content, _ := os.ReadFile(envFile); string(content) != "API_URL=https://api.example.com\n" {
+		t.Errorf("Cloned env file = %q, expected the values of prod", content)
+	}
+	if err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath}); err != nil {
+		t.Errorf("Validate() returned error for the cloned configuration: %v", err)
+	}
+}
+
+func TestCloneEnvironmentErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		from, to string
+		expected string
+	}{
+		{"unknown source", "staging", "qa", "unknown environment 'staging'"},
+		{"existing environment", "prod", "dev", "environment 'dev' already exists"},
+		{"existing env file", "prod", "local", "local.env already exists"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, configPath := writeConfig(t, map[string]string{
+				"dev":  "API_URL=https://dev.example.com\n",
+				"prod": "API_URL=https://api.example.com\n",
+			}, nil)
+			if err := os.WriteFile(filepath.Join(tempDir, "local.env"), nil, 0644); err != nil {
+				t.Fatalf("Failed to create env file: %v", err)
+			}
+
+			_, err := envied.CloneEnvironment(configPath, tt.from, tt.to, envied.CloneOptions{})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("CloneEnvironment() = %v, expected error containing %q", err, tt.expected)
+			}
+			if unknown := (*envied.ErrUnknownEnvironment)(nil); tt.from == "staging" && !errors.As(err, &unknown) {
+				t.Errorf("CloneEnvironment() = %v, expected ErrUnknownEnvironment", err)
+			}
+		})
+	}
+}
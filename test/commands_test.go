@@ -0,0 +1,320 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestValidate(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+
+	if err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath}); err != nil {
+		t.Fatalf("Validate() returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "config")); !os.IsNotExist(err) {
+		t.Error("Validate() must not write the output directory")
+	}
+}
+
+func TestValidateReportsInconsistentEnvironments(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nDEBUG=true\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+
+	if err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath}); err == nil {
+		t.Fatal("Validate() expected error for a variable missing in prod")
+	}
+}
+
+func TestGenerateWithOutputAndEnvOverride(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+	})
+
+	staging := filepath.Join(tempDir, "staging.env")
+	if err := os.WriteFile(staging, []byte("API_URL=https://staging.example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to create env file: %v", err)
+	}
+	outputFile := filepath.Join(tempDir, "out", "config_env.gen.go")
+
+	err := envied.Generate(envied.GenerateOptions{
+		ConfigPath: configPath,
+		OutputFile: outputFile,
+		EnvFiles:   map[string]string{"prod": staging},
+	})
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "staging.example.com") {
+		t.Error("Generated file should contain the value of the overriding env file")
+	}
+
+	err = envied.Generate(envied.GenerateOptions{
+		ConfigPath: configPath,
+		OutputFile: outputFile,
+		EnvFiles:   map[string]string{"qa": staging},
+	})
+	if err == nil {
+		t.Error("Generate() expected error for an env file of an unknown environment")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	envs := map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}
+	tempDir, configPath := writeConfig(t, envs, nil)
+	opts := envied.GenerateOptions{ConfigPath: configPath}
+
+	if err := envied.Check(opts); !errors.Is(err, envied.ErrOutdated) {
+		t.Fatalf("Check() before generation = %v, want ErrOutdated", err)
+	}
+
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+	if err := envied.Check(opts); err != nil {
+		t.Fatalf("Check() after generation returned error: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, "prod.env"), []byte("API_URL=https://new.example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to update env file: %v", err)
+	}
+	err := envied.Check(opts)
+	if !errors.Is(err, envied.ErrOutdated) {
+		t.Fatalf("Check() after changing an env file = %v, want ErrOutdated", err)
+	}
+	if !strings.Contains(err.Error(), "differs at line") {
+		t.Errorf("Check() error should point at the first difference, got: %v", err)
+	}
+}
+
+func TestCheckRandomObfuscationKeys(t *testing.T) {
+	for _, algorithm := range []string{envied.ObfuscationXOR, envied.ObfuscationAESGCM} {
+		t.Run(algorithm, func(t *testing.T) {
+			tempDir, configPath := writeConfig(t, map[string]string{
+				"dev": "API_KEY=secret\nHOSTS=a.example.com,b.example.com\n",
+			}, func(config *envied.ConfigFile) {
+				config.RandomSeed = 0
+				config.Obfuscation = algorithm
+				config.Variables = map[string]envied.VariableConfig{"HOSTS": {Separator: ","}}
+			})
+			opts := envied.GenerateOptions{ConfigPath: configPath}
+
+			if err := envied.GenerateFromConfigFile(configPath); err != nil {
+				t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+			}
+			if err := envied.Check(opts); err != nil {
+				t.Fatalf("Check() after generation with random keys returned error: %v", err)
+			}
+
+			// A value of the same length is detected by decoding
+			if err := os.WriteFile(filepath.Join(tempDir, "dev.env"), []byte("API_KEY=public\nHOSTS=a.example.com,b.example.com\n"), 0644); err != nil {
+				t.Fatalf("Failed to update env file: %v", err)
+			}
+			if err := envied.Check(opts); !errors.Is(err, envied.ErrOutdated) {
+				t.Errorf("Check() after changing an obfuscated value = %v, want ErrOutdated", err)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	envs := map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}
+	tempDir, configPath := writeConfig(t, envs, nil)
+	opts := envied.GenerateOptions{ConfigPath: configPath}
+
+	if err := envied.Verify(opts); !errors.Is(err, envied.ErrOutdated) {
+		t.Fatalf("Verify() before generation = %v, want ErrOutdated", err)
+	}
+
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+	if err := envied.Verify(opts); err != nil {
+		t.Fatalf("Verify() after generation returned error: %v", err)
+	}
+
+	// Only the raw contents are hashed, a comment is a change as well
+	if err := os.WriteFile(filepath.Join(tempDir, "prod.env"), []byte("# edited\nAPI_URL=https://api.example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to update env file: %v", err)
+	}
+	err := envied.Verify(opts)
+	if !errors.Is(err, envied.ErrOutdated) {
+		t.Fatalf("Verify() after changing an env file = %v, want ErrOutdated", err)
+	}
+	if !strings.Contains(err.Error(), "prod:") || strings.Contains(err.Error(), "dev:") {
+		t.Errorf("Verify() error should name only the changed environment, got: %v", err)
+	}
+}
+
+func TestVerifyValueReceiver(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.Getters = &envied.GettersConfig{Receiver: envied.GetterReceiverValue}
+	})
+
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+	if err := envied.Verify(envied.GenerateOptions{ConfigPath: configPath}); err != nil {
+		t.Errorf("Verify() with value receivers returned error: %v", err)
+	}
+}
+
+func TestExplain(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":     "# API\nAPI_URL=https://dev.example.com\nPORT=8080\n",
+		"staging": "PORT=8080\nAPI_URL=https://api.example.com\n",
+		"prod":    "API_URL=https://api.example.com\nPORT=80\nDEBUG=false\n",
+	}, func(config *envied.ConfigFile) {
+		config.AllowExtraVariables = true
+		config.Obfuscation = envied.ObfuscationXOR
+	})
+	opts := envied.GenerateOptions{ConfigPath: configPath}
+
+	explanation, err := envied.Explain(opts, "API_URL")
+	if err != nil {
+		t.Fatalf("Explain() returned error: %v", err)
+	}
+	if len(explanation.Environments) != 3 || !explanation.ValuesDiffer {
+		t.Fatalf("Unexpected explanation: %+v", explanation)
+	}
+	dev, prod, staging := explanation.Environments[0], explanation.Environments[1], explanation.Environments[2]
+	if dev.Source != filepath.Join(tempDir, "dev.env")+":2" || staging.Source != filepath.Join(tempDir, "staging.env")+":2" {
+		t.Errorf("Sources = %q, %q, expected file:line", dev.Source, staging.Source)
+	}
+	if dev.ValueGroup != 1 || prod.ValueGroup != 2 || staging.ValueGroup != 2 {
+		t.Errorf("Value groups = %d, %d, %d, expected 1, 2, 2", dev.ValueGroup, prod.ValueGroup, staging.ValueGroup)
+	}
+	if dev.Obfuscation != envied.ObfuscationXOR || len(dev.Identifiers) != 4 || dev.Identifiers[2] != "dev_enviedkeyAPI_URL" {
+		t.Errorf("Unexpected dev explanation: %+v", dev)
+	}
+	if strings.Join(explanation.Identifiers, ",") != "ConfigInterface.GetAPI_URL,WithAPI_URL" {
+		t.Errorf("Shared identifiers = %v", explanation.Identifiers)
+	}
+
+	explanation, err = envied.Explain(opts, "DEBUG")
+	if err != nil {
+		t.Fatalf("Explain() returned error: %v", err)
+	}
+	if strings.Join(explanation.Missing, ",") != "dev,staging" || explanation.ValuesDiffer {
+		t.Errorf("Unexpected explanation of an environment specific variable: %+v", explanation)
+	}
+	if identifiers := explanation.Environments[0].Identifiers; identifiers[len(identifiers)-1] != "ProdInterface.GetDEBUG" {
+		t.Errorf("Identifiers = %v, expected the environment interface getter", identifiers)
+	}
+
+	if _, err := envied.Explain(opts, "MISSING"); err == nil || !strings.Contains(err.Error(), "not defined in any environment") {
+		t.Errorf("Explain() of an unknown variable = %v, expected error", err)
+	}
+}
+
+func TestDiffEnvironments(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\nDEBUG=true\nNAME=app\n",
+		"prod": "API_URL=https://api.example.com\nPORT=high\nTIMEOUT=30\nNAME=app\n",
+	}, nil)
+	opts := envied.GenerateOptions{ConfigPath: configPath}
+
+	differences, err := envied.DiffEnvironments(opts, "dev", "prod")
+	if err != nil {
+		t.Fatalf("DiffEnvironments() returned error: %v", err)
+	}
+
+	expected := []envied.VariableDifference{
+		{Name: "API_URL", Kind: envied.DiffValueChanged, FromType: envied.FieldTypeString, ToType: envied.FieldTypeString},
+		{Name: "DEBUG", Kind: envied.DiffRemoved, FromType: envied.FieldTypeBool},
+		{Name: "PORT", Kind: envied.DiffTypeChanged, FromType: envied.FieldTypeInt, ToType: envied.FieldTypeString},
+		{Name: "TIMEOUT", Kind: envied.DiffAdded, ToType: envied.FieldTypeInt},
+	}
+	if len(differences) != len(expected) {
+		t.Fatalf("DiffEnvironments() = %+v, want %+v", differences, expected)
+	}
+	for i := range expected {
+		if differences[i] != expected[i] {
+			t.Errorf("difference %d = %+v, want %+v", i, differences[i], expected[i])
+		}
+	}
+
+	var unknown *envied.ErrUnknownEnvironment
+	if _, err := envied.DiffEnvironments(opts, "dev", "qa"); !errors.As(err, &unknown) {
+		t.Errorf("DiffEnvironments() with unknown environment = %v, want ErrUnknownEnvironment", err)
+	}
+}
+
+func TestInitProject(t *testing.T) {
+	dir := t.TempDir()
+
+	written, err := envied.InitProject(dir)
+	if err != nil {
+		t.Fatalf("InitProject() returned error: %v", err)
+	}
+	if len(written) != 3 {
+		t.Errorf("InitProject() wrote %v, want two env files and the configuration", written)
+	}
+
+	configFile, err := envied.LoadConfigFile(filepath.Join(dir, envied.DefaultConfigFileName))
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+	if configFile.Environments["prod"].StructName != "Prod" {
+		t.Errorf("prod struct name = %q, want Prod", configFile.Environments["prod"].StructName)
+	}
+
+	if _, err := envied.InitProject(dir); err == nil {
+		t.Error("InitProject() expected error when the configuration already exists")
+	}
+}
+
+func TestGenerateTimings(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+
+	var err error
+	output := captureStdout(t, func() {
+		err = envied.Generate(envied.GenerateOptions{ConfigPath: configPath, Timings: true})
+	})
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	for _, phase := range []string{"⏱️ Timings:", "load configuration", "read environments", "checks", "prepare fields", "write code", "emit files", "total"} {
+		if !strings.Contains(output, phase) {
+			t.Errorf("Generation report should contain %q:\n%s", phase, output)
+		}
+	}
+
+	output = captureStdout(t, func() {
+		err = envied.Generate(envied.GenerateOptions{ConfigPath: configPath})
+	})
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if strings.Contains(output, "⏱️") {
+		t.Errorf("Timings should only be reported on request:\n%s", output)
+	}
+}
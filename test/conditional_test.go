@@ -0,0 +1,90 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateWithConditionalVariable(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nSENTRY_DSN=https://dev-sentry\n",
+		"prod": "API_URL=https://api.example.com\nSENTRY_DSN=https://sentry.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.Variables = map[string]envied.VariableConfig{
+			"SENTRY_DSN": {Only: []string{"prod"}},
+		}
+	})
+
+	iface := content[strings.Index(content, "type ConfigInterface interface"):]
+	iface = iface[:strings.Index(iface, "}")]
+	if strings.Contains(iface, "SENTRY_DSN") {
+		t.Error("Conditional variable should not be part of the shared interface")
+	}
+	if strings.Contains(content, "func (c *DevConfig) GetSENTRY_DSN") {
+		t.Error("Conditional variable should not be generated for dev")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	fmt.Println(config.NewProdConfig().GetSENTRY_DSN())
+	fmt.Println(config.NewDevConfig().GetAPI_URL())
+}
+`)
+	if err != nil {
+		t.Fatalf("Generated code failed: %v\n%s", err, output)
+	}
+
+	expected := "https://sentry.example.com\nhttps://dev.example.com\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestGenerateWithInvalidConditionalVariable(t *testing.T) {
+	tests := []struct {
+		name     string
+		only     []string
+		expected string
+	}{
+		{
+			name:     "missing in enabled environment",
+			only:     []string{"dev", "prod"},
+			expected: "variable 'SENTRY_DSN' is missing in environment 'dev'",
+		},
+		{
+			name:     "unknown environment",
+			only:     []string{"prod", "staging"},
+			expected: "unknown environment 'staging'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{
+				"dev":  "API_URL=https://dev.example.com\n",
+				"prod": "API_URL=https://api.example.com\nSENTRY_DSN=https://sentry.example.com\n",
+			}, func(config *envied.ConfigFile) {
+				config.Variables = map[string]envied.VariableConfig{
+					"SENTRY_DSN": {Only: tt.only},
+				}
+			})
+
+			err := envied.GenerateFromConfigFile(configPath)
+			if err == nil {
+				t.Fatal("Expected error")
+			}
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Error %q should contain %q", err.Error(), tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,303 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestConfigFileSaveRoundTrip(t *testing.T) {
+	tempDir := t.TempDir()
+	configPath := filepath.Join(tempDir, envied.DefaultConfigFileName)
+
+	original := &envied.ConfigFile{
+		PackageName: "config",
+		OutputDir:   "internal/config",
+		RandomSeed:  12345,
+		Environments: map[string]envied.EnvironmentConfig{
+			"prod": {EnvFile: "env/prod.env", StructName: "ProdConfig"},
+			"dev":  {EnvFile: "env/dev.env", StructName: "DevConfig"},
+		},
+	}
+
+	if err := original.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	loaded, err := envied.LoadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+
+	if !reflect.DeepEqual(original, loaded) {
+		t.Errorf("Loaded config = %+v, expected %+v", loaded, original)
+	}
+}
+
+func TestConfigFileSaveDeterministic(t *testing.T) {
+	tempDir := t.TempDir()
+	firstPath := filepath.Join(tempDir, "first.json")
+	secondPath := filepath.Join(tempDir, "second.json")
+
+	config := &envied.ConfigFile{
+		PackageName: "config",
+		OutputDir:   "internal/config",
+		Environments: map[string]envied.EnvironmentConfig{
+			"staging": {EnvFile: "env/staging.env", StructName: "StagingConfig"},
+			"dev":     {EnvFile: "env/dev.env", StructName: "DevConfig"},
+			"prod":    {EnvFile: "env/prod.env", StructName: "ProdConfig"},
+		},
+	}
+
+	if err := config.Save(firstPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if err := config.Save(secondPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	first, _ := os.ReadFile(firstPath)
+	second, _ := os.ReadFile(secondPath)
+	if string(first) != string(second) {
+		t.Error("Save() output is not deterministic")
+	}
+
+	content := string(first)
+	if !strings.HasSuffix(content, "}\n") {
+		t.Error("Saved config should end with a newline")
+	}
+
+	devIndex := strings.Index(content, `"dev"`)
+	prodIndex := strings.Index(content, `"prod"`)
+	stagingIndex := strings.Index(content, `"staging"`)
+	if !(devIndex < prodIndex && prodIndex < stagingIndex) {
+		t.Error("Environments should be written in sorted order")
+	}
+}
+
+// jsonFieldNames returns JSON field names declared by struct tags
+func jsonFieldNames(typ reflect.Type) []string {
+	var names []string
+	for i := 0; i < typ.NumField(); i++ {
+		tag := typ.Field(i).Tag.Get("json")
+		name := strings.Split(tag, ",")[0]
+		if name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// schemaPropertyNames returns property names of a JSON Schema object
+func schemaPropertyNames(schema map[string]interface{}) []string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	var names []string
+	for name := range properties {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestConfigSchemaMatchesConfigFile(t *testing.T) {
+	var schema map[string]interface{}
+	if err := json.Unmarshal([]byte(envied.ConfigSchema), &schema); err != nil {
+		t.Fatalf("ConfigSchema is not valid JSON: %v", err)
+	}
+
+	if schema["$schema"] != envied.ConfigSchemaDraft {
+		t.Errorf("$schema = %v, expected %v", schema["$schema"], envied.ConfigSchemaDraft)
+	}
+
+	expected := jsonFieldNames(reflect.TypeOf(envied.ConfigFile{}))
+	actual := schemaPropertyNames(schema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("ConfigSchema properties = %v, expected %v", actual, expected)
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	environments := properties["environments"].(map[string]interface{})
+	environmentSchema := environments["additionalProperties"].(map[string]interface{})
+
+	expected = jsonFieldNames(reflect.TypeOf(envied.EnvironmentConfig{}))
+	actual = schemaPropertyNames(environmentSchema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Environment schema properties = %v, expected %v", actual, expected)
+	}
+
+	environmentProperties := environmentSchema["properties"].(map[string]interface{})
+	consulSchema := environmentProperties["consul"].(map[string]interface{})
+
+	expected = jsonFieldNames(reflect.TypeOf(envied.ConsulConfig{}))
+	actual = schemaPropertyNames(consulSchema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Consul schema properties = %v, expected %v", actual, expected)
+	}
+
+	etcdSchema := environmentProperties["etcd"].(map[string]interface{})
+
+	expected = jsonFieldNames(reflect.TypeOf(envied.EtcdConfig{}))
+	actual = schemaPropertyNames(etcdSchema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Etcd schema properties = %v, expected %v", actual, expected)
+	}
+
+	redisSchema := environmentProperties["redis"].(map[string]interface{})
+
+	expected = jsonFieldNames(reflect.TypeOf(envied.RedisConfig{}))
+	actual = schemaPropertyNames(redisSchema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Redis schema properties = %v, expected %v", actual, expected)
+	}
+
+	sqlSchema := environmentProperties["sql"].(map[string]interface{})
+
+	expected = jsonFieldNames(reflect.TypeOf(envied.SQLConfig{}))
+	actual = schemaPropertyNames(sqlSchema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("SQL schema properties = %v, expected %v", actual, expected)
+	}
+
+	variables := properties["variables"].(map[string]interface{})
+	variableSchema := variables["additionalProperties"].(map[string]interface{})
+
+	expected = jsonFieldNames(reflect.TypeOf(envied.VariableConfig{}))
+	actual = schemaPropertyNames(variableSchema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Variable schema properties = %v, expected %v", actual, expected)
+	}
+
+	emitSchema := properties["emit"].(map[string]interface{})
+
+	expected = jsonFieldNames(reflect.TypeOf(envied.EmitConfig{}))
+	actual = schemaPropertyNames(emitSchema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Emit schema properties = %v, expected %v", actual, expected)
+	}
+
+	annotationsSchema := properties["annotations"].(map[string]interface{})
+
+	expected = jsonFieldNames(reflect.TypeOf(envied.AnnotationsConfig{}))
+	actual = schemaPropertyNames(annotationsSchema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Annotations schema properties = %v, expected %v", actual, expected)
+	}
+
+	gettersSchema := properties["getters"].(map[string]interface{})
+
+	expected = jsonFieldNames(reflect.TypeOf(envied.GettersConfig{}))
+	actual = schemaPropertyNames(gettersSchema)
+	if !reflect.DeepEqual(expected, actual) {
+		t.Errorf("Getters schema properties = %v, expected %v", actual, expected)
+	}
+}
+
+func TestSeedDecoding(t *testing.T) {
+	tests := []struct {
+		name        string
+		input       string
+		expected    envied.Seed
+		expectError bool
+	}{
+		{
+			name:     "integer",
+			input:    `{"random_seed": 12345}`,
+			expected: 12345,
+		},
+		{
+			name:     "max int64",
+			input:    `{"random_seed": 9223372036854775807}`,
+			expected: 9223372036854775807,
+		},
+		{
+			name:     "min int64 as string",
+			input:    `{"random_seed": "-9223372036854775808"}`,
+			expected: -9223372036854775808,
+		},
+		{
+			name:     "missing",
+			input:    `{}`,
+			expected: 0,
+		},
+		{
+			name:     "null",
+			input:    `{"random_seed": null}`,
+			expected: 0,
+		},
+		{
+			name:        "overflow",
+			input:       `{"random_seed": 9223372036854775808}`,
+			expectError: true,
+		},
+		{
+			name:        "overflow as string",
+			input:       `{"random_seed": "99999999999999999999"}`,
+			expectError: true,
+		},
+		{
+			name:        "float",
+			input:       `{"random_seed": 1.5}`,
+			expectError: true,
+		},
+		{
+			name:        "not a number",
+			input:       `{"random_seed": "abc"}`,
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var config envied.ConfigFile
+			err := json.Unmarshal([]byte(tt.input), &config)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error for %s, got seed %d", tt.input, config.RandomSeed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if config.RandomSeed != tt.expected {
+				t.Errorf("RandomSeed = %d, expected %d", config.RandomSeed, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoadConfigFileSchemaViolations(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"wrong type", `{"package_name": "config", "output_dir": "out", "environments": {"dev": {"env_file": 1, "struct_name": "Dev"}}}`, "environments.dev.env_file must be a string"},
+		{"typo", `{"package_name": "config", "output_dir": "out", "environments": {"dev": {"env_file": "dev.env", "strcut_name": "Dev"}}}`, `environments.dev has unknown property "strcut_name", did you mean "struct_name"?`},
+		{"missing property", `{"package_name": "config", "output_dir": "out", "environments": {"dev": {"env_file": "dev.env"}}}`, "environments.dev.struct_name is required"},
+		{"top-level typo", `{"package_name": "config", "output_dir": "out", "environments": {}, "variabels": {}}`, `configuration has unknown property "variabels", did you mean "variables"?`},
+		{"array item", `{"package_name": "config", "output_dir": "out", "environments": {}, "variables": {"A": {"only": ["prod", 1]}}}`, "variables.A.only[1] must be a string"},
+		{"no source", `{"package_name": "config", "output_dir": "out", "environments": {"dev": {"struct_name": "Dev"}}}`, "environments.dev must set one of env_file, consul, etcd, redis or sql"},
+		{"seed", `{"package_name": "config", "output_dir": "out", "environments": {}, "random_seed": 1.5}`, "random_seed must be an integer or a string matching"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			configPath := filepath.Join(t.TempDir(), envied.DefaultConfigFileName)
+			if err := os.WriteFile(configPath, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create config file: %v", err)
+			}
+			if _, err := envied.LoadConfigFile(configPath); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("LoadConfigFile() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,233 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+const jsonConfig = `{
+  "package_name": "config",
+  "output_dir": "internal/config",
+  "random_seed": 12345,
+  "allow_extra_variables": true,
+  "environments": {
+    "dev": {"env_file": "dev.env", "struct_name": "Dev", "obfuscate": false},
+    "prod": {
+      "env_file": "prod.env",
+      "struct_name": "Prod",
+      "overlays": ["prod.local.env"],
+      "profiles": {"ProdServer": ["PORT", "API_URL"]}
+    }
+  },
+  "variables": {
+    "API_URL": {"description": "Base URL: with a colon", "transform": ["trim", "lower"]},
+    "SECRET": {"only": ["prod"]}
+  },
+  "annotations": {"nolint": ["all"]}
+}
+`
+
+const jsoncConfig = `// go-envied configuration
+{
+  "package_name": "config",
+  "output_dir": "internal/config", // next to the code using it
+  "random_seed": 12345,
+  "allow_extra_variables": true,
+  /* dev is not obfuscated,
+     prod has a local overlay */
+  "environments": {
+    "dev": {"env_file": "dev.env", "struct_name": "Dev", "obfuscate": false,},
+    "prod": {
+      "env_file": "prod.env",
+      "struct_name": "Prod",
+      "overlays": ["prod.local.env",],
+      "profiles": {"ProdServer": ["PORT", "API_URL"]},
+    },
+  },
+  "variables": {
+    "API_URL": {"description": "Base URL: with a colon", "transform": ["trim", "lower"]},
+    "SECRET": {"only": ["prod"]}
+  },
+  "annotations": {"nolint": ["all"]}, // comments in "strings" like "http://x" are kept
+}
+`
+
+const yamlConfig = `# go-envied configuration
+package_name: config
+output_dir: internal/config
+random_seed: 12345
+allow_extra_variables: true
+environments:
+  dev: {env_file: dev.env, struct_name: Dev, obfuscate: false}
+  prod:
+    env_file: prod.env
+    struct_name: Prod
+    overlays:
+    - prod.local.env
+    profiles:
+      ProdServer: [PORT, API_URL]
+variables:
+  API_URL:
+    description: "Base URL: with a colon"  # quoted because of the colon
+    transform:
+      - trim
+      - lower
+  SECRET:
+    only: [prod]
+annotations:
+  nolint: ["all"]
+`
+
+const tomlConfig = `# go-envied configuration
+package_name = "config"
+output_dir = "internal/config"
+random_seed = 12_345
+allow_extra_variables = true
+
+[environments.dev]
+env_file = "dev.env"
+struct_name = "Dev"
+obfuscate = false
+
+[environments.prod]
+env_file = "prod.env"
+struct_name = "Prod"
+overlays = ["prod.local.env"]
+profiles = { ProdServer = ["PORT", "API_URL"] }
+
+[variables]
+API_URL = { description = "Base URL: with a colon", transform = [
+  "trim",
+  "lower", # trailing comma
+] }
+SECRET.only = ["prod"]
+
+[annotations]
+nolint = ['all']
+`
+
+func TestLoadConfigFileFormats(t *testing.T) {
+	dir := t.TempDir()
+	paths := map[string]string{
+		"go-envied-config.json":  jsonConfig,
+		"go-envied-config.jsonc": jsoncConfig,
+		"go-envied-config.yaml":  yamlConfig,
+		"go-envied-config.toml":  tomlConfig,
+	}
+	configs := make(map[string]*envied.ConfigFile)
+	for name, content := range paths {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+		config, err := envied.LoadConfigFile(path)
+		if err != nil {
+			t.Fatalf("LoadConfigFile(%s) returned error: %v", name, err)
+		}
+		configs[name] = config
+	}
+
+	expected := configs["go-envied-config.json"]
+	for _, name := range []string{"go-envied-config.jsonc", "go-envied-config.yaml", "go-envied-config.toml"} {
+		if !reflect.DeepEqual(configs[name], expected) {
+			t.Errorf("LoadConfigFile(%s) = %+v, expected %+v", name, configs[name], expected)
+		}
+	}
+}
+
+func TestLoadConfigFileFormatErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"yaml duplicate key", "package_name: a\npackage_name: b\n", "go-envied-config.yaml:2: key package_name is already defined"},
+		{"yaml sequence of mappings", "annotations:\n  nolint:\n    - name: all\n", "go-envied-config.yaml:3: sequences of mappings are not supported"},
+		{"yaml indentation", "environments:\n  dev:\n    env_file: dev.env\n   struct_name: Dev\n", "go-envied-config.yaml:4: unexpected indentation"},
+		{"yaml unterminated flow", "variables: {A: {only: [prod]}\n", "go-envied-config.yaml:1: key variables: unterminated flow collection"},
+		{"toml bare string", "package_name = config\n", `go-envied-config.toml:1: key package_name: invalid value "config", quote strings`},
+		{"toml duplicate key", "[environments.dev]\nenv_file = \"a\"\nenv_file = \"b\"\n", "go-envied-config.toml:3: key env_file is already defined"},
+		{"toml array of tables", "[[environments]]\n", "go-envied-config.toml:1: arrays of tables are not supported"},
+		{"json unterminated comment", "{\n  /* package_name\n  \"package_name\": \"config\"\n}\n", "go-envied-config.json:2: unterminated /* comment"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name := "go-envied-config.yaml"
+			if strings.HasPrefix(tt.name, "toml") {
+				name = "go-envied-config.toml"
+			}
+			if strings.HasPrefix(tt.name, "json") {
+				name = "go-envied-config.json"
+			}
+			path := filepath.Join(t.TempDir(), name)
+			if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create %s: %v", name, err)
+			}
+			if _, err := envied.LoadConfigFile(path); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("LoadConfigFile() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFindAndGenerateYAMLConfig(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"dev.env":               "API_URL=https://dev.example.com\nPORT=8080\n",
+		"go-envied-config.yaml": "package_name: config\noutput_dir: config\nrandom_seed: 12345\nenvironments:\n  dev:\n    env_file: dev.env\n    struct_name: Dev\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	t.Chdir(dir)
+	configPath := envied.FindConfigFile()
+	if configPath != "go-envied-config.yaml" {
+		t.Fatalf("FindConfigFile() = %q, expected the YAML configuration", configPath)
+	}
+	if err := envied.Generate(envied.GenerateOptions{}); err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "config", "config_env.gen.go")); err != nil {
+		t.Errorf("Generated file should exist: %v", err)
+	}
+
+	config, err := envied.LoadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+	if err := config.Save(configPath); err == nil || !strings.Contains(err.Error(), "only JSON files are written") {
+		t.Errorf("Save() = %v, expected YAML files to be rejected", err)
+	}
+}
+
+func TestSaveKeepsJSONComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "go-envied-config.json")
+	if err := os.WriteFile(path, []byte(jsoncConfig), 0644); err != nil {
+		t.Fatalf("Failed to create config: %v", err)
+	}
+
+	config, err := envied.LoadConfigFile(path)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+	if err := config.Save(path); err == nil || !strings.Contains(err.Error(), "has comments") {
+		t.Errorf("Save() = %v, expected files with comments to be rejected", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if string(content) != jsoncConfig {
+		t.Error("Save() must not rewrite a file with comments")
+	}
+}
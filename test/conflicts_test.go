@@ -0,0 +1,186 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateDetectsPackageConflicts(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	outputDir := filepath.Join(tempDir, "config")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+
+	existing := `package config
+
+type DevConfig struct{}
+
+func (c *DevConfig) Environment() string { return "local" }
+
+var Other = 1
+`
+	if err := os.WriteFile(filepath.Join(outputDir, "existing.go"), []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to create existing.go: %v", err)
+	}
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil {
+		t.Fatal("Expected conflict error")
+	}
+	for _, symbol := range []string{"DevConfig (existing.go)", "DevConfig.Environment (existing.go)"} {
+		if !strings.Contains(err.Error(), symbol) {
+			t.Errorf("Error %q should mention %q", err.Error(), symbol)
+		}
+	}
+	if strings.Contains(err.Error(), "Other") {
+		t.Errorf("Error %q should not mention unrelated declarations", err.Error())
+	}
+
+	// Force strategy generates anyway
+	config, err := envied.LoadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+	config.OnConflict = envied.ConflictForce
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Errorf("GenerateFromConfigFile() with force returned error: %v", err)
+	}
+}
+
+func TestGenerateRenamesConflictingTypes(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+
+	outputDir := filepath.Join(tempDir, "config")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+	existing := "package config\n\ntype DevConfig struct{}\n"
+	if err := os.WriteFile(filepath.Join(outputDir, "existing.go"), []byte(existing), 0644); err != nil {
+		t.Fatalf("Failed to create existing.go: %v", err)
+	}
+
+	opts := envied.GenerateOptions{ConfigPath: configPath, OnConflict: envied.ConflictRename}
+	warnings, err := envied.GenerateWithWarnings(opts)
+	if err != nil {
+		t.Fatalf("GenerateWithWarnings() with rename returned error: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != envied.WarningConflictRenamed || warnings[0].Environment != "dev" {
+		t.Errorf("Expected one conflict_renamed warning for dev, got %+v", warnings)
+	}
+
+	content, err := os.ReadFile(filepath.Join(outputDir, "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	for _, want := range []string{"type EnviedDevConfig struct", "func NewEnviedDevConfig(", "type ProdConfig struct"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Generated file should contain %q", want)
+		}
+	}
+	if err := envied.Check(opts); err != nil {
+		t.Errorf("Check() with rename returned error: %v", err)
+	}
+
+	// Declarations of the package API can't be renamed
+	if err := os.WriteFile(filepath.Join(outputDir, "existing.go"), []byte("package config\n\nfunc NewLayeredConfig() {}\n"), 0644); err != nil {
+		t.Fatalf("Failed to update existing.go: %v", err)
+	}
+	if err := envied.Generate(opts); err == nil || !strings.Contains(err.Error(), "NewLayeredConfig (existing.go)") {
+		t.Errorf("Expected conflict error for NewLayeredConfig, got %v", err)
+	}
+}
+
+func TestGenerateIgnoresPreviousOutputAndExternalTests(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+
+	testFile := `package config_test
+
+func NewDevConfig() {}
+`
+	if err := os.WriteFile(filepath.Join(tempDir, "config", "config_test.go"), []byte(testFile), 0644); err != nil {
+		t.Fatalf("Failed to create config_test.go: %v", err)
+	}
+
+	// Regenerating over the previous output must not report conflicts
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Errorf("Regeneration returned error: %v", err)
+	}
+}
+
+func TestGenerateDetectsSymbolCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		envs     map[string]string
+		modify   func(*envied.ConfigFile)
+		expected string
+	}{
+		{
+			name: "shared struct name",
+			envs: map[string]string{"dev": "API_URL=dev\n", "prod": "API_URL=prod\n"},
+			modify: func(config *envied.ConfigFile) {
+				prod := config.Environments["prod"]
+				prod.StructName = "Dev"
+				config.Environments["prod"] = prod
+			},
+			expected: "environment 'dev' and environment 'prod' both declare DevConfig",
+		},
+		{
+			name: "struct name clashing with ConfigInterface",
+			envs: map[string]string{"dev": "API_URL=dev\nDEBUG=true\n", "prod": "API_URL=prod\n"},
+			modify: func(config *envied.ConfigFile) {
+				dev := config.Environments["dev"]
+				dev.StructName = "Config"
+				config.Environments["dev"] = dev
+				config.Variables = map[string]envied.VariableConfig{"DEBUG": {Only: []string{"dev"}}}
+			},
+			expected: "go-envied and environment 'dev' both declare ConfigInterface",
+		},
+		{
+			name: "variables of different environments sanitized to one field",
+			envs: map[string]string{"dev": "API_URL=dev\nMY-VAR=a\n", "prod": "API_URL=prod\nMY_VAR=b\n"},
+			modify: func(config *envied.ConfigFile) {
+				config.Naming = &envied.NamingConfig{Sanitize: envied.SanitizeReplace}
+				config.Variables = map[string]envied.VariableConfig{"MY-VAR": {Only: []string{"dev"}}, "MY_VAR": {Only: []string{"prod"}}}
+			},
+			expected: "variables MY-VAR and MY_VAR have the same field name MY_VAR",
+		},
+		{
+			name:     "environment prefixes of obfuscated values",
+			envs:     map[string]string{"dev": "API_URL=dev\n", "DEV": "API_URL=prod\n"},
+			expected: "variable API_URL of environment 'DEV' and variable API_URL of environment 'dev' both declare dev_enviedkeyAPI_URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, configPath := writeConfig(t, tt.envs, tt.modify)
+			err := envied.GenerateFromConfigFile(configPath)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("GenerateFromConfigFile() = %v, expected error containing %q", err, tt.expected)
+			}
+			if _, err := os.Stat(filepath.Join(tempDir, "config", "config_env.gen.go")); !os.IsNotExist(err) {
+				t.Error("Generated file must not be written when symbols collide")
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestCheckConsistency(t *testing.T) {
+	environments := map[string]map[string]string{
+		"dev":     {"API_URL": "https://dev.example.com", "DEBUG": "true"},
+		"staging": {"API_URL": "https://staging.example.com"},
+		"prod":    {"PORT": "80", "ANALYTICS_KEY": "secret"},
+	}
+
+	report, err := envied.CheckConsistency(environments)
+	if err == nil || !strings.Contains(err.Error(), "variable 'ANALYTICS_KEY' is missing in environment 'dev'") {
+		t.Errorf("CheckConsistency() = %v, expected the first missing variable", err)
+	}
+	expected := []envied.MissingVariable{
+		{Variable: "ANALYTICS_KEY", Environment: "dev"},
+		{Variable: "ANALYTICS_KEY", Environment: "staging"},
+		{Variable: "API_URL", Environment: "prod"},
+		{Variable: "DEBUG", Environment: "prod"},
+		{Variable: "DEBUG", Environment: "staging"},
+		{Variable: "PORT", Environment: "dev"},
+		{Variable: "PORT", Environment: "staging"},
+	}
+	if !reflect.DeepEqual(report.Missing, expected) {
+		t.Errorf("Missing = %+v, expected %+v", report.Missing, expected)
+	}
+	if !reflect.DeepEqual(report.Environments, []string{"dev", "prod", "staging"}) {
+		t.Errorf("Environments = %v, expected sorted names", report.Environments)
+	}
+
+	report, err = envied.CheckConsistency(environments, envied.WithExtraVariablesAllowed())
+	if err != nil || report.OK() || len(report.Missing) != len(expected) {
+		t.Errorf("CheckConsistency() = %+v, %v, expected missing variables without error", report, err)
+	}
+}
+
+func TestCheckConsistencyConditionalVariables(t *testing.T) {
+	environments := map[string]map[string]string{
+		"dev":  {"API_URL": "https://dev.example.com", "DEBUG": "true"},
+		"prod": {"API_URL": "https://api.example.com", "ANALYTICS_KEY": "secret"},
+	}
+
+	report, err := envied.CheckConsistency(environments, envied.WithConditionalVariables(map[string][]string{
+		"DEBUG":         {"dev"},
+		"ANALYTICS_KEY": {"prod"},
+	}))
+	if err != nil || !report.OK() {
+		t.Errorf("CheckConsistency() = %+v, %v, expected consistent environments", report, err)
+	}
+
+	_, err = envied.CheckConsistency(environments, envied.WithConditionalVariables(map[string][]string{"DEBUG": {"dev", "qa"}}))
+	if err == nil || !strings.Contains(err.Error(), "variable 'DEBUG' is enabled for unknown environment 'qa'") {
+		t.Errorf("CheckConsistency() = %v, expected unknown environment error", err)
+	}
+
+	report, err = envied.CheckConsistency(environments, envied.WithConditionalVariables(map[string][]string{"SENTRY_DSN": {"prod"}}))
+	if err == nil || !reflect.DeepEqual(report.Missing[0], envied.MissingVariable{Variable: "ANALYTICS_KEY", Environment: "dev"}) {
+		t.Errorf("CheckConsistency() = %+v, %v, expected missing variables", report.Missing, err)
+	}
+}
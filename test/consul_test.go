@@ -0,0 +1,256 @@
+package test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// fakeConsul serves the Consul KV HTTP API with blocking queries
+type fakeConsul struct {
+	mu      sync.Mutex
+	values  map[string]string
+	index   uint64
+	changed chan struct{}
+	token   string
+}
+
+func newFakeConsul(t *testing.T, values map[string]string) (*fakeConsul, *httptest.Server) {
+	t.Helper()
+
+	consul := &fakeConsul{values: values, index: 1, changed: make(chan struct{})}
+	server := httptest.NewServer(consul)
+	t.Cleanup(server.Close)
+	return consul, server
+}
+
+// set updates a key and wakes up blocking queries
+func (c *fakeConsul) set(key, value string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.values[key] = value
+	c.index++
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+func (c *fakeConsul) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if c.token != "" && r.Header.Get("X-Consul-Token") != c.token {
+		http.Error(w, "ACL not found", http.StatusForbidden)
+		return
+	}
+
+	prefix := strings.TrimPrefix(r.URL.Path, "/v1/kv/")
+	if index, _ := strconv.ParseUint(r.URL.Query().Get("index"), 10, 64); index > 0 {
+		c.mu.Lock()
+		current, changed := c.index, c.changed
+		c.mu.Unlock()
+		if index == current {
+			select {
+			case <-changed:
+			case <-r.Context().Done():
+				return
+			}
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	type pair struct {
+		Key   string
+		Value []byte
+	}
+	var pairs []pair
+	for key, value := range c.values {
+		if strings.HasPrefix(key, prefix+"/") {
+			pairs = append(pairs, pair{Key: key, Value: []byte(value)})
+		}
+	}
+
+	w.Header().Set("X-Consul-Index", strconv.FormatUint(c.index, 10))
+	if len(pairs) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	json.NewEncoder(w).Encode(pairs)
+}
+
+func TestGenerateFromConsul(t *testing.T) {
+	consul, server := newFakeConsul(t, map[string]string{
+		"app/prod/API_URL":    "https://api.example.com",
+		"app/prod/PORT":       "80",
+		"app/prod/folder/":    "",
+		"app/staging/API_URL": "https://staging.example.com",
+	})
+	consul.token = "acl-token"
+	t.Setenv("ENVIED_CONSUL_TOKEN", "acl-token")
+
+	_, content := generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\nPORT=8080\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		config.Environments["prod"] = envied.EnvironmentConfig{
+			StructName: "Prod",
+			Consul: &envied.ConsulConfig{
+				Address:  server.URL,
+				Prefix:   "app/prod/",
+				TokenEnv: "ENVIED_CONSUL_TOKEN",
+			},
+		}
+	})
+
+	if !strings.Contains(content, `API_URL: "https://api.example.com",`) {
+		t.Error("Generated code should contain the value read from Consul")
+	}
+	if !strings.Contains(content, "GetPORT() int") {
+		t.Error("Consul values should be typed like env file values")
+	}
+	if strings.Contains(content, "staging") {
+		t.Error("Keys outside the prefix should be ignored")
+	}
+
+	// The source hash covers the values rendered as a sorted env file
+	sum := sha256.Sum256([]byte("API_URL=https://api.example.com\nPORT=80\n"))
+	if !strings.Contains(content, hex.EncodeToString(sum[:])) {
+		t.Error("Generated code should contain the hash of the Consul values")
+	}
+}
+
+func TestGenerateFromConsulTimesOut(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	t.Cleanup(server.Close)
+	t.Cleanup(func() { close(release) })
+
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.SourceTimeout = "100ms"
+		config.Environments["prod"] = envied.EnvironmentConfig{
+			StructName: "Prod",
+			Consul:     &envied.ConsulConfig{Address: server.URL, Prefix: "app/prod/"},
+		}
+	})
+
+	start := time.Now()
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "source_timeout") {
+		t.Errorf("Expected timeout error pointing at source_timeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("Generation took %s, expected it to give up after source_timeout", elapsed)
+	}
+}
+
+func TestSourceTimeoutMustBeADuration(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.SourceTimeout = "soon"
+	})
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "invalid source_timeout") {
+		t.Errorf("Expected invalid source_timeout error, got %v", err)
+	}
+}
+
+func TestConsulEnvironmentRequiresSingleSource(t *testing.T) {
+	_, server := newFakeConsul(t, map[string]string{})
+
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		dev := config.Environments["dev"]
+		dev.Consul = &envied.ConsulConfig{Address: server.URL, Prefix: "app/dev"}
+		config.Environments["dev"] = dev
+	})
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "only one of env_file and consul can be set") {
+		t.Errorf("Expected single source error, got %v", err)
+	}
+}
+
+func TestConsulSourceWatch(t *testing.T) {
+	consul, server := newFakeConsul(t, map[string]string{
+		"app/prod/FEATURE_X": "false",
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := envied.NewConsulSource(ctx, envied.ConsulConfig{Address: server.URL, Prefix: "app/prod"})
+	if err != nil {
+		t.Fatalf("NewConsulSource() returned error: %v", err)
+	}
+	if value, exists := source.Lookup("FEATURE_X"); !exists || value != "false" {
+		t.Errorf("Lookup() = %q, %v, expected false", value, exists)
+	}
+
+	changes := make(chan envied.MapSource, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- source.Watch(ctx, func(values envied.MapSource) {
+			changes <- values
+		})
+	}()
+
+	consul.set("app/prod/FEATURE_X", "true")
+
+	select {
+	case values := <-changes:
+		if values["FEATURE_X"] != "true" {
+			t.Errorf("onChange values = %v", values)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not report the change")
+	}
+	if value, _ := source.Lookup("FEATURE_X"); value != "true" {
+		t.Errorf("Lookup() after change = %q, expected true", value)
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch() returned %v, expected context.Canceled", err)
+	}
+}
+
+func TestHermeticEnvFileReplacesConsul(t *testing.T) {
+	projectDir, configPath := writeHermeticProject(t)
+
+	config, err := envied.LoadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+	config.Environments["prod"] = envied.EnvironmentConfig{
+		StructName: "Prod",
+		Consul:     &envied.ConsulConfig{Address: "http://127.0.0.1:1", Prefix: "app/prod"},
+	}
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	err = envied.GenerateHermetic(envied.HermeticOptions{
+		ConfigPath: configPath,
+		OutputFile: filepath.Join(t.TempDir(), "config_env.gen.go"),
+		EnvFiles:   map[string]string{"prod": filepath.Join(projectDir, "env", "prod.env")},
+	})
+	if err != nil {
+		t.Fatalf("GenerateHermetic() returned error: %v", err)
+	}
+}
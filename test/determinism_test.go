@@ -0,0 +1,179 @@
+package test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestAssertDeterministic(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	if err := envied.AssertDeterministic(envied.GenerateOptions{ConfigPath: configPath}); err != nil {
+		t.Errorf("AssertDeterministic() returned error: %v", err)
+	}
+
+	// Nothing is written to disk
+	if _, err := os.Stat(filepath.Join(tempDir, "config")); !os.IsNotExist(err) {
+		t.Error("AssertDeterministic() should not write the output")
+	}
+}
+
+func TestAssertDeterministicRequiresSourceDateEpoch(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "")
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	err := envied.AssertDeterministic(envied.GenerateOptions{ConfigPath: configPath})
+	if err == nil {
+		t.Fatal("Expected error for the embedded wall clock time")
+	}
+	if !strings.Contains(err.Error(), "embeds the generation time") || !strings.Contains(err.Error(), "SOURCE_DATE_EPOCH") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestAssertDeterministicCommandWithOverrides(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://{{.Host}}/dev\n",
+		"prod": "API_URL=https://{{.Host}}/prod\n",
+	}, func(config *envied.ConfigFile) {
+		config.Substitutions = map[string]string{"Host": "localhost"}
+		config.Obfuscation = envied.ObfuscationNone
+	})
+
+	// The configured prod env file is missing, only the override given with -env exists
+	if err := os.Remove(filepath.Join(tempDir, "prod.env")); err != nil {
+		t.Fatalf("Failed to remove env file: %v", err)
+	}
+	staging := filepath.Join(tempDir, "staging.env")
+	if err := os.WriteFile(staging, []byte("API_URL=https://{{.Host}}/{{.Path}}\n"), 0644); err != nil {
+		t.Fatalf("Failed to create env file: %v", err)
+	}
+
+	binary := filepath.Join(t.TempDir(), "envied")
+	if output, err := exec.Command("go", "build", "-o", binary, "github.com/petrovyuri/go-envied/cmd/envied").CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build envied: %v\n%s", err, output)
+	}
+	output, err := exec.Command(binary, "generate", "-assert-deterministic", "-config", configPath,
+		"-env", "prod="+staging, "-set", "Path=v1").CombinedOutput()
+	if err != nil {
+		t.Fatalf("envied generate -assert-deterministic failed: %v\n%s", err, output)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "localhost/v1") {
+		t.Errorf("Generated file should use the -env and -set overrides:\n%s", content)
+	}
+}
+
+func TestAssertDeterministicDetectsRandomSeed(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.RandomSeed = 0
+	})
+
+	err := envied.AssertDeterministic(envied.GenerateOptions{ConfigPath: configPath})
+	if err == nil {
+		t.Fatal("Expected error for random obfuscation keys")
+	}
+	if !strings.Contains(err.Error(), "not deterministic") || !strings.Contains(err.Error(), "run 1:") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestGenerateIsReproducible(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+	envs := make(map[string]string)
+	for _, envName := range []string{"dev", "qa", "staging", "prod", "demo"} {
+		var content strings.Builder
+		for _, name := range []string{"ZONE", "API_URL", "PORT", "DEBUG", "TIMEOUT", "RATIO", "NAME", "B_KEY", "A_KEY", "M_KEY", "HOSTS"} {
+			content.WriteString(name + "=" + envName + "-" + strings.ToLower(name) + "\n")
+		}
+		envs[envName] = content.String()
+	}
+
+	tempDir, configPath := writeConfig(t, envs, func(config *envied.ConfigFile) {
+		config.BuildTags = true
+		config.Obfuscation = envied.ObfuscationAESGCM
+		dev := config.Environments["dev"]
+		dev.Profiles = map[string][]string{"Web": {"PORT", "API_URL"}, "Keys": {"B_KEY", "A_KEY"}}
+		config.Environments["dev"] = dev
+		out := filepath.Join(config.OutputDir, "emit")
+		config.Emit = &envied.EmitConfig{
+			Markdown:   filepath.Join(out, "ENV.md"),
+			EnvExample: filepath.Join(out, ".env.example"),
+			Manifest:   filepath.Join(out, "manifest.json"),
+			Fixtures:   filepath.Join(out, "fixtures"),
+			Properties: filepath.Join(out, "properties"),
+			INI:        filepath.Join(out, "config.ini"),
+			Helm:       filepath.Join(out, "helm"),
+		}
+	})
+
+	generate := func() map[string]string {
+		if err := envied.GenerateFromConfigFile(configPath); err != nil {
+			t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+		}
+		files := make(map[string]string)
+		err := filepath.WalkDir(filepath.Join(tempDir, "config"), func(path string, entry os.DirEntry, err error) error {
+			if err != nil || entry.IsDir() {
+				return err
+			}
+			content, err := os.ReadFile(path)
+			files[path] = string(content)
+			return err
+		})
+		if err != nil {
+			t.Fatalf("Failed to read generated files: %v", err)
+		}
+		return files
+	}
+
+	first := generate()
+	for run := 2; run <= 5; run++ {
+		files := generate()
+		if len(files) != len(first) {
+			t.Fatalf("Run %d generated %d files, expected %d", run, len(files), len(first))
+		}
+		for path, content := range first {
+			if files[path] != content {
+				t.Errorf("Run %d generated %s differently", run, path)
+			}
+		}
+	}
+}
+
+func TestGenerateReportsErrorsInEnvironmentOrder(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"prod":    "API_URL=https://api.example.com\n",
+		"staging": "API_URL=https://staging.example.com\n",
+		"dev":     "API_URL=https://dev.example.com\n",
+	}, nil)
+	for _, envName := range []string{"prod", "staging", "dev"} {
+		if err := os.Remove(filepath.Join(tempDir, envName+".env")); err != nil {
+			t.Fatalf("Failed to remove env file: %v", err)
+		}
+	}
+
+	for run := 1; run <= 10; run++ {
+		err := envied.GenerateFromConfigFile(configPath)
+		if err == nil || !strings.HasPrefix(err.Error(), "environment 'dev'") {
+			t.Fatalf("Run %d: GenerateFromConfigFile() = %v, expected the error of environment 'dev'", run, err)
+		}
+	}
+}
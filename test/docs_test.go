@@ -0,0 +1,159 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestVariableDescriptionsInGeneratedCode(t *testing.T) {
+	_, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\n",
+	}, func(config *envied.ConfigFile) {
+		config.Variables = map[string]envied.VariableConfig{
+			"API_URL": {Description: "Base URL of the backend API.\nMust not end with a slash."},
+		}
+	})
+
+	expected := []string{
+		"\t// GetAPI_URL returns API_URL - Base URL of the backend API.\n\t// Must not end with a slash.\n\tGetAPI_URL() string\n",
+		"\t// Base URL of the backend API.\n\t// Must not end with a slash.\n\tAPI_URL string\n",
+		"// GetAPI_URL returns API_URL - Base URL of the backend API.\n// Must not end with a slash.\nfunc (c *ProdConfig) GetAPI_URL() string {\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+	if strings.Contains(content, "GetPORT returns") {
+		t.Error("Variables without description should not get a description comment")
+	}
+}
+
+func TestEmitDocs(t *testing.T) {
+	docsDir := t.TempDir()
+	emit := &envied.EmitConfig{
+		Markdown:   filepath.Join(docsDir, "CONFIG.md"),
+		EnvExample: filepath.Join(docsDir, ".env.example"),
+		Manifest:   filepath.Join(docsDir, "manifest", "envied.json"),
+	}
+
+	generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\nSECRET=prod-secret\n",
+	}, func(config *envied.ConfigFile) {
+		config.Emit = emit
+		config.Variables = map[string]envied.VariableConfig{
+			"API_URL": {Description: "Base URL | backend"},
+			"SECRET":  {Only: []string{"prod"}, Description: "Signing secret"},
+		}
+	})
+
+	markdown, err := os.ReadFile(emit.Markdown)
+	if err != nil {
+		t.Fatalf("Failed to read Markdown: %v", err)
+	}
+	for _, want := range []string{
+		"| `API_URL` | string | dev, prod | Base URL \\| backend |",
+		"| `PORT` | int | dev, prod |  |",
+		"| `SECRET` | string | prod | Signing secret |",
+	} {
+		if !strings.Contains(string(markdown), want) {
+			t.Errorf("Markdown should contain %q:\n%s", want, markdown)
+		}
+	}
+
+	example, err := os.ReadFile(emit.EnvExample)
+	if err != nil {
+		t.Fatalf("Failed to read .env.example: %v", err)
+	}
+	if !strings.Contains(string(example), "# Signing secret\n# Type: string\n# Environments: prod\nSECRET=\n") {
+		t.Errorf("Unexpected .env.example:\n%s", example)
+	}
+	if strings.Contains(string(example), "prod-secret") {
+		t.Error(".env.example must not contain values")
+	}
+
+	data, err := os.ReadFile(emit.Manifest)
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	if strings.Contains(string(data), "prod-secret") {
+		t.Error("Manifest must not contain values")
+	}
+
+	var manifest envied.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Manifest is not valid JSON: %v", err)
+	}
+	if len(manifest.Environments) != 2 || manifest.Environments[0].Name != "dev" || manifest.Environments[0].SourceHash == "" {
+		t.Errorf("Unexpected manifest environments: %+v", manifest.Environments)
+	}
+	if len(manifest.Variables) != 3 || manifest.Variables[0].Name != "API_URL" || manifest.Variables[0].Description != "Base URL | backend" {
+		t.Errorf("Unexpected manifest variables: %+v", manifest.Variables)
+	}
+}
+
+func TestEmitDocsEncrypted(t *testing.T) {
+	key := bytes.Repeat([]byte{7}, 32)
+	t.Setenv("ENVIED_ARTIFACT_KEY", base64.StdEncoding.EncodeToString(key))
+
+	docsDir := t.TempDir()
+	emit := &envied.EmitConfig{
+		Markdown:      filepath.Join(docsDir, "CONFIG.md"),
+		Manifest:      filepath.Join(docsDir, "envied.json"),
+		EncryptKeyEnv: "ENVIED_ARTIFACT_KEY",
+	}
+	generateConfig(t, map[string]string{
+		"dev":  "STRIPE_SECRET_KEY=sk_dev\n",
+		"prod": "STRIPE_SECRET_KEY=sk_prod\n",
+	}, func(config *envied.ConfigFile) {
+		config.Emit = emit
+	})
+
+	for _, path := range []string{emit.Markdown, emit.Manifest} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		if bytes.Contains(data, []byte("STRIPE_SECRET_KEY")) {
+			t.Errorf("%s must not contain variable names in plain text", path)
+		}
+
+		plaintext, err := envied.DecryptArtifact(data, key)
+		if err != nil {
+			t.Fatalf("DecryptArtifact() returned error: %v", err)
+		}
+		if !bytes.Contains(plaintext, []byte("STRIPE_SECRET_KEY")) {
+			t.Errorf("Decrypted %s should contain the variable names", path)
+		}
+
+		if _, err := envied.DecryptArtifact(data, bytes.Repeat([]byte{8}, 32)); err == nil {
+			t.Error("DecryptArtifact() expected error for a wrong key")
+		}
+	}
+}
+
+func TestEmitDocsEncryptionKeyMissing(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "PORT=8080\n",
+		"prod": "PORT=80\n",
+	}, func(config *envied.ConfigFile) {
+		config.Emit = &envied.EmitConfig{
+			Manifest:      filepath.Join(t.TempDir(), "envied.json"),
+			EncryptKeyEnv: "ENVIED_MISSING_ARTIFACT_KEY",
+		}
+	})
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "ENVIED_MISSING_ARTIFACT_KEY") {
+		t.Errorf("Expected missing key error, got %v", err)
+	}
+}
@@ -0,0 +1,257 @@
+package test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// doctorConfig writes a configuration with dev read from an env file and prod from Consul
+// at address, with the Vault secret of staging checked only with values from vault
+func doctorConfig(t *testing.T, consulAddress, vaultAddress string) string {
+	t.Helper()
+
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":     "API_URL=https://dev.example.com\n",
+		"staging": "API_URL=\n",
+	}, func(config *envied.ConfigFile) {
+		config.Environments["prod"] = envied.EnvironmentConfig{
+			StructName: "Prod",
+			Consul:     &envied.ConsulConfig{Address: consulAddress, Prefix: "app/prod", TokenEnv: "ENVIED_CONSUL_TOKEN"},
+		}
+		staging := config.Environments["staging"]
+		staging.Vault = &envied.VaultConfig{Address: vaultAddress, Path: "app/staging"}
+		config.Environments["staging"] = staging
+	})
+	return configPath
+}
+
+// remoteChecks returns the checks of remote sources
+func remoteChecks(checks []envied.DoctorCheck) []envied.DoctorCheck {
+	var remote []envied.DoctorCheck
+	for _, check := range checks {
+		if check.Check == envied.DoctorCredentials || check.Check == envied.DoctorAccess {
+			remote = append(remote, check)
+		}
+	}
+	return remote
+}
+
+func TestDoctor(t *testing.T) {
+	consul, consulServer := newFakeConsul(t, map[string]string{"app/prod/API_URL": "https://api.example.com"})
+	consul.token = "acl-token"
+	vaultServer := newFakeVault(t, "ci-token", map[string]map[string]any{"app/staging": {"API_URL": "https://staging.example.com"}})
+	t.Setenv("ENVIED_CONSUL_TOKEN", "acl-token")
+	t.Setenv("VAULT_TOKEN", "ci-token")
+	configPath := doctorConfig(t, consulServer.URL, vaultServer.URL)
+
+	checks, err := envied.Doctor(envied.GenerateOptions{ConfigPath: configPath}, 0)
+	if err != nil {
+		t.Fatalf("Doctor() returned error: %v", err)
+	}
+	checks = remoteChecks(checks)
+	if len(checks) != 2 || checks[0].Check != envied.DoctorCredentials || checks[1].Check != envied.DoctorAccess {
+		t.Fatalf("Doctor() = %+v, expected the credentials and access of prod", checks)
+	}
+	for _, check := range checks {
+		if !check.OK || check.Environment != "prod" {
+			t.Errorf("Check %+v, expected to pass for prod", check)
+		}
+	}
+	if !strings.Contains(checks[1].Message, "read 1 variables") {
+		t.Errorf("Access message = %q, expected the number of variables read", checks[1].Message)
+	}
+
+	checks, err = envied.Doctor(envied.GenerateOptions{ConfigPath: configPath, ValuesFrom: envied.ValuesFromVault}, 0)
+	if err != nil {
+		t.Fatalf("Doctor() returned error: %v", err)
+	}
+	checks = remoteChecks(checks)
+	if len(checks) != 4 || checks[3].Environment != "staging" || !checks[3].OK {
+		t.Errorf("Doctor() with values from vault = %+v, expected staging to pass", checks)
+	}
+}
+
+func TestDoctorReportsSetupProblems(t *testing.T) {
+	consul, consulServer := newFakeConsul(t, map[string]string{"app/prod/API_URL": "https://api.example.com"})
+	consul.token = "acl-token"
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(slow.Close)
+
+	tests := []struct {
+		name    string
+		consul  string
+		token   string
+		check   string
+		message string
+		hint    string
+	}{
+		{"wrong token", consulServer.URL, "developer-token", envied.DoctorAccess, "403 Forbidden", "check that the credentials may read"},
+		{"unreachable", closed.URL, "acl-token", envied.DoctorAccess, "failed to query", "can't connect"},
+		{"no answer", slow.URL, "acl-token", envied.DoctorAccess, "failed to query", "no answer in time"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("ENVIED_CONSUL_TOKEN", tt.token)
+			configPath := doctorConfig(t, tt.consul, closed.URL)
+
+			checks, err := envied.Doctor(envied.GenerateOptions{ConfigPath: configPath}, 200*time.Millisecond)
+			if err != nil {
+				t.Fatalf("Doctor() returned error: %v", err)
+			}
+			failed := checks[len(checks)-1]
+			if failed.OK || failed.Check != tt.check || !strings.Contains(failed.Message, tt.message) || !strings.Contains(failed.Hint, tt.hint) {
+				t.Errorf("Doctor() = %+v, expected %s to fail with %q and a hint containing %q", checks, tt.check, tt.message, tt.hint)
+			}
+		})
+	}
+}
+
+func TestDoctorChecksCredentialsBeforeAccess(t *testing.T) {
+	t.Setenv("VAULT_TOKEN", "")
+	configPath := doctorConfig(t, "http://127.0.0.1:1", "http://127.0.0.1:1")
+
+	checks, err := envied.Doctor(envied.GenerateOptions{ConfigPath: configPath, ValuesFrom: envied.ValuesFromVault}, time.Second)
+	if err != nil {
+		t.Fatalf("Doctor() returned error: %v", err)
+	}
+	last := checks[len(checks)-1]
+	if last.Environment != "staging" || last.Check != envied.DoctorCredentials || last.OK || !strings.Contains(last.Message, "VAULT_TOKEN holding the token is not set") {
+		t.Errorf("Doctor() = %+v, expected the missing Vault token without an access check", checks)
+	}
+}
+
+// projectCheck returns the first check of a kind
+func projectCheck(t *testing.T, checks []envied.DoctorCheck, kind string) envied.DoctorCheck {
+	t.Helper()
+	for _, check := range checks {
+		if check.Check == kind {
+			return check
+		}
+	}
+	t.Fatalf("Doctor() = %+v, expected a %s check", checks, kind)
+	return envied.DoctorCheck{}
+}
+
+func TestDoctorProject(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+	if err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module example.com/app\n\ngo 1.25\n"), 0644); err != nil {
+		t.Fatalf("Failed to write go.mod: %v", err)
+	}
+
+	checks, err := envied.Doctor(envied.GenerateOptions{ConfigPath: configPath}, 0)
+	if err != nil {
+		t.Fatalf("Doctor() returned error: %v", err)
+	}
+	// config, two env files, output and toolchain
+	if len(checks) != 5 {
+		t.Fatalf("Doctor() = %+v, expected 5 checks", checks)
+	}
+	for _, check := range checks {
+		if !check.OK && check.Check != envied.DoctorToolchain {
+			t.Errorf("Check %+v, expected to pass", check)
+		}
+	}
+	if output := projectCheck(t, checks, envied.DoctorOutput); !strings.Contains(output.Message, "package config in module") {
+		t.Errorf("Output check = %+v, expected the package and module", output)
+	}
+}
+
+func TestDoctorProjectProblems(t *testing.T) {
+	tests := []struct {
+		name  string
+		setup func(t *testing.T, tempDir string)
+		check string
+		hint  string
+	}{
+		{
+			name:  "missing env file",
+			setup: func(t *testing.T, tempDir string) { os.Remove(filepath.Join(tempDir, "prod.env")) },
+			check: envied.DoctorEnvFile,
+			hint:  "create",
+		},
+		{
+			name:  "world-writable env file",
+			setup: func(t *testing.T, tempDir string) { os.Chmod(filepath.Join(tempDir, "dev.env"), 0666) },
+			check: envied.DoctorEnvFile,
+			hint:  "chmod o-w",
+		},
+		{
+			name: "encrypted env file without passphrase",
+			setup: func(t *testing.T, tempDir string) {
+				t.Setenv(envied.PassphraseEnv, "")
+				if _, err := envied.EncryptEnvFile(filepath.Join(tempDir, "dev.env"), "correct horse"); err != nil {
+					t.Fatalf("EncryptEnvFile() returned error: %v", err)
+				}
+			},
+			check: envied.DoctorEnvFile,
+			hint:  "set " + envied.PassphraseEnv,
+		},
+		{
+			name: "other package in output directory",
+			setup: func(t *testing.T, tempDir string) {
+				os.MkdirAll(filepath.Join(tempDir, "config"), 0755)
+				os.WriteFile(filepath.Join(tempDir, "config", "settings.go"), []byte("package settings\n"), 0644)
+			},
+			check: envied.DoctorOutput,
+			hint:  "set package_name to settings",
+		},
+		{
+			name:  "output outside a module",
+			setup: func(t *testing.T, tempDir string) { os.Remove(filepath.Join(tempDir, "go.mod")) },
+			check: envied.DoctorOutput,
+			hint:  "go mod init",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, configPath := writeConfig(t, map[string]string{
+				"dev":  "API_URL=https://dev.example.com\n",
+				"prod": "API_URL=https://api.example.com\n",
+			}, nil)
+			if err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module example.com/app\n\ngo 1.25\n"), 0644); err != nil {
+				t.Fatalf("Failed to write go.mod: %v", err)
+			}
+			tt.setup(t, tempDir)
+
+			checks, err := envied.Doctor(envied.GenerateOptions{ConfigPath: configPath}, 0)
+			if err != nil {
+				t.Fatalf("Doctor() returned error: %v", err)
+			}
+			var failed []envied.DoctorCheck
+			for _, check := range checks {
+				if !check.OK && check.Check != envied.DoctorToolchain {
+					failed = append(failed, check)
+				}
+			}
+			if len(failed) != 1 || failed[0].Check != tt.check || !strings.Contains(failed[0].Hint, tt.hint) {
+				t.Errorf("Failed checks = %+v, expected %s to fail with a hint containing %q", failed, tt.check, tt.hint)
+			}
+		})
+	}
+}
+
+func TestDoctorMissingConfig(t *testing.T) {
+	checks, err := envied.Doctor(envied.GenerateOptions{ConfigPath: filepath.Join(t.TempDir(), envied.DefaultConfigFileName)}, 0)
+	if err != nil {
+		t.Fatalf("Doctor() returned error: %v", err)
+	}
+	if len(checks) != 1 || checks[0].Check != envied.DoctorConfig || checks[0].OK || !strings.Contains(checks[0].Hint, "envied init") {
+		t.Errorf("Doctor() = %+v, expected a failed config check", checks)
+	}
+}
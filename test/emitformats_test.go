@@ -0,0 +1,54 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestEmitPropertiesAndINI(t *testing.T) {
+	outDir := t.TempDir()
+	emit := &envied.EmitConfig{
+		Properties: filepath.Join(outDir, "properties"),
+		INI:        filepath.Join(outDir, "config.ini"),
+	}
+	generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\nGREETING=\" Grüße; #1 \"\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\nGREETING=hello\nSECRET=C:\\keys\n",
+	}, func(config *envied.ConfigFile) {
+		config.Emit = emit
+		config.Variables = map[string]envied.VariableConfig{
+			"API_URL": {Description: "Base URL"},
+			"SECRET":  {Only: []string{"prod"}},
+		}
+	})
+
+	properties, err := os.ReadFile(filepath.Join(emit.Properties, "dev.properties"))
+	if err != nil {
+		t.Fatalf("Failed to read properties: %v", err)
+	}
+	for _, want := range []string{"# Environment: dev\n", "# Base URL\nAPI_URL=https://dev.example.com\n", "PORT=8080\n", `GREETING=\ Gr\u00FC\u00DFe; #1 ` + "\n"} {
+		if !strings.Contains(string(properties), want) {
+			t.Errorf("Properties should contain %q:\n%s", want, properties)
+		}
+	}
+	if strings.Contains(string(properties), "SECRET") {
+		t.Error("Properties of dev should not contain variables restricted to prod")
+	}
+
+	ini, err := os.ReadFile(emit.INI)
+	if err != nil {
+		t.Fatalf("Failed to read INI file: %v", err)
+	}
+	for _, want := range []string{
+		"\n[dev]\n; Base URL\nAPI_URL = https://dev.example.com\nGREETING = \" Grüße; #1 \"\nPORT = 8080\n",
+		"\n[prod]\n; Base URL\nAPI_URL = https://api.example.com\nGREETING = hello\nPORT = 80\nSECRET = \"C:\\\\keys\"\n",
+	} {
+		if !strings.Contains(string(ini), want) {
+			t.Errorf("INI file should contain %q:\n%s", want, ini)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+package test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestEmptyEnvironment(t *testing.T) {
+	envs := map[string]string{
+		"dev":  "# export API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}
+	tempDir, configPath := writeConfig(t, envs, nil)
+
+	err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+	expected := "environment 'dev' has no variables, check that " + filepath.Join(tempDir, "dev.env") + " is the intended source"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("Validate() = %v, expected error containing %q", err, expected)
+	}
+
+	_, configPath = writeConfig(t, envs, func(config *envied.ConfigFile) {
+		config.AllowEmptyEnvironments = true
+		config.AllowExtraVariables = true
+	})
+	warnings, err := envied.ValidateWithWarnings(envied.GenerateOptions{ConfigPath: configPath})
+	if err != nil {
+		t.Fatalf("ValidateWithWarnings() returned error: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != envied.WarningEmptyEnvironment || warnings[0].Environment != "dev" {
+		t.Errorf("Expected an empty environment warning, got %+v", warnings)
+	}
+}
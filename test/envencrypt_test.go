@@ -0,0 +1,106 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestEncryptEnvRoundTrip(t *testing.T) {
+	plaintext := []byte("# comment\nAPI_KEY=secret-value\n")
+	encrypted, err := envied.EncryptEnv(plaintext, "correct horse")
+	if err != nil {
+		t.Fatalf("EncryptEnv() returned error: %v", err)
+	}
+	if !envied.IsEncryptedEnv(encrypted) || strings.Contains(string(encrypted), "secret-value") {
+		t.Fatalf("EncryptEnv() = %q, expected an encrypted file", encrypted)
+	}
+
+	decrypted, err := envied.DecryptEnv(encrypted, "correct horse")
+	if err != nil {
+		t.Fatalf("DecryptEnv() returned error: %v", err)
+	}
+	if string(decrypted) != string(plaintext) {
+		t.Errorf("DecryptEnv() = %q, expected %q", decrypted, plaintext)
+	}
+	if _, err := envied.DecryptEnv(encrypted, "wrong horse"); err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("DecryptEnv() with a wrong passphrase = %v, expected error", err)
+	}
+}
+
+func TestEncryptedEnvFileGeneration(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nAPI_KEY=dev-secret-value\n",
+		"prod": "API_URL=https://api.example.com\nAPI_KEY=prod-secret-value\n",
+	}, nil)
+	t.Setenv(envied.PassphraseEnv, "correct horse")
+
+	files, err := envied.ConfigEnvFiles(configPath)
+	if err != nil {
+		t.Fatalf("ConfigEnvFiles() returned error: %v", err)
+	}
+	expected := []string{filepath.Join(tempDir, "dev.env"), filepath.Join(tempDir, "prod.env")}
+	if !slices.Equal(files, expected) {
+		t.Fatalf("ConfigEnvFiles() = %v, expected %v", files, expected)
+	}
+	for _, file := range files {
+		if changed, err := envied.EncryptEnvFile(file, "correct horse"); err != nil || !changed {
+			t.Fatalf("EncryptEnvFile(%s) = %v, %v, expected the file to be encrypted", file, changed, err)
+		}
+		if changed, err := envied.EncryptEnvFile(file, "correct horse"); err != nil || changed {
+			t.Errorf("EncryptEnvFile(%s) = %v, %v, expected an encrypted file to be left unchanged", file, changed, err)
+		}
+	}
+
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error for encrypted env files: %v", err)
+	}
+	generated, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(generated), "API_KEY") {
+		t.Error("Generated file is missing the variables of the encrypted env files")
+	}
+
+	// Pruning keeps the env files encrypted
+	if err := envied.PruneVariables(configPath, []string{"API_KEY"}); err != nil {
+		t.Fatalf("PruneVariables() returned error: %v", err)
+	}
+	content, _ := os.ReadFile(expected[1])
+	if !envied.IsEncryptedEnv(content) {
+		t.Fatal("PruneVariables() decrypted the env file")
+	}
+	if changed, err := envied.DecryptEnvFile(expected[1], "correct horse"); err != nil || !changed {
+		t.Fatalf("DecryptEnvFile() = %v, %v, expected the file to be decrypted", changed, err)
+	}
+	if content, _ := os.ReadFile(expected[1]); string(content) != "API_URL=https://api.example.com\n" {
+		t.Errorf("Decrypted env file = %q, expected API_URL only", content)
+	}
+}
+
+func TestEncryptedEnvFileRequiresPassphrase(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+	if _, err := envied.EncryptEnvFile(filepath.Join(tempDir, "prod.env"), "correct horse"); err != nil {
+		t.Fatalf("EncryptEnvFile() returned error: %v", err)
+	}
+
+	t.Setenv(envied.PassphraseEnv, "")
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "set "+envied.PassphraseEnv) {
+		t.Errorf("GenerateFromConfigFile() = %v, expected a missing passphrase error", err)
+	}
+
+	t.Setenv(envied.PassphraseEnv, "wrong horse")
+	err = envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "wrong passphrase") {
+		t.Errorf("GenerateFromConfigFile() = %v, expected a wrong passphrase error", err)
+	}
+}
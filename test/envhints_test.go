@@ -0,0 +1,100 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestReadEnvFileHints(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	content := "# envied: type=string, sensitive=true\nVERSION=007\n\n# envied: sensitive=false\n\nPORT=8080\n# envied: type=int64\n# Chat to notify\nCHAT_ID=-42\n"
+	if err := os.WriteFile(envFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create env file: %v", err)
+	}
+
+	envVars, err := envied.ReadEnvFileWithMetadata(envFile)
+	if err != nil {
+		t.Fatalf("ReadEnvFileWithMetadata() returned error: %v", err)
+	}
+	if hints := envVars["VERSION"].Hints; hints.Type != "string" || hints.Sensitive == nil || !*hints.Sensitive {
+		t.Errorf("VERSION hints = %+v, expected string and sensitive", hints)
+	}
+	// Hints apply to the next line only
+	if hints := envVars["PORT"].Hints; hints != (envied.EnvHints{}) {
+		t.Errorf("PORT hints = %+v, expected none", hints)
+	}
+	if hints := envVars["CHAT_ID"].Hints; hints != (envied.EnvHints{}) {
+		t.Errorf("CHAT_ID hints = %+v, expected none", hints)
+	}
+
+	for _, invalid := range []string{"# envied: type=uint8\nPORT=1\n", "# envied: secret=true\nPORT=1\n", "# envied: sensitive=maybe\nPORT=1\n", "# envied: type\nPORT=1\n"} {
+		if err := os.WriteFile(envFile, []byte(invalid), 0644); err != nil {
+			t.Fatalf("Failed to update env file: %v", err)
+		}
+		if _, err := envied.ReadEnvFileWithMetadata(envFile); err == nil || !strings.Contains(err.Error(), envFile+":1:") {
+			t.Errorf("ReadEnvFileWithMetadata(%q) = %v, expected error at line 1", invalid, err)
+		}
+	}
+}
+
+func TestGenerateWithEnvHints(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "# envied: type=string\nVERSION=007\n# envied: sensitive=true\nAPI_KEY=dev-key\nAPI_URL=https://dev.example.com\n",
+		"prod": "# envied: type=string\nVERSION=1.2\nAPI_KEY=prod-key\n# envied: sensitive=false\nAPI_URL=https://api.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		obfuscate := false
+		dev := config.Environments["dev"]
+		dev.Obfuscate = &obfuscate
+		config.Environments["dev"] = dev
+	})
+
+	for _, want := range []string{
+		"GetVERSION() string",
+		"var dev_enviedkeyAPI_KEY =",
+		"var prod_enviedkeyAPI_KEY =",
+		`API_URL: "https://dev.example.com",`,
+		`API_URL: "https://api.example.com",`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+	if strings.Contains(content, "dev-key") {
+		t.Error("Sensitive values must be obfuscated in non-obfuscated environments")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	dev := config.NewDevConfig()
+	fmt.Println(dev.GetVERSION(), dev.GetAPI_KEY())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if expected := "007 dev-key\n"; output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "# envied: type=string\nPORT=8080\n",
+		"prod": "PORT=80\n",
+	}, func(config *envied.ConfigFile) {
+		config.Fields = map[string]envied.FieldType{"PORT": envied.FieldTypeInt}
+	})
+	err = envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+	if err == nil || !strings.Contains(err.Error(), `env file hint type "string" conflicts with "int"`) {
+		t.Errorf("Validate() = %v, expected a conflicting type error", err)
+	}
+}
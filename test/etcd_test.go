@@ -0,0 +1,276 @@
+package test
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// fakeEtcd serves the parts of the etcd v3 JSON gateway used by go-envied
+type fakeEtcd struct {
+	mu       sync.Mutex
+	values   map[string]string
+	revision int64
+	changed  chan struct{}
+	password string
+	tokens   int // Tokens issued, only the latest one is valid
+}
+
+func newFakeEtcd(values map[string]string) *fakeEtcd {
+	return &fakeEtcd{values: values, revision: 1, changed: make(chan struct{})}
+}
+
+// set updates a key and notifies watchers
+func (e *fakeEtcd) set(key, value string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.values[key] = value
+	e.revision++
+	close(e.changed)
+	e.changed = make(chan struct{})
+}
+
+// token returns the valid authentication token
+func (e *fakeEtcd) token() string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return "token-" + strconv.Itoa(e.tokens)
+}
+
+// expireToken invalidates the issued token as its TTL would
+func (e *fakeEtcd) expireToken() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.tokens++
+}
+
+func (e *fakeEtcd) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if e.password != "" && r.URL.Path != "/v3/auth/authenticate" && r.Header.Get("Authorization") != e.token() {
+		http.Error(w, `{"error":"etcdserver: invalid auth token","code":16}`, http.StatusUnauthorized)
+		return
+	}
+
+	switch r.URL.Path {
+	case "/v3/auth/authenticate":
+		var request struct{ Name, Password string }
+		json.NewDecoder(r.Body).Decode(&request)
+		if request.Name != "envied" || request.Password != e.password {
+			http.Error(w, `{"error":"authentication failed"}`, http.StatusBadRequest)
+			return
+		}
+		e.mu.Lock()
+		e.tokens++
+		e.mu.Unlock()
+		json.NewEncoder(w).Encode(map[string]string{"token": e.token()})
+
+	case "/v3/kv/range":
+		var request struct {
+			Key      []byte `json:"key"`
+			RangeEnd []byte `json:"range_end"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+		type kv struct {
+			Key   []byte `json:"key"`
+			Value []byte `json:"value"`
+		}
+		var kvs []kv
+		for key, value := range e.values {
+			if key >= string(request.Key) && key < string(request.RangeEnd) {
+				kvs = append(kvs, kv{Key: []byte(key), Value: []byte(value)})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{
+			"header": map[string]string{"revision": strconv.FormatInt(e.revision, 10)},
+			"kvs":    kvs,
+		})
+
+	case "/v3/watch":
+		var request struct {
+			CreateRequest struct {
+				StartRevision string `json:"start_revision"`
+			} `json:"create_request"`
+		}
+		json.NewDecoder(r.Body).Decode(&request)
+		startRevision, _ := strconv.ParseInt(request.CreateRequest.StartRevision, 10, 64)
+
+		e.mu.Lock()
+		changed, revision := e.changed, e.revision
+		e.mu.Unlock()
+
+		json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"created": true}})
+		w.(http.Flusher).Flush()
+
+		// Changes after the start revision are replayed immediately
+		if revision >= startRevision {
+			changed = make(chan struct{})
+			close(changed)
+		}
+
+		select {
+		case <-changed:
+			json.NewEncoder(w).Encode(map[string]any{"result": map[string]any{"events": []map[string]any{{"type": "PUT"}}}})
+		case <-r.Context().Done():
+		}
+
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestGenerateFromEtcdWithTLSAndAuth(t *testing.T) {
+	etcd := newFakeEtcd(map[string]string{
+		"/myapp/prod/API_URL":   "https://api.example.com",
+		"/myapp/prod/db/PORT":   "5432",
+		"/myapp/production/OLD": "ignored",
+	})
+	etcd.password = "secret"
+	server := httptest.NewTLSServer(etcd)
+	defer server.Close()
+
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	caPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(caFile, caPEM, 0644); err != nil {
+		t.Fatalf("Failed to write CA file: %v", err)
+	}
+	t.Setenv("ENVIED_ETCD_PASSWORD", "secret")
+
+	_, content := generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\ndb_PORT=1\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		config.NameValidation = envied.NameValidationOff
+		config.Environments["prod"] = envied.EnvironmentConfig{
+			StructName: "Prod",
+			Etcd: &envied.EtcdConfig{
+				Endpoints:   []string{"127.0.0.1:1", server.URL},
+				Prefix:      "/myapp/prod",
+				Username:    "envied",
+				PasswordEnv: "ENVIED_ETCD_PASSWORD",
+				CACert:      caFile,
+			},
+		}
+	})
+
+	if !strings.Contains(content, `API_URL: "https://api.example.com",`) {
+		t.Error("Generated code should contain the value read from etcd")
+	}
+	if !strings.Contains(content, "db_PORT: envied.ParseInt(\"5432\")") {
+		t.Error("Nested keys should be joined with '_'")
+	}
+	if strings.Contains(content, "OLD") {
+		t.Error("Keys outside the prefix should be ignored")
+	}
+}
+
+func TestEtcdAuthenticationFailure(t *testing.T) {
+	etcd := newFakeEtcd(map[string]string{})
+	etcd.password = "secret"
+	server := httptest.NewServer(etcd)
+	defer server.Close()
+
+	t.Setenv("ETCD_PASSWORD", "wrong")
+	_, err := envied.NewEtcdSource(context.Background(), envied.EtcdConfig{
+		Endpoints: []string{server.URL},
+		Prefix:    "/myapp/prod",
+		Username:  "envied",
+	})
+	if err == nil || !strings.Contains(err.Error(), "authentication failed") {
+		t.Errorf("Expected authentication error, got %v", err)
+	}
+}
+
+func TestEtcdSourceWatch(t *testing.T) {
+	etcd := newFakeEtcd(map[string]string{"/myapp/prod/FEATURE_X": "false"})
+	server := httptest.NewServer(etcd)
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := envied.NewEtcdSource(ctx, envied.EtcdConfig{Endpoints: []string{server.URL}, Prefix: "/myapp/prod/"})
+	if err != nil {
+		t.Fatalf("NewEtcdSource() returned error: %v", err)
+	}
+	if value, exists := source.Lookup("FEATURE_X"); !exists || value != "false" {
+		t.Errorf("Lookup() = %q, %v, expected false", value, exists)
+	}
+
+	changes := make(chan envied.MapSource, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- source.Watch(ctx, func(values envied.MapSource) {
+			changes <- values
+		})
+	}()
+
+	etcd.set("/myapp/prod/FEATURE_X", "true")
+
+	select {
+	case values := <-changes:
+		if values["FEATURE_X"] != "true" {
+			t.Errorf("onChange values = %v", values)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not report the change")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch() returned %v, expected context.Canceled", err)
+	}
+}
+
+func TestEtcdSourceWatchRenewsExpiredToken(t *testing.T) {
+	etcd := newFakeEtcd(map[string]string{"/myapp/prod/FEATURE_X": "false"})
+	etcd.password = "secret"
+	server := httptest.NewServer(etcd)
+	defer server.Close()
+	t.Setenv("ETCD_PASSWORD", "secret")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := envied.NewEtcdSource(ctx, envied.EtcdConfig{Endpoints: []string{server.URL}, Prefix: "/myapp/prod/", Username: "envied"})
+	if err != nil {
+		t.Fatalf("NewEtcdSource() returned error: %v", err)
+	}
+	etcd.expireToken()
+
+	changes := make(chan envied.MapSource, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- source.Watch(ctx, func(values envied.MapSource) {
+			changes <- values
+		})
+	}()
+
+	etcd.set("/myapp/prod/FEATURE_X", "true")
+
+	select {
+	case values := <-changes:
+		if values["FEATURE_X"] != "true" {
+			t.Errorf("onChange values = %v", values)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not report the change after the token expired")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch() returned %v, expected context.Canceled", err)
+	}
+}
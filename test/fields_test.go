@@ -0,0 +1,87 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestPinnedFieldTypes(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "PORT=1\nVERSION=007\nRATIO=2\nRELEASED=2024-01-02T03:04:05Z\n",
+		"prod": "PORT=8080\nVERSION=1.2\nRATIO=2.5\nRELEASED=2024-01-02T03:04:05Z\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		config.Fields = map[string]envied.FieldType{
+			"PORT":     envied.FieldTypeInt,
+			"VERSION":  envied.FieldTypeString,
+			"RATIO":    envied.FieldTypeFloat,
+			"RELEASED": envied.FieldTypeString,
+		}
+	})
+
+	for _, want := range []string{
+		"GetPORT() int",
+		"GetVERSION() string",
+		"GetRATIO() float64",
+		"GetRELEASED() string",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	dev := config.NewDevConfig()
+	fmt.Println(dev.GetPORT()+1, dev.GetVERSION(), dev.GetRATIO(), dev.GetRELEASED())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if expected := "2 007 2 2024-01-02T03:04:05Z\n"; output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestPinnedFieldTypeErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		modify   func(*envied.ConfigFile)
+		expected string
+	}{
+		{"value of another type", func(config *envied.ConfigFile) {
+			config.Fields = map[string]envied.FieldType{"PORT": envied.FieldTypeBool}
+		}, `is not a bool`},
+		{"unsupported type", func(config *envied.ConfigFile) {
+			config.Fields = map[string]envied.FieldType{"PORT": "uint8"}
+		}, `unsupported type "uint8"`},
+		{"conflicting variable type", func(config *envied.ConfigFile) {
+			config.Fields = map[string]envied.FieldType{"PORT": envied.FieldTypeInt}
+			config.Variables = map[string]envied.VariableConfig{"PORT": {Type: "int64"}}
+		}, `fields pins type "int" but variables declares "int64"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{
+				"dev":  "PORT=8080\n",
+				"prod": "PORT=80\n",
+			}, tt.modify)
+
+			err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Validate() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,164 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateWithAESGCMObfuscation(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_KEY=secret-dev\nPORT=8080\n",
+		"prod": "API_KEY=secret-prod\nPORT=80\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationAESGCM
+	})
+
+	if !strings.Contains(content, "envied.MustOpenString") {
+		t.Error("Generated code should open AES-GCM sealed values")
+	}
+	if strings.Contains(content, "MustDecodeString") || strings.Contains(content, "secret-dev") {
+		t.Error("Generated code should not contain XOR obfuscated or plain values")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	cfg := config.NewDevConfig()
+	fmt.Println(cfg.GetAPI_KEY())
+	fmt.Println(cfg.GetPORT())
+}
+`)
+	if err != nil {
+		t.Fatalf("Generated code failed: %v\n%s", err, output)
+	}
+	if output != "secret-dev\n8080\n" {
+		t.Errorf("Output = %q, expected %q", output, "secret-dev\n8080\n")
+	}
+}
+
+func TestCryptoModeFIPS(t *testing.T) {
+	t.Run("defaults to aes-gcm", func(t *testing.T) {
+		_, content := generateConfig(t, map[string]string{
+			"dev": "API_KEY=secret-dev\n",
+		}, func(config *envied.ConfigFile) {
+			config.CryptoMode = envied.CryptoModeFIPS
+		})
+
+		if !strings.Contains(content, "envied.MustOpenString") || strings.Contains(content, "MustDecodeString") {
+			t.Error("FIPS mode should obfuscate with AES-GCM")
+		}
+	})
+
+	t.Run("rejects xor", func(t *testing.T) {
+		_, configPath := writeConfig(t, map[string]string{
+			"dev": "API_KEY=secret-dev\n",
+		}, func(config *envied.ConfigFile) {
+			config.CryptoMode = envied.CryptoModeFIPS
+			config.Obfuscation = envied.ObfuscationXOR
+		})
+
+		err := envied.GenerateFromConfigFile(configPath)
+		if err == nil || !strings.Contains(err.Error(), "does not allow 'xor' obfuscation") {
+			t.Errorf("Expected xor to be rejected in FIPS mode, got %v", err)
+		}
+	})
+
+	t.Run("rejects unknown mode", func(t *testing.T) {
+		_, configPath := writeConfig(t, map[string]string{
+			"dev": "API_KEY=secret-dev\n",
+		}, func(config *envied.ConfigFile) {
+			config.CryptoMode = "fips-140-9"
+		})
+
+		err := envied.GenerateFromConfigFile(configPath)
+		if err == nil || !strings.Contains(err.Error(), "unknown crypto mode") {
+			t.Errorf("Expected unknown crypto mode error, got %v", err)
+		}
+	})
+}
+
+func TestManifestReportsCrypto(t *testing.T) {
+	t.Setenv("ENVIED_ARTIFACT_KEY", "BwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwcHBwc=")
+
+	tests := []struct {
+		name     string
+		modify   func(*envied.ConfigFile)
+		expected envied.ManifestCrypto
+	}{
+		{"default", func(*envied.ConfigFile) {}, envied.ManifestCrypto{Mode: "default", Obfuscation: "XOR"}},
+		{"none", func(config *envied.ConfigFile) {
+			config.Obfuscation = envied.ObfuscationNone
+		}, envied.ManifestCrypto{Mode: "default", Obfuscation: "none"}},
+		{"fips", func(config *envied.ConfigFile) {
+			config.CryptoMode = envied.CryptoModeFIPS
+		}, envied.ManifestCrypto{Mode: "fips", Obfuscation: "AES-256-GCM", KeyDerivation: "HKDF-SHA256"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifestPath := filepath.Join(t.TempDir(), "envied.json")
+			generateConfig(t, map[string]string{
+				"dev": "API_KEY=secret-dev\n",
+			}, func(config *envied.ConfigFile) {
+				config.Emit = &envied.EmitConfig{Manifest: manifestPath}
+				tt.modify(config)
+			})
+
+			data, err := os.ReadFile(manifestPath)
+			if err != nil {
+				t.Fatalf("Failed to read manifest: %v", err)
+			}
+			var manifest envied.Manifest
+			if err := json.Unmarshal(data, &manifest); err != nil {
+				t.Fatalf("Manifest is not valid JSON: %v", err)
+			}
+			if manifest.Crypto == nil || *manifest.Crypto != tt.expected {
+				t.Errorf("Manifest crypto = %+v, expected %+v", manifest.Crypto, tt.expected)
+			}
+		})
+	}
+}
+
+func TestSealString(t *testing.T) {
+	key, data, err := envied.SealString("API_KEY", "secret", 42)
+	if err != nil {
+		t.Fatalf("SealString() returned error: %v", err)
+	}
+	if bytes.Contains(data, []byte("secret")) {
+		t.Error("Sealed data must not contain the plain value")
+	}
+
+	value, err := envied.OpenString("API_KEY", key, data)
+	if err != nil || value != "secret" {
+		t.Errorf("OpenString() = %q, %v, expected %q", value, err, "secret")
+	}
+	if _, err := envied.OpenString("OTHER_KEY", key, data); err == nil {
+		t.Error("OpenString() expected error for a different variable name")
+	}
+
+	key2, data2, err := envied.SealString("API_KEY", "secret", 42)
+	if err != nil {
+		t.Fatalf("SealString() returned error: %v", err)
+	}
+	if !bytes.Equal(key, key2) || !bytes.Equal(data, data2) {
+		t.Error("SealString() with a seed should be reproducible")
+	}
+
+	_, random1, _ := envied.SealString("API_KEY", "secret", 0)
+	_, random2, _ := envied.SealString("API_KEY", "secret", 0)
+	if bytes.Equal(random1, random2) {
+		t.Error("SealString() without a seed should be random")
+	}
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+const legacyGenerated = `// Code generated by go-envied. DO NOT EDIT.
+// Generated merged configuration file for all environments
+
+package config
+
+import "github.com/petrovyuri/go-envied"
+
+var dev_enviedkeyTOKEN = []int{1, 2}
+
+var dev_envieddataTOKEN = []int{1 ^ 'o', 2 ^ 'k'}
+
+type DevConfigConfig struct {
+	TOKEN string
+}
+
+func NewDevConfigConfig() *DevConfigConfig {
+	return &DevConfigConfig{
+		TOKEN: envied.DeobfuscateString(dev_enviedkeyTOKEN, dev_envieddataTOKEN),
+	}
+}
+`
+
+func TestDecodeString(t *testing.T) {
+	keys, values := envied.ObfuscateString("secret", 42)
+
+	value, err := envied.DecodeString(keys, values)
+	if err != nil {
+		t.Fatalf("DecodeString() returned error: %v", err)
+	}
+	if value != "secret" {
+		t.Errorf("DecodeString() = %q, expected %q", value, "secret")
+	}
+
+	if _, err := envied.DecodeString(keys, values[:2]); err == nil {
+		t.Error("Expected error for mismatched lengths")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("MustDecodeString() should panic for mismatched lengths")
+		}
+	}()
+	envied.MustDecodeString(keys, values[:2])
+}
+
+func TestFixFile(t *testing.T) {
+	tempDir := t.TempDir()
+	generatedFile := filepath.Join(tempDir, "config_env.gen.go")
+	if err := os.WriteFile(generatedFile, []byte(legacyGenerated), 0644); err != nil {
+		t.Fatalf("Failed to create generated file: %v", err)
+	}
+
+	fixed, err := envied.FixFile(generatedFile)
+	if err != nil {
+		t.Fatalf("FixFile() returned error: %v", err)
+	}
+	if !fixed {
+		t.Fatal("FixFile() should report the legacy file as changed")
+	}
+
+	content, err := os.ReadFile(generatedFile)
+	if err != nil {
+		t.Fatalf("Failed to read fixed file: %v", err)
+	}
+	if !strings.Contains(string(content), "envied.MustDecodeString(dev_enviedkeyTOKEN, dev_envieddataTOKEN)") {
+		t.Errorf("Deprecated call was not rewritten:\n%s", content)
+	}
+	if strings.Contains(string(content), "DeobfuscateString") {
+		t.Error("Fixed file should not use deprecated helpers")
+	}
+
+	// Running again is a no-op
+	fixed, err = envied.FixFile(generatedFile)
+	if err != nil {
+		t.Fatalf("FixFile() returned error: %v", err)
+	}
+	if fixed {
+		t.Error("FixFile() should not change an up-to-date file")
+	}
+}
+
+func TestFixDirSkipsHandWrittenFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	handWritten := strings.Replace(legacyGenerated, "// Code generated by go-envied. DO NOT EDIT.\n", "", 1)
+
+	files := map[string]string{
+		"config_env.gen.go":  legacyGenerated,
+		"config.go":          handWritten,
+		"nested/old.gen.go":  legacyGenerated,
+		"notes.txt":          "envied.DeobfuscateString",
+		".hidden/old.gen.go": legacyGenerated,
+	}
+	for name, content := range files {
+		path := filepath.Join(tempDir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	changed, err := envied.FixDir(tempDir)
+	if err != nil {
+		t.Fatalf("FixDir() returned error: %v", err)
+	}
+
+	expected := []string{
+		filepath.Join(tempDir, "config_env.gen.go"),
+		filepath.Join(tempDir, "nested", "old.gen.go"),
+	}
+	if strings.Join(changed, ",") != strings.Join(expected, ",") {
+		t.Errorf("FixDir() changed %v, expected %v", changed, expected)
+	}
+
+	content, _ := os.ReadFile(filepath.Join(tempDir, "config.go"))
+	if string(content) != handWritten {
+		t.Error("Hand-written files should not be modified")
+	}
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestEmitFixtures(t *testing.T) {
+	fixturesDir := filepath.Join(t.TempDir(), "fixtures")
+	variables := map[string]envied.VariableConfig{
+		"HOSTS":   {Separator: ";"},
+		"KEY":     {Type: "[]byte", Encoding: "hex"},
+		"LIMITS":  {Type: "map[string]int"},
+		"SECRET":  {Only: []string{"prod"}},
+		"STARTED": {TimeLayout: "DateOnly"},
+		"TIMEOUT": {Type: "int64"},
+	}
+	envs := map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\nDEBUG=true\nRATE=0.25\nHOSTS=a;b\nKEY=c0ffee\nLIMITS={\"rps\":10}\nSTARTED=2024-05-01\nTIMEOUT=30\nTRUE=yes\n",
+		"prod": "API_URL=https://api.example.com\nPORT=1\nDEBUG=false\nRATE=0.5\nHOSTS=c;d\nKEY=deadbeef\nLIMITS={\"rps\":100}\nSTARTED=2024-06-01\nTIMEOUT=60\nTRUE=no\nSECRET=prod-secret\n",
+	}
+
+	generateConfig(t, envs, func(config *envied.ConfigFile) {
+		config.Variables = variables
+		config.Emit = &envied.EmitConfig{Fixtures: fixturesDir, Manifest: filepath.Join(fixturesDir, "real.json")}
+	})
+
+	fixture, err := os.ReadFile(filepath.Join(fixturesDir, "prod.env"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	for _, real := range []string{"api.example.com", "prod-secret", "deadbeef", "2024-06-01", "100"} {
+		if strings.Contains(string(fixture), real) {
+			t.Errorf("Fixture must not contain the real value %q:\n%s", real, fixture)
+		}
+	}
+	for _, want := range []string{"API_URL=\"fake-api-url\"\n", "HOSTS=\"fake-hosts-1;fake-hosts-2\"\n", "STARTED=2000-01-01\n", "SECRET=\"fake-secret\"\n"} {
+		if !strings.Contains(string(fixture), want) {
+			t.Errorf("Fixture should contain %q:\n%s", want, fixture)
+		}
+	}
+
+	// Generating from the fixtures gives the same variables and types
+	fixtureEnvs := make(map[string]string)
+	for envName := range envs {
+		content, err := os.ReadFile(filepath.Join(fixturesDir, envName+".env"))
+		if err != nil {
+			t.Fatalf("Failed to read fixture: %v", err)
+		}
+		fixtureEnvs[envName] = string(content)
+	}
+	generateConfig(t, fixtureEnvs, func(config *envied.ConfigFile) {
+		config.Variables = variables
+		config.Emit = &envied.EmitConfig{Manifest: filepath.Join(fixturesDir, "fake.json")}
+	})
+
+	manifests := make(map[string]envied.Manifest)
+	for _, name := range []string{"real.json", "fake.json"} {
+		data, err := os.ReadFile(filepath.Join(fixturesDir, name))
+		if err != nil {
+			t.Fatalf("Failed to read manifest: %v", err)
+		}
+		var manifest envied.Manifest
+		if err := json.Unmarshal(data, &manifest); err != nil {
+			t.Fatalf("Manifest is not valid JSON: %v", err)
+		}
+		manifests[name] = manifest
+	}
+	real, fake := manifests["real.json"].Variables, manifests["fake.json"].Variables
+	if len(real) != len(fake) {
+		t.Fatalf("Fixture variables = %+v, expected %+v", fake, real)
+	}
+	for i := range real {
+		if real[i].Name != fake[i].Name || real[i].Type != fake[i].Type || strings.Join(real[i].Environments, ",") != strings.Join(fake[i].Environments, ",") {
+			t.Errorf("Fixture variable %+v, expected %+v", fake[i], real[i])
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// Seeds of the fuzz targets, run by go test; go test -fuzz=Fuzz<Name> explores from them
+
+var envFileSeeds = []string{
+	"API_URL=https://dev.example.com\nPORT=8080\n",
+	"# envied: type=string, sensitive=true\nAPI_KEY='literal ${X}'\n",
+	"export DEBUG=true\nNAME=\"quoted\"\nURL=${API_URL:-fallback}/v1\n",
+	"=no-key\nNO_VALUE\n  SPACED = value  \n",
+	"A=${B}\nB=${A}\n",
+	"NUL=a\x00b\n",
+	"BAD=\xff\xfe\n",
+	"CRLF=value\r\nNEXT=1\r\n",
+	"ESCAPED=\"a\\n\\\"b\\\\\" # comment\nUNTERMINATED='x\n",
+}
+
+var valueSeeds = []string{"", "0", "1", "true", "-42", "3.14", "1e308", "NaN", "-Inf", "2024-01-02T15:04:05Z", "héllo 🌍", "\xff"}
+
+func FuzzReadEnvFile(f *testing.F) {
+	for _, seed := range envFileSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, content string) {
+		filename := filepath.Join(t.TempDir(), "fuzz.env")
+		if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write env file: %v", err)
+		}
+
+		values, err := envied.ReadEnvFileWithMetadata(filename)
+		if strings.ContainsRune(content, 0) || !utf8.ValidString(content) {
+			if err == nil {
+				t.Fatal("Env files with NUL bytes or invalid UTF-8 must be rejected")
+			}
+			return
+		}
+		if err != nil {
+			return
+		}
+		for key, value := range values {
+			if key != strings.TrimSpace(key) || strings.ContainsAny(key, "=\n") {
+				t.Errorf("Malformed key %q", key)
+			}
+			if value.Line < 1 {
+				t.Errorf("Value of %s has no line", key)
+			}
+		}
+
+		// Interpolation must fail or succeed, never hang or panic
+		_, _ = envied.ReadEnvFile(filename)
+	})
+}
+
+func TestReadEnvFileRejectsMalformedContent(t *testing.T) {
+	cases := map[string]string{
+		"NUL byte":      "A=1\nB=a\x00b\n",
+		"invalid UTF-8": "A=1\nB=\xff\n",
+		"enormous line": "A=1\nB=" + strings.Repeat("x", 1<<21) + "\n",
+	}
+	for name, content := range cases {
+		t.Run(name, func(t *testing.T) {
+			filename := filepath.Join(t.TempDir(), "bad.env")
+			if err := os.WriteFile(filename, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write env file: %v", err)
+			}
+			_, err := envied.ReadEnvFile(filename)
+			if err == nil {
+				t.Fatal("ReadEnvFile() expected error")
+			}
+			if !strings.Contains(err.Error(), "bad.env:2") {
+				t.Errorf("Error should point at the line: %v", err)
+			}
+		})
+	}
+}
+
+func FuzzDetectFieldType(f *testing.F) {
+	for _, seed := range valueSeeds {
+		f.Add(seed)
+	}
+
+	f.Fuzz(func(t *testing.T, value string) {
+		var err error
+		switch fieldType := envied.DetectFieldType(value); fieldType {
+		case envied.FieldTypeBool:
+			_, err = strconv.ParseBool(value)
+		case envied.FieldTypeInt:
+			_, err = strconv.Atoi(value)
+		case envied.FieldTypeFloat:
+			_, err = strconv.ParseFloat(value, 64)
+		case envied.FieldTypeTime:
+			envied.ParseTime("2006-01-02T15:04:05Z07:00", value)
+		case envied.FieldTypeString:
+		default:
+			t.Fatalf("Unexpected type %s for %q", fieldType, value)
+		}
+		if err != nil {
+			t.Errorf("Value %q detected as a type it doesn't parse as: %v", value, err)
+		}
+	})
+}
+
+func FuzzObfuscateRoundTrip(f *testing.F) {
+	for _, seed := range valueSeeds {
+		f.Add(seed, "go-envied-obfuscation", int64(42))
+	}
+	f.Add("value", "", int64(0))
+
+	f.Fuzz(func(t *testing.T, value, key string, seed int64) {
+		if got := envied.Deobfuscate(envied.Obfuscate(value, key), key); got != value {
+			t.Errorf("Obfuscate round trip of %q with key %q returned %q", value, key, got)
+		}
+
+		// Values obfuscated per rune are read from env files, which are valid UTF-8
+		if !utf8.ValidString(value) {
+			return
+		}
+		keys, encrypted := envied.ObfuscateString(value, seed)
+		got, err := envied.DecodeString(keys, encrypted)
+		if err != nil {
+			t.Fatalf("DecodeString() returned error: %v", err)
+		}
+		if got != value {
+			t.Errorf("ObfuscateString round trip of %q returned %q", value, got)
+		}
+	})
+}
@@ -0,0 +1,242 @@
+package test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"go/format"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// writeConfig writes env files for the given environments and a config file
+// generating into <tempDir>/config, and returns the temp directory and config path
+func writeConfig(t *testing.T, envs map[string]string, modify func(*envied.ConfigFile)) (string, string) {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	config := &envied.ConfigFile{
+		PackageName:  "config",
+		OutputDir:    filepath.Join(tempDir, "config"),
+		RandomSeed:   12345,
+		Environments: make(map[string]envied.EnvironmentConfig),
+	}
+
+	for envName, content := range envs {
+		envFile := filepath.Join(tempDir, envName+".env")
+		if err := os.WriteFile(envFile, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", envFile, err)
+		}
+		config.Environments[envName] = envied.EnvironmentConfig{
+			EnvFile:    envFile,
+			StructName: strings.ToUpper(envName[:1]) + envName[1:],
+		}
+	}
+
+	if modify != nil {
+		modify(config)
+	}
+
+	configPath := filepath.Join(tempDir, envied.DefaultConfigFileName)
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	return tempDir, configPath
+}
+
+// generateConfig generates the merged configuration into <tempDir>/config
+// and returns the temp directory and generated source
+func generateConfig(t *testing.T, envs map[string]string, modify func(*envied.ConfigFile)) (string, string) {
+	t.Helper()
+
+	tempDir, configPath := writeConfig(t, envs, modify)
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+
+	return tempDir, string(content)
+}
+
+// runGenerated compiles and runs a main package using the generated "config" package,
+// buildFlags are passed to go run
+func runGenerated(t *testing.T, dir string, mainSrc string, buildFlags ...string) (string, error) {
+	t.Helper()
+
+	root, err := filepath.Abs("..")
+	if err != nil {
+		t.Fatalf("Failed to resolve module root: %v", err)
+	}
+
+	goMod := "module generated\n\ngo 1.25\n\nrequire github.com/petrovyuri/go-envied v0.0.0\n\n" +
+		"replace github.com/petrovyuri/go-envied => " + root + "\n"
+	if err := os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(mainSrc), 0644); err != nil {
+		t.Fatalf("Failed to create main.go: %v", err)
+	}
+
+	args := append(append([]string{"run"}, buildFlags...), ".")
+	cmd := exec.Command("go", args...)
+	cmd.Dir = dir
+	output, err := cmd.CombinedOutput()
+	return string(output), err
+}
+
+func TestGeneratedStrictFactory(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\n",
+	}, nil)
+
+	if !strings.Contains(content, `var Environments = []string{"dev", "prod"}`) {
+		t.Error("Generated code should list environments in sorted order")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"errors"
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	cfg, err := config.NewEnvironmentConfig("prod")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(cfg.GetAPI_URL(), cfg.GetPORT())
+
+	_, err = config.NewEnvironmentConfig("staging")
+	var unknown *config.ErrUnknownEnvironment
+	if !errors.As(err, &unknown) {
+		panic("expected ErrUnknownEnvironment")
+	}
+	fmt.Println(err)
+}
+`)
+	if err != nil {
+		t.Fatalf("Generated code failed: %v\n%s", err, output)
+	}
+
+	expected := "https://api.example.com 80\nunknown environment 'staging', valid environments: dev, prod\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestErrUnknownEnvironment(t *testing.T) {
+	var err error = &envied.ErrUnknownEnvironment{Name: "stage", Valid: []string{"dev", "prod"}}
+
+	var unknown *envied.ErrUnknownEnvironment
+	if !errors.As(err, &unknown) {
+		t.Fatal("errors.As should match *ErrUnknownEnvironment")
+	}
+	if unknown.Name != "stage" {
+		t.Errorf("Name = %q, expected %q", unknown.Name, "stage")
+	}
+	if !strings.Contains(err.Error(), "dev, prod") {
+		t.Errorf("Error() = %q, expected list of valid environments", err.Error())
+	}
+}
+
+func TestGeneratedMetadataAccessors(t *testing.T) {
+	t.Setenv("SOURCE_DATE_EPOCH", "1700000000")
+
+	devContent := "API_URL=https://dev.example.com\n"
+	dir, _ := generateConfig(t, map[string]string{
+		"dev":  devContent,
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	var cfg config.ConfigInterface = config.NewDevConfig()
+	fmt.Println(cfg.Environment())
+	fmt.Println(cfg.GeneratedAt().Unix())
+	fmt.Println(cfg.SourceHash())
+}
+`)
+	if err != nil {
+		t.Fatalf("Generated code failed: %v\n%s", err, output)
+	}
+
+	sum := sha256.Sum256([]byte(devContent))
+	expected := "dev\n1700000000\n" + hex.EncodeToString(sum[:]) + "\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestGenerateRuntimeImport(t *testing.T) {
+	if envied.RuntimeImportPath != "github.com/petrovyuri/go-envied" {
+		t.Errorf("RuntimeImportPath = %q, expected %q", envied.RuntimeImportPath, "github.com/petrovyuri/go-envied")
+	}
+
+	_, content := generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+	if !strings.Contains(content, "\t\"github.com/petrovyuri/go-envied\"\n") {
+		t.Error("Generated code should import the detected runtime path")
+	}
+
+	_, content = generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.RuntimeImport = "example.com/fork/enviedruntime"
+	})
+	if !strings.Contains(content, "\tenvied \"example.com/fork/enviedruntime\"\n") {
+		t.Error("Generated code should import the configured runtime path with an alias")
+	}
+}
+
+func TestGeneratedCodeIsFormatted(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nGREETING=\"say \\\"hi\\\" \\\\o/\"\nPORT=8080\nRATE=2.5\n",
+		"prod": "API_URL=https://api.example.com\nGREETING='`backquoted`'\nPORT=80\nRATE=1\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		config.BuildTags = true
+	})
+
+	files, err := filepath.Glob(filepath.Join(dir, "config", "*.go"))
+	if err != nil || len(files) < 2 {
+		t.Fatalf("Expected the generated file and build tag files, got %v (%v)", files, err)
+	}
+	for _, file := range files {
+		source, err := os.ReadFile(file)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file, err)
+		}
+		formatted, err := format.Source(source)
+		if err != nil {
+			t.Fatalf("%s doesn't parse: %v", file, err)
+		}
+		if string(formatted) != string(source) {
+			t.Errorf("%s is not gofmt-clean", filepath.Base(file))
+		}
+	}
+	if !strings.Contains(content, `GREETING: "say \"hi\" \\o/",`) {
+		t.Errorf("Generated code should contain the escaped greeting:\n%s", content)
+	}
+}
@@ -0,0 +1,116 @@
+package test
+
+import (
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGeneratorDoesNotModifyConfig(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("GENERATOR_TOKEN", "secret-token")
+
+	config := &envied.Config{
+		PackageName: "config",
+		Environment: "Dev",
+		OutputDir:   tempDir,
+		Fields: []envied.Field{
+			{EnvName: "GENERATOR_TOKEN", Type: envied.FieldTypeString},
+		},
+	}
+	generator := envied.NewGenerator(config)
+	outputFile := filepath.Join(tempDir, "config_dev.go")
+
+	if err := generator.GenerateFromEnvVars(); err != nil {
+		t.Fatalf("GenerateFromEnvVars() returned error: %v", err)
+	}
+	first, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+
+	if config.Fields[0].Value != "" {
+		t.Errorf("Config field value was modified to %q", config.Fields[0].Value)
+	}
+
+	// Reusing the generator must produce the same output
+	if err := generator.GenerateFromEnvVars(); err != nil {
+		t.Fatalf("GenerateFromEnvVars() returned error: %v", err)
+	}
+	second, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+
+	if string(first) != string(second) {
+		t.Errorf("Reused generator produced different output:\n%s\n---\n%s", first, second)
+	}
+}
+
+func TestGeneratorConcurrentGenerate(t *testing.T) {
+	tempDir := t.TempDir()
+	envFile := filepath.Join(tempDir, "dev.env")
+	if err := os.WriteFile(envFile, []byte("API_URL=https://dev.example.com\nPORT=8080\n"), 0644); err != nil {
+		t.Fatalf("Failed to create dev.env: %v", err)
+	}
+
+	config := &envied.Config{
+		PackageName: "config",
+		Environment: "Dev",
+		OutputDir:   filepath.Join(tempDir, "out"),
+	}
+	generator := envied.NewGenerator(config)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 8)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			errs <- generator.GenerateFromEnvFile(envFile)
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("GenerateFromEnvFile() returned error: %v", err)
+		}
+	}
+
+	if len(config.Fields) != 0 {
+		t.Errorf("Config fields were modified: %v", config.Fields)
+	}
+}
+
+func TestGeneratorQuotesValues(t *testing.T) {
+	tempDir := t.TempDir()
+	t.Setenv("QUOTED_INT", `8"0\`)
+	t.Setenv("QUOTED_BOOL", `tr"ue`)
+	t.Setenv("QUOTED_FLOAT", "1.5\\n")
+
+	config := &envied.Config{
+		PackageName: "config",
+		Environment: "Dev",
+		OutputDir:   tempDir,
+		Fields: []envied.Field{
+			{EnvName: "QUOTED_INT", Type: envied.FieldTypeInt},
+			{EnvName: "QUOTED_BOOL", Type: envied.FieldTypeBool},
+			{EnvName: "QUOTED_FLOAT", Type: envied.FieldTypeFloat},
+		},
+	}
+	if err := envied.NewGenerator(config).GenerateFromEnvVars(); err != nil {
+		t.Fatalf("GenerateFromEnvVars() returned error: %v", err)
+	}
+
+	outputFile := filepath.Join(tempDir, "config_dev.go")
+	if _, err := parser.ParseFile(token.NewFileSet(), outputFile, nil, 0); err != nil {
+		t.Errorf("Generated file with quotes and backslashes in values doesn't parse: %v", err)
+	}
+}
@@ -0,0 +1,96 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGetterNaming(t *testing.T) {
+	prefix := ""
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\n",
+	}, func(config *envied.ConfigFile) {
+		config.Getters = &envied.GettersConfig{Prefix: &prefix, Receiver: envied.GetterReceiverValue}
+		config.Variables = map[string]envied.VariableConfig{
+			"API_URL": {Getter: "APIURL"},
+			"PORT":    {Getter: "Port"},
+		}
+	})
+
+	for _, want := range []string{
+		"\tAPIURL() string\n",
+		"func (c DevConfig) Port() int {",
+		"func (c DevConfig) Lookup(name string) (string, bool) {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	var cfg config.ConfigInterface = config.DevConfig{API_URL: "https://local", PORT: 1}
+	fmt.Println(cfg.APIURL(), cfg.Port(), config.NewProdConfig().Port())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if expected := "https://local 1 80\n"; output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestUnexportedGetters(t *testing.T) {
+	_, content := generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.Getters = &envied.GettersConfig{Unexported: true}
+	})
+
+	if !strings.Contains(content, "func (c *DevConfig) getAPI_URL() string {") {
+		t.Error("Generated getters should be unexported")
+	}
+}
+
+func TestGetterNamingErrors(t *testing.T) {
+	empty := ""
+	tests := []struct {
+		name     string
+		getters  *envied.GettersConfig
+		getter   string
+		expected string
+	}{
+		{"collides with the field", &envied.GettersConfig{Prefix: &empty}, "Port", "getter API_URL collides with the field API_URL"},
+		{"collides with a method", nil, "Lookup", "getter Lookup collides with a generated method"},
+		{"collides with another getter", nil, "GetAPI_URL", "variables API_URL and PORT have the same getter GetAPI_URL"},
+		{"not an identifier", nil, "Get-Port", `getter "Get-Port" is not a Go identifier`},
+		{"unknown receiver", &envied.GettersConfig{Receiver: "interface"}, "", "unknown getter receiver 'interface'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{
+				"dev": "API_URL=https://dev.example.com\nPORT=8080\n",
+			}, func(config *envied.ConfigFile) {
+				config.Getters = tt.getters
+				config.Variables = map[string]envied.VariableConfig{"PORT": {Getter: tt.getter}}
+			})
+
+			err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Validate() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// recoverPanic runs fn and returns the value it panicked with
+func recoverPanic(fn func()) (recovered any) {
+	defer func() { recovered = recover() }()
+	fn()
+	return nil
+}
+
+func TestWithoutNetworkPanicsOnAccess(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	tests := map[string]func(){
+		"default transport": func() { http.Get(server.URL) },
+		"consul source": func() {
+			envied.NewConsulSource(context.Background(), envied.ConsulConfig{Address: server.URL, Prefix: "app"})
+		},
+		"redis source": func() {
+			envied.NewRedisSource(context.Background(), envied.RedisConfig{Address: server.Listener.Addr().String(), Key: "app"})
+		},
+	}
+	for name, access := range tests {
+		t.Run(name, func(t *testing.T) {
+			recovered := recoverPanic(func() {
+				envied.WithoutNetwork(func() error {
+					access()
+					return nil
+				})
+			})
+			if recovered == nil || !strings.Contains(recovered.(string), "no-network mode") {
+				t.Errorf("Expected network access panic, got %v", recovered)
+			}
+		})
+	}
+
+	// Defaults are restored afterwards
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("http.Get() after WithoutNetwork returned error: %v", err)
+	}
+	response.Body.Close()
+}
+
+func TestWithoutNetworkOverlappingCalls(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	started, release, done := make(chan struct{}), make(chan struct{}), make(chan struct{})
+	go func() {
+		defer close(done)
+		envied.WithoutNetwork(func() error {
+			close(started)
+			<-release
+			return nil
+		})
+	}()
+	<-started
+
+	recovered := recoverPanic(func() {
+		envied.WithoutNetwork(func() error {
+			// The first call returns while this one still runs
+			close(release)
+			<-done
+			http.Get(server.URL)
+			return nil
+		})
+	})
+	if recovered == nil || !strings.Contains(recovered.(string), "no-network mode") {
+		t.Errorf("Expected network access panic after the first call returned, got %v", recovered)
+	}
+
+	// Defaults are restored when the last call returns
+	response, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("http.Get() after WithoutNetwork returned error: %v", err)
+	}
+	response.Body.Close()
+}
+
+func TestGenerateNoNetwork(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+
+	opts := envied.GenerateOptions{ConfigPath: configPath, NoNetwork: true}
+	if err := envied.Generate(opts); err != nil {
+		t.Fatalf("Generate() with NoNetwork returned error: %v", err)
+	}
+	if err := envied.Check(opts); err != nil {
+		t.Errorf("Check() with NoNetwork returned error: %v", err)
+	}
+
+	_, configPath = writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.Environments["prod"] = envied.EnvironmentConfig{
+			StructName: "Prod",
+			Consul:     &envied.ConsulConfig{Prefix: "myapp/prod"},
+		}
+	})
+	err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath, NoNetwork: true})
+	if err == nil || !strings.Contains(err.Error(), "environment 'prod' reads consul") {
+		t.Errorf("Expected remote source error, got %v", err)
+	}
+}
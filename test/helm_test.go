@@ -0,0 +1,47 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestEmitHelmValues(t *testing.T) {
+	helmDir := filepath.Join(t.TempDir(), "chart")
+	generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n# envied: sensitive=true\nAPI_KEY=dev-key\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\nAPI_KEY=prod-key\n",
+	}, func(config *envied.ConfigFile) {
+		obfuscate := false
+		dev := config.Environments["dev"]
+		dev.Obfuscate = &obfuscate
+		config.Environments["dev"] = dev
+		config.Emit = &envied.EmitConfig{Helm: helmDir, HelmSecret: "Backend_{environment}"}
+	})
+
+	dev, err := os.ReadFile(filepath.Join(helmDir, "values-dev.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	expected := "# Environment: dev\nenv:\n" +
+		"  - name: API_URL\n    value: \"https://dev.example.com\"\n" +
+		"  - name: PORT\n    value: \"8080\"\n" +
+		"secretEnv:\n  - name: API_KEY\n    valueFrom:\n      secretKeyRef:\n        name: backend-dev\n        key: API_KEY\n"
+	if !strings.HasSuffix(string(dev), expected) {
+		t.Errorf("Unexpected dev values:\n%s", dev)
+	}
+
+	prod, err := os.ReadFile(filepath.Join(helmDir, "values-prod.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read values: %v", err)
+	}
+	if strings.Contains(string(prod), "prod-key") || strings.Contains(string(prod), "api.example.com") {
+		t.Error("Helm values must not contain obfuscated values")
+	}
+	if want := "env:\n  - name: PORT\n    value: \"80\"\nsecretEnv:\n  - name: API_KEY\n    valueFrom:\n      secretKeyRef:\n        name: backend-prod\n        key: API_KEY\n"; !strings.Contains(string(prod), want) {
+		t.Errorf("Helm values should contain %q:\n%s", want, prod)
+	}
+}
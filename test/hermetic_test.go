@@ -0,0 +1,188 @@
+package test
+
+import (
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// captureStdout returns everything written to os.Stdout while fn runs
+func captureStdout(t *testing.T, fn func()) string {
+	t.Helper()
+
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+
+	stdout := os.Stdout
+	os.Stdout = writer
+	defer func() { os.Stdout = stdout }()
+
+	done := make(chan string)
+	go func() {
+		data, _ := io.ReadAll(reader)
+		done <- string(data)
+	}()
+
+	fn()
+	writer.Close()
+	return <-done
+}
+
+// writeHermeticProject creates a project with a config using paths relative to the config file
+func writeHermeticProject(t *testing.T) (string, string) {
+	t.Helper()
+
+	projectDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(projectDir, "env"), 0755); err != nil {
+		t.Fatalf("Failed to create env directory: %v", err)
+	}
+	for envName, content := range map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	} {
+		if err := os.WriteFile(filepath.Join(projectDir, "env", envName+".env"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create env file: %v", err)
+		}
+	}
+
+	config := &envied.ConfigFile{
+		PackageName: "config",
+		OutputDir:   "ignored",
+		RandomSeed:  12345,
+		Environments: map[string]envied.EnvironmentConfig{
+			"dev":  {EnvFile: "env/dev.env", StructName: "Dev"},
+			"prod": {EnvFile: "env/prod.env", StructName: "Prod"},
+		},
+	}
+	configPath := filepath.Join(projectDir, envied.DefaultConfigFileName)
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	return projectDir, configPath
+}
+
+func TestGenerateHermetic(t *testing.T) {
+	_, configPath := writeHermeticProject(t)
+	outputFile := filepath.Join(t.TempDir(), "bazel-out", "config_env.gen.go")
+
+	var err error
+	output := captureStdout(t, func() {
+		err = envied.GenerateHermetic(envied.HermeticOptions{
+			ConfigPath: configPath,
+			OutputFile: outputFile,
+		})
+	})
+	if err != nil {
+		t.Fatalf("GenerateHermetic() returned error: %v", err)
+	}
+	if output != "" {
+		t.Errorf("Hermetic mode should print nothing, got %q", output)
+	}
+
+	content, err := os.ReadFile(outputFile)
+	if err != nil {
+		t.Fatalf("Failed to read declared output: %v", err)
+	}
+	if !strings.Contains(string(content), "func NewProdConfig(opts ...Override)") {
+		t.Error("Generated file should contain the prod configuration")
+	}
+}
+
+func TestGenerateHermeticEnvFileOverride(t *testing.T) {
+	_, configPath := writeHermeticProject(t)
+	outputFile := filepath.Join(t.TempDir(), "config_env.gen.go")
+
+	overrideFile := filepath.Join(t.TempDir(), "prod.env")
+	if err := os.WriteFile(overrideFile, []byte("API_URL=https://override.example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to create override env file: %v", err)
+	}
+
+	err := envied.GenerateHermetic(envied.HermeticOptions{
+		ConfigPath: configPath,
+		OutputFile: outputFile,
+		EnvFiles:   map[string]string{"staging": overrideFile},
+	})
+	if err == nil || !strings.Contains(err.Error(), "unknown environment 'staging'") {
+		t.Errorf("Expected unknown environment error, got %v", err)
+	}
+
+	err = envied.GenerateHermetic(envied.HermeticOptions{
+		ConfigPath: configPath,
+		OutputFile: outputFile,
+		EnvFiles:   map[string]string{"prod": overrideFile},
+	})
+	if err != nil {
+		t.Fatalf("GenerateHermetic() returned error: %v", err)
+	}
+}
+
+func TestGenerateHermeticRejectsRemoteSources(t *testing.T) {
+	for source, modify := range map[string]func(*envied.EnvironmentConfig){
+		"reads consul": func(env *envied.EnvironmentConfig) {
+			env.EnvFile = ""
+			env.Consul = &envied.ConsulConfig{Address: "http://127.0.0.1:1", Prefix: "app/prod"}
+		},
+		"Vault": func(env *envied.EnvironmentConfig) {
+			env.Vault = &envied.VaultConfig{Address: "http://127.0.0.1:1", Path: "app/prod"}
+		},
+	} {
+		_, configPath := writeHermeticProject(t)
+		config, err := envied.LoadConfigFile(configPath)
+		if err != nil {
+			t.Fatalf("LoadConfigFile() returned error: %v", err)
+		}
+		prod := config.Environments["prod"]
+		modify(&prod)
+		config.Environments["prod"] = prod
+		if err := config.Save(configPath); err != nil {
+			t.Fatalf("Save() returned error: %v", err)
+		}
+
+		err = envied.GenerateHermetic(envied.HermeticOptions{
+			ConfigPath: configPath,
+			OutputFile: filepath.Join(t.TempDir(), "config_env.gen.go"),
+		})
+		if err == nil || !strings.Contains(err.Error(), source) || !strings.Contains(err.Error(), "hermetic mode") {
+			t.Errorf("Expected hermetic mode error for %s, got %v", source, err)
+		}
+
+		// An env file given for the environment replaces its source
+		overrideFile := filepath.Join(t.TempDir(), "prod.env")
+		if err := os.WriteFile(overrideFile, []byte("API_URL=https://override.example.com\n"), 0644); err != nil {
+			t.Fatalf("Failed to create override env file: %v", err)
+		}
+		outputFile := filepath.Join(t.TempDir(), "config_env.gen.go")
+		err = envied.GenerateHermetic(envied.HermeticOptions{
+			ConfigPath: configPath,
+			OutputFile: outputFile,
+			EnvFiles:   map[string]string{"prod": overrideFile},
+		})
+		if err != nil {
+			t.Errorf("GenerateHermetic() with an env file replacing %s returned error: %v", source, err)
+			continue
+		}
+		content, err := os.ReadFile(outputFile)
+		if err != nil {
+			t.Fatalf("Failed to read declared output: %v", err)
+		}
+		if !strings.Contains(string(content), "func NewProdConfig(opts ...Override)") {
+			t.Errorf("Generated file should contain the prod configuration read from the env file replacing %s", source)
+		}
+	}
+}
+
+func TestGenerateHermeticRequiresExplicitPaths(t *testing.T) {
+	if err := envied.GenerateHermetic(envied.HermeticOptions{OutputFile: "out.go"}); err == nil {
+		t.Error("Expected error without configuration path")
+	}
+	if err := envied.GenerateHermetic(envied.HermeticOptions{ConfigPath: "config.json"}); err == nil {
+		t.Error("Expected error without output path")
+	}
+}
@@ -0,0 +1,107 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestInt64AndUint64Fields(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "CHAT_ID=-1001234567890\nMAX_BYTES=18446744073709551615\nPORT=8080\n",
+		"prod": "CHAT_ID=-1009876543210\nMAX_BYTES=9223372036854775808\nPORT=80\n",
+	}, func(config *envied.ConfigFile) {
+		config.Variables = map[string]envied.VariableConfig{
+			"CHAT_ID":   {Type: "int64"},
+			"MAX_BYTES": {Type: "uint64"},
+		}
+	})
+
+	for _, want := range []string{
+		"GetCHAT_ID() int64",
+		"GetMAX_BYTES() uint64",
+		`CHAT_ID:   envied.ParseInt64("-1009876543210"),`,
+		`MAX_BYTES: envied.ParseUint64("9223372036854775808"),`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+	"github.com/petrovyuri/go-envied"
+)
+
+func main() {
+	dev := config.NewDevConfig()
+	fmt.Println(dev.GetCHAT_ID(), dev.GetMAX_BYTES())
+
+	value, _ := dev.Lookup("MAX_BYTES")
+	fmt.Println(value)
+
+	cfg, err := config.NewLayeredConfig("dev", envied.MapSource{"CHAT_ID": "-42"})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(cfg.GetCHAT_ID())
+
+	_, err = config.NewLayeredConfig("dev", envied.MapSource{"MAX_BYTES": "-1"})
+	fmt.Println(err)
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+
+	expected := "-1001234567890 18446744073709551615\n18446744073709551615\n-42\n" +
+		"❌ ERROR: variable MAX_BYTES: invalid uint64 value \"-1\"\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestIntegerOverflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		variable envied.VariableConfig
+		expected string
+	}{
+		{"int on 32-bit targets", "2147483648", envied.VariableConfig{}, `value 2147483648 overflows int on 32-bit targets, declare "type": "int64"`},
+		{"negative int on 32-bit targets", "-2147483649", envied.VariableConfig{}, "overflows int on 32-bit targets"},
+		{"int64", "9223372036854775808", envied.VariableConfig{}, `overflows int64, declare "type": "uint64"`},
+		{"uint64", "18446744073709551616", envied.VariableConfig{}, "overflows 64-bit integers, quote it"},
+		{"declared int64", "9223372036854775808", envied.VariableConfig{Type: "int64"}, `value "9223372036854775808" is not an int64`},
+		{"declared uint64", "-1", envied.VariableConfig{Type: "uint64"}, `value "-1" is not a uint64`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variable := tt.variable
+			_, configPath := writeConfig(t, map[string]string{
+				"dev": "LIMIT=" + tt.value + "\n",
+			}, func(config *envied.ConfigFile) {
+				config.Variables = map[string]envied.VariableConfig{"LIMIT": variable}
+			})
+
+			err := envied.GenerateFromConfigFile(configPath)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("GenerateFromConfigFile() error = %v, expected %q", err, tt.expected)
+			}
+		})
+	}
+
+	// The largest values fitting int on all targets are still int
+	_, content := generateConfig(t, map[string]string{
+		"dev": "LIMIT=2147483647\nFLOOR=-2147483648\n",
+	}, nil)
+	if !strings.Contains(content, "GetLIMIT() int\n") || !strings.Contains(content, "GetFLOOR() int\n") {
+		t.Error("Values within the int32 range should be int")
+	}
+}
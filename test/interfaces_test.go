@@ -0,0 +1,62 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateWithExtraVariables(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nDEBUG_TOOLBAR=true\n",
+		"prod": "API_URL=https://api.example.com\nREPLICAS=3\n",
+	}, func(config *envied.ConfigFile) {
+		config.AllowExtraVariables = true
+	})
+
+	iface := content[strings.Index(content, "type ConfigInterface interface"):]
+	iface = iface[:strings.Index(iface, "}")]
+	if !strings.Contains(iface, "GetAPI_URL() string") {
+		t.Error("Shared interface should contain common variables")
+	}
+	if strings.Contains(iface, "DEBUG_TOOLBAR") || strings.Contains(iface, "REPLICAS") {
+		t.Error("Shared interface should not contain environment specific variables")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	var dev config.DevInterface = config.NewDevConfig()
+	var prod config.ProdInterface = config.NewProdConfig()
+	var shared config.ConfigInterface = prod
+
+	fmt.Println(dev.GetDEBUG_TOOLBAR(), prod.GetREPLICAS(), shared.GetAPI_URL())
+}
+`)
+	if err != nil {
+		t.Fatalf("Generated code failed: %v\n%s", err, output)
+	}
+
+	expected := "true 3 https://api.example.com\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestGenerateWithExtraVariablesRequiresOptIn(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nDEBUG_TOOLBAR=true\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, nil)
+
+	if err := envied.GenerateFromConfigFile(configPath); err == nil {
+		t.Error("Expected consistency error without allow_extra_variables")
+	}
+}
@@ -0,0 +1,102 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateIntoInternalPackage(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\nDEBUG=false\n",
+	}, func(config *envied.ConfigFile) {
+		config.InternalPackage = true
+		config.AllowExtraVariables = true
+	})
+	if err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module generated\n\ngo 1.25\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+
+	internalFile := filepath.Join(tempDir, "internal", "envied", "config", "config_env.gen.go")
+	internal, err := os.ReadFile(internalFile)
+	if err != nil {
+		t.Fatalf("Generated code should be written to the internal package: %v", err)
+	}
+	if !strings.Contains(string(internal), "package config\n") {
+		t.Error("Internal package should be named after package_name")
+	}
+
+	shim, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read shim: %v", err)
+	}
+	for _, want := range []string{
+		`import enviedinternal "generated/internal/envied/config"`,
+		"type ProdConfig = enviedinternal.ProdConfig",
+		"type ProdInterface = enviedinternal.ProdInterface",
+		"var NewDevConfig = enviedinternal.NewDevConfig",
+	} {
+		if !strings.Contains(string(shim), want) {
+			t.Errorf("Shim should contain %q", want)
+		}
+	}
+	if strings.Contains(string(shim), "https://") || strings.Contains(string(shim), "_enviedkey") {
+		t.Error("Shim must not contain values")
+	}
+
+	opts := envied.GenerateOptions{ConfigPath: configPath}
+	if err := envied.Check(opts); err != nil {
+		t.Errorf("Check() after generation returned error: %v", err)
+	}
+	if err := envied.Verify(opts); err != nil {
+		t.Errorf("Verify() after generation returned error: %v", err)
+	}
+
+	output, err := runGenerated(t, tempDir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	var prod config.ProdInterface = config.NewProdConfig(config.WithPORT(81))
+	fmt.Println(prod.GetAPI_URL(), prod.GetPORT(), prod.GetDEBUG())
+
+	cfg, err := config.NewEnvironmentConfig("dev")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(cfg.GetAPI_URL(), config.Environments)
+}
+`)
+	if err != nil {
+		t.Fatalf("Generated code failed: %v\n%s", err, output)
+	}
+	expected := "https://api.example.com 81 false\nhttps://dev.example.com [dev prod]\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestInternalPackageRequiresModule(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.InternalPackage = true
+	})
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "inside a Go module") {
+		t.Errorf("Expected missing module error, got %v", err)
+	}
+}
@@ -0,0 +1,161 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// writeInterpolationConfig creates a dev environment over a base env file with interpolation
+func writeInterpolationConfig(t *testing.T, base, dev string) (string, string) {
+	t.Helper()
+
+	return writeConfig(t, map[string]string{"dev": dev}, func(config *envied.ConfigFile) {
+		baseFile := filepath.Join(filepath.Dir(config.OutputDir), "base.env")
+		if err := os.WriteFile(baseFile, []byte(base), 0644); err != nil {
+			t.Fatalf("Failed to create base env file: %v", err)
+		}
+		devConfig := config.Environments["dev"]
+		devConfig.BaseEnvFile = baseFile
+		config.Environments["dev"] = devConfig
+		config.Interpolate = true
+		config.Obfuscation = envied.ObfuscationNone
+	})
+}
+
+func TestInterpolationAcrossLayers(t *testing.T) {
+	base := "HOST=localhost\nAPI_URL=https://${HOST}:${PORT}/v1\nPORT=80\n"
+	tempDir, configPath := writeInterpolationConfig(t, base, "HOST=dev.example.com\nPRICE=$$5\n")
+	if err := os.WriteFile(filepath.Join(tempDir, "dev.env.local"), []byte("PORT=8080\n"), 0644); err != nil {
+		t.Fatalf("Failed to create local overlay: %v", err)
+	}
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+
+	for _, want := range []string{`"https://dev.example.com:8080/v1"`, `"$5"`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Generated code should contain %s", want)
+		}
+	}
+}
+
+func TestInterpolationErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		base     string
+		dev      string
+		expected string
+	}{
+		{"cycle", "B=${C}\nC=${A}\n", "A=x-${B}\n", "reference cycle A ({dir}/dev.env:1) -> B ({dir}/base.env:1) -> C ({dir}/base.env:2) -> A"},
+		{"self reference", "", "A=${A}\n", "reference cycle A ({dir}/dev.env:1) -> A"},
+		{"undefined", "B=${MISSING}\n", "A=${B}\n", "A ({dir}/dev.env:1) -> B ({dir}/base.env:1) references undefined variable ${MISSING}"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tempDir, configPath := writeInterpolationConfig(t, tt.base, tt.dev)
+			expected := strings.ReplaceAll(tt.expected, "{dir}", tempDir)
+			if err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath}); err == nil || !strings.Contains(err.Error(), expected) {
+				t.Errorf("Validate() = %v, expected error containing %q", err, expected)
+			}
+		})
+	}
+}
+
+func TestInterpolationDisabled(t *testing.T) {
+	_, content := generateConfig(t, map[string]string{"dev": "HOST=localhost\nURL=http://${HOST}\n"}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+	})
+	if !strings.Contains(content, `"http://${HOST}"`) {
+		t.Error("References should be kept without interpolate")
+	}
+}
+
+func TestReadEnvFileInterpolation(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	content := "API_URL=https://${API_HOST}/v1\nAPI_HOST=${SUBDOMAIN}.example.com\nSUBDOMAIN=api\nLITERAL='${API_HOST}'\nPRICE=\"$$5\"\nHOME_DIR=${ENVIED_TEST_HOME}/app\n"
+	if err := os.WriteFile(envFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create env file: %v", err)
+	}
+
+	if _, err := envied.ReadEnvFile(envFile); err == nil || !strings.Contains(err.Error(), "HOME_DIR ("+envFile+":6) references undefined variable ${ENVIED_TEST_HOME}") {
+		t.Errorf("ReadEnvFile() = %v, expected an undefined reference error", err)
+	}
+
+	t.Setenv("ENVIED_TEST_HOME", "/home/envied")
+	envVars, err := envied.ReadEnvFileWithLookup(envFile, os.LookupEnv)
+	if err != nil {
+		t.Fatalf("ReadEnvFileWithLookup() returned error: %v", err)
+	}
+	expected := map[string]string{
+		"API_URL":   "https://api.example.com/v1",
+		"API_HOST":  "api.example.com",
+		"SUBDOMAIN": "api",
+		"LITERAL":   "${API_HOST}",
+		"PRICE":     "$5",
+		"HOME_DIR":  "/home/envied/app",
+	}
+	if !reflect.DeepEqual(envVars, expected) {
+		t.Errorf("ReadEnvFileWithLookup() = %v, expected %v", envVars, expected)
+	}
+}
+
+func TestReadEnvFileInterpolationCycle(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	if err := os.WriteFile(envFile, []byte("A=${B}\nB=x${A}\n"), 0644); err != nil {
+		t.Fatalf("Failed to create env file: %v", err)
+	}
+
+	expected := "reference cycle A (" + envFile + ":1) -> B (" + envFile + ":2) -> A"
+	if _, err := envied.ReadEnvFile(envFile); err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("ReadEnvFile() = %v, expected error containing %q", err, expected)
+	}
+}
+
+func TestInterpolationDefaults(t *testing.T) {
+	envFile := filepath.Join(t.TempDir(), ".env")
+	content := "PORT=${PORT:-8080}\nHOST=${HOST-localhost}\nEMPTY=\nUNSET_OR_EMPTY=${EMPTY:-x}\nUNSET=${EMPTY-x}\nURL=http://${HOST}:${PORT}${PATH_PREFIX:-/}\n"
+	if err := os.WriteFile(envFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create env file: %v", err)
+	}
+
+	envVars, err := envied.ReadEnvFile(envFile)
+	if err != nil {
+		t.Fatalf("ReadEnvFile() returned error: %v", err)
+	}
+	expected := map[string]string{
+		"PORT":           "8080",
+		"HOST":           "localhost",
+		"EMPTY":          "",
+		"UNSET_OR_EMPTY": "x",
+		"UNSET":          "",
+		"URL":            "http://localhost:8080/",
+	}
+	if !reflect.DeepEqual(envVars, expected) {
+		t.Errorf("ReadEnvFile() = %v, expected %v", envVars, expected)
+	}
+
+	// Self references with a default read the process environment like docker-compose
+	t.Setenv("PORT", "9090")
+	envVars, err = envied.ReadEnvFileWithLookup(envFile, os.LookupEnv)
+	if err != nil {
+		t.Fatalf("ReadEnvFileWithLookup() returned error: %v", err)
+	}
+	if envVars["PORT"] != "9090" || envVars["URL"] != "http://localhost:9090/" {
+		t.Errorf("ReadEnvFileWithLookup() = %v, expected PORT from the process environment", envVars)
+	}
+
+	_, configPath := writeInterpolationConfig(t, "", "PORT=${PORT:-8080}\n")
+	if err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath}); err != nil {
+		t.Errorf("Validate() returned error for a default: %v", err)
+	}
+}
@@ -0,0 +1,112 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestReadJSONFile(t *testing.T) {
+	jsonFile := filepath.Join(t.TempDir(), "dev.json")
+	content := `{
+  "API_URL": "https://dev.example.com",
+  "PORT": 8080,
+  "RATIO": 0.5,
+  "DEBUG": false,
+  "VERSION": "007",
+  "EMPTY": null
+}
+`
+	if err := os.WriteFile(jsonFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create JSON file: %v", err)
+	}
+
+	envVars, err := envied.ReadJSONFile(jsonFile)
+	if err != nil {
+		t.Fatalf("ReadJSONFile() returned error: %v", err)
+	}
+
+	expected := map[string]envied.EnvValue{
+		"API_URL": {Value: "https://dev.example.com", WasQuoted: true, Line: 2},
+		"PORT":    {Value: "8080", Line: 3},
+		"RATIO":   {Value: "0.5", Line: 4},
+		"DEBUG":   {Value: "false", Line: 5},
+		"VERSION": {Value: "007", WasQuoted: true, Line: 6},
+		"EMPTY":   {Line: 7},
+	}
+	if len(envVars) != len(expected) {
+		t.Errorf("ReadJSONFile() = %+v, expected %d variables", envVars, len(expected))
+	}
+	for name, want := range expected {
+		if got := envVars[name]; got != want {
+			t.Errorf("%s = %+v, expected %+v", name, got, want)
+		}
+	}
+}
+
+func TestReadJSONFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"not an object", `["A"]`, ":1: expected an object of variables"},
+		{"nested object", "{\n  \"DB\": {\"HOST\": \"a\"}\n}", ":2: key DB: nested objects and arrays are not supported"},
+		{"array", `{"HOSTS": ["a"]}`, "key HOSTS: nested objects and arrays are not supported"},
+		{"duplicate key", "{\n  \"A\": 1,\n  \"A\": 2\n}", ":3: key A is already defined at line 2"},
+		{"trailing data", `{"A": 1} {}`, "unexpected data after the object"},
+		{"invalid", `{"A": }`, ":1: key A: "},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonFile := filepath.Join(t.TempDir(), "env.json")
+			if err := os.WriteFile(jsonFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create JSON file: %v", err)
+			}
+			if _, err := envied.ReadJSONFile(jsonFile); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("ReadJSONFile() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateFromJSONEnvFile(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "",
+	}, func(config *envied.ConfigFile) {
+		prodFile := filepath.Join(filepath.Dir(config.Environments["prod"].EnvFile), "prod.json")
+		if err := os.WriteFile(prodFile, []byte(`{"API_URL": "https://api.example.com", "PORT": 443}`), 0644); err != nil {
+			t.Fatalf("Failed to create JSON file: %v", err)
+		}
+		config.Environments["prod"] = envied.EnvironmentConfig{EnvFile: prodFile, StructName: "Prod"}
+	})
+
+	if !strings.Contains(content, "GetPORT() int") {
+		t.Error("JSON values should be typed like .env values")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	prod := config.NewProdConfig()
+	fmt.Println(prod.GetAPI_URL(), prod.GetPORT())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if expected := "https://api.example.com 443\n"; output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
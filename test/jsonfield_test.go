@@ -0,0 +1,101 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// jsonFieldProgram prints the map variables of the generated prod configuration
+const jsonFieldProgram = `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+	"github.com/petrovyuri/go-envied"
+)
+
+func main() {
+	prod := config.NewProdConfig()
+	fmt.Println(prod.GetFEATURE_FLAGS(), prod.GetLABELS(), prod.GetLIMITS()["nested"])
+
+	value, _ := prod.Lookup("FEATURE_FLAGS")
+	fmt.Println(value)
+
+	cfg, err := config.NewLayeredConfig("prod", envied.MapSource{"FEATURE_FLAGS": "{\"c\":true}"})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(cfg.GetFEATURE_FLAGS())
+
+	_, err = config.NewLayeredConfig("prod", envied.MapSource{"FEATURE_FLAGS": "yes"})
+	fmt.Println(err != nil)
+}
+`
+
+func TestJSONFields(t *testing.T) {
+	for _, obfuscation := range []string{envied.ObfuscationNone, envied.ObfuscationXOR} {
+		t.Run(obfuscation, func(t *testing.T) {
+			dir, content := generateConfig(t, map[string]string{
+				"dev":  "FEATURE_FLAGS={\"a\":false}\nLABELS={}\nLIMITS={\"nested\":{\"rps\":1}}\n",
+				"prod": "FEATURE_FLAGS={\"a\":true,\"b\":false}\nLABELS={\"team\":\"core\"}\nLIMITS={\"nested\":{\"rps\":100}}\n",
+			}, func(config *envied.ConfigFile) {
+				config.Obfuscation = obfuscation
+				config.Variables = map[string]envied.VariableConfig{
+					"FEATURE_FLAGS": {Type: "map[string]bool"},
+					"LABELS":        {Type: "map[string]string"},
+					"LIMITS":        {Type: "map[string]any"},
+				}
+			})
+
+			if !strings.Contains(content, "GetFEATURE_FLAGS() map[string]bool") {
+				t.Error("Generated code should contain a map getter")
+			}
+			if obfuscation == envied.ObfuscationXOR && strings.Contains(content, "team") {
+				t.Error("JSON values should be obfuscated")
+			}
+
+			output, err := runGenerated(t, dir, jsonFieldProgram)
+			if err != nil {
+				t.Fatalf("Failed to run program: %v\n%s", err, output)
+			}
+
+			expected := "map[a:true b:false] map[team:core] map[rps:100]\n{\"a\":true,\"b\":false}\nmap[c:true]\ntrue\n"
+			if output != expected {
+				t.Errorf("Output = %q, expected %q", output, expected)
+			}
+		})
+	}
+}
+
+func TestJSONFieldErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		variable envied.VariableConfig
+		expected string
+	}{
+		{"invalid JSON", "{a:true}", envied.VariableConfig{Type: "map[string]bool"}, "value is not a JSON object of map[string]bool"},
+		{"wrong value type", `{"a":"yes"}`, envied.VariableConfig{Type: "map[string]bool"}, "value is not a JSON object of map[string]bool"},
+		{"unsupported type", `{"a":1}`, envied.VariableConfig{Type: "map[int]int"}, `unsupported type "map[int]int"`},
+		{"with separator", `{"a":1}`, envied.VariableConfig{Type: "map[string]int", Separator: ","}, "type can't be combined with separator"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			variable := tt.variable
+			_, configPath := writeConfig(t, map[string]string{
+				"dev": "FEATURE_FLAGS=" + tt.value + "\n",
+			}, func(config *envied.ConfigFile) {
+				config.Variables = map[string]envied.VariableConfig{"FEATURE_FLAGS": variable}
+			})
+
+			err := envied.GenerateFromConfigFile(configPath)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("GenerateFromConfigFile() error = %v, expected %q", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,108 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestLayerPrecedence(t *testing.T) {
+	embedded := envied.MapSource{"API_URL": "https://api.example.com", "PORT": "80", "DEBUG": "false"}
+	file := envied.MapSource{"PORT": "8080", "DEBUG": "true"}
+	env := envied.MapSource{"PORT": "9090"}
+
+	layered := envied.Layer(embedded, nil, file, env)
+
+	expected := map[string]string{
+		"API_URL": "https://api.example.com",
+		"PORT":    "9090",
+		"DEBUG":   "true",
+	}
+	for name, want := range expected {
+		if value, exists := layered.Lookup(name); !exists || value != want {
+			t.Errorf("Lookup(%q) = %q, %v, expected %q", name, value, exists, want)
+		}
+	}
+	if _, exists := layered.Lookup("MISSING"); exists {
+		t.Error("Lookup() should report missing variables")
+	}
+}
+
+func TestOSEnvSource(t *testing.T) {
+	t.Setenv("ENVIED_LAYER_TEST", "from-env")
+
+	if value, exists := envied.OSEnv().Lookup("ENVIED_LAYER_TEST"); !exists || value != "from-env" {
+		t.Errorf("OSEnv().Lookup() = %q, %v", value, exists)
+	}
+}
+
+func TestCoerce(t *testing.T) {
+	if value, err := envied.CoerceInt("PORT", "8080"); err != nil || value != 8080 {
+		t.Errorf("CoerceInt() = %d, %v", value, err)
+	}
+	if value, err := envied.CoerceBool("DEBUG", "true"); err != nil || !value {
+		t.Errorf("CoerceBool() = %v, %v", value, err)
+	}
+	if value, err := envied.CoerceFloat("RATIO", "0.5"); err != nil || value != 0.5 {
+		t.Errorf("CoerceFloat() = %v, %v", value, err)
+	}
+
+	_, err := envied.CoerceInt("PORT", "eighty")
+	if err == nil || !strings.Contains(err.Error(), `variable PORT: invalid int value "eighty"`) {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestGeneratedLayeredConfig(t *testing.T) {
+	dir, _ := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\nDEBUG=true\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\nDEBUG=false\n",
+	}, nil)
+
+	deployFile := filepath.Join(dir, "deploy.env")
+	if err := os.WriteFile(deployFile, []byte("PORT=8443\nDEBUG=true\n"), 0644); err != nil {
+		t.Fatalf("Failed to create deploy env file: %v", err)
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+	"os"
+
+	"generated/config"
+	"github.com/petrovyuri/go-envied"
+)
+
+func main() {
+	file, err := envied.FileSource("deploy.env")
+	if err != nil {
+		panic(err)
+	}
+	os.Setenv("PORT", "9000")
+
+	cfg, err := config.NewLayeredConfig("prod", file, envied.OSEnv())
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(cfg.GetAPI_URL(), cfg.GetPORT(), cfg.GetDEBUG())
+
+	value, _ := envied.Layer(config.NewProdConfig(), file).Lookup("PORT")
+	fmt.Println(value)
+
+	_, err = config.NewLayeredConfig("prod", envied.MapSource{"PORT": "http"})
+	fmt.Println(err)
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+
+	expected := "https://api.example.com 9000 true\n8443\n❌ ERROR: variable PORT: invalid int value \"http\"\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
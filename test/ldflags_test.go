@@ -0,0 +1,148 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// generateLinked generates a configuration whose sensitive prod values are injected with -ldflags -X
+// into a module named like the one of runGenerated, returning the -ldflags recipes by environment
+func generateLinked(t *testing.T, modify func(*envied.ConfigFile)) (string, map[string]string) {
+	t.Helper()
+
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nAPI_KEY=changeme\nHOSTS=a.dev;b.dev\n",
+		"prod": "API_URL=https://api.example.com\n# envied: sensitive=true\nAPI_KEY=s3cr3t-prod-key\n# envied: sensitive=true\nHOSTS=a.prod;b.prod\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		config.Ldflags = true
+		config.Variables = map[string]envied.VariableConfig{"HOSTS": {Separator: ";"}}
+		if modify != nil {
+			modify(config)
+		}
+	})
+	if err := os.WriteFile(filepath.Join(tempDir, "go.mod"), []byte("module generated\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	recipes := make(map[string]string)
+	err := envied.Generate(envied.GenerateOptions{
+		ConfigPath: configPath,
+		Messages: func(message envied.Message) {
+			if message.ID == envied.MessageLdflags {
+				recipes[message.Args[0].(string)] = message.Args[1].(string)
+			}
+		},
+	})
+	if err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	return tempDir, recipes
+}
+
+const linkedMain = `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	if err := config.CheckLinkedValues("prod"); err != nil {
+		fmt.Println(err)
+		return
+	}
+	cfg, err := config.NewEnvironmentConfig("prod")
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(cfg.GetAPI_KEY(), cfg.GetHOSTS())
+}
+`
+
+func TestLdflags(t *testing.T) {
+	tempDir, recipes := generateLinked(t, nil)
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	code := string(content)
+	for _, expected := range []string{
+		"prod_enviedlinkedAPI_KEY string // API_KEY",
+		"API_KEY: prod_enviedlinkedAPI_KEY,",
+		`HOSTS:   envied.SplitList(prod_enviedlinkedHOSTS, ";"),`,
+		`API_KEY: "changeme",`,
+		"func CheckLinkedValues(env string) error {",
+	} {
+		if !strings.Contains(code, expected) {
+			t.Errorf("Generated code is missing %q", expected)
+		}
+	}
+	if strings.Contains(code, "s3cr3t-prod-key") || strings.Contains(code, "a.prod") {
+		t.Error("Generated code embeds values injected at link time")
+	}
+
+	expected := "-X 'generated/config.prod_enviedlinkedAPI_KEY=$API_KEY' -X 'generated/config.prod_enviedlinkedHOSTS=$HOSTS'"
+	if recipes["prod"] != expected {
+		t.Errorf("Recipe of prod = %q, expected %q", recipes["prod"], expected)
+	}
+	if recipe, exists := recipes["dev"]; exists {
+		t.Errorf("Recipe of dev without linked values = %q", recipe)
+	}
+
+	output, err := runGenerated(t, tempDir, linkedMain)
+	if err != nil {
+		t.Fatalf("Failed to run generated code: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "environment 'prod' is missing values injected with -ldflags -X: API_KEY, HOSTS") {
+		t.Errorf("Output without -ldflags = %q, expected the missing values", output)
+	}
+
+	output, err = runGenerated(t, tempDir, linkedMain, "-ldflags=-X 'generated/config.prod_enviedlinkedAPI_KEY=linked key' -X generated/config.prod_enviedlinkedHOSTS=a;b")
+	if err != nil {
+		t.Fatalf("Failed to run generated code: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "linked key [a b]") {
+		t.Errorf("Output with -ldflags = %q, expected the injected values", output)
+	}
+}
+
+func TestLdflagsSplitEnvironments(t *testing.T) {
+	tempDir, _ := generateLinked(t, func(config *envied.ConfigFile) {
+		config.BuildTags = true
+		config.SplitEnvironments = true
+	})
+
+	output, err := runGenerated(t, tempDir, linkedMain, "-tags=envied_prod")
+	if err != nil {
+		t.Fatalf("Failed to run generated code: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "missing values injected with -ldflags -X: API_KEY, HOSTS") {
+		t.Errorf("Output without -ldflags = %q, expected the missing values", output)
+	}
+
+	output, err = runGenerated(t, tempDir, linkedMain, "-tags=envied_prod", "-ldflags=-X generated/config.prod_enviedlinkedAPI_KEY=key -X generated/config.prod_enviedlinkedHOSTS=a")
+	if err != nil {
+		t.Fatalf("Failed to run generated code: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "key [a]") {
+		t.Errorf("Output with -ldflags = %q, expected the injected values", output)
+	}
+}
+
+func TestCheckLinked(t *testing.T) {
+	if err := envied.CheckLinked("prod", map[string]string{"API_KEY": "key"}); err != nil {
+		t.Errorf("CheckLinked() with all values = %v", err)
+	}
+	err := envied.CheckLinked("prod", map[string]string{"TOKEN": "", "API_KEY": "", "API_URL": "url"})
+	missing, ok := err.(*envied.ErrMissingLinkedValues)
+	if !ok || strings.Join(missing.Missing, ",") != "API_KEY,TOKEN" {
+		t.Errorf("CheckLinked() = %v, expected API_KEY and TOKEN missing", err)
+	}
+}
@@ -0,0 +1,64 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestLiteralEmbedding(t *testing.T) {
+	env := "PORT=08080\nDEBUG=1\nRATE=2.50\nLIMIT=Inf\nTIMEOUT=30\nMAX=18446744073709551615\n# envied: sensitive=true\nPIN=1234\nAPI_URL=https://api.example.com\n"
+	dir, content := generateConfig(t, map[string]string{"prod": env}, func(config *envied.ConfigFile) {
+		config.Literals = true
+		config.Variables = map[string]envied.VariableConfig{
+			"MAX":     {Type: "uint64"},
+			"TIMEOUT": {Type: "int64"},
+		}
+	})
+
+	expected := []string{
+		"\t\tPORT:    8080,\n",
+		"\t\tDEBUG:   true,\n",
+		"\t\tRATE:    2.5,\n",
+		"\t\tTIMEOUT: 30,\n",
+		"\t\tMAX:     18446744073709551615,\n",
+		// Infinity has no literal and sensitive values keep their Parse call
+		"\t\tLIMIT:   envied.ParseFloat(\"Inf\"),\n",
+		"\t\tPIN:     envied.ParseInt(\"1234\"),\n",
+	}
+	for _, want := range expected {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	c := config.NewProdConfig()
+	fmt.Println(c.GetPORT(), c.GetDEBUG(), c.GetRATE(), c.GetLIMIT(), c.GetTIMEOUT(), c.GetMAX(), c.GetPIN())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if output != "8080 true 2.5 +Inf 30 18446744073709551615 1234\n" {
+		t.Errorf("Unexpected output: %q", output)
+	}
+}
+
+func TestLiteralEmbeddingDisabled(t *testing.T) {
+	_, content := generateConfig(t, map[string]string{"prod": "PORT=8080\nDEBUG=true\n"}, nil)
+	for _, want := range []string{"\t\tPORT:  envied.ParseInt(\"8080\"),\n", "\t\tDEBUG: envied.ParseBool(\"true\"),\n"} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+}
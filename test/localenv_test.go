@@ -0,0 +1,80 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestLocalOverlay(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\nPORT=8080\n",
+	}, nil)
+	devFile := filepath.Join(tempDir, "dev.env")
+	localFile := filepath.Join(tempDir, "dev.env.local")
+	if err := os.WriteFile(localFile, []byte("PORT=9090\n"), 0644); err != nil {
+		t.Fatalf("Failed to create local overlay: %v", err)
+	}
+
+	output := captureStdout(t, func() {
+		if err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath, Verbose: true}); err != nil {
+			t.Errorf("Generate() returned error: %v", err)
+		}
+	})
+	if want := "🧭 dev/PORT: " + localFile + ":1 (overrides " + devFile + ":2)\n"; !strings.Contains(output, want) {
+		t.Errorf("Verbose output should contain %q, got:\n%s", want, output)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), `PORT:    envied.ParseInt("9090")`) {
+		t.Error("The local overlay should override the env file")
+	}
+
+	config, err := envied.LoadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+	dev := config.Environments["dev"]
+	dev.NoLocal = true
+	config.Environments["dev"] = dev
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+	content, err = os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), `PORT:    envied.ParseInt("8080")`) {
+		t.Error("no_local should ignore the local overlay")
+	}
+}
+
+func TestLocalOverlayKeepsFormat(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"dev.yaml":       "SERVER:\n  PORT: 8080\n",
+		"dev.local.yaml": "SERVER:\n  PORT: 9090\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to create %s: %v", name, err)
+		}
+	}
+
+	_, content := generateConfig(t, map[string]string{"dev": "PORT=1\n"}, func(config *envied.ConfigFile) {
+		dev := config.Environments["dev"]
+		dev.EnvFile = filepath.Join(dir, "dev.yaml")
+		config.Environments["dev"] = dev
+	})
+	if !strings.Contains(content, `SERVER_PORT: envied.ParseInt("9090")`) {
+		t.Error("The local overlay of a YAML env file should be read as YAML")
+	}
+}
@@ -0,0 +1,149 @@
+package test
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// serveManifests generates the manifest of a configuration, encrypted if key is set, and serves
+// it as service api next to the given extra manifests
+func serveManifests(t *testing.T, key []byte, extra map[string]string) (*httptest.Server, string) {
+	t.Helper()
+
+	tempDir, _ := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nAPI_KEY=dev-secret-value\n",
+		"prod": "API_URL=https://api.example.com\nAPI_KEY=prod-secret-value\n",
+	}, func(config *envied.ConfigFile) {
+		config.Emit = &envied.EmitConfig{Manifest: filepath.Join(config.OutputDir, "manifest.json")}
+		if key != nil {
+			t.Setenv("ENVIED_ARTIFACT_KEY", base64.StdEncoding.EncodeToString(key))
+			config.Emit.EncryptKeyEnv = "ENVIED_ARTIFACT_KEY"
+		}
+	})
+
+	manifests := map[string]string{"api": filepath.Join(tempDir, "config", "manifest.json")}
+	for service, path := range extra {
+		manifests[service] = path
+	}
+	handler, err := envied.NewManifestHandler(envied.ManifestServerOptions{Manifests: manifests, Token: "audit-token", Key: key})
+	if err != nil {
+		t.Fatalf("NewManifestHandler() returned error: %v", err)
+	}
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return server, tempDir
+}
+
+// getManifest requests a path of the manifest server with a token
+func getManifest(t *testing.T, server *httptest.Server, method, path, token string) (int, string) {
+	t.Helper()
+
+	request, err := http.NewRequest(method, server.URL+path, nil)
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	if token != "" {
+		request.Header.Set("Authorization", "Bearer "+token)
+	}
+	response, err := server.Client().Do(request)
+	if err != nil {
+		t.Fatalf("Request %s %s failed: %v", method, path, err)
+	}
+	defer response.Body.Close()
+	body, err := io.ReadAll(response.Body)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	return response.StatusCode, string(body)
+}
+
+func TestManifestServer(t *testing.T) {
+	server, _ := serveManifests(t, nil, nil)
+
+	status, body := getManifest(t, server, http.MethodGet, "/manifests", "audit-token")
+	if status != http.StatusOK {
+		t.Fatalf("GET /manifests = %d: %s", status, body)
+	}
+	var summaries []envied.ManifestSummary
+	if err := json.Unmarshal([]byte(body), &summaries); err != nil {
+		t.Fatalf("Listing is not valid JSON: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].Service != "api" || summaries[0].Variables != 2 {
+		t.Errorf("Listing = %+v, expected service api with 2 variables", summaries)
+	}
+
+	status, body = getManifest(t, server, http.MethodGet, "/manifests/api", "audit-token")
+	if status != http.StatusOK {
+		t.Fatalf("GET /manifests/api = %d: %s", status, body)
+	}
+	var manifest envied.Manifest
+	if err := json.Unmarshal([]byte(body), &manifest); err != nil {
+		t.Fatalf("Manifest is not valid JSON: %v", err)
+	}
+	if len(manifest.Variables) != 2 || manifest.Variables[0].Name != "API_KEY" {
+		t.Errorf("Manifest variables = %+v, expected API_KEY and API_URL", manifest.Variables)
+	}
+	if strings.Contains(body, "secret-value") || strings.Contains(body, "example.com") {
+		t.Error("Served manifest must not contain values")
+	}
+}
+
+func TestManifestServerRefusesRequests(t *testing.T) {
+	server, tempDir := serveManifests(t, nil, map[string]string{"leak": ""})
+	leak, _ := serveManifests(t, nil, map[string]string{"env": filepath.Join(tempDir, "prod.env")})
+
+	tests := []struct {
+		name   string
+		server *httptest.Server
+		method string
+		path   string
+		token  string
+		status int
+	}{
+		{"missing token", server, http.MethodGet, "/manifests/api", "", http.StatusUnauthorized},
+		{"wrong token", server, http.MethodGet, "/manifests/api", "audit-tokem", http.StatusUnauthorized},
+		{"write", server, http.MethodPost, "/manifests/api", "audit-token", http.StatusMethodNotAllowed},
+		{"unknown service", server, http.MethodGet, "/manifests/web", "audit-token", http.StatusNotFound},
+		{"unreadable manifest", server, http.MethodGet, "/manifests/leak", "audit-token", http.StatusInternalServerError},
+		{"env file instead of a manifest", leak, http.MethodGet, "/manifests/env", "audit-token", http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, body := getManifest(t, tt.server, tt.method, tt.path, tt.token)
+			if status != tt.status {
+				t.Errorf("%s %s = %d, expected %d: %s", tt.method, tt.path, status, tt.status, body)
+			}
+			if strings.Contains(body, "secret-value") || strings.Contains(body, "example.com") {
+				t.Errorf("Response must not contain values: %s", body)
+			}
+		})
+	}
+}
+
+func TestManifestServerDecryptsManifests(t *testing.T) {
+	key := make([]byte, 32)
+	if _, err := rand.Read(key); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	server, _ := serveManifests(t, key, nil)
+
+	status, body := getManifest(t, server, http.MethodGet, "/manifests/api", "audit-token")
+	if status != http.StatusOK || !strings.Contains(body, `"API_URL"`) {
+		t.Errorf("GET /manifests/api = %d, expected the decrypted manifest: %s", status, body)
+	}
+}
+
+func TestManifestServerRequiresToken(t *testing.T) {
+	if _, err := envied.NewManifestHandler(envied.ManifestServerOptions{Manifests: map[string]string{"api": "manifest.json"}}); err == nil {
+		t.Error("NewManifestHandler() expected error without a token")
+	}
+}
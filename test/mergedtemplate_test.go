@@ -0,0 +1,62 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestMergedDataRender(t *testing.T) {
+	port := envied.Field{EnvName: "PORT", FieldName: "PORT", Getter: "GetPORT", Type: envied.FieldTypeInt, Description: "Listening port"}
+	data := &envied.MergedData{
+		PackageName:   "config",
+		RuntimeImport: `"github.com/petrovyuri/go-envied"`,
+		Fields:        []envied.Field{port},
+		Environments: []envied.MergedEnvironmentData{{
+			Name:       "dev",
+			StructName: "Dev",
+			Data:       []envied.MergedVariableData{{Name: "devSecret", Comment: "Static key for SECRET in dev environment", Value: "[]int{1, 2}"}},
+			Types: []envied.MergedTypeData{{
+				Name:           "DevConfig",
+				Doc:            "generated configuration for dev environment",
+				ConstructorDoc: "a new configuration for dev environment",
+				Receiver:       "*",
+				Environment:    "dev",
+				SourceHash:     "abc",
+				Fields: []envied.MergedFieldData{{
+					Field:       port,
+					Initializer: `envied.ParseInt("8080")`,
+					Overridable: true,
+					Lookup:      "envied.FormatValue(c.PORT)",
+				}},
+			}},
+		}},
+		Overridable: []envied.Field{port},
+		Layered:     []envied.MergedFieldData{{Field: port, Coerce: `envied.CoerceInt("PORT", value)`}},
+	}
+
+	code, err := data.Render()
+	if err != nil {
+		t.Fatalf("Render() returned error: %v", err)
+	}
+	for _, want := range []string{
+		"package config\n",
+		"\t// GetPORT returns PORT - Listening port\n\tGetPORT() int\n",
+		`var Environments = []string{"dev"}`,
+		"var devSecret = []int{1, 2}\n",
+		"\t// Listening port\n\tPORT int\n",
+		"\t\tPORT: envied.ParseInt(\"8080\"),\n",
+		"\tif o.PORT != nil {\n\t\tc.PORT = *o.PORT\n\t}\n",
+		"\t\tparsed, err := envied.CoerceInt(\"PORT\", value)\n",
+		"func (c *DevConfig) Lookup(name string) (string, bool) {",
+		"\treturn \"abc\"\n",
+	} {
+		if !strings.Contains(string(code), want) {
+			t.Errorf("Rendered code should contain %q:\n%s", want, code)
+		}
+	}
+	if strings.Contains(string(code), "enviedObfuscationVersion") {
+		t.Error("The version marker is only written with an obfuscation version")
+	}
+}
@@ -0,0 +1,177 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateMessages(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nAPI_KEY=changeme\n",
+		"prod": "API_URL=https://api.example.com\nAPI_KEY=secret\n",
+	}, nil)
+
+	var messages []envied.Message
+	stdout := captureStdout(t, func() {
+		err := envied.Generate(envied.GenerateOptions{
+			ConfigPath: configPath,
+			Catalog:    envied.Catalog{envied.MessageGenerated: "✅ Konfiguration erzeugt"},
+			Messages:   func(message envied.Message) { messages = append(messages, message) },
+		})
+		if err != nil {
+			t.Fatalf("Generate() returned error: %v", err)
+		}
+	})
+	if stdout != "" {
+		t.Errorf("Generate() with a message handler wrote %q to standard output", stdout)
+	}
+
+	var ids []envied.MessageID
+	for _, message := range messages {
+		ids = append(ids, message.ID)
+	}
+	for _, expected := range []envied.MessageID{envied.MessageWarning, envied.MessageConsistencyPassed, envied.MessageGenerating, envied.MessageGenerated, envied.MessageAllGenerated} {
+		if !slices.Contains(ids, expected) {
+			t.Errorf("Messages %v are missing %s", ids, expected)
+		}
+	}
+
+	for _, message := range messages {
+		switch message.ID {
+		case envied.MessageGenerated:
+			if message.Text != "✅ Konfiguration erzeugt" {
+				t.Errorf("Generated message = %q, expected the catalog text", message.Text)
+			}
+		case envied.MessageWarning:
+			if len(message.Args) != 1 || !strings.Contains(message.Text, "API_KEY") {
+				t.Errorf("Warning message = %+v, expected the warning as argument", message)
+			}
+		case envied.MessageGenerating:
+			if message.Text != envied.DefaultCatalog[envied.MessageGenerating] {
+				t.Errorf("Generating message = %q, expected the default text for an ID missing from the catalog", message.Text)
+			}
+		}
+	}
+}
+
+func TestDefaultCatalog(t *testing.T) {
+	if text := envied.DefaultCatalog.Format(envied.MessageOutputLocation, "config"); text != "📁 Files are located in config" {
+		t.Errorf("Format() = %q", text)
+	}
+	if text := envied.Catalog(nil).Format(envied.MessageWritten, "README.md"); text != "📝 Written README.md" {
+		t.Errorf("Format() of a nil catalog = %q, expected the default text", text)
+	}
+}
+
+func TestErrorMessages(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	err := envied.Check(envied.GenerateOptions{ConfigPath: configPath})
+	var messageErr *envied.Error
+	if !errors.As(err, &messageErr) || messageErr.ID != envied.MessageOutdatedMissingFile {
+		t.Fatalf("Check() error = %v, expected an error with ID %s", err, envied.MessageOutdatedMissingFile)
+	}
+	if !errors.Is(err, envied.ErrOutdated) {
+		t.Errorf("Check() error %v should wrap ErrOutdated", err)
+	}
+	if err.Error() != envied.DefaultCatalog.Error(err) {
+		t.Errorf("Error() = %q, expected the default catalog text", err.Error())
+	}
+
+	catalog := envied.Catalog{
+		envied.MessageOutdatedMissingFile: "❌ FEHLER: %v: %s fehlt",
+		envied.MessageOutdated:            "erzeugte Konfiguration ist veraltet",
+	}
+	if text := catalog.Error(err); !strings.HasPrefix(text, "❌ FEHLER: erzeugte Konfiguration ist veraltet: ") {
+		t.Errorf("Catalog.Error() = %q, expected the wrapped error translated as well", text)
+	}
+
+	diffErr := func() error {
+		_, err := envied.DiffEnvironments(envied.GenerateOptions{ConfigPath: configPath}, "dev", "staging")
+		return err
+	}()
+	if text := (envied.Catalog{envied.MessageUnknownEnvironment: "unbekannte Umgebung '%s', gültig: %s"}).Error(diffErr); text != "unbekannte Umgebung 'staging', gültig: dev" {
+		t.Errorf("Catalog.Error() of an unknown environment = %q", text)
+	}
+	if text := envied.DefaultCatalog.Error(errors.New("plain")); text != "plain" {
+		t.Errorf("Catalog.Error() of an error without ID = %q", text)
+	}
+}
+
+func TestWarningMessages(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\nAPI_KEY=changeme\n",
+	}, nil)
+
+	warnings, err := envied.ValidateWithWarnings(envied.GenerateOptions{ConfigPath: configPath, Messages: func(envied.Message) {}})
+	if err != nil {
+		t.Fatalf("ValidateWithWarnings() returned error: %v", err)
+	}
+	if len(warnings) == 0 {
+		t.Fatal("ValidateWithWarnings() returned no warnings")
+	}
+	for _, warning := range warnings {
+		if warning.ID == "" || warning.Message != envied.DefaultCatalog.Format(warning.ID, warning.Args...) {
+			t.Errorf("Warning %+v should hold its message ID and arguments", warning)
+		}
+	}
+}
+
+func TestCommandCatalog(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	catalogPath := filepath.Join(t.TempDir(), "catalog.json")
+	data, err := json.Marshal(map[string]string{
+		"valid":               "✅ Konfiguration ist gültig",
+		"unknown_environment": "unbekannte Umgebung '%s', gültig: %s",
+	})
+	if err != nil {
+		t.Fatalf("Failed to encode catalog: %v", err)
+	}
+	if err := os.WriteFile(catalogPath, data, 0644); err != nil {
+		t.Fatalf("Failed to write catalog: %v", err)
+	}
+
+	binary := filepath.Join(t.TempDir(), "envied")
+	if output, err := exec.Command("go", "build", "-o", binary, "github.com/petrovyuri/go-envied/cmd/envied").CombinedOutput(); err != nil {
+		t.Fatalf("Failed to build envied: %v\n%s", err, output)
+	}
+
+	validate := exec.Command(binary, "validate", "-config", configPath)
+	validate.Env = append(os.Environ(), "ENVIED_CATALOG="+catalogPath)
+	output, err := validate.CombinedOutput()
+	if err != nil {
+		t.Fatalf("envied validate failed: %v\n%s", err, output)
+	}
+	if !strings.Contains(string(output), "✅ Konfiguration ist gültig") {
+		t.Errorf("envied validate should print the catalog message:\n%s", output)
+	}
+
+	diff := exec.Command(binary, "diff", "-config", configPath, "dev", "staging")
+	diff.Env = append(os.Environ(), "ENVIED_CATALOG="+catalogPath)
+	output, err = diff.CombinedOutput()
+	if err == nil {
+		t.Fatalf("envied diff with an unknown environment succeeded:\n%s", output)
+	}
+	if !strings.Contains(string(output), "unbekannte Umgebung 'staging', gültig: dev") {
+		t.Errorf("envied diff should print the library error from the catalog:\n%s", output)
+	}
+
+	invalid := exec.Command(binary, "validate", "-config", configPath)
+	invalid.Env = append(os.Environ(), "ENVIED_CATALOG="+filepath.Join(t.TempDir(), "missing.json"))
+	if output, err := invalid.CombinedOutput(); err == nil || !strings.Contains(string(output), "invalid message catalog") {
+		t.Errorf("envied with a missing catalog should fail: %v\n%s", err, output)
+	}
+}
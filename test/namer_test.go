@@ -0,0 +1,230 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestCamelCaseNaming(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "APP_API_URL=https://dev.example.com\nAPP_PORT=8080\n",
+		"prod": "APP_API_URL=https://api.example.com\nAPP_PORT=80\n",
+	}, func(config *envied.ConfigFile) {
+		config.Naming = &envied.NamingConfig{Strategy: envied.NamingCamelCase, StripPrefix: "APP_"}
+	})
+
+	for _, want := range []string{
+		"\tApiUrl string\n",
+		"\tGetPort() int\n",
+		"func (c *DevConfig) GetApiUrl() string {",
+		"_envieddataApiUrl",
+		`case "APP_API_URL":`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	cfg := config.DevConfig{ApiUrl: "https://local", Port: 1}
+	fmt.Println(cfg.GetApiUrl(), cfg.GetPort(), config.NewProdConfig().GetApiUrl())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if expected := "https://local 1 https://api.example.com\n"; output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestGoNaming(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev": "DATABASE_URL=postgres://dev\nAPI_KEY=dev-key\nHTTP_PORT=8080\nLOG_LEVEL=debug\n",
+	}, func(config *envied.ConfigFile) {
+		config.Naming = &envied.NamingConfig{Strategy: envied.NamingGo}
+	})
+
+	for _, want := range []string{
+		"\tDatabaseURL string\n",
+		"\tGetAPIKey() string\n",
+		"func (c *DevConfig) GetHTTPPort() int {",
+		"\t\"DatabaseURL\": \"DATABASE_URL\",\n",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	cfg := config.NewDevConfig()
+	fmt.Println(cfg.GetHTTPPort(), cfg.GetLogLevel(), config.FieldEnvNames["APIKey"], len(config.FieldEnvNames))
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if expected := "8080 debug API_KEY 4\n"; output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+
+	if _, preserved := generateConfig(t, map[string]string{"dev": "PORT=8080\n"}, nil); strings.Contains(preserved, "FieldEnvNames") {
+		t.Error("FieldEnvNames should only be generated when fields are renamed")
+	}
+}
+
+// lowerNamer is a user namer: lowercase fields, Env-prefixed getters and env- prefixed files
+type lowerNamer struct{}
+
+func (lowerNamer) Field(variable string) string    { return "f" + strings.ToLower(variable) }
+func (lowerNamer) Getter(variable string) string   { return "Env" + variable }
+func (lowerNamer) Constant(variable string) string { return strings.ToLower(variable) }
+func (lowerNamer) File(environment string) string  { return "env-" + environment }
+
+func TestCustomNamer(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\nPORT=8080\n",
+	}, func(config *envied.ConfigFile) {
+		config.BuildTags = true
+		config.Naming = &envied.NamingConfig{Strategy: envied.NamingCamelCase}
+	})
+
+	if err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath, Namer: lowerNamer{}}); err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	for _, want := range []string{"\tfapi_url string\n", "func (c *DevConfig) GetEnvAPI_URL() string {", "_enviedkeyapi_url"} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "config", "config_env_env-dev.gen.go")); err != nil {
+		t.Errorf("The file of the dev build tag should be named by the namer: %v", err)
+	}
+}
+
+func TestSanitizedNaming(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev": "MY-VAR=dash\n1ST_KEY=first\napp.port=8080\n",
+	}, func(config *envied.ConfigFile) {
+		config.Naming = &envied.NamingConfig{Sanitize: envied.SanitizeReplace}
+	})
+
+	for _, want := range []string{
+		"\tMY_VAR   string\n",
+		"func (c *DevConfig) GetX1ST_KEY() string {",
+		"\tapp_port int\n",
+		`case "app.port":`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	cfg := config.NewDevConfig(config.WithMY_VAR("override"))
+	port, _ := cfg.Lookup("app.port")
+	fmt.Println(cfg.GetMY_VAR(), cfg.GetX1ST_KEY(), port)
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if expected := "override first 8080\n"; output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestSanitizedNamingErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		sanitize string
+		expected string
+	}{
+		{"not sanitized", "", `variable MY-VAR: field name "MY-VAR" is not a Go identifier, set naming.sanitize`},
+		{"collision", envied.SanitizeReplace, "variables MY-VAR and MY_VAR have the same field name MY_VAR, rename one of them"},
+		{"collision after removal", envied.SanitizeRemove, "variables MY-VAR and MYVAR have the same field name MYVAR"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{
+				"dev": "MY-VAR=dash\nMY_VAR=underscore\nMYVAR=plain\n",
+			}, func(config *envied.ConfigFile) {
+				config.Naming = &envied.NamingConfig{Sanitize: tt.sanitize}
+			})
+
+			err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Validate() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNamingErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		naming   *envied.NamingConfig
+		namer    envied.Namer
+		expected string
+	}{
+		{"unknown strategy", &envied.NamingConfig{Strategy: "snake"}, nil, "unknown naming strategy 'snake'"},
+		{"fields collide", &envied.NamingConfig{Strategy: envied.NamingCamelCase}, nil, "variables API_URL and Api_Url have the same field name ApiUrl"},
+		{"field collides with a method", &envied.NamingConfig{Strategy: envied.NamingCamelCase, StripPrefix: "API_"}, nil, "variable API_LOOKUP: field Lookup collides with a generated method"},
+		{"file is not a plain name", nil, fileNamer{}, `environment 'dev': file name "../dev" is not a plain file name`},
+		{"unknown sanitize strategy", &envied.NamingConfig{Sanitize: "escape"}, nil, "unknown sanitize strategy 'escape'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{
+				"dev": "API_LOOKUP=true\nAPI_URL=https://dev.example.com\nApi_Url=https://other.example.com\n",
+			}, func(config *envied.ConfigFile) {
+				config.Naming = tt.naming
+			})
+
+			err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath, Namer: tt.namer})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Validate() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+// fileNamer names per-environment files outside the output directory
+type fileNamer struct{ envied.Namer }
+
+func (fileNamer) File(environment string) string { return "../" + environment }
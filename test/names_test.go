@@ -0,0 +1,178 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestVariableNameValidation(t *testing.T) {
+	tests := []struct {
+		name        string
+		mode        string
+		pattern     string
+		content     string
+		expectError string
+		expectWarn  string
+	}{
+		{
+			name:    "valid names",
+			content: "API_URL=https://dev.example.com\n_PRIVATE=true\n",
+		},
+		{
+			name:       "lowercase name warns by default",
+			content:    "api_url=https://dev.example.com\n",
+			expectWarn: "variable name 'api_url' in environment 'dev' does not match",
+		},
+		{
+			name:        "lowercase name rejected in error mode",
+			mode:        envied.NameValidationError,
+			content:     "api_url=https://dev.example.com\n",
+			expectError: "variable name 'api_url' in environment 'dev' does not match",
+		},
+		{
+			name:        "leading digit rejected",
+			mode:        envied.NameValidationError,
+			content:     "1API=value\n",
+			expectError: "variable name '1API'",
+		},
+		{
+			name:    "validation off",
+			mode:    envied.NameValidationOff,
+			content: "api_url=https://dev.example.com\n",
+		},
+		{
+			name:    "custom pattern",
+			mode:    envied.NameValidationError,
+			pattern: `^[A-Za-z_][A-Za-z0-9_]*$`,
+			content: "apiUrl=https://dev.example.com\n",
+		},
+		{
+			name:        "invalid pattern",
+			pattern:     `^[A-Z`,
+			content:     "API_URL=https://dev.example.com\n",
+			expectError: "invalid name pattern",
+		},
+		{
+			name:        "unknown mode",
+			mode:        "strict",
+			content:     "API_URL=https://dev.example.com\n",
+			expectError: "unknown name validation mode 'strict'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{"dev": tt.content}, func(config *envied.ConfigFile) {
+				config.NameValidation = tt.mode
+				config.NamePattern = tt.pattern
+			})
+
+			var err error
+			output := captureStdout(t, func() {
+				err = envied.GenerateFromConfigFile(configPath)
+			})
+
+			if tt.expectError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.expectError) {
+					t.Errorf("Expected error containing %q, got %v", tt.expectError, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.expectWarn != "" && !strings.Contains(output, tt.expectWarn) {
+				t.Errorf("Expected warning %q in output:\n%s", tt.expectWarn, output)
+			}
+			if tt.expectWarn == "" && strings.Contains(output, "Warning") {
+				t.Errorf("Unexpected warning in output:\n%s", output)
+			}
+		})
+	}
+}
+
+func TestConfusableVariableNames(t *testing.T) {
+	tests := []struct {
+		name    string
+		envs    map[string]string
+		message string
+	}{
+		{
+			name: "case difference across environments",
+			envs: map[string]string{
+				"dev":  "API_KEY=dev\n",
+				"prod": "Api_Key=prod\n",
+			},
+			message: "variable names 'API_KEY' (dev), 'Api_Key' (prod) differ only by case or confusable characters",
+		},
+		{
+			name: "letter O and digit zero",
+			envs: map[string]string{
+				"dev": "TIMEOUT=1\nTIME0UT=2\n",
+			},
+			message: "variable names 'TIME0UT' (dev), 'TIMEOUT' (dev)",
+		},
+		{
+			name: "letter I and digit one",
+			envs: map[string]string{
+				"dev":  "RETRY_LIMIT=1\n",
+				"prod": "RETRY_L1MIT=1\n",
+			},
+			message: "'RETRY_L1MIT' (prod), 'RETRY_LIMIT' (dev)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" rejected in error mode", func(t *testing.T) {
+			_, configPath := writeConfig(t, tt.envs, func(config *envied.ConfigFile) {
+				config.NameValidation = envied.NameValidationError
+				config.NamePattern = `^[A-Za-z_][A-Za-z0-9_]*$`
+				config.AllowExtraVariables = true
+			})
+
+			err := envied.GenerateFromConfigFile(configPath)
+			if err == nil || !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("Expected error containing %q, got %v", tt.message, err)
+			}
+		})
+
+		t.Run(tt.name+" warns by default", func(t *testing.T) {
+			_, configPath := writeConfig(t, tt.envs, func(config *envied.ConfigFile) {
+				config.AllowExtraVariables = true
+			})
+
+			var err error
+			output := captureStdout(t, func() {
+				err = envied.GenerateFromConfigFile(configPath)
+			})
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !strings.Contains(output, tt.message) {
+				t.Errorf("Expected warning %q in output:\n%s", tt.message, output)
+			}
+		})
+	}
+}
+
+func TestConfusableNamesIgnoredWhenValidationOff(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "TIMEOUT=1\nTIME0UT=2\n",
+	}, func(config *envied.ConfigFile) {
+		config.NameValidation = envied.NameValidationOff
+	})
+
+	var err error
+	output := captureStdout(t, func() {
+		err = envied.GenerateFromConfigFile(configPath)
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if strings.Contains(output, "confusable") {
+		t.Errorf("Unexpected warning in output:\n%s", output)
+	}
+}
@@ -0,0 +1,172 @@
+package test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateWithoutObfuscation(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nGREETING=say \"hi\"\nPORT=8080\n",
+		"prod": "API_URL=https://api.example.com\nGREETING=hello\nPORT=80\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+	})
+
+	if strings.Contains(content, "MustDecodeString") {
+		t.Error("Generated code should not contain obfuscated values")
+	}
+	if !strings.Contains(content, `API_URL:  "https://api.example.com",`) {
+		t.Error("Generated code should contain plain string constants")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	cfg := config.NewDevConfig()
+	fmt.Println(cfg.GetAPI_URL())
+	fmt.Println(cfg.GetGREETING())
+}
+`)
+	if err != nil {
+		t.Fatalf("Generated code failed: %v\n%s", err, output)
+	}
+
+	expected := "https://dev.example.com\nsay \"hi\"\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestGenerateWithUnknownObfuscationMode(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = "rot13"
+	})
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil {
+		t.Fatal("Expected error for unknown obfuscation mode")
+	}
+	if !strings.Contains(err.Error(), "rot13") {
+		t.Errorf("Error %q should mention the unknown mode", err.Error())
+	}
+}
+
+func TestGenerateWithPerEnvironmentObfuscation(t *testing.T) {
+	disabled := false
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\n",
+		"prod": "API_URL=https://api.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		dev := config.Environments["dev"]
+		dev.Obfuscate = &disabled
+		config.Environments["dev"] = dev
+	})
+
+	if !strings.Contains(content, `API_URL: "https://dev.example.com",`) {
+		t.Error("Dev environment should contain plain string constants")
+	}
+	if strings.Contains(content, "https://api.example.com") {
+		t.Error("Prod environment should be obfuscated")
+	}
+	if !strings.Contains(content, "prod_enviedkeyAPI_URL") {
+		t.Error("Prod environment should contain obfuscation keys")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	fmt.Println(config.NewDevConfig().GetAPI_URL())
+	fmt.Println(config.NewProdConfig().GetAPI_URL())
+}
+`)
+	if err != nil {
+		t.Fatalf("Generated code failed: %v\n%s", err, output)
+	}
+
+	expected := "https://dev.example.com\nhttps://api.example.com\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestCheckObfuscationVersion(t *testing.T) {
+	if !envied.CheckObfuscationVersion(envied.ObfuscationVersion) {
+		t.Error("Current version should be accepted")
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected panic for unsupported version")
+		}
+	}()
+	envied.CheckObfuscationVersion(envied.ObfuscationVersion + 1)
+}
+
+func TestGeneratedObfuscationVersionMarker(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	marker := fmt.Sprintf("const enviedObfuscationVersion = %d\n", envied.ObfuscationVersion)
+	if !strings.Contains(content, marker) {
+		t.Fatal("Generated code should contain the obfuscation version marker")
+	}
+
+	// Simulate a file generated by an older go-envied release
+	stale := strings.Replace(content, marker, "const enviedObfuscationVersion = 0\n", 1)
+	generatedFile := filepath.Join(dir, "config", "config_env.gen.go")
+	if err := os.WriteFile(generatedFile, []byte(stale), 0644); err != nil {
+		t.Fatalf("Failed to write stale file: %v", err)
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	fmt.Println(config.NewDevConfig().GetAPI_URL())
+}
+`)
+	if err == nil {
+		t.Fatal("Stale generated code should fail at startup")
+	}
+	if !strings.Contains(output, "regenerate the configuration") {
+		t.Errorf("Unexpected output: %s", output)
+	}
+}
+
+func TestGeneratedWithoutObfuscationHasNoVersionMarker(t *testing.T) {
+	_, content := generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+	})
+
+	if strings.Contains(content, "enviedObfuscationVersion") {
+		t.Error("Plain configuration should not contain the obfuscation version marker")
+	}
+}
@@ -0,0 +1,55 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGeneratedConstructorOverrides(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\nTIMEOUT=5\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\nTIMEOUT=slow\nSECRET=prod-secret\n",
+	}, func(config *envied.ConfigFile) {
+		config.AllowExtraVariables = true
+	})
+
+	if !strings.Contains(content, "func WithPORT(value int) Override {") {
+		t.Error("Generated code should contain WithPORT override")
+	}
+	if strings.Contains(content, "WithTIMEOUT") {
+		t.Error("Variables with different types in different environments should not get overrides")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	prod := config.NewProdConfig(config.WithPORT(8081), config.WithSECRET("test-secret"))
+	fmt.Println(prod.GetPORT(), prod.GetSECRET(), prod.GetAPI_URL())
+
+	// Overrides of variables missing in the environment are ignored
+	dev, err := config.NewEnvironmentConfig("dev", config.WithSECRET("ignored"), config.WithAPI_URL("http://localhost"))
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(dev.GetPORT(), dev.GetAPI_URL())
+
+	fmt.Println(config.NewProdConfig().GetPORT())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+
+	expected := "8081 test-secret https://api.example.com\n8080 http://localhost\n80\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
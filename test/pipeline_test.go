@@ -0,0 +1,209 @@
+package test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestPipelineHooks(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nAPI_KEY=changeme\n",
+		"prod": "API_URL=https://api.example.com\nAPI_KEY=secret\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationXOR
+	})
+
+	var ran []envied.Stage
+	var obfuscated []string
+	var codeFile string
+	pipeline := envied.NewPipeline(envied.GenerateOptions{ConfigPath: configPath}).
+		Hook(envied.StageSources, func(state *envied.PipelineState) error {
+			ran = append(ran, envied.StageSources)
+			for _, variables := range state.Variables {
+				variables["SERVICE_NAME"] = envied.EnvValue{Value: "billing"}
+			}
+			return nil
+		}).
+		Hook(envied.StageModel, func(state *envied.PipelineState) error {
+			ran = append(ran, envied.StageModel)
+			for _, envName := range state.Environments {
+				for i, field := range state.Fields[envName] {
+					if field.EnvName == "API_URL" {
+						state.Fields[envName][i].Description = "Base URL of the billing API"
+					}
+				}
+			}
+			return nil
+		}).
+		Hook(envied.StageValidate, func(state *envied.PipelineState) error {
+			ran = append(ran, envied.StageValidate)
+			return nil
+		}).
+		Hook(envied.StageObfuscate, func(state *envied.PipelineState) error {
+			ran = append(ran, envied.StageObfuscate)
+			for name := range state.Obfuscated["prod"] {
+				obfuscated = append(obfuscated, name)
+			}
+			return nil
+		}).
+		Hook(envied.StageEmit, func(state *envied.PipelineState) error {
+			ran = append(ran, envied.StageEmit)
+			codeFile = state.CodeFile
+			return os.WriteFile(filepath.Join(filepath.Dir(state.CodeFile), "service.txt"), []byte("billing"), 0644)
+		})
+
+	if _, err := pipeline.Generate(); err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+
+	expected := []envied.Stage{envied.StageSources, envied.StageModel, envied.StageValidate, envied.StageObfuscate, envied.StageEmit}
+	if !slices.Equal(ran, expected) {
+		t.Errorf("Hooks ran in order %v, expected %v", ran, expected)
+	}
+	if !slices.Contains(obfuscated, "API_KEY") {
+		t.Errorf("Obfuscate hook saw %v, expected API_KEY", obfuscated)
+	}
+	if codeFile != filepath.Join(tempDir, "config", "config_env.gen.go") {
+		t.Errorf("Emit hook got code file %q", codeFile)
+	}
+
+	content, err := os.ReadFile(codeFile)
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	for _, expected := range []string{"SERVICE_NAME", "Base URL of the billing API"} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Generated code is missing %q", expected)
+		}
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "config", "service.txt")); err != nil {
+		t.Errorf("Emit hook did not write its file: %v", err)
+	}
+}
+
+func TestPipelineHooksReplaceState(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nAPI_KEY=changeme\n",
+		"prod": "API_URL=https://api.example.com\nAPI_KEY=secret\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationXOR
+	})
+
+	pipeline := envied.NewPipeline(envied.GenerateOptions{ConfigPath: configPath}).
+		Hook(envied.StageSources, func(state *envied.PipelineState) error {
+			replaced := make(map[string]map[string]envied.EnvValue)
+			for envName, variables := range state.Variables {
+				replaced[envName] = map[string]envied.EnvValue{"SERVICE_NAME": {Value: "billing"}}
+				for name, value := range variables {
+					replaced[envName][name] = value
+				}
+			}
+			state.Variables = replaced
+			return nil
+		}).
+		Hook(envied.StageModel, func(state *envied.PipelineState) error {
+			state.Environments = []string{"prod"}
+			return nil
+		}).
+		Hook(envied.StageObfuscate, func(state *envied.PipelineState) error {
+			state.Obfuscated = map[string]map[string]*envied.ObfuscationResult{}
+			return nil
+		})
+	if _, err := pipeline.Generate(); err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	for _, expected := range []string{"SERVICE_NAME", "type ProdConfig struct", `"secret"`} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Generated code is missing %q", expected)
+		}
+	}
+	for _, unexpected := range []string{"type DevConfig struct", "_envieddata"} {
+		if strings.Contains(string(content), unexpected) {
+			t.Errorf("Generated code contains %q dropped by the hooks", unexpected)
+		}
+	}
+
+	_, err = envied.NewPipeline(envied.GenerateOptions{ConfigPath: configPath}).
+		Hook(envied.StageValidate, func(state *envied.PipelineState) error {
+			state.Environments = append(state.Environments, "staging")
+			return nil
+		}).
+		Generate()
+	if err == nil || !strings.Contains(err.Error(), "validate stage: environment 'staging' is not in the configuration") {
+		t.Fatalf("Generate() = %v, expected an unknown environment error", err)
+	}
+}
+
+func TestPipelineHookError(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	emitted := false
+	_, err := envied.NewPipeline(envied.GenerateOptions{ConfigPath: configPath}).
+		Hook(envied.StageValidate, func(state *envied.PipelineState) error {
+			return errors.New("API_URL must use https in every environment of the service")
+		}).
+		Hook(envied.StageEmit, func(state *envied.PipelineState) error {
+			emitted = true
+			return nil
+		}).
+		Generate()
+	if err == nil || !strings.Contains(err.Error(), "validate stage: API_URL must use https") {
+		t.Fatalf("Generate() = %v, expected the error of the validate hook", err)
+	}
+	if emitted {
+		t.Error("Emit hook ran after a failed validate hook")
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "config", "config_env.gen.go")); !os.IsNotExist(err) {
+		t.Errorf("Generated file exists after a failed validate hook: %v", err)
+	}
+}
+
+func TestPipelineValidate(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	var ran []envied.Stage
+	pipeline := envied.NewPipeline(envied.GenerateOptions{ConfigPath: configPath})
+	for _, stage := range []envied.Stage{envied.StageSources, envied.StageValidate, envied.StageEmit} {
+		pipeline.Hook(stage, func(state *envied.PipelineState) error {
+			ran = append(ran, stage)
+			return nil
+		})
+	}
+	if _, err := pipeline.Validate(); err != nil {
+		t.Fatalf("Validate() returned error: %v", err)
+	}
+	if expected := []envied.Stage{envied.StageSources, envied.StageValidate}; !slices.Equal(ran, expected) {
+		t.Errorf("Validate() ran hooks %v, expected %v", ran, expected)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "config")); !os.IsNotExist(err) {
+		t.Errorf("Validate() wrote output: %v", err)
+	}
+}
+
+func TestPipelineUnknownStage(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, nil)
+
+	_, err := envied.NewPipeline(envied.GenerateOptions{ConfigPath: configPath}).
+		Hook("render", func(state *envied.PipelineState) error { return nil }).
+		Generate()
+	if err == nil || !strings.Contains(err.Error(), "unknown pipeline stage 'render'") {
+		t.Fatalf("Generate() = %v, expected an unknown stage error", err)
+	}
+}
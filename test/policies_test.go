@@ -0,0 +1,91 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestPolicies(t *testing.T) {
+	tests := []struct {
+		name     string
+		dev      string
+		prod     string
+		policies []envied.PolicyConfig
+		expected string
+	}{
+		{
+			name:     "forbidden value",
+			dev:      "DEBUG=true\nSTRIPE_LIVE_KEY=\n",
+			prod:     "DEBUG=TRUE\nSTRIPE_LIVE_KEY=sk_live_secret\n",
+			policies: []envied.PolicyConfig{{Environments: []string{"prod"}, Variable: "DEBUG", Value: "true", Message: "debug output leaks customer data"}},
+			expected: "environment 'prod' defines DEBUG=TRUE (from ",
+		},
+		{
+			name:     "forbidden variable",
+			dev:      "DEBUG=true\nSTRIPE_LIVE_KEY=sk_live_secret\n",
+			prod:     "DEBUG=false\nSTRIPE_LIVE_KEY=sk_live_secret\n",
+			policies: []envied.PolicyConfig{{Environments: []string{"dev*"}, Variable: "*_LIVE_*"}},
+			expected: "environment 'dev' defines STRIPE_LIVE_KEY (from ",
+		},
+		{
+			name:     "allowed value",
+			dev:      "DEBUG=true\n",
+			prod:     "DEBUG=false\n",
+			policies: []envied.PolicyConfig{{Environments: []string{"prod"}, Variable: "DEBUG", Value: "true"}},
+		},
+		{
+			name:     "environment pattern matching nothing",
+			dev:      "DEBUG=true\n",
+			prod:     "DEBUG=false\n",
+			policies: []envied.PolicyConfig{{Environments: []string{"production"}, Variable: "DEBUG", Value: "true"}},
+			expected: "environment pattern 'production' of policy 1 matches no environment",
+		},
+		{
+			name:     "invalid variable pattern",
+			dev:      "DEBUG=true\n",
+			prod:     "DEBUG=false\n",
+			policies: []envied.PolicyConfig{{Variable: "DEBUG["}},
+			expected: "policy 1 has invalid variable pattern 'DEBUG['",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{"dev": tt.dev, "prod": tt.prod}, func(config *envied.ConfigFile) {
+				config.Policies = tt.policies
+			})
+
+			err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath})
+			if tt.expected == "" {
+				if err != nil {
+					t.Fatalf("Generate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("Generate() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestPolicyViolationMessage(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "DEBUG=true\n",
+		"prod": "DEBUG=true\n",
+	}, func(config *envied.ConfigFile) {
+		config.Policies = []envied.PolicyConfig{{Environments: []string{"prod"}, Variable: "DEBUG", Value: "true", Message: "debug output leaks customer data"}}
+	})
+
+	err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath})
+	if err == nil {
+		t.Fatal("Generate() succeeded, expected the policy violation")
+	}
+	for _, expected := range []string{"defines DEBUG=true (from ", "prod.env:1)", "forbidden by policy 1: DEBUG=true in prod", "debug output leaks customer data"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Generate() = %v, expected error containing %q", err, expected)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestProfiles(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\nQUEUE=jobs-dev\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\nQUEUE=jobs\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationXOR
+		dev := config.Environments["dev"]
+		dev.Profiles = map[string][]string{
+			"DevServer": {"PORT", "API_URL"},
+			"DevWorker": {"QUEUE", "API_URL"},
+		}
+		config.Environments["dev"] = dev
+	})
+
+	for _, want := range []string{
+		"type DevServerConfig struct {",
+		"func NewDevWorkerConfig(opts ...Override) *DevWorkerConfig {",
+		"func (c *DevWorkerConfig) GetQUEUE() string {",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+	if strings.Count(content, "var dev_enviedkeyAPI_URL =") != 1 {
+		t.Error("Profiles should share the obfuscated data of their environment")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	server := config.NewDevServerConfig(config.WithPORT(9090))
+	worker := config.NewDevWorkerConfig()
+	fmt.Println(server.GetAPI_URL(), server.GetPORT(), server.Environment())
+	fmt.Println(worker.GetAPI_URL(), worker.GetQUEUE())
+
+	_, found := worker.Lookup("PORT")
+	fmt.Println(found)
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+
+	expected := "https://dev.example.com 9090 dev\nhttps://dev.example.com jobs-dev\nfalse\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestProfileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		profiles map[string][]string
+		expected string
+	}{
+		{"undefined variable", map[string][]string{"DevServer": {"HOST"}}, "variable HOST is not defined in the environment"},
+		{"duplicate variable", map[string][]string{"DevServer": {"PORT", "PORT"}}, "variable PORT is listed twice"},
+		{"no variables", map[string][]string{"DevServer": {}}, "no variables listed"},
+		{"struct name of an environment", map[string][]string{"Prod": {"PORT"}}, "struct name Prod is already used by environment 'prod'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{
+				"dev":  "PORT=8080\n",
+				"prod": "PORT=80\n",
+			}, func(config *envied.ConfigFile) {
+				dev := config.Environments["dev"]
+				dev.Profiles = tt.profiles
+				config.Environments["dev"] = dev
+			})
+
+			err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Validate() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProfileConflictsWithPackageFiles(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev": "PORT=8080\n",
+	}, func(config *envied.ConfigFile) {
+		dev := config.Environments["dev"]
+		dev.Profiles = map[string][]string{"DevServer": {"PORT"}}
+		config.Environments["dev"] = dev
+	})
+
+	outputDir := filepath.Join(tempDir, "config")
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		t.Fatalf("Failed to create output directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(outputDir, "server.go"), []byte("package config\n\ntype DevServerConfig struct{}\n"), 0644); err != nil {
+		t.Fatalf("Failed to create server.go: %v", err)
+	}
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "DevServerConfig (server.go)") {
+		t.Errorf("GenerateFromConfigFile() = %v, expected a conflict with the profile struct", err)
+	}
+}
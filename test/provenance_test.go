@@ -0,0 +1,111 @@
+package test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// writeOverlayConfig creates a dev environment with a YAML and a .env overlay
+func writeOverlayConfig(t *testing.T) (string, string) {
+	t.Helper()
+
+	return writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\nPORT=8080\nDEBUG=false\n",
+	}, func(config *envied.ConfigFile) {
+		dir := filepath.Dir(config.Environments["dev"].EnvFile)
+		overlays := map[string]string{
+			"team.yaml": "PORT: 8081\nDEBUG: true\n",
+			"local.env": "PORT=9090\n",
+		}
+		for name, content := range overlays {
+			if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to create %s: %v", name, err)
+			}
+		}
+
+		dev := config.Environments["dev"]
+		dev.Overlays = []string{filepath.Join(dir, "team.yaml"), filepath.Join(dir, "local.env")}
+		config.Environments["dev"] = dev
+		config.Emit = &envied.EmitConfig{Manifest: filepath.Join(dir, "envied.json")}
+	})
+}
+
+func TestOverlayProvenance(t *testing.T) {
+	tempDir, configPath := writeOverlayConfig(t)
+	devFile := filepath.Join(tempDir, "dev.env")
+	teamFile := filepath.Join(tempDir, "team.yaml")
+	localFile := filepath.Join(tempDir, "local.env")
+
+	output := captureStdout(t, func() {
+		if err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath, Verbose: true}); err != nil {
+			t.Errorf("Generate() returned error: %v", err)
+		}
+	})
+	for _, want := range []string{
+		"🧭 dev/API_URL: " + devFile + ":1\n",
+		"🧭 dev/DEBUG: " + teamFile + ":2 (overrides " + devFile + ":3)\n",
+		"🧭 dev/PORT: " + localFile + ":1 (overrides " + devFile + ":2, " + teamFile + ":1)\n",
+	} {
+		if !strings.Contains(output, want) {
+			t.Errorf("Verbose output should contain %q, got:\n%s", want, output)
+		}
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+	if !strings.Contains(string(content), "GetDEBUG() bool") {
+		t.Error("Overlay values should replace the values of the env file")
+	}
+
+	data, err := os.ReadFile(filepath.Join(tempDir, "envied.json"))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	var manifest envied.Manifest
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		t.Fatalf("Manifest is not valid JSON: %v", err)
+	}
+	for _, variable := range manifest.Variables {
+		if variable.Name != "PORT" {
+			continue
+		}
+		expected := envied.Provenance{Source: localFile + ":1", Overrides: []string{devFile + ":2", teamFile + ":1"}}
+		if !reflect.DeepEqual(variable.Sources["dev"], expected) {
+			t.Errorf("Manifest sources of PORT = %+v, expected %+v", variable.Sources, expected)
+		}
+	}
+
+	explanation, err := envied.Explain(envied.GenerateOptions{ConfigPath: configPath}, "DEBUG")
+	if err != nil {
+		t.Fatalf("Explain() returned error: %v", err)
+	}
+	dev := explanation.Environments[0]
+	if dev.Source != teamFile+":2" || !reflect.DeepEqual(dev.Overrides, []string{devFile + ":3"}) {
+		t.Errorf("Explain() source = %q overriding %v, expected the YAML overlay", dev.Source, dev.Overrides)
+	}
+}
+
+func TestOverlayChangesSourceHash(t *testing.T) {
+	tempDir, configPath := writeOverlayConfig(t)
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+	if err := envied.Verify(envied.GenerateOptions{ConfigPath: configPath}); err != nil {
+		t.Fatalf("Verify() returned error right after generation: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(tempDir, "local.env"), []byte("PORT=9091\n"), 0644); err != nil {
+		t.Fatalf("Failed to update overlay: %v", err)
+	}
+	if err := envied.Verify(envied.GenerateOptions{ConfigPath: configPath}); err == nil {
+		t.Error("Verify() should report a changed overlay")
+	}
+}
@@ -0,0 +1,201 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestFindUnusedVariables(t *testing.T) {
+	dir, _ := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\nDEBUG=true\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\nDEBUG=false\n",
+	}, nil)
+	configPath := filepath.Join(dir, envied.DefaultConfigFileName)
+
+	if output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	cfg := config.NewProdConfig()
+	fmt.Println(cfg.GetAPI_URL(), cfg.DEBUG)
+}
+`); err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+
+	t.Chdir(dir)
+	unused, err := envied.FindUnusedVariables(configPath, "./...")
+	if err != nil {
+		t.Fatalf("FindUnusedVariables() returned error: %v", err)
+	}
+
+	if len(unused) != 1 {
+		t.Fatalf("Expected 1 unused variable, got %+v", unused)
+	}
+	if unused[0].Name != "PORT" || unused[0].Getter != "GetPORT" {
+		t.Errorf("Unexpected unused variable: %+v", unused[0])
+	}
+	if strings.Join(unused[0].Environments, ",") != "dev,prod" {
+		t.Errorf("Expected environments dev,prod, got %v", unused[0].Environments)
+	}
+}
+
+func TestFindUnusedVariablesResolvesTypes(t *testing.T) {
+	dir, _ := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\nDEBUG=true\nTIMEOUT=5\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\nDEBUG=false\nTIMEOUT=5\n",
+	}, nil)
+	configPath := filepath.Join(dir, envied.DefaultConfigFileName)
+
+	// GetPORT of another type doesn't use PORT, DEBUG is looked up by name
+	if output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+type server struct{}
+
+func (server) GetPORT() int { return 0 }
+
+func main() {
+	cfg := config.NewProdConfig()
+	fmt.Println(cfg.GetAPI_URL(), server{}.GetPORT())
+	fmt.Println(cfg.Lookup("DEBUG"))
+}
+`); err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+
+	t.Chdir(dir)
+	unused, err := envied.FindUnusedVariables(configPath, "./...")
+	if err != nil {
+		t.Fatalf("FindUnusedVariables() returned error: %v", err)
+	}
+	var names []string
+	for _, variable := range unused {
+		names = append(names, variable.Name)
+	}
+	if strings.Join(names, ",") != "PORT,TIMEOUT" {
+		t.Errorf("Expected PORT and TIMEOUT to be unused, got %+v", unused)
+	}
+
+	// A variable delivered by an emitter is read by the deployment
+	config, err := envied.LoadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+	config.Emit = &envied.EmitConfig{Properties: filepath.Join(dir, "properties")}
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if unused, err := envied.FindUnusedVariables(configPath, "./..."); err != nil || len(unused) != 0 {
+		t.Errorf("Expected no unused variables with a properties emitter, got %+v (%v)", unused, err)
+	}
+	config.Emit = nil
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	// Any variable may be read by a lookup with a computed name
+	dynamic := `package main
+
+import (
+	"fmt"
+	"os"
+
+	"generated/config"
+)
+
+func main() {
+	fmt.Println(config.NewProdConfig().Lookup(os.Args[1]))
+}
+`
+	if err := os.WriteFile(filepath.Join(dir, "main.go"), []byte(dynamic), 0644); err != nil {
+		t.Fatalf("Failed to write main.go: %v", err)
+	}
+	if unused, err := envied.FindUnusedVariables(configPath, "./..."); err != nil || len(unused) != 0 {
+		t.Errorf("Expected no unused variables with a dynamic lookup, got %+v (%v)", unused, err)
+	}
+}
+
+func TestPruneVariables(t *testing.T) {
+	dir, configPath := writeConfig(t, map[string]string{
+		"dev":  "# Service\nAPI_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "API_URL=https://api.example.com\n  PORT = \"80\nPORT=81\n\"\n",
+	}, func(config *envied.ConfigFile) {
+		config.Variables = map[string]envied.VariableConfig{
+			"PORT":    {Transform: []string{"trim"}},
+			"API_URL": {Transform: []string{"trim"}},
+		}
+	})
+
+	if err := envied.PruneVariables(configPath, []string{"PORT"}); err != nil {
+		t.Fatalf("PruneVariables() returned error: %v", err)
+	}
+
+	expected := map[string]string{
+		"dev.env":  "# Service\nAPI_URL=https://dev.example.com\n",
+		"prod.env": "API_URL=https://api.example.com\n",
+	}
+	for name, want := range expected {
+		content, err := os.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", name, err)
+		}
+		if string(content) != want {
+			t.Errorf("%s = %q, expected %q", name, content, want)
+		}
+	}
+
+	config, err := envied.LoadConfigFile(configPath)
+	if err != nil {
+		t.Fatalf("LoadConfigFile() returned error: %v", err)
+	}
+	if _, exists := config.Variables["PORT"]; exists {
+		t.Error("Pruned variable settings should be removed from the configuration")
+	}
+	if _, exists := config.Variables["API_URL"]; !exists {
+		t.Error("Other variable settings should be kept")
+	}
+}
+
+func TestPruneVariablesCommentedConfig(t *testing.T) {
+	dir, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\nPORT=8080\n",
+	}, func(config *envied.ConfigFile) {
+		config.Variables = map[string]envied.VariableConfig{"PORT": {Transform: []string{"trim"}}}
+	})
+	content, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatalf("Failed to read config: %v", err)
+	}
+	if err := os.WriteFile(configPath, append([]byte("// Service configuration\n"), content...), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	err = envied.PruneVariables(configPath, []string{"PORT"})
+	if err == nil || !strings.Contains(err.Error(), "has comments") {
+		t.Fatalf("PruneVariables() = %v, expected an error for the commented configuration", err)
+	}
+
+	// Nothing is pruned, so the env files and settings stay consistent
+	env, err := os.ReadFile(filepath.Join(dir, "dev.env"))
+	if err != nil {
+		t.Fatalf("Failed to read dev.env: %v", err)
+	}
+	if !strings.Contains(string(env), "PORT=8080") {
+		t.Errorf("dev.env was pruned although the configuration can't be written:\n%s", env)
+	}
+}
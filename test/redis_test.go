@@ -0,0 +1,229 @@
+package test
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// fakeRedis serves the RESP commands used by go-envied
+type fakeRedis struct {
+	mu          sync.Mutex
+	hashes      map[string]map[string]string // Hashes by "<db>:<key>"
+	password    string
+	subscribers map[string][]net.Conn
+	listener    net.Listener
+}
+
+func newFakeRedis(t *testing.T, password string) *fakeRedis {
+	t.Helper()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	redis := &fakeRedis{
+		hashes:      make(map[string]map[string]string),
+		password:    password,
+		subscribers: make(map[string][]net.Conn),
+		listener:    listener,
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go redis.serve(conn)
+		}
+	}()
+	return redis
+}
+
+// hset sets a field and publishes a keyspace notification
+func (r *fakeRedis) hset(db int, key, field, value string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	hashKey := fmt.Sprintf("%d:%s", db, key)
+	if r.hashes[hashKey] == nil {
+		r.hashes[hashKey] = make(map[string]string)
+	}
+	r.hashes[hashKey][field] = value
+
+	channel := fmt.Sprintf("__keyspace@%d__:%s", db, key)
+	for _, conn := range r.subscribers[channel] {
+		writeRESP(conn, []string{"message", channel, "hset"})
+	}
+}
+
+// writeRESP writes an array of bulk strings
+func writeRESP(w io.Writer, items []string) {
+	fmt.Fprintf(w, "*%d\r\n", len(items))
+	for _, item := range items {
+		fmt.Fprintf(w, "$%d\r\n%s\r\n", len(item), item)
+	}
+}
+
+func (r *fakeRedis) serve(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	db := 0
+	authenticated := r.password == ""
+
+	for {
+		args, err := readRESPCommand(reader)
+		if err != nil {
+			return
+		}
+
+		r.mu.Lock()
+		switch strings.ToUpper(args[0]) {
+		case "AUTH":
+			if args[len(args)-1] == r.password {
+				authenticated = true
+				io.WriteString(conn, "+OK\r\n")
+			} else {
+				io.WriteString(conn, "-WRONGPASS invalid username-password pair\r\n")
+			}
+		case "SELECT":
+			db, _ = strconv.Atoi(args[1])
+			io.WriteString(conn, "+OK\r\n")
+		case "HGETALL":
+			if !authenticated {
+				io.WriteString(conn, "-NOAUTH Authentication required.\r\n")
+				break
+			}
+			var items []string
+			for field, value := range r.hashes[fmt.Sprintf("%d:%s", db, args[1])] {
+				items = append(items, field, value)
+			}
+			writeRESP(conn, items)
+		case "SUBSCRIBE":
+			r.subscribers[args[1]] = append(r.subscribers[args[1]], conn)
+			fmt.Fprintf(conn, "*3\r\n$9\r\nsubscribe\r\n$%d\r\n%s\r\n:1\r\n", len(args[1]), args[1])
+		default:
+			fmt.Fprintf(conn, "-ERR unknown command '%s'\r\n", args[0])
+		}
+		r.mu.Unlock()
+	}
+}
+
+// readRESPCommand reads a command sent as an array of bulk strings
+func readRESPCommand(reader *bufio.Reader) ([]string, error) {
+	line, err := reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	count, err := strconv.Atoi(strings.TrimSpace(line[1:]))
+	if err != nil {
+		return nil, err
+	}
+
+	args := make([]string, count)
+	for i := range args {
+		if _, err := reader.ReadString('\n'); err != nil {
+			return nil, err
+		}
+		arg, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		args[i] = strings.TrimSuffix(arg, "\r\n")
+	}
+	return args, nil
+}
+
+func TestGenerateFromRedis(t *testing.T) {
+	redis := newFakeRedis(t, "secret")
+	redis.hset(2, "myapp:prod", "API_URL", "https://api.example.com")
+	redis.hset(2, "myapp:prod", "PORT", "8080")
+	redis.hset(0, "myapp:prod", "API_URL", "https://wrong-db.example.com")
+	t.Setenv("ENVIED_REDIS_PASSWORD", "secret")
+
+	_, content := generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\nPORT=3000\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		config.Environments["prod"] = envied.EnvironmentConfig{
+			StructName: "Prod",
+			Redis: &envied.RedisConfig{
+				Address:     redis.listener.Addr().String(),
+				Key:         "myapp:prod",
+				DB:          2,
+				PasswordEnv: "ENVIED_REDIS_PASSWORD",
+			},
+		}
+	})
+
+	if !strings.Contains(content, `API_URL: "https://api.example.com",`) {
+		t.Error("Generated code should contain the value read from the selected database")
+	}
+	if !strings.Contains(content, `envied.ParseInt("8080")`) {
+		t.Error("Generated code should contain the typed value read from Redis")
+	}
+}
+
+func TestRedisAuthenticationFailure(t *testing.T) {
+	redis := newFakeRedis(t, "secret")
+
+	t.Setenv("REDIS_PASSWORD", "wrong")
+	_, err := envied.NewRedisSource(context.Background(), envied.RedisConfig{
+		Address: redis.listener.Addr().String(),
+		Key:     "myapp:prod",
+	})
+	if err == nil || !strings.Contains(err.Error(), "WRONGPASS") {
+		t.Errorf("Expected authentication error, got %v", err)
+	}
+}
+
+func TestRedisSourceWatch(t *testing.T) {
+	redis := newFakeRedis(t, "")
+	redis.hset(0, "myapp:prod", "FEATURE_X", "false")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	source, err := envied.NewRedisSource(ctx, envied.RedisConfig{Address: redis.listener.Addr().String(), Key: "myapp:prod"})
+	if err != nil {
+		t.Fatalf("NewRedisSource() returned error: %v", err)
+	}
+	if value, exists := source.Lookup("FEATURE_X"); !exists || value != "false" {
+		t.Errorf("Lookup() = %q, %v, expected false", value, exists)
+	}
+
+	changes := make(chan envied.MapSource, 1)
+	done := make(chan error, 1)
+	go func() {
+		done <- source.Watch(ctx, func(values envied.MapSource) {
+			changes <- values
+		})
+	}()
+
+	redis.hset(0, "myapp:prod", "FEATURE_X", "true")
+
+	select {
+	case values := <-changes:
+		if values["FEATURE_X"] != "true" {
+			t.Errorf("onChange values = %v", values)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Watch() did not report the change")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Watch() returned %v, expected context.Canceled", err)
+	}
+}
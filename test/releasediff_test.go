@@ -0,0 +1,189 @@
+package test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// releaseHashKey is the key of value hashes in release manifests
+var releaseHashKey = bytes.Repeat([]byte{0x42}, 32)
+
+// writeRelease generates a configuration emitting its manifest with hashes keyed by hashKeyEnv
+// and returns the config path and manifest
+func writeRelease(t *testing.T, hashKeyEnv string) (string, string, *envied.Manifest) {
+	t.Helper()
+
+	t.Setenv("ENVIED_TEST_HASH_KEY", base64.StdEncoding.EncodeToString(releaseHashKey))
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_KEY=dev-key\nPORT=8080\nLEGACY=1\n",
+		"prod": "API_KEY=prod-key\nPORT=80\nLEGACY=1\n",
+	}, func(config *envied.ConfigFile) {
+		config.Emit = &envied.EmitConfig{
+			Manifest:   filepath.Join(filepath.Dir(config.OutputDir), "manifest.json"),
+			HashKeyEnv: hashKeyEnv,
+		}
+	})
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() returned error: %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(tempDir, "manifest.json"))
+	if err != nil {
+		t.Fatalf("Failed to read manifest: %v", err)
+	}
+	manifest, err := envied.ParseManifest(data, nil)
+	if err != nil {
+		t.Fatalf("ParseManifest() returned error: %v", err)
+	}
+	return tempDir, configPath, manifest
+}
+
+func TestDiffRelease(t *testing.T) {
+	tempDir, configPath, previous := writeRelease(t, "ENVIED_TEST_HASH_KEY")
+	if hash := previous.Variables[0].Hashes["prod"]; hash != envied.ValueHash(releaseHashKey, "prod", "API_KEY", "prod-key") {
+		t.Errorf("Manifest hash of API_KEY = %q, expected ValueHash of the value", hash)
+	}
+
+	for envName, content := range map[string]string{
+		"dev":  "API_KEY=dev-key\nPORT=local\nTIMEOUT=5s\n",
+		"prod": "API_KEY=rotated-key\nPORT=80\nTIMEOUT=5s\n",
+	} {
+		if err := os.WriteFile(filepath.Join(tempDir, envName+".env"), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to update %s.env: %v", envName, err)
+		}
+	}
+
+	differences, err := envied.DiffRelease(envied.GenerateOptions{ConfigPath: configPath}, previous)
+	if err != nil {
+		t.Fatalf("DiffRelease() returned error: %v", err)
+	}
+	difference := func(envName, name, kind string, from, to envied.FieldType) envied.ReleaseDifference {
+		return envied.ReleaseDifference{Environment: envName, VariableDifference: envied.VariableDifference{Name: name, Kind: kind, FromType: from, ToType: to}}
+	}
+	expected := []envied.ReleaseDifference{
+		difference("dev", "LEGACY", envied.DiffRemoved, envied.FieldTypeBool, ""),
+		difference("dev", "PORT", envied.DiffTypeChanged, envied.FieldTypeInt, envied.FieldTypeString),
+		difference("dev", "TIMEOUT", envied.DiffAdded, "", envied.FieldTypeString),
+		difference("prod", "API_KEY", envied.DiffValueChanged, envied.FieldTypeString, envied.FieldTypeString),
+		difference("prod", "LEGACY", envied.DiffRemoved, envied.FieldTypeBool, ""),
+		// Types are shared by all environments, so prod PORT becomes a string with dev
+		difference("prod", "PORT", envied.DiffTypeChanged, envied.FieldTypeInt, envied.FieldTypeString),
+		difference("prod", "TIMEOUT", envied.DiffAdded, "", envied.FieldTypeString),
+	}
+	if !reflect.DeepEqual(differences, expected) {
+		t.Errorf("DiffRelease() = %+v, expected %+v", differences, expected)
+	}
+
+	var report bytes.Buffer
+	if err := envied.WriteReleaseReport(&report, differences, envied.ReportMarkdown); err != nil {
+		t.Fatalf("WriteReleaseReport() returned error: %v", err)
+	}
+	for _, want := range []string{
+		"### dev\n\n| Change | Variable | Type |\n|--------|----------|------|\n| removed | `LEGACY` | bool |\n",
+		"| type changed | `PORT` | int → string |\n",
+		"### prod\n",
+		"| value changed | `API_KEY` | string |\n",
+	} {
+		if !strings.Contains(report.String(), want) {
+			t.Errorf("Report should contain %q:\n%s", want, report.String())
+		}
+	}
+	if strings.Contains(report.String(), "rotated-key") || strings.Contains(report.String(), "prod-key") {
+		t.Error("Report must not contain values")
+	}
+
+	report.Reset()
+	if err := envied.WriteReleaseReport(&report, differences[:1], envied.ReportJSON); err != nil {
+		t.Fatalf("WriteReleaseReport() returned error: %v", err)
+	}
+	if want := `"environment": "dev",` + "\n" + `    "name": "LEGACY",`; !strings.Contains(report.String(), want) {
+		t.Errorf("JSON report should contain %q:\n%s", want, report.String())
+	}
+	if err := envied.WriteReleaseReport(&report, nil, "html"); err == nil {
+		t.Error("WriteReleaseReport() expected error for an unknown format")
+	}
+}
+
+func TestManifestHashesWithoutKey(t *testing.T) {
+	_, _, manifest := writeRelease(t, "")
+	hashes := make(map[string]map[string]string)
+	for _, variable := range manifest.Variables {
+		hashes[variable.Name] = variable.Hashes
+	}
+
+	// Obfuscated values are sensitive, their unkeyed hashes could be brute-forced
+	if hashes["API_KEY"] != nil {
+		t.Errorf("Manifest hashes of API_KEY = %v, expected none without hash_key_env", hashes["API_KEY"])
+	}
+	if hash := hashes["PORT"]["prod"]; hash != envied.ValueHash(nil, "prod", "PORT", "80") {
+		t.Errorf("Manifest hash of PORT = %q, expected the unkeyed ValueHash", hash)
+	}
+	if envied.ValueHash(nil, "prod", "PORT", "80") == envied.ValueHash(releaseHashKey, "prod", "PORT", "80") {
+		t.Error("ValueHash() with a key should differ from the unkeyed hash")
+	}
+}
+
+func TestDiffReleasesWithoutHashes(t *testing.T) {
+	_, _, previous := writeRelease(t, "ENVIED_TEST_HASH_KEY")
+	current := *previous
+	current.Variables = nil
+	for i, variable := range previous.Variables {
+		variable.Hashes = map[string]string{"dev": "changed", "prod": "changed"}
+		current.Variables = append(current.Variables, variable)
+		// Manifests of older releases have no hashes
+		previous.Variables[i].Hashes = nil
+	}
+
+	if differences := envied.DiffReleases(previous, &current); len(differences) != 0 {
+		t.Errorf("DiffReleases() = %+v, expected no differences without hashes", differences)
+	}
+
+	var report bytes.Buffer
+	if err := envied.WriteReleaseReport(&report, nil, ""); err != nil || !strings.Contains(report.String(), "No variables changed") {
+		t.Errorf("WriteReleaseReport() = %v, %q, expected an empty report", err, report.String())
+	}
+}
+
+func TestReadManifestAtRef(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	tempDir, _, previous := writeRelease(t, "ENVIED_TEST_HASH_KEY")
+	git := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", append([]string{"-c", "user.name=test", "-c", "user.email=test@example.com"}, args...)...)
+		cmd.Dir = tempDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s failed: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+	git("init", "-q")
+	git("add", "manifest.json")
+	git("commit", "-q", "-m", "release")
+	git("tag", "v1.0.0")
+	if err := os.WriteFile(filepath.Join(tempDir, "manifest.json"), []byte("{}"), 0644); err != nil {
+		t.Fatalf("Failed to overwrite manifest: %v", err)
+	}
+
+	t.Chdir(tempDir)
+	manifest, err := envied.ReadManifestAtRef("v1.0.0", "manifest.json", nil)
+	if err != nil {
+		t.Fatalf("ReadManifestAtRef() returned error: %v", err)
+	}
+	want, _ := json.Marshal(previous)
+	got, _ := json.Marshal(manifest)
+	if !bytes.Equal(got, want) {
+		t.Errorf("ReadManifestAtRef() = %s, expected the committed manifest %s", got, want)
+	}
+	if _, err := envied.ReadManifestAtRef("v2.0.0", "manifest.json", nil); err == nil {
+		t.Error("ReadManifestAtRef() expected error for an unknown revision")
+	}
+}
@@ -0,0 +1,80 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestEmitRename(t *testing.T) {
+	outDir := t.TempDir()
+	generateConfig(t, map[string]string{
+		"prod": "DB_URL=postgres://db\nPORT=80\n# envied: sensitive=true\nAPI_KEY=prod-key\n",
+	}, func(config *envied.ConfigFile) {
+		obfuscate := false
+		prod := config.Environments["prod"]
+		prod.Obfuscate = &obfuscate
+		config.Environments["prod"] = prod
+		config.Emit = &envied.EmitConfig{
+			EnvExample: filepath.Join(outDir, ".env.example"),
+			Properties: filepath.Join(outDir, "properties"),
+			Helm:       filepath.Join(outDir, "chart"),
+			HelmSecret: "backend",
+			Rename: map[string]map[string]string{
+				"env_example":    {"DB_URL": "DATABASE_URL"},
+				"properties":     {"DB_URL": "spring.datasource.url"},
+				"helm":           {"DB_URL": "DATABASE_URL", "API_KEY": "SERVICE_API_KEY"},
+				"sealed_secrets": {"API_KEY": "api-key"},
+			},
+		}
+	})
+
+	for path, expected := range map[string][]string{
+		".env.example":               {"\nDATABASE_URL=\n", "\nPORT=\n"},
+		"properties/prod.properties": {"\nspring.datasource.url=postgres://db\n"},
+		"chart/values-prod.yaml": {
+			"  - name: DATABASE_URL\n    value: \"postgres://db\"\n",
+			"  - name: SERVICE_API_KEY\n    valueFrom:\n      secretKeyRef:\n        name: backend\n        key: api-key\n",
+		},
+	} {
+		content, err := os.ReadFile(filepath.Join(outDir, path))
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		for _, want := range expected {
+			if !strings.Contains(string(content), want) {
+				t.Errorf("%s should contain %q:\n%s", path, want, content)
+			}
+		}
+		if strings.Contains(string(content), "DB_URL") {
+			t.Errorf("%s should not contain the internal name DB_URL:\n%s", path, content)
+		}
+	}
+}
+
+func TestEmitRenameErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		rename   map[string]map[string]string
+		expected string
+	}{
+		{"unknown emitter", map[string]map[string]string{"docker": {"DB_URL": "DATABASE_URL"}}, "unknown emitter 'docker'"},
+		{"unknown variable", map[string]map[string]string{"helm": {"DB_URI": "DATABASE_URL"}}, "emit.rename.helm: unknown variable 'DB_URI'"},
+		{"invalid name", map[string]map[string]string{"helm": {"DB_URL": "DATABASE URL"}}, "'DATABASE URL' is not a valid variable name"},
+		{"collision", map[string]map[string]string{"ini": {"DB_URL": "PORT"}}, "DB_URL and PORT are both written as PORT"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{"prod": "DB_URL=postgres://db\nPORT=80\n"}, func(config *envied.ConfigFile) {
+				config.Emit = &envied.EmitConfig{INI: filepath.Join(config.OutputDir, "config.ini"), Rename: tt.rename}
+			})
+			if err := envied.GenerateFromConfigFile(configPath); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("GenerateFromConfigFile() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
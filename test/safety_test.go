@@ -0,0 +1,71 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestGenerateRejectsEnvFileInsideOutputDir(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\n",
+	}, func(config *envied.ConfigFile) {
+		config.OutputDir = filepath.Dir(config.Environments["dev"].EnvFile)
+	})
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil {
+		t.Fatal("Expected error for env file inside output directory")
+	}
+	if !strings.Contains(err.Error(), "inside output directory") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+}
+
+func TestGenerateRejectsOutputDirOutsideModule(t *testing.T) {
+	tempDir := t.TempDir()
+	projectDir := filepath.Join(tempDir, "project")
+	if err := os.MkdirAll(projectDir, 0755); err != nil {
+		t.Fatalf("Failed to create project directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(projectDir, "go.mod"), []byte("module project\n"), 0644); err != nil {
+		t.Fatalf("Failed to create go.mod: %v", err)
+	}
+
+	envFile := filepath.Join(projectDir, "dev.env")
+	if err := os.WriteFile(envFile, []byte("API_URL=https://dev.example.com\n"), 0644); err != nil {
+		t.Fatalf("Failed to create dev.env: %v", err)
+	}
+
+	config := &envied.ConfigFile{
+		PackageName: "config",
+		OutputDir:   filepath.Join(tempDir, "outside"),
+		Environments: map[string]envied.EnvironmentConfig{
+			"dev": {EnvFile: envFile, StructName: "DevConfig"},
+		},
+	}
+	configPath := filepath.Join(projectDir, envied.DefaultConfigFileName)
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil {
+		t.Fatal("Expected error for output directory outside module")
+	}
+	if !strings.Contains(err.Error(), "outside module") {
+		t.Errorf("Unexpected error: %v", err)
+	}
+
+	// Override flag allows generation
+	config.AllowUnsafePaths = true
+	if err := config.Save(configPath); err != nil {
+		t.Fatalf("Save() returned error: %v", err)
+	}
+	if err := envied.GenerateFromConfigFile(configPath); err != nil {
+		t.Fatalf("GenerateFromConfigFile() with allow_unsafe_paths returned error: %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package test
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// writeSealingCertificate writes a self-signed certificate like the one of a Sealed Secrets controller
+func writeSealingCertificate(t *testing.T, path string) *rsa.PrivateKey {
+	t.Helper()
+
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "sealed-secret"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("Failed to create certificate: %v", err)
+	}
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0644); err != nil {
+		t.Fatalf("Failed to write certificate: %v", err)
+	}
+	return key
+}
+
+// unseal decrypts a value sealed like kubeseal does
+func unseal(t *testing.T, key *rsa.PrivateKey, encoded, label string) string {
+	t.Helper()
+
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		t.Fatalf("Sealed value is not base64: %v", err)
+	}
+	keyLength := int(binary.BigEndian.Uint16(data))
+	sessionKey, err := rsa.DecryptOAEP(sha256.New(), nil, key, data[2:2+keyLength], []byte(label))
+	if err != nil {
+		t.Fatalf("Failed to decrypt session key: %v", err)
+	}
+	block, err := aes.NewCipher(sessionKey)
+	if err != nil {
+		t.Fatalf("Invalid session key: %v", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		t.Fatalf("Failed to create GCM: %v", err)
+	}
+	value, err := gcm.Open(nil, make([]byte, gcm.NonceSize()), data[2+keyLength:], nil)
+	if err != nil {
+		t.Fatalf("Failed to decrypt value: %v", err)
+	}
+	return string(value)
+}
+
+func TestEmitSealedSecrets(t *testing.T) {
+	outDir := t.TempDir()
+	certPath := filepath.Join(outDir, "sealed-secrets.pem")
+	key := writeSealingCertificate(t, certPath)
+	sealedDir := filepath.Join(outDir, "sealed")
+
+	generateConfig(t, map[string]string{
+		"prod": "API_URL=https://api.example.com\nPORT=80\n# envied: sensitive=true\nPIN=1234\n",
+	}, func(config *envied.ConfigFile) {
+		config.Emit = &envied.EmitConfig{SealedSecrets: sealedDir, SealedSecretsCert: certPath, Namespace: "backend"}
+	})
+
+	content, err := os.ReadFile(filepath.Join(sealedDir, "sealedsecret-prod.yaml"))
+	if err != nil {
+		t.Fatalf("Failed to read SealedSecret: %v", err)
+	}
+	for _, want := range []string{
+		"apiVersion: bitnami.com/v1alpha1\nkind: SealedSecret\nmetadata:\n  name: config-prod\n  namespace: backend\n",
+		"  template:\n    metadata:\n      name: config-prod\n      namespace: backend\n",
+	} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("SealedSecret should contain %q:\n%s", want, content)
+		}
+	}
+	if strings.Contains(string(content), "api.example.com") || strings.Contains(string(content), "PORT") {
+		t.Errorf("SealedSecret should hold only the sealed sensitive values:\n%s", content)
+	}
+
+	// Obfuscated strings and values marked sensitive are sealed for the Secret
+	for name, expected := range map[string]string{"API_URL": "https://api.example.com", "PIN": "1234"} {
+		match := regexp.MustCompile(`(?m)^    ` + name + `: (\S+)$`).FindStringSubmatch(string(content))
+		if match == nil {
+			t.Fatalf("SealedSecret should contain %s:\n%s", name, content)
+		}
+		if value := unseal(t, key, match[1], "backend/config-prod"); value != expected {
+			t.Errorf("Unsealed %s = %q, expected %q", name, value, expected)
+		}
+	}
+}
+
+func TestEmitSealedSecretsRequiresCertificate(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{"prod": "PORT=80\n"}, func(config *envied.ConfigFile) {
+		config.Emit = &envied.EmitConfig{SealedSecrets: filepath.Join(filepath.Dir(config.OutputDir), "sealed")}
+	})
+	if err := envied.GenerateFromConfigFile(configPath); err == nil || !strings.Contains(err.Error(), "emit.sealed_secrets requires emit.sealed_secrets_cert") {
+		t.Errorf("GenerateFromConfigFile() = %v, expected a missing certificate error", err)
+	}
+}
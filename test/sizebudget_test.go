@@ -0,0 +1,81 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestSizeBudget(t *testing.T) {
+	certificate := strings.Repeat("A", 3000)
+	tests := []struct {
+		name     string
+		modify   func(config *envied.ConfigFile)
+		expected []string
+	}{
+		{
+			name: "within budget",
+			modify: func(config *envied.ConfigFile) {
+				config.SizeBudget = &envied.SizeBudgetConfig{Environment: 4096, Variable: 4096}
+			},
+		},
+		{
+			name: "environment over budget",
+			modify: func(config *envied.ConfigFile) {
+				config.SizeBudget = &envied.SizeBudgetConfig{Environment: 2048}
+			},
+			expected: []string{"environment 'dev' embeds 3.0 KiB, over the size budget of 2.0 KiB:", "\n  TLS_CERT: 2.9 KiB\n  API_URL: 23 B"},
+		},
+		{
+			name: "variable over budget",
+			modify: func(config *envied.ConfigFile) {
+				config.SizeBudget = &envied.SizeBudgetConfig{Variable: 1024}
+			},
+			expected: []string{"variable TLS_CERT of environment 'dev' embeds 2.9 KiB, over its limit of 1.0 KiB"},
+		},
+		{
+			name: "per-variable limit overrides the budget",
+			modify: func(config *envied.ConfigFile) {
+				config.SizeBudget = &envied.SizeBudgetConfig{Variable: 4096}
+				config.Variables = map[string]envied.VariableConfig{"API_URL": {MaxBytes: 16}}
+			},
+			expected: []string{"variable API_URL of environment 'dev' embeds 23 B, over its limit of 16 B"},
+		},
+		{
+			name: "obfuscated data counts",
+			modify: func(config *envied.ConfigFile) {
+				config.Obfuscation = ""
+				config.SizeBudget = &envied.SizeBudgetConfig{Environment: 4096}
+			},
+			expected: []string{"environment 'dev' embeds", "TLS_CERT: 46.9 KiB (obfuscated)"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeConfig(t, map[string]string{
+				"dev": "API_URL=https://dev.example.com\nTLS_CERT=" + certificate + "\n",
+			}, func(config *envied.ConfigFile) {
+				config.Obfuscation = envied.ObfuscationNone
+				tt.modify(config)
+			})
+
+			err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+			if len(tt.expected) == 0 {
+				if err != nil {
+					t.Fatalf("Validate() returned error: %v", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatal("Validate() succeeded, expected a size budget error")
+			}
+			for _, expected := range tt.expected {
+				if !strings.Contains(err.Error(), expected) {
+					t.Errorf("Validate() = %v, expected error containing %q", err, expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// fakeSQLTables holds the rows returned by the fake driver, keyed by DSN
+var fakeSQLTables = map[string]fakeSQLRows{
+	"settings": {
+		columns: []string{"name", "value"},
+		values: [][]driver.Value{
+			{"API_URL", "https://api.example.com"},
+			{"PORT", "8080"},
+			{"EMPTY", nil},
+		},
+	},
+	"duplicates": {
+		columns: []string{"name", "value"},
+		values:  [][]driver.Value{{"PORT", "1"}, {"PORT", "2"}},
+	},
+	"wide": {
+		columns: []string{"name", "value", "env"},
+		values:  [][]driver.Value{{"PORT", "1", "prod"}},
+	},
+}
+
+func init() {
+	sql.Register("envied-fake", fakeSQLDriver{})
+}
+
+// fakeSQLDriver returns the rows of fakeSQLTables[dsn] for every query
+type fakeSQLDriver struct{}
+
+func (fakeSQLDriver) Open(dsn string) (driver.Conn, error) {
+	rows, exists := fakeSQLTables[dsn]
+	if !exists {
+		return nil, errors.New("unknown database " + dsn)
+	}
+	return fakeSQLConn{rows: rows}, nil
+}
+
+type fakeSQLConn struct {
+	rows fakeSQLRows
+}
+
+func (c fakeSQLConn) Prepare(query string) (driver.Stmt, error) {
+	return fakeSQLStmt(c), nil
+}
+
+func (fakeSQLConn) Close() error { return nil }
+
+func (fakeSQLConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeSQLStmt struct {
+	rows fakeSQLRows
+}
+
+func (fakeSQLStmt) Close() error { return nil }
+
+func (fakeSQLStmt) NumInput() int { return -1 }
+
+func (fakeSQLStmt) Exec(args []driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec is not supported")
+}
+
+func (s fakeSQLStmt) Query(args []driver.Value) (driver.Rows, error) {
+	rows := s.rows
+	return &rows, nil
+}
+
+type fakeSQLRows struct {
+	columns []string
+	values  [][]driver.Value
+	next    int
+}
+
+func (r *fakeSQLRows) Columns() []string { return r.columns }
+
+func (r *fakeSQLRows) Close() error { return nil }
+
+func (r *fakeSQLRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.values) {
+		return io.EOF
+	}
+	copy(dest, r.values[r.next])
+	r.next++
+	return nil
+}
+
+// sqlEnvironment returns a prod environment reading the fake database
+func sqlEnvironment(config *envied.SQLConfig) func(*envied.ConfigFile) {
+	return func(configFile *envied.ConfigFile) {
+		configFile.Obfuscation = envied.ObfuscationNone
+		configFile.Environments["prod"] = envied.EnvironmentConfig{StructName: "Prod", SQL: config}
+	}
+}
+
+func TestGenerateFromSQL(t *testing.T) {
+	t.Setenv("ENVIED_DSN", "settings")
+
+	_, content := generateConfig(t, map[string]string{
+		"dev": "API_URL=https://dev.example.com\nPORT=3000\nEMPTY=\n",
+	}, sqlEnvironment(&envied.SQLConfig{
+		Driver: "envied-fake",
+		DSNEnv: "ENVIED_DSN",
+		Query:  "SELECT name, value FROM settings WHERE env = 'prod'",
+	}))
+
+	if !strings.Contains(content, `API_URL: "https://api.example.com",`) {
+		t.Error("Generated code should contain the value read from the table")
+	}
+	if !strings.Contains(content, `envied.ParseInt("8080")`) {
+		t.Error("Generated code should contain the typed value read from the table")
+	}
+}
+
+func TestSQLSourceErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   envied.SQLConfig
+		expected string
+	}{
+		{"unregistered driver", envied.SQLConfig{Driver: "oracle", DSN: "settings", Query: "SELECT"}, `sql driver "oracle" is not registered`},
+		{"missing dsn", envied.SQLConfig{Driver: "envied-fake", Query: "SELECT"}, "one of dsn and dsn_env must be set"},
+		{"duplicate names", envied.SQLConfig{Driver: "envied-fake", DSN: "duplicates", Query: "SELECT"}, "PORT is returned more than once"},
+		{"wrong columns", envied.SQLConfig{Driver: "envied-fake", DSN: "wide", Query: "SELECT"}, "must return 2 columns"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := tt.config
+			_, configPath := writeConfig(t, map[string]string{"dev": "PORT=1\n"}, sqlEnvironment(&config))
+
+			err := envied.GenerateFromConfigFile(configPath)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("GenerateFromConfigFile() error = %v, expected %q", err, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// stringSliceProgram prints the list variables of the generated prod configuration
+const stringSliceProgram = `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+	"github.com/petrovyuri/go-envied"
+)
+
+func main() {
+	prod := config.NewProdConfig()
+	fmt.Printf("%q %q\n", prod.GetALLOWED_ORIGINS(), prod.GetSCOPES())
+
+	value, _ := prod.Lookup("SCOPES")
+	fmt.Println(value)
+
+	cfg, err := config.NewLayeredConfig("prod", envied.MapSource{"ALLOWED_ORIGINS": "x.com, y.com"})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Printf("%q\n", cfg.GetALLOWED_ORIGINS())
+}
+`
+
+func TestStringSliceFields(t *testing.T) {
+	for _, obfuscation := range []string{envied.ObfuscationNone, envied.ObfuscationXOR, envied.ObfuscationAESGCM} {
+		t.Run(obfuscation, func(t *testing.T) {
+			dir, content := generateConfig(t, map[string]string{
+				"dev":  "ALLOWED_ORIGINS=localhost\nSCOPES=read\n",
+				"prod": "ALLOWED_ORIGINS=a.com, b.com,c.com\nSCOPES=read|write\n",
+			}, func(config *envied.ConfigFile) {
+				config.Obfuscation = obfuscation
+				config.Variables = map[string]envied.VariableConfig{
+					"ALLOWED_ORIGINS": {Separator: ","},
+					"SCOPES":          {Separator: "|"},
+				}
+			})
+
+			if !strings.Contains(content, "GetALLOWED_ORIGINS() []string") {
+				t.Error("Generated code should contain a []string getter")
+			}
+			switch obfuscation {
+			case envied.ObfuscationNone:
+				if !strings.Contains(content, `ALLOWED_ORIGINS: []string{"a.com", "b.com", "c.com"},`) {
+					t.Error("Generated code should contain a plain []string literal")
+				}
+			case envied.ObfuscationXOR:
+				if !strings.Contains(content, "envied.MustDecodeStrings(") || strings.Contains(content, "a.com") {
+					t.Error("Generated code should contain XOR obfuscated elements only")
+				}
+			case envied.ObfuscationAESGCM:
+				if !strings.Contains(content, "envied.MustOpenStrings(") || strings.Contains(content, "a.com") {
+					t.Error("Generated code should contain sealed elements only")
+				}
+			}
+
+			output, err := runGenerated(t, dir, stringSliceProgram)
+			if err != nil {
+				t.Fatalf("Failed to run program: %v\n%s", err, output)
+			}
+
+			expected := "[\"a.com\" \"b.com\" \"c.com\"] [\"read\" \"write\"]\nread|write\n[\"x.com\" \"y.com\"]\n"
+			if output != expected {
+				t.Errorf("Output = %q, expected %q", output, expected)
+			}
+		})
+	}
+}
+
+func TestStringSliceWithTimeLayout(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev": "DATES=2024-06-01\n",
+	}, func(config *envied.ConfigFile) {
+		config.Variables = map[string]envied.VariableConfig{
+			"DATES": {Separator: ",", TimeLayout: "DateOnly"},
+		}
+	})
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), "only one of separator and time_layout can be set") {
+		t.Errorf("Expected separator and time_layout conflict, got %v", err)
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value     string
+		separator string
+		expected  []string
+	}{
+		{"a.com,b.com", ",", []string{"a.com", "b.com"}},
+		{" a ; b ", ";", []string{"a", "b"}},
+		{"a,,b", "", []string{"a", "", "b"}},
+		{"", ",", []string{}},
+	}
+
+	for _, tt := range tests {
+		if result := envied.SplitList(tt.value, tt.separator); !reflect.DeepEqual(result, tt.expected) {
+			t.Errorf("SplitList(%q, %q) = %q, expected %q", tt.value, tt.separator, result, tt.expected)
+		}
+	}
+}
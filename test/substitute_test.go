@@ -0,0 +1,67 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// writeRegionConfig creates eu and us environments sharing one env file with a {{.Region}} placeholder
+func writeRegionConfig(t *testing.T) (string, string) {
+	t.Helper()
+
+	return writeConfig(t, map[string]string{"eu": "ENDPOINT=https://api.{{.Region}}.example.com/{{ .Version }}\n"}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		config.Substitutions = map[string]string{"Region": "eu-west-1", "Version": "v1"}
+		config.Environments["us"] = envied.EnvironmentConfig{
+			EnvFile:       config.Environments["eu"].EnvFile,
+			StructName:    "Us",
+			Substitutions: map[string]string{"Region": "us-east-1"},
+		}
+	})
+}
+
+func TestSubstitutions(t *testing.T) {
+	tempDir, configPath := writeRegionConfig(t)
+	if err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath, Set: map[string]string{"Version": "v2"}}); err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	content, err := os.ReadFile(filepath.Join(tempDir, "config", "config_env.gen.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+
+	for _, want := range []string{`"https://api.eu-west-1.example.com/v2"`, `"https://api.us-east-1.example.com/v2"`} {
+		if !strings.Contains(string(content), want) {
+			t.Errorf("Generated code should contain %s", want)
+		}
+	}
+}
+
+func TestSubstitutionErrors(t *testing.T) {
+	_, configPath := writeRegionConfig(t)
+	err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath, Set: map[string]string{"Version": ""}})
+	if err != nil {
+		t.Fatalf("Generate() returned error for an empty substitution: %v", err)
+	}
+
+	tempDir, configPath := writeConfig(t, map[string]string{"dev": "ENDPOINT=https://{{.Host}}/{{.Path}}\n"}, func(config *envied.ConfigFile) {
+		config.Substitutions = map[string]string{"Host": "localhost"}
+	})
+	expected := "ENDPOINT (" + filepath.Join(tempDir, "dev.env") + ":1) references undefined substitution {{.Path}}"
+	if err := envied.GenerateFromConfigFile(configPath); err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("GenerateFromConfigFile() = %v, expected error containing %q", err, expected)
+	}
+}
+
+func TestSubstitutionsDisabledWithoutValues(t *testing.T) {
+	_, content := generateConfig(t, map[string]string{"dev": "GREETING=\"Hello {{.Name}}\"\n"}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+	})
+	if !strings.Contains(content, `"Hello {{.Name}}"`) {
+		t.Errorf("Values without substitutions should be kept:\n%s", content)
+	}
+}
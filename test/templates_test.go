@@ -0,0 +1,127 @@
+package test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestTemplateFuncs(t *testing.T) {
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "upper",
+			template: `{{upper "api_url"}}`,
+			expected: "API_URL",
+		},
+		{
+			name:     "camel",
+			template: `{{camel "DATABASE_URL"}}`,
+			expected: "databaseUrl",
+		},
+		{
+			name:     "pascal",
+			template: `{{pascal "DATABASE_URL"}}`,
+			expected: "DatabaseUrl",
+		},
+		{
+			name:     "snake",
+			template: `{{snake "DatabaseURL"}}`,
+			expected: "database_url",
+		},
+		{
+			name:     "kebab",
+			template: `{{kebab "maxTokens"}}`,
+			expected: "max-tokens",
+		},
+		{
+			name:     "title",
+			template: `{{title "hello WORLD"}}`,
+			expected: "Hello World",
+		},
+		{
+			name:     "quote",
+			template: `{{quote "a\"b"}}`,
+			expected: `"a\"b"`,
+		},
+		{
+			name:     "backquote",
+			template: `{{backquote "raw"}}`,
+			expected: "`raw`",
+		},
+		{
+			name:     "chunk",
+			template: `{{range chunk 2 "abcde"}}[{{.}}]{{end}}`,
+			expected: "[ab][cd][e]",
+		},
+		{
+			name:     "obfuscate round trip",
+			template: `{{deobfuscate (obfuscate "secret" "key") "key"}}`,
+			expected: "secret",
+		},
+		{
+			name:     "obfuscateString",
+			template: `{{$r := obfuscateString "hi" 42}}{{len $r.Key}}`,
+			expected: "2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl, err := template.New("test").Funcs(envied.TemplateFuncs()).Parse(tt.template)
+			if err != nil {
+				t.Fatalf("Failed to parse template: %v", err)
+			}
+
+			var buf bytes.Buffer
+			if err := tmpl.Execute(&buf, nil); err != nil {
+				t.Fatalf("Failed to execute template: %v", err)
+			}
+
+			if buf.String() != tt.expected {
+				t.Errorf("%s = %q, expected %q", tt.template, buf.String(), tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateWithCustomTemplate(t *testing.T) {
+	tempDir := t.TempDir()
+	envFile := filepath.Join(tempDir, "dev.env")
+
+	err := os.WriteFile(envFile, []byte("API_URL=https://dev.example.com\n"), 0644)
+	if err != nil {
+		t.Fatalf("Failed to create dev.env: %v", err)
+	}
+
+	generator := envied.NewGenerator(&envied.Config{
+		PackageName: "custom",
+		Environment: "Dev",
+		OutputDir:   tempDir,
+		Template:    `{{range .Fields}}{{camel .EnvName}}={{shout .EnvName}}{{end}}`,
+		Funcs: template.FuncMap{
+			"shout": func(s string) string { return s + "!" },
+		},
+	})
+
+	if err := generator.GenerateFromEnvFile(envFile); err != nil {
+		t.Fatalf("GenerateFromEnvFile() returned error: %v", err)
+	}
+
+	content, err := os.ReadFile(filepath.Join(tempDir, "config_dev.go"))
+	if err != nil {
+		t.Fatalf("Failed to read generated file: %v", err)
+	}
+
+	if !strings.Contains(string(content), "apiUrl=API_URL!") {
+		t.Errorf("Unexpected generated content: %s", content)
+	}
+}
@@ -0,0 +1,98 @@
+package test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestTimeFields(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "CERT_EXPIRY=2025-01-01T00:00:00Z\nLAUNCH_DATE=2024-06-01\nCUTOFF=09:30\n",
+		"prod": "CERT_EXPIRY=2026-03-15T12:00:00+02:00\nLAUNCH_DATE=2024-07-01\nCUTOFF=10:00\n",
+	}, func(config *envied.ConfigFile) {
+		config.Variables = map[string]envied.VariableConfig{
+			"LAUNCH_DATE": {TimeLayout: "DateOnly"},
+			"CUTOFF":      {TimeLayout: "15:04"},
+		}
+	})
+
+	for _, want := range []string{
+		"GetCERT_EXPIRY() time.Time",
+		`CERT_EXPIRY: envied.ParseTime("2006-01-02T15:04:05Z07:00", "2026-03-15T12:00:00+02:00"),`,
+		`LAUNCH_DATE: envied.ParseTime("2006-01-02", "2024-07-01"),`,
+		`CUTOFF:      envied.ParseTime("15:04", "10:00"),`,
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q", want)
+		}
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+	"time"
+
+	"generated/config"
+	"github.com/petrovyuri/go-envied"
+)
+
+func main() {
+	prod := config.NewProdConfig()
+	fmt.Println(prod.GetCERT_EXPIRY().UTC().Format(time.RFC3339), prod.GetLAUNCH_DATE().Format("Jan 2"))
+
+	value, _ := prod.Lookup("LAUNCH_DATE")
+	fmt.Println(value)
+
+	cfg, err := config.NewLayeredConfig("prod", envied.MapSource{"LAUNCH_DATE": "2024-08-01"})
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(cfg.GetLAUNCH_DATE().Format(time.DateOnly))
+
+	_, err = config.NewLayeredConfig("prod", envied.MapSource{"CUTOFF": "noon"})
+	fmt.Println(err)
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+
+	expected := "2026-03-15T10:00:00Z Jul 1\n2024-07-01\n2024-08-01\n" +
+		"❌ ERROR: variable CUTOFF: invalid time value \"noon\", expected layout \"15:04\"\n"
+	if output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
+
+func TestTimeLayoutMismatch(t *testing.T) {
+	_, configPath := writeConfig(t, map[string]string{
+		"dev":  "LAUNCH_DATE=2024-06-01\n",
+		"prod": "LAUNCH_DATE=June 1st\n",
+	}, func(config *envied.ConfigFile) {
+		config.Variables = map[string]envied.VariableConfig{
+			"LAUNCH_DATE": {TimeLayout: "DateOnly"},
+		}
+	})
+
+	err := envied.GenerateFromConfigFile(configPath)
+	if err == nil || !strings.Contains(err.Error(), `variable LAUNCH_DATE: value "June 1st" does not match time layout "DateOnly"`) {
+		t.Errorf("Expected layout mismatch error, got %v", err)
+	}
+}
+
+func TestParseTime(t *testing.T) {
+	expected := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	if value := envied.ParseTime(time.RFC3339, "2025-01-01T00:00:00Z"); !value.Equal(expected) {
+		t.Errorf("ParseTime() = %v, expected %v", value, expected)
+	}
+	if envied.DetectFieldType("2025-01-01T00:00:00Z") != envied.FieldTypeTime {
+		t.Error("RFC 3339 values should be detected as time.Time")
+	}
+	if envied.DetectFieldType("2025-01-01") != envied.FieldTypeString {
+		t.Error("Dates without a configured layout should stay strings")
+	}
+}
@@ -0,0 +1,133 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestReadTOMLFile(t *testing.T) {
+	tomlFile := filepath.Join(t.TempDir(), "config.toml")
+	content := `# API settings
+API_URL = "https://dev.example.com" # trailing comment
+PORT = 8080
+DEBUG = true
+TIMEOUT = 1_500
+NAME = 'C:\path'
+site.NAME = "dev"
+
+[DATABASE]
+HOST = "localhost"
+# envied: type=string
+PORT = 5432
+
+[DATABASE.CREDENTIALS]
+"USER" = "admin"
+`
+	if err := os.WriteFile(tomlFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create TOML file: %v", err)
+	}
+
+	envVars, err := envied.ReadTOMLFile(tomlFile, "")
+	if err != nil {
+		t.Fatalf("ReadTOMLFile() returned error: %v", err)
+	}
+
+	expected := map[string]envied.EnvValue{
+		"API_URL":                   {Value: "https://dev.example.com", WasQuoted: true, Line: 2},
+		"PORT":                      {Value: "8080", Line: 3},
+		"DEBUG":                     {Value: "true", Line: 4},
+		"TIMEOUT":                   {Value: "1500", Line: 5},
+		"NAME":                      {Value: `C:\path`, WasQuoted: true, Line: 6},
+		"site_NAME":                 {Value: "dev", WasQuoted: true, Line: 7},
+		"DATABASE_HOST":             {Value: "localhost", WasQuoted: true, Line: 10},
+		"DATABASE_PORT":             {Value: "5432", Line: 12, Hints: envied.EnvHints{Type: "string"}},
+		"DATABASE_CREDENTIALS_USER": {Value: "admin", WasQuoted: true, Line: 15},
+	}
+	if len(envVars) != len(expected) {
+		t.Errorf("ReadTOMLFile() = %+v, expected %d variables", envVars, len(expected))
+	}
+	for name, want := range expected {
+		if got := envVars[name]; got != want {
+			t.Errorf("%s = %+v, expected %+v", name, got, want)
+		}
+	}
+
+	envVars, err = envied.ReadTOMLFile(tomlFile, ".")
+	if err != nil {
+		t.Fatalf("ReadTOMLFile() returned error: %v", err)
+	}
+	if _, exists := envVars["DATABASE.CREDENTIALS.USER"]; !exists {
+		t.Errorf("Table keys should be joined with the separator, got %v", envVars)
+	}
+}
+
+func TestReadTOMLFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"array", "HOSTS = [\"a\", \"b\"]\n", ":1: key HOSTS: arrays are not supported"},
+		{"inline table", "DB = { host = \"a\" }\n", ":1: key DB: inline tables are not supported"},
+		{"array of tables", "[[servers]]\n", ":1: arrays of tables are not supported"},
+		{"multi-line string", "KEY = \"\"\"\nline\n\"\"\"\n", ":1: key KEY: multi-line strings are not supported"},
+		{"duplicate key", "A_B = 1\n\n[A]\nB = 2\n", ":4: key A_B is already defined at line 1"},
+		{"duplicate table", "[A]\n[A]\n", ":2: table A is already defined at line 1"},
+		{"missing equals", "KEY value\n", ":1: expected 'key = value'"},
+		{"missing value", "KEY =\n", ":1: key KEY: missing value"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tomlFile := filepath.Join(t.TempDir(), "env.toml")
+			if err := os.WriteFile(tomlFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create TOML file: %v", err)
+			}
+			if _, err := envied.ReadTOMLFile(tomlFile, ""); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("ReadTOMLFile() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateFromTOMLEnvFile(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nDATABASE_HOST=localhost\nDATABASE_PORT=5432\n",
+		"prod": "",
+	}, func(config *envied.ConfigFile) {
+		prodFile := filepath.Join(filepath.Dir(config.Environments["prod"].EnvFile), "config.toml")
+		toml := "API_URL = \"https://api.example.com\"\n\n[DATABASE]\nHOST = \"db.example.com\"\nPORT = 5433\n"
+		if err := os.WriteFile(prodFile, []byte(toml), 0644); err != nil {
+			t.Fatalf("Failed to create TOML file: %v", err)
+		}
+		config.Environments["prod"] = envied.EnvironmentConfig{EnvFile: prodFile, StructName: "Prod"}
+	})
+
+	if !strings.Contains(content, "GetDATABASE_PORT() int") {
+		t.Error("TOML values should be typed like .env values")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	prod := config.NewProdConfig()
+	fmt.Println(prod.GetAPI_URL(), prod.GetDATABASE_HOST(), prod.GetDATABASE_PORT())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if expected := "https://api.example.com db.example.com 5433\n"; output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
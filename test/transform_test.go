@@ -0,0 +1,125 @@
+package test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestApplyTransforms(t *testing.T) {
+	tests := []struct {
+		name        string
+		value       string
+		transforms  []string
+		expected    string
+		expectError bool
+	}{
+		{
+			name:       "no transforms",
+			value:      " Value ",
+			transforms: nil,
+			expected:   " Value ",
+		},
+		{
+			name:       "trim",
+			value:      "  value\t",
+			transforms: []string{"trim"},
+			expected:   "value",
+		},
+		{
+			name:       "lowercase",
+			value:      "MiXeD",
+			transforms: []string{"lowercase"},
+			expected:   "mixed",
+		},
+		{
+			name:       "uppercase",
+			value:      "MiXeD",
+			transforms: []string{"uppercase"},
+			expected:   "MIXED",
+		},
+		{
+			name:       "base64decode then trim",
+			value:      "ICBzZWNyZXQgIA==",
+			transforms: []string{"base64decode", "trim"},
+			expected:   "secret",
+		},
+		{
+			name:        "invalid base64",
+			value:       "not base64!",
+			transforms:  []string{"base64decode"},
+			expectError: true,
+		},
+		{
+			name:        "unknown transform",
+			value:       "value",
+			transforms:  []string{"reverse"},
+			expectError: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := envied.ApplyTransforms(tt.value, tt.transforms)
+
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error, got %q", result)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("ApplyTransforms(%q, %v) = %q, expected %q", tt.value, tt.transforms, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestRegisterTransform(t *testing.T) {
+	envied.RegisterTransform("test_strip_prefix", func(value string) (string, error) {
+		return strings.TrimPrefix(value, "vault:"), nil
+	})
+
+	found := false
+	for _, name := range envied.Transforms() {
+		if name == "test_strip_prefix" {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Registered transform should be listed by Transforms()")
+	}
+
+	result, err := envied.ApplyTransforms("vault:secret", []string{"test_strip_prefix"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if result != "secret" {
+		t.Errorf("Result = %q, expected %q", result, "secret")
+	}
+}
+
+func TestGenerateWithTransforms(t *testing.T) {
+	_, content := generateConfig(t, map[string]string{
+		"dev":  "PORT=ODA4MA==\nAPI_URL=HTTPS://DEV.EXAMPLE.COM\n",
+		"prod": "PORT=ODA=\nAPI_URL=HTTPS://API.EXAMPLE.COM\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		config.Variables = map[string]envied.VariableConfig{
+			"PORT":    {Transform: []string{"base64decode"}},
+			"API_URL": {Transform: []string{"lowercase"}},
+		}
+	})
+
+	// Values are typed after transformation
+	if !strings.Contains(content, `PORT:    envied.ParseInt("8080")`) {
+		t.Error("Decoded PORT should be detected as int")
+	}
+	if !strings.Contains(content, `API_URL: "https://api.example.com"`) {
+		t.Error("API_URL should be lowercased")
+	}
+}
@@ -0,0 +1,83 @@
+package test
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestUnsafeCharacters(t *testing.T) {
+	envs := map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nGREETING=\"bell\x07 and ‮reversed\"\n",
+		"prod": "API_URL=https://api.example.com\nGREETING=\"line\none\ttab\"\n",
+	}
+
+	// Allowed by default
+	_, content := generateConfig(t, envs, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+	})
+	if !strings.Contains(content, `"bell\a and \u202ereversed"`) {
+		t.Errorf("Values should be embedded unchanged by default:\n%s", content)
+	}
+
+	tempDir, configPath := writeConfig(t, envs, func(config *envied.ConfigFile) {
+		config.OnUnsafeCharacters = envied.UnsafeCharactersReject
+	})
+	err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath})
+	expected := "environment 'dev': GREETING (" + filepath.Join(tempDir, "dev.env") + ":2) contains control character U+0007 at byte 4"
+	if err == nil || !strings.Contains(err.Error(), expected) {
+		t.Errorf("Validate() = %v, expected error containing %q", err, expected)
+	}
+
+	_, configPath = writeConfig(t, envs, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		config.OnUnsafeCharacters = envied.UnsafeCharactersEscape
+	})
+	var warnings []envied.Warning
+	captureStdout(t, func() {
+		warnings, err = envied.GenerateWithWarnings(envied.GenerateOptions{ConfigPath: configPath})
+	})
+	if err != nil {
+		t.Fatalf("GenerateWithWarnings() returned error: %v", err)
+	}
+	if len(warnings) != 1 || warnings[0].Code != envied.WarningUnsafeCharacters || warnings[0].Environment != "dev" || warnings[0].Variable != "GREETING" {
+		t.Errorf("Expected an unsafe characters warning for dev, got %+v", warnings)
+	}
+
+	_, configPath = writeConfig(t, envs, func(config *envied.ConfigFile) {
+		config.OnUnsafeCharacters = "strip"
+	})
+	if err := envied.Validate(envied.GenerateOptions{ConfigPath: configPath}); err == nil || !strings.Contains(err.Error(), "unknown unsafe characters policy 'strip'") {
+		t.Errorf("Validate() = %v, expected an unknown policy error", err)
+	}
+}
+
+func TestUnsafeCharactersEscaped(t *testing.T) {
+	dir, _ := generateConfig(t, map[string]string{
+		"dev":  "GREETING=\"bell\x07 and ‮reversed\"\n",
+		"prod": "GREETING=hello\n",
+	}, func(config *envied.ConfigFile) {
+		config.OnUnsafeCharacters = envied.UnsafeCharactersEscape
+	})
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	fmt.Print(config.NewDevConfig().GetGREETING())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run generated code: %v\n%s", err, output)
+	}
+	if output != `bell\x07 and \u202ereversed` {
+		t.Errorf("Escaped value = %q", output)
+	}
+}
@@ -0,0 +1,131 @@
+package test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+// newFakeVault serves KV v2 secrets of the default mount to requests with the token
+func newFakeVault(t *testing.T, token string, secrets map[string]map[string]any) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != token {
+			http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+			return
+		}
+		secret, exists := secrets[strings.TrimPrefix(r.URL.Path, "/v1/secret/data/")]
+		if !exists {
+			http.Error(w, `{"errors":[]}`, http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"data": secret, "metadata": map[string]any{"version": 3}}})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// writeVaultConfig writes a configuration whose prod environment takes its values from vault
+func writeVaultConfig(t *testing.T, prod map[string]any) (string, string) {
+	t.Helper()
+
+	server := newFakeVault(t, "ci-token", map[string]map[string]any{"app/prod": prod})
+	t.Setenv("VAULT_TOKEN", "ci-token")
+	return writeConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "API_URL=\nPORT=\n",
+	}, func(config *envied.ConfigFile) {
+		config.Obfuscation = envied.ObfuscationNone
+		prod := config.Environments["prod"]
+		prod.Vault = &envied.VaultConfig{Address: server.URL, Path: "app/prod"}
+		config.Environments["prod"] = prod
+	})
+}
+
+func TestVaultValues(t *testing.T) {
+	tempDir, configPath := writeVaultConfig(t, map[string]any{"API_URL": "https://api.example.com", "PORT": 443})
+	outputFile := filepath.Join(tempDir, "config", "config_env.gen.go")
+
+	// Without the values prod is checked against dev but not generated
+	if err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath}); err != nil {
+		t.Fatalf("Generate() returned error: %v", err)
+	}
+	content, _ := os.ReadFile(outputFile)
+	if !strings.Contains(string(content), "DevConfig") || strings.Contains(string(content), "ProdConfig") {
+		t.Error("Generated file without values must contain dev only")
+	}
+
+	if err := envied.Generate(envied.GenerateOptions{ConfigPath: configPath, ValuesFrom: envied.ValuesFromVault}); err != nil {
+		t.Fatalf("Generate() with values from vault returned error: %v", err)
+	}
+	content, _ = os.ReadFile(outputFile)
+	for _, expected := range []string{"type ProdConfig struct", `"https://api.example.com"`, `envied.ParseInt("443")`} {
+		if !strings.Contains(string(content), expected) {
+			t.Errorf("Generated file is missing %q", expected)
+		}
+	}
+
+	if err := envied.Verify(envied.GenerateOptions{ConfigPath: configPath}); err != nil {
+		t.Errorf("Verify() returned error for an environment with values from vault: %v", err)
+	}
+}
+
+func TestVaultValuesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		prod     map[string]any
+		modify   func(t *testing.T, opts *envied.GenerateOptions)
+		expected string
+	}{
+		{
+			name:     "variable not in the structure",
+			prod:     map[string]any{"API_URL": "https://api.example.com", "DEBUG": "true"},
+			expected: "has variable DEBUG, which is not declared in",
+		},
+		{
+			name: "missing token",
+			prod: map[string]any{"API_URL": "https://api.example.com"},
+			modify: func(t *testing.T, opts *envied.GenerateOptions) {
+				t.Setenv("VAULT_TOKEN", "")
+			},
+			expected: "set VAULT_TOKEN",
+		},
+		{
+			name: "denied token",
+			prod: map[string]any{"API_URL": "https://api.example.com"},
+			modify: func(t *testing.T, opts *envied.GenerateOptions) {
+				t.Setenv("VAULT_TOKEN", "developer-token")
+			},
+			expected: "403 Forbidden",
+		},
+		{
+			name: "unknown values source",
+			prod: map[string]any{"API_URL": "https://api.example.com"},
+			modify: func(t *testing.T, opts *envied.GenerateOptions) {
+				opts.ValuesFrom = "consul"
+			},
+			expected: "unknown values source 'consul'",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, configPath := writeVaultConfig(t, tt.prod)
+			opts := envied.GenerateOptions{ConfigPath: configPath, ValuesFrom: envied.ValuesFromVault}
+			if tt.modify != nil {
+				tt.modify(t, &opts)
+			}
+			err := envied.Generate(opts)
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Generate() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
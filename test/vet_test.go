@@ -0,0 +1,51 @@
+package test
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestFindGetenvCalls(t *testing.T) {
+	dir, _ := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nPORT=8080\n",
+		"prod": "API_URL=https://api.example.com\nPORT=80\n",
+	}, nil)
+	configPath := filepath.Join(dir, envied.DefaultConfigFileName)
+
+	if output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+	env "os"
+
+	"generated/config"
+)
+
+func main() {
+	name := "PORT"
+	_, found := env.LookupEnv("API_URL")
+	fmt.Println(config.NewProdConfig().GetAPI_URL(), found, env.Getenv(name), env.Getenv("HOME") != "")
+	fmt.Println(env.Getenv("PORT"))
+}
+`); err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+
+	t.Chdir(dir)
+	calls, err := envied.FindGetenvCalls(configPath, "./...")
+	if err != nil {
+		t.Fatalf("FindGetenvCalls() returned error: %v", err)
+	}
+
+	if len(calls) != 2 {
+		t.Fatalf("Expected 2 calls, got %+v", calls)
+	}
+	if calls[0].Function != "os.LookupEnv" || calls[0].Name != "API_URL" || calls[0].Getter != "GetAPI_URL" || calls[0].Position.Line != 12 {
+		t.Errorf("Unexpected first call: %+v", calls[0])
+	}
+	if calls[1].Function != "os.Getenv" || calls[1].Name != "PORT" || calls[1].Position.Line != 14 {
+		t.Errorf("Unexpected second call: %+v", calls[1])
+	}
+}
@@ -0,0 +1,60 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestValidateWithWarnings(t *testing.T) {
+	tempDir, configPath := writeConfig(t, map[string]string{
+		"dev":  "API_KEY=changeme\nPORT=1\nVERSION=007\nRETRIES=1\nname=app\n",
+		"prod": "API_KEY=secret\nPORT=8080\nVERSION=007\nRETRIES=3\nname=app\n",
+	}, func(config *envied.ConfigFile) {
+		config.Fields = map[string]envied.FieldType{"RETRIES": envied.FieldTypeInt}
+	})
+
+	staging := filepath.Join(tempDir, "staging.env")
+	if err := os.WriteFile(staging, []byte("API_KEY=<your-key>\nPORT=8080\nVERSION=007\nRETRIES=3\nname=app\n"), 0644); err != nil {
+		t.Fatalf("Failed to create env file: %v", err)
+	}
+
+	var warnings []envied.Warning
+	var err error
+	output := captureStdout(t, func() {
+		warnings, err = envied.ValidateWithWarnings(envied.GenerateOptions{
+			ConfigPath: configPath,
+			EnvFiles:   map[string]string{"prod": staging},
+		})
+	})
+	if err != nil {
+		t.Fatalf("ValidateWithWarnings() returned error: %v", err)
+	}
+
+	expected := []envied.Warning{
+		{Code: envied.WarningEnvFileOverride, Environment: "prod"},
+		{Code: envied.WarningNamePattern, Environment: "dev", Variable: "name"},
+		{Code: envied.WarningPlaceholder, Environment: "dev", Variable: "API_KEY"},
+		{Code: envied.WarningAmbiguousType, Environment: "dev", Variable: "PORT"},
+		{Code: envied.WarningAmbiguousType, Environment: "dev", Variable: "VERSION"},
+		{Code: envied.WarningPlaceholder, Environment: "prod", Variable: "API_KEY"},
+		{Code: envied.WarningAmbiguousType, Environment: "prod", Variable: "VERSION"},
+	}
+	if len(warnings) != len(expected) {
+		t.Fatalf("ValidateWithWarnings() = %+v, expected %d warnings", warnings, len(expected))
+	}
+	for i, warning := range warnings {
+		if warning.Code != expected[i].Code || warning.Environment != expected[i].Environment || warning.Variable != expected[i].Variable {
+			t.Errorf("warning %d = %+v, expected %+v", i, warning, expected[i])
+		}
+		if !strings.Contains(output, "⚠️ Warning: "+warning.Message) {
+			t.Errorf("Warning %q should be logged as well", warning.Message)
+		}
+		if strings.Contains(warning.Message, "changeme") || strings.Contains(warning.Message, "secret") {
+			t.Errorf("Warning %q must not include values", warning.Message)
+		}
+	}
+}
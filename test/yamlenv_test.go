@@ -0,0 +1,130 @@
+package test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/petrovyuri/go-envied"
+)
+
+func TestReadYAMLFile(t *testing.T) {
+	yamlFile := filepath.Join(t.TempDir(), "dev.yaml")
+	content := `---
+# API settings
+API_URL: https://dev.example.com # trailing comment
+PORT: 8080
+VERSION: "007"
+NAME: 'it''s'
+EMPTY: ~
+DATABASE:
+  HOST: localhost
+  CREDENTIALS:
+    USER: admin
+  # envied: type=string
+  PORT: 5432
+CACHE:
+`
+	if err := os.WriteFile(yamlFile, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to create YAML file: %v", err)
+	}
+
+	envVars, err := envied.ReadYAMLFile(yamlFile, "")
+	if err != nil {
+		t.Fatalf("ReadYAMLFile() returned error: %v", err)
+	}
+
+	expected := map[string]envied.EnvValue{
+		"API_URL":                   {Value: "https://dev.example.com", Line: 3},
+		"PORT":                      {Value: "8080", Line: 4},
+		"VERSION":                   {Value: "007", WasQuoted: true, Line: 5},
+		"NAME":                      {Value: "it's", WasQuoted: true, Line: 6},
+		"EMPTY":                     {Line: 7},
+		"DATABASE_HOST":             {Value: "localhost", Line: 9},
+		"DATABASE_CREDENTIALS_USER": {Value: "admin", Line: 11},
+		"DATABASE_PORT":             {Value: "5432", Line: 13, Hints: envied.EnvHints{Type: "string"}},
+		"CACHE":                     {Line: 14},
+	}
+	if len(envVars) != len(expected) {
+		t.Errorf("ReadYAMLFile() = %+v, expected %d variables", envVars, len(expected))
+	}
+	for name, want := range expected {
+		if got := envVars[name]; got != want {
+			t.Errorf("%s = %+v, expected %+v", name, got, want)
+		}
+	}
+
+	envVars, err = envied.ReadYAMLFile(yamlFile, ".")
+	if err != nil {
+		t.Fatalf("ReadYAMLFile() returned error: %v", err)
+	}
+	if _, exists := envVars["DATABASE.CREDENTIALS.USER"]; !exists {
+		t.Errorf("Nested keys should be joined with the separator, got %v", envVars)
+	}
+}
+
+func TestReadYAMLFileErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected string
+	}{
+		{"sequence", "HOSTS:\n  - a\n", ":2: sequences are not supported"},
+		{"flow collection", "FLAGS: {a: true}\n", ":1: key FLAGS: flow collections are not supported"},
+		{"block scalar", "KEY: |\n  line\n", ":1: key KEY: block scalars are not supported"},
+		{"duplicate key", "A:\n  B: 1\nA_B: 2\n", ":3: key A_B is already defined at line 2"},
+		{"inconsistent indentation", "A:\n  B: 1\n    C: 2\n", ":3: unexpected indentation"},
+		{"missing colon", "KEY value\n", ":1: expected 'key: value'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			yamlFile := filepath.Join(t.TempDir(), "env.yml")
+			if err := os.WriteFile(yamlFile, []byte(tt.content), 0644); err != nil {
+				t.Fatalf("Failed to create YAML file: %v", err)
+			}
+			if _, err := envied.ReadYAMLFile(yamlFile, ""); err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("ReadYAMLFile() = %v, expected error containing %q", err, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateFromYAMLEnvFile(t *testing.T) {
+	dir, content := generateConfig(t, map[string]string{
+		"dev":  "API_URL=https://dev.example.com\nDATABASE_HOST=localhost\nDATABASE_PORT=5432\n",
+		"prod": "",
+	}, func(config *envied.ConfigFile) {
+		prodFile := filepath.Join(filepath.Dir(config.Environments["prod"].EnvFile), "prod.yaml")
+		yaml := "API_URL: https://api.example.com\nDATABASE:\n  HOST: db.example.com\n  PORT: 5433\n"
+		if err := os.WriteFile(prodFile, []byte(yaml), 0644); err != nil {
+			t.Fatalf("Failed to create YAML file: %v", err)
+		}
+		config.Environments["prod"] = envied.EnvironmentConfig{EnvFile: prodFile, StructName: "Prod"}
+	})
+
+	if !strings.Contains(content, "GetDATABASE_PORT() int") {
+		t.Error("YAML values should be typed like .env values")
+	}
+
+	output, err := runGenerated(t, dir, `package main
+
+import (
+	"fmt"
+
+	"generated/config"
+)
+
+func main() {
+	prod := config.NewProdConfig()
+	fmt.Println(prod.GetAPI_URL(), prod.GetDATABASE_HOST(), prod.GetDATABASE_PORT())
+}
+`)
+	if err != nil {
+		t.Fatalf("Failed to run program: %v\n%s", err, output)
+	}
+	if expected := "https://api.example.com db.example.com 5433\n"; output != expected {
+		t.Errorf("Output = %q, expected %q", output, expected)
+	}
+}
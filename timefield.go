@@ -0,0 +1,70 @@
+package envied
+
+import "time"
+
+// timeLayoutNames maps the names of time package layouts accepted in time_layout to the layouts
+var timeLayoutNames = map[string]string{
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"DateTime":    time.DateTime,
+	"DateOnly":    time.DateOnly,
+	"TimeOnly":    time.TimeOnly,
+	"Kitchen":     time.Kitchen,
+}
+
+// resolveTimeLayout returns the layout for a time_layout setting, which is either
+// the name of a time package layout or a Go layout string
+func resolveTimeLayout(layout string) string {
+	if resolved, exists := timeLayoutNames[layout]; exists {
+		return resolved
+	}
+	return layout
+}
+
+// applyTimeLayouts types variables with a configured time_layout as time.Time,
+// values not matching the layout are an error
+func applyTimeLayouts(fields []Field, variables map[string]VariableConfig) error {
+	for i := range fields {
+		configured := variables[fields[i].EnvName].TimeLayout
+		if configured == "" {
+			continue
+		}
+
+		layout := resolveTimeLayout(configured)
+		if _, err := time.Parse(layout, fields[i].Value); err != nil {
+			return newError(MessageTimeLayoutMismatch, fields[i].EnvName, fields[i].Value, configured)
+		}
+		fields[i].Type = FieldTypeTime
+		fields[i].Layout = layout
+	}
+	return nil
+}
+
+// usesTime reports whether any field is a time.Time, so generated code must import time
+func usesTime(fields []Field) bool {
+	for _, field := range fields {
+		if field.Type == FieldTypeTime {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseTime converts a string in the given layout to time.Time
+func ParseTime(layout, value string) time.Time {
+	result, _ := time.Parse(layout, value)
+	return result
+}
+
+// CoerceTime converts a layered value in the given layout to time.Time
+func CoerceTime(name, layout, value string) (time.Time, error) {
+	parsed, err := time.Parse(layout, value)
+	if err != nil {
+		return time.Time{}, newError(MessageInvalidTimeValue, name, value, layout)
+	}
+	return parsed, nil
+}
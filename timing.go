@@ -0,0 +1,45 @@
+package envied
+
+import "time"
+
+// phaseTiming is the duration of one phase of a run
+type phaseTiming struct {
+	name     string
+	duration time.Duration
+}
+
+// phaseTimer measures consecutive phases of a run for the timing breakdown of the
+// generation report. A nil timer measures nothing, so phases are marked unconditionally.
+type phaseTimer struct {
+	last   time.Time
+	phases []phaseTiming
+}
+
+// newPhaseTimer returns a timer whose first phase started at start
+func newPhaseTimer(start time.Time) *phaseTimer {
+	return &phaseTimer{last: start}
+}
+
+// mark ends the current phase under the given name and starts the next one
+func (t *phaseTimer) mark(name string) {
+	if t == nil {
+		return
+	}
+	now := time.Now()
+	t.phases = append(t.phases, phaseTiming{name: name, duration: now.Sub(t.last)})
+	t.last = now
+}
+
+// report writes the duration of every phase and the total to log
+func (t *phaseTimer) report(log *messageLog) {
+	if t == nil {
+		return
+	}
+	var total time.Duration
+	log.say(MessageTimings)
+	for _, phase := range t.phases {
+		log.say(MessageTiming, phase.name, phase.duration)
+		total += phase.duration
+	}
+	log.say(MessageTiming, "total", total)
+}
@@ -0,0 +1,181 @@
+package envied
+
+import (
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// tomlNumberPattern matches decimal TOML numbers that may use underscores between digits
+var tomlNumberPattern = regexp.MustCompile(`^[+-]?\d+(_\d+)*(\.\d+(_\d+)*)?([eE][+-]?\d+(_\d+)*)?$`)
+
+// isTOMLFile reports whether an env file is read as TOML, by its extension
+func isTOMLFile(filename string) bool {
+	return strings.ToLower(filepath.Ext(filename)) == ".toml"
+}
+
+// ReadTOMLFile reads variables from a TOML document. Keys of tables and dotted keys are joined
+// into variable names with separator (DefaultKeySeparator if empty), e.g. host in [database]
+// becomes database_host. Strings are quoted values like quoted values of .env files, other
+// values are read as written. Arrays, inline tables, arrays of tables and multi-line strings
+// are not supported.
+func ReadTOMLFile(filename, separator string) (map[string]EnvValue, error) {
+	if separator == "" {
+		separator = DefaultKeySeparator
+	}
+	content, err := readEnvFileContent(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEnvFileContent(filename, content); err != nil {
+		return nil, err
+	}
+	content = normalizeEnvFileContent(content)
+
+	envVars := make(map[string]EnvValue)
+	tables := make(map[string]int)
+	prefix := ""
+
+	// Hints apply to the key on the next line only
+	var hints EnvHints
+	for i, line := range strings.Split(string(content), "\n") {
+		lineNumber := i + 1
+		trimmed := strings.TrimSpace(line)
+		lineHints := hints
+		hints = EnvHints{}
+
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			parsed, ok, err := parseEnvHints(trimmed)
+			if err != nil {
+				return nil, newError(MessageAtFileLine, filename, lineNumber, err)
+			}
+			if ok {
+				hints = parsed
+			}
+			continue
+		case strings.HasPrefix(trimmed, "[["):
+			return nil, newError(MessageArraysOfTables, filename, lineNumber)
+		case strings.HasPrefix(trimmed, "["):
+			keys, rest, err := parseTOMLKey(trimmed[1:])
+			if err != nil {
+				return nil, newError(MessageAtFileLine, filename, lineNumber, err)
+			}
+			rest, found := strings.CutPrefix(rest, "]")
+			if rest = strings.TrimSpace(rest); !found || (rest != "" && !strings.HasPrefix(rest, "#")) {
+				return nil, newError(MessageUnterminatedTableName, filename, lineNumber)
+			}
+			prefix = strings.Join(keys, separator)
+			if previous, exists := tables[prefix]; exists {
+				return nil, newError(MessageTableAlreadyDefined, filename, lineNumber, prefix, previous)
+			}
+			tables[prefix] = lineNumber
+			continue
+		}
+
+		keys, rest, err := parseTOMLKey(trimmed)
+		if err != nil {
+			return nil, newError(MessageAtFileLine, filename, lineNumber, err)
+		}
+		rawValue, found := strings.CutPrefix(rest, "=")
+		if !found {
+			return nil, newError(MessageExpectedTOMLKeyValue, filename, lineNumber)
+		}
+		name := strings.Join(keys, separator)
+		if prefix != "" {
+			name = prefix + separator + name
+		}
+		value, quoted, err := parseTOMLValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, newError(MessageKeyError, filename, lineNumber, name, err)
+		}
+		if previous, exists := envVars[name]; exists {
+			return nil, newError(MessageKeyAlreadyDefined, filename, lineNumber, name, previous.Line)
+		}
+		envVars[name] = EnvValue{Value: value, WasQuoted: quoted, Line: lineNumber, Hints: lineHints}
+	}
+	return envVars, nil
+}
+
+// parseTOMLKey parses the bare, quoted or dotted key at the start of s and returns
+// its parts with the rest of s
+func parseTOMLKey(s string) ([]string, string, error) {
+	var keys []string
+	for {
+		s = strings.TrimLeft(s, " \t")
+		if s == "" {
+			return nil, "", newError(MessageExpectedKey)
+		}
+
+		var key string
+		if s[0] == '"' || s[0] == '\'' {
+			var err error
+			if key, s, err = cutYAMLQuoted(s); err != nil {
+				return nil, "", err
+			}
+		} else {
+			end := strings.IndexFunc(s, func(r rune) bool {
+				return !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-')
+			})
+			if end == -1 {
+				end = len(s)
+			}
+			if end == 0 {
+				return nil, "", newError(MessageUnexpectedInKey, s[:1])
+			}
+			key, s = s[:end], s[end:]
+		}
+		keys = append(keys, key)
+
+		s = strings.TrimLeft(s, " \t")
+		if rest, found := strings.CutPrefix(s, "."); found {
+			s = rest
+			continue
+		}
+		return keys, s, nil
+	}
+}
+
+// parseTOMLValue parses a value and reports whether it was a string
+func parseTOMLValue(raw string) (string, bool, error) {
+	if raw == "" {
+		return "", false, newError(MessageMissingValue)
+	}
+	switch {
+	case strings.HasPrefix(raw, `"""`), strings.HasPrefix(raw, "'''"):
+		return "", false, newError(MessageMultiLineStringsNotSupported)
+	case raw[0] == '"' || raw[0] == '\'':
+		// Literal strings have no escapes, basic strings use the escapes of Go strings
+		var value, rest string
+		if raw[0] == '\'' {
+			end := strings.IndexByte(raw[1:], '\'')
+			if end == -1 {
+				return "", false, newError(MessageUnterminatedLiteralString)
+			}
+			value, rest = raw[1:end+1], raw[end+2:]
+		} else {
+			var err error
+			if value, rest, err = cutYAMLQuoted(raw); err != nil {
+				return "", false, err
+			}
+		}
+		if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", false, newError(MessageUnexpectedAfterString, rest)
+		}
+		return value, true, nil
+	case raw[0] == '[':
+		return "", false, newError(MessageTOMLArraysNotSupported)
+	case raw[0] == '{':
+		return "", false, newError(MessageInlineTablesNotSupported)
+	}
+
+	if i := strings.Index(raw, "#"); i >= 0 {
+		raw = strings.TrimSpace(raw[:i])
+	}
+	if tomlNumberPattern.MatchString(raw) {
+		raw = strings.ReplaceAll(raw, "_", "")
+	}
+	return raw, false, nil
+}
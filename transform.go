@@ -0,0 +1,93 @@
+package envied
+
+import (
+	"encoding/base64"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// TransformFunc transforms a raw env value before typing and obfuscation
+type TransformFunc func(value string) (string, error)
+
+var (
+	transformsMu sync.RWMutex
+	transforms   = map[string]TransformFunc{
+		"trim":      func(value string) (string, error) { return strings.TrimSpace(value), nil },
+		"lowercase": func(value string) (string, error) { return strings.ToLower(value), nil },
+		"uppercase": func(value string) (string, error) { return strings.ToUpper(value), nil },
+		"base64decode": func(value string) (string, error) {
+			decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(value))
+			if err != nil {
+				return "", newError(MessageInvalidBase64, err)
+			}
+			return string(decoded), nil
+		},
+	}
+)
+
+// RegisterTransform registers a named transform usable in the "transform" list of a variable.
+// Registering an existing name replaces the previous transform.
+func RegisterTransform(name string, fn TransformFunc) {
+	transformsMu.Lock()
+	defer transformsMu.Unlock()
+	transforms[name] = fn
+}
+
+// Transforms returns the sorted names of all registered transforms
+func Transforms() []string {
+	transformsMu.RLock()
+	defer transformsMu.RUnlock()
+
+	names := make([]string, 0, len(transforms))
+	for name := range transforms {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupTransform returns the transform registered under name
+func lookupTransform(name string) (TransformFunc, error) {
+	transformsMu.RLock()
+	defer transformsMu.RUnlock()
+
+	fn, exists := transforms[name]
+	if !exists {
+		return nil, newError(MessageUnknownTransform, name)
+	}
+	return fn, nil
+}
+
+// ApplyTransforms applies the named transforms to value in order
+func ApplyTransforms(value string, names []string) (string, error) {
+	for _, name := range names {
+		fn, err := lookupTransform(name)
+		if err != nil {
+			return "", err
+		}
+		value, err = fn(value)
+		if err != nil {
+			return "", newError(MessageTransformFailed, name, err)
+		}
+	}
+	return value, nil
+}
+
+// applyVariableTransforms applies the configured transforms to env values in place
+func applyVariableTransforms(envVars map[string]EnvValue, variables map[string]VariableConfig) error {
+	for name, variable := range variables {
+		envValue, exists := envVars[name]
+		if !exists || len(variable.Transform) == 0 {
+			continue
+		}
+
+		value, err := ApplyTransforms(envValue.Value, variable.Transform)
+		if err != nil {
+			return newError(MessageTransformVariableError, name, err)
+		}
+		envValue.Value = value
+		envVars[name] = envValue
+	}
+	return nil
+}
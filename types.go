@@ -0,0 +1,123 @@
+package envied
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// integerPattern matches values written as integer literals
+var integerPattern = regexp.MustCompile(`^[+-]?[0-9]+$`)
+
+// declaredFieldTypes lists the types accepted in a variable's type
+var declaredFieldTypes = append([]FieldType{FieldTypeString, FieldTypeBool, FieldTypeInt, FieldTypeFloat, FieldTypeInt64, FieldTypeUint64, FieldTypeBytes}, mapFieldTypes...)
+
+// isDeclaredType reports whether a field type can be declared with a variable's type
+func isDeclaredType(fieldType FieldType) bool {
+	for _, declared := range declaredFieldTypes {
+		if fieldType == declared {
+			return true
+		}
+	}
+	return false
+}
+
+// checkDeclaredValue reports whether a value can be converted to a declared type
+func checkDeclaredValue(fieldType FieldType, value, encoding string) error {
+	switch fieldType {
+	case FieldTypeString:
+	case FieldTypeBool:
+		if _, err := strconv.ParseBool(value); err != nil {
+			return newError(MessageValueNotBool, value)
+		}
+	case FieldTypeInt:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return newError(MessageValueNotInt, value)
+		}
+	case FieldTypeFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return newError(MessageValueNotFloat64, value)
+		}
+	case FieldTypeBytes:
+		// The value is not included, binary values are usually secrets
+		if _, err := decodeBytes(encoding, value); err != nil {
+			return newError(MessageValueNotValid, encoding)
+		}
+	case FieldTypeInt64:
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return newError(MessageValueNotInt64, value)
+		}
+	case FieldTypeUint64:
+		if _, err := strconv.ParseUint(value, 10, 64); err != nil {
+			return newError(MessageValueNotUint64, value)
+		}
+	default:
+		if err := decodeJSONValue(fieldType, value); err != nil {
+			return newError(MessageValueNotJSONObject, fieldType, err)
+		}
+	}
+	return nil
+}
+
+// applyDeclaredTypes types variables declared with a type, values that can't be
+// converted to that type are an error
+func applyDeclaredTypes(fields []Field, variables map[string]VariableConfig) error {
+	for i := range fields {
+		variable := variables[fields[i].EnvName]
+		if variable.Encoding != "" && variable.Type != string(FieldTypeBytes) {
+			return newError(MessageEncodingRequiresType, fields[i].EnvName, FieldTypeBytes)
+		}
+		if variable.Type == "" {
+			continue
+		}
+
+		fieldType := FieldType(variable.Type)
+		if !isDeclaredType(fieldType) {
+			names := make([]string, len(declaredFieldTypes))
+			for j, declared := range declaredFieldTypes {
+				names[j] = string(declared)
+			}
+			return newError(MessageUnsupportedVariableType, fields[i].EnvName, variable.Type, strings.Join(names, ", "))
+		}
+		if variable.Separator != "" || variable.TimeLayout != "" {
+			return newError(MessageTypeWithSeparator, fields[i].EnvName)
+		}
+		if fieldType == FieldTypeBytes {
+			encoding, err := resolveEncoding(variable.Encoding)
+			if err != nil {
+				return newError(MessageVariableError, fields[i].EnvName, err)
+			}
+			fields[i].Encoding = encoding
+		}
+		if err := checkDeclaredValue(fieldType, fields[i].Value, fields[i].Encoding); err != nil {
+			return newError(MessageVariableError, fields[i].EnvName, err)
+		}
+		fields[i].Type = fieldType
+		fields[i].Layout = ""
+	}
+	return nil
+}
+
+// checkIntegerRanges fails for detected integers that would produce wrong values: int values
+// overflowing int on 32-bit targets and integer literals too large for int64, which would
+// otherwise be detected as float64. Declared types are checked by applyDeclaredTypes.
+func checkIntegerRanges(fields []Field, variables map[string]VariableConfig) error {
+	for _, field := range fields {
+		if variables[field.EnvName].Type != "" && field.Type != FieldTypeInt {
+			continue
+		}
+		switch {
+		case field.Type == FieldTypeInt:
+			if value, _ := strconv.ParseInt(field.Value, 10, 64); value < math.MinInt32 || value > math.MaxInt32 {
+				return newError(MessageIntOverflows32Bit, field.EnvName, field.Value)
+			}
+		case field.Type == FieldTypeFloat && integerPattern.MatchString(field.Value):
+			if _, err := strconv.ParseUint(strings.TrimPrefix(field.Value, "+"), 10, 64); err == nil {
+				return newError(MessageIntOverflowsInt64, field.EnvName, field.Value)
+			}
+			return newError(MessageIntOverflows64Bit, field.EnvName, field.Value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package envied
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Unsafe character policies, for control characters and invalid UTF-8 in values
+const (
+	UnsafeCharactersAllow  = "allow"  // Embed values as they are (default)
+	UnsafeCharactersReject = "reject" // Refuse to generate
+	UnsafeCharactersEscape = "escape" // Replace them by escape sequences such as \x01, with a warning
+)
+
+// unsafeCharactersMode returns the effective unsafe character policy of the config
+func unsafeCharactersMode(configFile *ConfigFile) (string, error) {
+	switch configFile.OnUnsafeCharacters {
+	case "":
+		return UnsafeCharactersAllow, nil
+	case UnsafeCharactersAllow, UnsafeCharactersReject, UnsafeCharactersEscape:
+		return configFile.OnUnsafeCharacters, nil
+	default:
+		return "", newError(MessageUnknownUnsafeCharactersPolicy, configFile.OnUnsafeCharacters, UnsafeCharactersAllow, UnsafeCharactersReject, UnsafeCharactersEscape)
+	}
+}
+
+// isUnsafeRune reports control characters other than tab and newline, and bidirectional
+// controls that make code and values display differently than they are
+func isUnsafeRune(r rune) bool {
+	return (unicode.IsControl(r) && r != '\t' && r != '\n') || unicode.Is(unicode.Bidi_Control, r)
+}
+
+// findUnsafeCharacter returns the byte offset and a description of the first unsafe
+// character of a value, -1 if there is none
+func findUnsafeCharacter(value string) (int, string) {
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		if r == utf8.RuneError && size == 1 {
+			return i, fmt.Sprintf("invalid UTF-8 byte 0x%02x", value[i])
+		}
+		if isUnsafeRune(r) {
+			return i, fmt.Sprintf("control character %U", r)
+		}
+		i += size
+	}
+	return -1, ""
+}
+
+// escapeUnsafeCharacters replaces unsafe characters and invalid UTF-8 bytes by Go escape sequences
+func escapeUnsafeCharacters(value string) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); {
+		r, size := utf8.DecodeRuneInString(value[i:])
+		switch {
+		case r == utf8.RuneError && size == 1, isUnsafeRune(r) && r < utf8.RuneSelf:
+			fmt.Fprintf(&escaped, `\x%02x`, value[i])
+		case isUnsafeRune(r):
+			fmt.Fprintf(&escaped, `\u%04x`, r)
+		default:
+			escaped.WriteString(value[i : i+size])
+		}
+		i += size
+	}
+	return escaped.String()
+}
+
+// checkUnsafeCharacters applies on_unsafe_characters to the values of all environments. Values
+// from remote sources and transforms can hold characters that corrupt generated string literals
+// or display differently than they are; by default they are embedded unchanged.
+func checkUnsafeCharacters(configFile *ConfigFile, allEnvVars map[string]map[string]EnvValue, provenances map[string]map[string]Provenance, warnings *warningLog) error {
+	mode, err := unsafeCharactersMode(configFile)
+	if err != nil || mode == UnsafeCharactersAllow {
+		return err
+	}
+
+	envNames := make([]string, 0, len(allEnvVars))
+	for envName := range allEnvVars {
+		envNames = append(envNames, envName)
+	}
+	sort.Strings(envNames)
+
+	for _, envName := range envNames {
+		envVars := allEnvVars[envName]
+		names := make([]string, 0, len(envVars))
+		for name := range envVars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			envValue := envVars[name]
+			offset, description := findUnsafeCharacter(envValue.Value)
+			if offset < 0 {
+				continue
+			}
+
+			variable := name
+			if source := provenances[envName][name].Source; source != "" {
+				variable = fmt.Sprintf("%s (%s)", name, source)
+			}
+			if mode == UnsafeCharactersReject {
+				return newError(MessageUnsafeCharacters, envName, variable, description, offset, UnsafeCharactersEscape)
+			}
+
+			envValue.Value = escapeUnsafeCharacters(envValue.Value)
+			envVars[name] = envValue
+			warnings.warn(Warning{
+				Code:        WarningUnsafeCharacters,
+				Environment: envName,
+				Variable:    name,
+			}, MessageWarningUnsafeCharacters, variable, envName, description)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,162 @@
+package envied
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ValuesFromVault is the GenerateOptions.ValuesFrom source reading values from Vault
+const ValuesFromVault = "vault"
+
+// Vault defaults, following the Vault CLI environment variables
+const (
+	DefaultVaultAddress  = "https://127.0.0.1:8200"
+	DefaultVaultMount    = "secret"
+	DefaultVaultTokenEnv = "VAULT_TOKEN"
+)
+
+// VaultConfig configures reading the values of an environment from a Vault KV v2 secret.
+// The env file of the environment holds the committed structure: the variable names with
+// type hints and quoting, and optional defaults. Values are read only by generation with
+// ValuesFrom set to ValuesFromVault, typically in CI.
+type VaultConfig struct {
+	Address   string `json:"address,omitempty"`   // Vault address (VAULT_ADDR or DefaultVaultAddress if empty)
+	Mount     string `json:"mount,omitempty"`     // Mount path of the KV v2 secrets engine (DefaultVaultMount if empty)
+	Path      string `json:"path"`                // Path of the secret holding the values below the mount
+	Namespace string `json:"namespace,omitempty"` // Vault Enterprise namespace (VAULT_NAMESPACE if empty)
+	TokenEnv  string `json:"token_env,omitempty"` // Environment variable holding the token (DefaultVaultTokenEnv if empty)
+}
+
+// address returns the Vault address with scheme
+func (v VaultConfig) address() string {
+	address := v.Address
+	if address == "" {
+		address = os.Getenv("VAULT_ADDR")
+	}
+	if address == "" {
+		address = DefaultVaultAddress
+	}
+	if !strings.Contains(address, "://") {
+		address = "https://" + address
+	}
+	return strings.TrimSuffix(address, "/")
+}
+
+// mount returns the mount path of the secrets engine
+func (v VaultConfig) mount() string {
+	if mount := strings.Trim(v.Mount, "/"); mount != "" {
+		return mount
+	}
+	return DefaultVaultMount
+}
+
+// describe returns a human-readable location of the values
+func (v VaultConfig) describe() string {
+	return fmt.Sprintf("vault %s/%s/%s", v.address(), v.mount(), strings.Trim(v.Path, "/"))
+}
+
+// fetch reads the values of the secret. Values that aren't JSON strings are kept as JSON.
+func (v VaultConfig) fetch(ctx context.Context, client *http.Client) (map[string]string, error) {
+	if strings.Trim(v.Path, "/") == "" {
+		return nil, newError(MessageVaultPathNotSet)
+	}
+	tokenEnv := v.TokenEnv
+	if tokenEnv == "" {
+		tokenEnv = DefaultVaultTokenEnv
+	}
+	token := os.Getenv(tokenEnv)
+	if token == "" {
+		return nil, newError(MessageVaultTokenNotSet, tokenEnv, v.describe())
+	}
+
+	requestURL := fmt.Sprintf("%s/v1/%s/data/%s", v.address(), v.mount(), strings.Trim(v.Path, "/"))
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, requestURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	request.Header.Set("X-Vault-Token", token)
+	namespace := v.Namespace
+	if namespace == "" {
+		namespace = os.Getenv("VAULT_NAMESPACE")
+	}
+	if namespace != "" {
+		request.Header.Set("X-Vault-Namespace", namespace)
+	}
+
+	response, err := client.Do(request)
+	if err != nil {
+		return nil, newError(MessageFailedQuery, v.describe(), err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(response.Body, 1024))
+		return nil, newError(MessageQueryStatus, v.describe(), response.Status, strings.TrimSpace(string(body)))
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(response.Body).Decode(&secret); err != nil {
+		return nil, newError(MessageInvalidResponse, v.describe(), err)
+	}
+	values := make(map[string]string, len(secret.Data.Data))
+	for name, raw := range secret.Data.Data {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = string(raw)
+		}
+		values[name] = value
+	}
+	return values, nil
+}
+
+// checkValuesFrom fails for unknown sources of GenerateOptions.ValuesFrom
+func checkValuesFrom(valuesFrom string) error {
+	switch valuesFrom {
+	case "", ValuesFromVault:
+		return nil
+	}
+	return newError(MessageUnknownValuesSource, valuesFrom, ValuesFromVault)
+}
+
+// mergeVaultValues replaces the values of the structure read from the env file of an environment
+// with the values of its Vault secret and returns their hash. The secret can't add variables, so
+// the committed structure stays the reviewable list of variables; variables without a value in
+// the secret keep the value of the structure. Vault must answer within timeout.
+func mergeVaultValues(envConfig EnvironmentConfig, timeout time.Duration, envVars map[string]EnvValue, provenance map[string]Provenance) (string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	values, err := envConfig.Vault.fetch(ctx, &http.Client{Timeout: timeout})
+	if err != nil {
+		return "", sourceTimeoutError(ctx, timeout, err)
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		envValue, declared := envVars[name]
+		if !declared {
+			return "", newError(MessageVaultUndeclaredVariable, envConfig.Vault.describe(), name, envConfig.EnvFile)
+		}
+		// Quoting and hints of the structure still decide the type
+		envValue.Value = values[name]
+		envVars[name] = envValue
+		previous := provenance[name]
+		provenance[name] = Provenance{Source: envConfig.Vault.describe(), Overrides: append(slices.Clone(previous.Overrides), previous.Source)}
+	}
+	return hashValues(values), nil
+}
@@ -0,0 +1,144 @@
+package envied
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// embeddedSourceHashes returns the source hashes stamped into generated files by environment name.
+// The Environment and SourceHash methods are matched by receiver type, pointer or value.
+func embeddedSourceHashes(sources ...[]byte) (map[string]string, error) {
+	environments := make(map[string]string)
+	hashes := make(map[string]string)
+	for _, source := range sources {
+		file, err := parser.ParseFile(token.NewFileSet(), "", source, 0)
+		if err != nil {
+			return nil, err
+		}
+		for _, decl := range file.Decls {
+			receiver, name, value, ok := constantMethod(decl)
+			if !ok {
+				continue
+			}
+			switch name {
+			case "Environment":
+				environments[receiver] = value
+			case "SourceHash":
+				hashes[receiver] = value
+			}
+		}
+	}
+
+	byEnvironment := make(map[string]string)
+	for receiver, hash := range hashes {
+		if envName, exists := environments[receiver]; exists {
+			byEnvironment[envName] = hash
+		}
+	}
+	return byEnvironment, nil
+}
+
+// constantMethod returns the receiver type, name and result of a method only returning a string literal
+func constantMethod(decl ast.Decl) (string, string, string, bool) {
+	fn, ok := decl.(*ast.FuncDecl)
+	if !ok || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil || len(fn.Body.List) != 1 {
+		return "", "", "", false
+	}
+	recvType := fn.Recv.List[0].Type
+	if star, ok := recvType.(*ast.StarExpr); ok {
+		recvType = star.X
+	}
+	receiver, ok := recvType.(*ast.Ident)
+	if !ok {
+		return "", "", "", false
+	}
+	ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+	if !ok || len(ret.Results) != 1 {
+		return "", "", "", false
+	}
+	lit, ok := ret.Results[0].(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", "", "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	if err != nil {
+		return "", "", "", false
+	}
+	return receiver.Name, fn.Name.Name, value, true
+}
+
+// Verify compares the SHA-256 of the current env files with the source hashes stamped into the
+// generated file and returns an error wrapping ErrOutdated if an env file changed since generation.
+// Unlike Check it neither parses env files nor regenerates, so it is cheap enough for pre-commit
+// hooks. Environments read from remote sources or taking their values from Vault are skipped.
+func Verify(opts GenerateOptions) error {
+	return opts.run(func() error {
+		return verify(opts)
+	})
+}
+
+// verify implements Verify
+func verify(opts GenerateOptions) error {
+	configFile, _, outputFile, err := opts.load()
+	if err != nil {
+		return err
+	}
+
+	// With internal_package the hashes are in the internal package, not in the shim
+	codeFile, _, err := configFile.generatedCodeFile(outputFile)
+	if err != nil {
+		return err
+	}
+	existing, err := readGenerated(codeFile)
+	if err != nil {
+		return err
+	}
+	sources := [][]byte{existing}
+	// With split_environments the hashes are in the files of the environments
+	if configFile.SplitEnvironments {
+		namer, err := configFile.effectiveNamer()
+		if err != nil {
+			return err
+		}
+		for _, envName := range configFile.environmentNames() {
+			if envCode, err := os.ReadFile(environmentFile(codeFile, namer.File(envName))); err == nil {
+				sources = append(sources, envCode)
+			}
+		}
+	}
+	embedded, err := embeddedSourceHashes(sources...)
+	if err != nil {
+		return newError(MessageVerifyFailedParse, codeFile, err)
+	}
+
+	var changed []string
+	for _, envName := range configFile.environmentNames() {
+		envConfig := configFile.Environments[envName]
+		if len(envConfig.remoteSources()) > 0 || envConfig.Vault != nil {
+			continue
+		}
+
+		stamped, exists := embedded[envName]
+		if !exists {
+			changed = append(changed, fmt.Sprintf("  %s: not in %s", envName, codeFile))
+			continue
+		}
+		current, err := hashEnvFiles(envConfig)
+		if err != nil {
+			return newError(MessageVerifyEnvironmentError, envName, err)
+		}
+		if current != stamped {
+			changed = append(changed, fmt.Sprintf("  %s: %s changed since generation", envName, envConfig.EnvFile))
+		}
+	}
+
+	if len(changed) > 0 {
+		return newError(MessageVerifyStale, ErrOutdated, codeFile, strings.Join(changed, "\n"))
+	}
+	return nil
+}
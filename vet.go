@@ -0,0 +1,159 @@
+package envied
+
+import (
+	"bytes"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+)
+
+// getenvFunctions are the functions of package os reading the process environment by name
+var getenvFunctions = map[string]bool{"Getenv": true, "LookupEnv": true}
+
+// GetenvCall is a direct lookup of a variable managed by go-envied, bypassing the generated configuration
+type GetenvCall struct {
+	Position token.Position // Position of the call
+	Function string         // os.Getenv or os.LookupEnv
+	Name     string         // Variable name
+	Getter   string         // Name of the generated getter to use instead
+}
+
+// packageFiles returns the paths of the Go files of a package, including tests
+func packageFiles(pkg listedPackage) []string {
+	var files []string
+	for _, names := range [][]string{pkg.GoFiles, pkg.CgoFiles, pkg.TestGoFiles, pkg.XTestGoFiles} {
+		for _, name := range names {
+			files = append(files, filepath.Join(pkg.Dir, name))
+		}
+	}
+	return files
+}
+
+// osImportName returns the name package os is imported as in a file, empty if not imported
+func osImportName(file *ast.File) string {
+	for _, spec := range file.Imports {
+		if path, _ := strconv.Unquote(spec.Path.Value); path != "os" {
+			continue
+		}
+		if spec.Name != nil {
+			return spec.Name.Name
+		}
+		return "os"
+	}
+	return ""
+}
+
+// getenvCallName returns the variable name of a call of os.Getenv or os.LookupEnv with a constant
+// string argument, with the called function
+func getenvCallName(call *ast.CallExpr, osName string) (string, string, bool) {
+	var function string
+	switch fun := call.Fun.(type) {
+	case *ast.SelectorExpr:
+		if ident, ok := fun.X.(*ast.Ident); !ok || ident.Name != osName {
+			return "", "", false
+		}
+		function = fun.Sel.Name
+	case *ast.Ident:
+		if osName != "." {
+			return "", "", false
+		}
+		function = fun.Name
+	default:
+		return "", "", false
+	}
+	if !getenvFunctions[function] || len(call.Args) != 1 {
+		return "", "", false
+	}
+
+	literal, ok := call.Args[0].(*ast.BasicLit)
+	if !ok || literal.Kind != token.STRING {
+		return "", "", false
+	}
+	name, err := strconv.Unquote(literal.Value)
+	if err != nil {
+		return "", "", false
+	}
+	return name, "os." + function, true
+}
+
+// FindGetenvCalls analyzes the packages matching patterns (./... by default) and returns the
+// os.Getenv and os.LookupEnv calls reading variables of the configuration, which should be read
+// from the typed generated configuration instead. Files generated by go-envied are skipped.
+func FindGetenvCalls(configFilePath string, patterns ...string) ([]GetenvCall, error) {
+	configFile, err := LoadConfigFile(configFilePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Variables of a single environment are managed too
+	configFile.AllowExtraVariables = true
+	mergedData, err := buildMergedConfig(configFile, configFilePath, nil)
+	if err != nil {
+		return nil, err
+	}
+	getters := make(map[string]string)
+	for _, envData := range mergedData.Environments {
+		for _, field := range envData.Fields {
+			getters[field.EnvName] = field.Getter
+		}
+	}
+
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	packages, err := listPackages(patterns)
+	if err != nil {
+		return nil, err
+	}
+
+	var calls []GetenvCall
+	fset := token.NewFileSet()
+	for _, pkg := range packages {
+		for _, filename := range packageFiles(pkg) {
+			src, err := os.ReadFile(filename)
+			if err != nil {
+				return nil, err
+			}
+			if bytes.HasPrefix(src, []byte(generatedHeader)) {
+				continue
+			}
+
+			file, err := parser.ParseFile(fset, filename, src, parser.SkipObjectResolution)
+			if err != nil {
+				return nil, err
+			}
+			osName := osImportName(file)
+			if osName == "" || osName == "_" {
+				continue
+			}
+			ast.Inspect(file, func(node ast.Node) bool {
+				call, ok := node.(*ast.CallExpr)
+				if !ok {
+					return true
+				}
+				name, function, ok := getenvCallName(call, osName)
+				if getter, managed := getters[name]; ok && managed {
+					calls = append(calls, GetenvCall{
+						Position: fset.Position(call.Pos()),
+						Function: function,
+						Name:     name,
+						Getter:   getter,
+					})
+				}
+				return true
+			})
+		}
+	}
+
+	sort.Slice(calls, func(i, j int) bool {
+		if calls[i].Position.Filename != calls[j].Position.Filename {
+			return calls[i].Position.Filename < calls[j].Position.Filename
+		}
+		return calls[i].Position.Offset < calls[j].Position.Offset
+	})
+	return calls, nil
+}
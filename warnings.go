@@ -0,0 +1,135 @@
+package envied
+
+import (
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Warning codes
+const (
+	WarningNamePattern      = "name_pattern"      // Variable name doesn't match the name pattern
+	WarningConfusableNames  = "confusable_names"  // Variable names differ only by case or confusable characters
+	WarningAmbiguousType    = "ambiguous_type"    // Detected type may not be the intended one, e.g. PORT=1 detected as bool
+	WarningEnvFileOverride  = "env_file_override" // Environment read from an env file given instead of its configured source
+	WarningPlaceholder      = "placeholder"       // Value looks like a placeholder such as changeme or <your-key>
+	WarningEmptyEnvironment = "empty_environment" // Environment has no variables, allowed by allow_empty_environments
+	WarningUnsafeCharacters = "unsafe_characters" // Value had control characters or invalid UTF-8, escaped by on_unsafe_characters
+	WarningConflictRenamed  = "conflict_renamed"  // Generated type renamed for a conflict with other package files, see ConflictRename
+)
+
+// Warning is a problem that doesn't fail generation. Warnings are written to the log
+// and returned by GenerateWithWarnings and ValidateWithWarnings, e.g. to post them as
+// review comments. They never include values.
+type Warning struct {
+	Code        string    // One of the Warning* codes
+	Environment string    // Environment the warning is about, empty if it concerns all environments
+	Variable    string    // Variable the warning is about, empty if it concerns no single variable
+	ID          MessageID // Message of the warning in a Catalog
+	Args        []any     // Arguments of the format of the ID
+	Message     string    // Message formatted with DefaultCatalog
+}
+
+// String returns the warning message
+func (w Warning) String() string {
+	return w.Message
+}
+
+// warningLog collects the warnings of a run and writes each to the log as it is reported
+type warningLog struct {
+	log      *messageLog
+	warnings []Warning
+}
+
+// Warning messages, with the arguments of their format
+const (
+	MessageWarningNamePattern      MessageID = "warning_name_pattern"      // variable, environment, pattern
+	MessageWarningConfusableNames  MessageID = "warning_confusable_names"  // variables
+	MessageWarningNumericBool      MessageID = "warning_numeric_bool"      // variable, environment
+	MessageWarningLeadingZeros     MessageID = "warning_leading_zeros"     // variable, environment
+	MessageWarningEnvFileOverride  MessageID = "warning_env_file_override" // environment, env file
+	MessageWarningPlaceholder      MessageID = "warning_placeholder"       // variable, environment
+	MessageWarningEmptyEnvironment MessageID = "warning_empty_environment" // environment, source
+	MessageWarningUnsafeCharacters MessageID = "warning_unsafe_characters" // variable, environment, characters
+	MessageWarningConflictRenamed  MessageID = "warning_conflict_renamed"  // struct name, generated struct name
+)
+
+// warningMessages holds the English warning messages, part of DefaultCatalog
+var warningMessages = Catalog{
+	MessageWarningNamePattern:      "variable name '%s' in environment '%s' does not match %s",
+	MessageWarningConfusableNames:  "variable names %s differ only by case or confusable characters",
+	MessageWarningNumericBool:      "variable %s in environment '%s' is detected as bool, not int, pin its type in fields",
+	MessageWarningLeadingZeros:     "variable %s in environment '%s' is detected as int and loses its leading zeros, pin its type in fields",
+	MessageWarningEnvFileOverride:  "environment '%s' is read from %s instead of its configured source",
+	MessageWarningPlaceholder:      "variable %s in environment '%s' looks like a placeholder value",
+	MessageWarningEmptyEnvironment: "environment '%s' has no variables, check that %s is the intended source",
+	MessageWarningUnsafeCharacters: "variable %s in environment '%s' contains %s, replaced by escape sequences",
+	MessageWarningConflictRenamed:  "%sConfig conflicts with existing package files and is generated as %sConfig",
+}
+
+// warn records a warning with its message
+func (w *warningLog) warn(warning Warning, id MessageID, args ...any) {
+	warning.ID = id
+	warning.Args = args
+	warning.Message = DefaultCatalog.Format(id, args...)
+	w.warnings = append(w.warnings, warning)
+	w.log.say(MessageWarning, w.log.format(id, args...))
+}
+
+// placeholderPattern matches values commonly left in env files instead of real values
+var placeholderPattern = regexp.MustCompile(`(?i)^(changeme|change[-_]me|replace[-_]?me|todo|tbd|fixme|placeholder|x{3,}|your[-_].+|<[^>]*>)$`)
+
+// leadingZeroPattern matches integer literals losing leading zeros when detected as int
+var leadingZeroPattern = regexp.MustCompile(`^[+-]?0[0-9]+$`)
+
+// warnEnvFileOverrides reports environments read from env files given in GenerateOptions.EnvFiles
+func warnEnvFileOverrides(configFile *ConfigFile, warnings *warningLog) {
+	envNames := append([]string(nil), configFile.envFileOverrides...)
+	sort.Strings(envNames)
+	for _, envName := range envNames {
+		warnings.warn(Warning{
+			Code:        WarningEnvFileOverride,
+			Environment: envName,
+		}, MessageWarningEnvFileOverride, envName, configFile.Environments[envName].EnvFile)
+	}
+}
+
+// warnSuspiciousValues reports placeholder values and values whose detected type may not
+// be the intended one. Variables with a declared type are not ambiguous.
+func warnSuspiciousValues(envName string, fields []Field, variables map[string]VariableConfig, warnings *warningLog) {
+	for _, field := range fields {
+		if placeholderPattern.MatchString(strings.TrimSpace(field.Value)) {
+			warnings.warn(Warning{
+				Code:        WarningPlaceholder,
+				Environment: envName,
+				Variable:    field.EnvName,
+			}, MessageWarningPlaceholder, field.EnvName, envName)
+		}
+
+		variable := variables[field.EnvName]
+		if variable.Type != "" || variable.Separator != "" || variable.TimeLayout != "" {
+			continue
+		}
+		var id MessageID
+		switch {
+		case field.Type == FieldTypeBool && isNumericBool(field.Value):
+			id = MessageWarningNumericBool
+		case field.Type == FieldTypeInt && leadingZeroPattern.MatchString(field.Value):
+			id = MessageWarningLeadingZeros
+		default:
+			continue
+		}
+		warnings.warn(Warning{
+			Code:        WarningAmbiguousType,
+			Environment: envName,
+			Variable:    field.EnvName,
+		}, id, field.EnvName, envName)
+	}
+}
+
+// isNumericBool reports whether a bool value is also an integer, e.g. 1 or 0
+func isNumericBool(value string) bool {
+	_, err := strconv.Atoi(value)
+	return err == nil
+}
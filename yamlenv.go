@@ -0,0 +1,242 @@
+package envied
+
+import (
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// DefaultKeySeparator joins nested YAML keys into variable names
+const DefaultKeySeparator = "_"
+
+// isYAMLFile reports whether an env file is read as YAML, by its extension
+func isYAMLFile(filename string) bool {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// readEnvFile reads the env file of an environment as YAML, TOML, JSON or in dotenv format
+func readEnvFile(envConfig EnvironmentConfig) (map[string]EnvValue, error) {
+	if isYAMLFile(envConfig.EnvFile) {
+		return ReadYAMLFile(envConfig.EnvFile, envConfig.KeySeparator)
+	}
+	if isTOMLFile(envConfig.EnvFile) {
+		return ReadTOMLFile(envConfig.EnvFile, envConfig.KeySeparator)
+	}
+	if isJSONFile(envConfig.EnvFile) {
+		return ReadJSONFile(envConfig.EnvFile)
+	}
+	return ReadEnvFileWithMetadata(envConfig.EnvFile)
+}
+
+// yamlMapping is a mapping being read, opened by a key without a value
+type yamlMapping struct {
+	indent int    // Indentation of the key opening the mapping
+	name   string // Variable name prefix of the mapping's keys
+	line   int    // Line of the key opening the mapping
+	empty  bool   // No keys read yet, an empty mapping is an empty value
+	keys   int    // Indentation of the mapping's keys, set by the first key
+}
+
+// ReadYAMLFile reads variables from a YAML mapping. Nested keys are flattened into variable
+// names joined with separator (DefaultKeySeparator if empty), e.g. database.host becomes
+// database_host. Quoted scalars are strings like quoted values of .env files, null and ~ are
+// empty. Sequences, flow collections, block scalars, anchors and tags are not supported.
+func ReadYAMLFile(filename, separator string) (map[string]EnvValue, error) {
+	if separator == "" {
+		separator = DefaultKeySeparator
+	}
+	content, err := readEnvFileContent(filename)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkEnvFileContent(filename, content); err != nil {
+		return nil, err
+	}
+	content = normalizeEnvFileContent(content)
+
+	envVars := make(map[string]EnvValue)
+	set := func(name string, value EnvValue) error {
+		if previous, exists := envVars[name]; exists {
+			return newError(MessageKeyAlreadyDefined, filename, value.Line, name, previous.Line)
+		}
+		envVars[name] = value
+		return nil
+	}
+
+	var stack []yamlMapping
+	// closeMappings closes the mappings not containing a key at indent, empty ones are empty values
+	closeMappings := func(indent int) error {
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			mapping := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			if mapping.empty {
+				if err := set(mapping.name, EnvValue{Line: mapping.line}); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	// Hints apply to the key on the next line only
+	var hints EnvHints
+	for i, line := range strings.Split(string(content), "\n") {
+		lineNumber := i + 1
+		line = strings.TrimRight(line, " \r")
+		trimmed := strings.TrimLeft(line, " ")
+		lineHints := hints
+		hints = EnvHints{}
+
+		switch {
+		case trimmed == "":
+			continue
+		case strings.HasPrefix(trimmed, "#"):
+			parsed, ok, err := parseEnvHints(trimmed)
+			if err != nil {
+				return nil, newError(MessageAtFileLine, filename, lineNumber, err)
+			}
+			if ok {
+				hints = parsed
+			}
+			continue
+		case trimmed == "---" && len(envVars) == 0 && len(stack) == 0:
+			continue
+		case strings.HasPrefix(trimmed, "\t"):
+			return nil, newError(MessageTabIndentation, filename, lineNumber)
+		case trimmed == "-" || strings.HasPrefix(trimmed, "- "):
+			return nil, newError(MessageSequencesNotSupported, filename, lineNumber)
+		}
+
+		indent := len(line) - len(trimmed)
+		if err := closeMappings(indent); err != nil {
+			return nil, err
+		}
+		key, rawValue, err := splitYAMLKey(trimmed)
+		if err != nil {
+			return nil, newError(MessageAtFileLine, filename, lineNumber, err)
+		}
+		name := key
+		if len(stack) == 0 {
+			if indent > 0 {
+				return nil, newError(MessageUnexpectedIndentation, filename, lineNumber)
+			}
+		} else {
+			parent := &stack[len(stack)-1]
+			if parent.empty {
+				parent.keys = indent
+			} else if indent != parent.keys {
+				return nil, newError(MessageUnexpectedIndentation, filename, lineNumber)
+			}
+			parent.empty = false
+			name = parent.name + separator + key
+		}
+
+		if rawValue == "" {
+			stack = append(stack, yamlMapping{indent: indent, name: name, line: lineNumber, empty: true})
+			continue
+		}
+		value, quoted, err := parseYAMLScalar(rawValue)
+		if err != nil {
+			return nil, newError(MessageKeyError, filename, lineNumber, name, err)
+		}
+		if err := set(name, EnvValue{Value: value, WasQuoted: quoted, Line: lineNumber, Hints: lineHints}); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := closeMappings(0); err != nil {
+		return nil, err
+	}
+	return envVars, nil
+}
+
+// splitYAMLKey splits a "key: value" line into the key and the raw value,
+// which is empty for keys opening a nested mapping
+func splitYAMLKey(line string) (string, string, error) {
+	if strings.HasPrefix(line, `"`) || strings.HasPrefix(line, "'") {
+		key, rest, err := cutYAMLQuoted(line)
+		if err != nil {
+			return "", "", err
+		}
+		rest, found := strings.CutPrefix(rest, ":")
+		if !found || (rest != "" && !strings.HasPrefix(rest, " ")) {
+			return "", "", newError(MessageExpectedColonAfterKey, key)
+		}
+		return key, strings.TrimSpace(rest), nil
+	}
+
+	if key, found := strings.CutSuffix(line, ":"); found && !strings.Contains(key, ": ") {
+		return strings.TrimSpace(key), "", nil
+	}
+	key, value, found := strings.Cut(line, ": ")
+	if !found {
+		return "", "", newError(MessageExpectedYAMLKeyValue)
+	}
+	return strings.TrimSpace(key), strings.TrimSpace(value), nil
+}
+
+// parseYAMLScalar parses a scalar value and reports whether it was quoted
+func parseYAMLScalar(raw string) (string, bool, error) {
+	switch raw[0] {
+	case '"', '\'':
+		value, rest, err := cutYAMLQuoted(raw)
+		if err != nil {
+			return "", false, err
+		}
+		if rest = strings.TrimSpace(rest); rest != "" && !strings.HasPrefix(rest, "#") {
+			return "", false, newError(MessageUnexpectedAfterQuotedValue, rest)
+		}
+		return value, true, nil
+	case '[', '{':
+		return "", false, newError(MessageFlowCollectionsNotSupported)
+	case '|', '>':
+		return "", false, newError(MessageBlockScalarsNotSupported)
+	case '&', '*', '!':
+		return "", false, newError(MessageAnchorsNotSupported)
+	}
+
+	if i := strings.Index(raw, " #"); i >= 0 {
+		raw = strings.TrimSpace(raw[:i])
+	}
+	switch raw {
+	case "~", "null", "Null", "NULL":
+		return "", false, nil
+	}
+	return raw, false, nil
+}
+
+// cutYAMLQuoted parses the quoted string at the start of s and returns it with the rest of s
+func cutYAMLQuoted(s string) (string, string, error) {
+	if s[0] == '\'' {
+		// Single quotes are escaped by doubling them
+		for i := 1; i < len(s); i++ {
+			if s[i] != '\'' {
+				continue
+			}
+			if i+1 < len(s) && s[i+1] == '\'' {
+				i++
+				continue
+			}
+			return strings.ReplaceAll(s[1:i], "''", "'"), s[i+1:], nil
+		}
+		return "", "", newError(MessageUnterminatedSingleQuotedString)
+	}
+
+	for i := 1; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '"':
+			value, err := strconv.Unquote(s[:i+1])
+			if err != nil {
+				return "", "", newError(MessageInvalidDoubleQuotedString, s[:i+1])
+			}
+			return value, s[i+1:], nil
+		}
+	}
+	return "", "", newError(MessageUnterminatedDoubleQuotedString)
+}